@@ -1,41 +1,125 @@
 package app
 
 import (
+	"context"
 	"fmt"
-	"time"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/agustinrabini/items-api-project/src/main/api/config"
 	"github.com/agustinrabini/items-api-project/src/main/api/dependencies"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/devservices"
+	"github.com/agustinrabini/items-api-project/src/main/api/rpc"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/telemetry"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jopitnow/go-jopit-toolkit/gingonic/handlers"
 	"github.com/jopitnow/go-jopit-toolkit/goutils/logger"
 )
 
-func Start() {
-	handler, err := dependencies.BuildDependencies()
+// Start runs the REST and gRPC APIs, and in dev mode the fake downstream
+// APIs, until it receives SIGINT or SIGTERM, then drains the in-flight
+// requests, stops the workers and closes the database. Startup failures are
+// returned instead of panicking.
+func Start() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	router, err := ConfigureRouter()
+	if err != nil {
+		return err
+	}
+
+	shutdownTracing, err := telemetry.Setup(ctx, config.ConfMap.Tracing())
+	if err != nil {
+		return err
+	}
+	defer flushTraces(shutdownTracing)
+
+	manager, err := dependencies.NewDependencyManager()
 	if err != nil {
-		fmt.Printf("Error Build Dependencies")
-		waitAndPanic(err)
+		return fmt.Errorf("error connecting to the database: %w", err)
+	}
+	defer closeDatabase(manager)
+
+	if config.ConfMap.MigrateOnStartup {
+		if err = migrate(ctx, manager); err != nil {
+			return err
+		}
+	}
 
+	var devWorkers []*devservices.Worker
+	if config.ConfMap.DevMode {
+		devWorkers, err = startDevServices()
+		if err != nil {
+			return err
+		}
+		// frees the ports when the startup fails below; after serving the
+		// listeners are already closed
+		defer closeDevServices(devWorkers)
+	}
+
+	handler, err := dependencies.BuildDependencies(manager)
+	if err != nil {
+		return fmt.Errorf("error building dependencies: %w", err)
 	}
 
-	router := ConfigureRouter()
 	RouterMapper(router, handler)
 
-	if errRouter := router.Run(config.ConfMap.APIRestServerPort); errRouter != nil {
-		logger.Errorf("Error starting router", errRouter)
-		waitAndPanic(errRouter)
+	listener, err := net.Listen("tcp", config.ConfMap.APIRestServerPort)
+	if err != nil {
+		return fmt.Errorf("error listening on %s: %w", config.ConfMap.APIRestServerPort, err)
+	}
+
+	grpcListener, err := net.Listen("tcp", config.ConfMap.GRPCServerPort)
+	if err != nil {
+		listener.Close()
+		return fmt.Errorf("error listening on %s: %w", config.ConfMap.GRPCServerPort, err)
 	}
+
+	workers := handler.Workers
+	for _, worker := range devWorkers {
+		workers = append(workers, worker)
+	}
+	workers = append(workers, rpc.NewWorker(rpc.NewServer(handler.Items.Service), grpcListener))
+
+	return Serve(ctx, listener, NewServer(router), config.ConfMap.ServerShutdownTimeout, workers...)
 }
 
-func ConfigureRouter() *gin.Engine {
+// ConfigureRouter reads the client IP from X-Forwarded-For only behind the
+// trusted proxies, as the rate limits and the audit log key on it.
+func ConfigureRouter() (*gin.Engine, error) {
 	logger.InitLog(config.ConfMap.LoggingPath, config.ConfMap.LoggingFile, config.ConfMap.LoggingLevel)
-	return handlers.DefaultJopitRouter()
+	router := handlers.DefaultJopitRouter()
+
+	if err := router.SetTrustedProxies(config.ConfMap.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("error setting the trusted proxies: %w", err)
+	}
 
+	return router, nil
 }
 
-func waitAndPanic(err error) {
-	time.Sleep(2 * time.Second) // needs one second to send the log
-	panic(err)
+// closeDatabase runs after the server is drained, so no request is using the
+// connection anymore. It gets its own deadline because the serve one may be
+// already spent.
+func closeDatabase(manager dependencies.DependencyManager) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.ConfMap.ServerShutdownTimeout)
+	defer cancel()
+
+	if err := manager.Close(ctx); err != nil {
+		logger.Error("Error disconnecting the database", err)
+	}
+}
+
+// flushTraces exports the spans still buffered, including the ones of the
+// requests drained on shutdown.
+func flushTraces(shutdown func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.ConfMap.ServerShutdownTimeout)
+	defer cancel()
+
+	if err := shutdown(ctx); err != nil {
+		logger.Error("Error flushing traces", err)
+	}
 }
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/config"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/devservices"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/logger"
+)
+
+const devServicesAddress = "127.0.0.1:0"
+
+// startDevServices serves the fake prices and shops APIs on free local ports
+// and points their clients to them, so it must run before the dependencies
+// are built. The returned workers serve them until shutdown; Start closes
+// them when it fails before serving.
+func startDevServices() ([]*devservices.Worker, error) {
+	pricesListener, err := net.Listen("tcp", devServicesAddress)
+	if err != nil {
+		return nil, fmt.Errorf("error listening for the dev prices service: %w", err)
+	}
+
+	shopsListener, err := net.Listen("tcp", devServicesAddress)
+	if err != nil {
+		pricesListener.Close()
+		return nil, fmt.Errorf("error listening for the dev shops service: %w", err)
+	}
+
+	prices := devservices.NewWorker("prices", devservices.NewPrices().Handler(), pricesListener)
+	shops := devservices.NewWorker("shops", devservices.NewShops().Handler(), shopsListener)
+
+	config.ConfMap.PricesBaseURL = prices.URL()
+	config.ConfMap.ShopsBaseURL = shops.URL()
+
+	logger.Infof("Dev mode: fake prices on %s and shops on %s, send \"Authorization: %s <user>\"", prices.URL(), shops.URL(), devservices.AuthScheme)
+
+	return []*devservices.Worker{prices, shops}, nil
+}
+
+func closeDevServices(workers []*devservices.Worker) {
+	for _, worker := range workers {
+		worker.Close()
+	}
+}
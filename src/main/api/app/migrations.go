@@ -0,0 +1,23 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/dependencies"
+)
+
+// migrate applies the pending schema migrations before the API serves, so
+// the handlers can count on the indexes and defaults they add.
+func migrate(ctx context.Context, manager dependencies.DependencyManager) error {
+	migrator, err := manager.Migrator()
+	if err != nil {
+		return fmt.Errorf("error loading the migrations: %w", err)
+	}
+
+	if _, err = migrator.Up(ctx, 0); err != nil {
+		return fmt.Errorf("error migrating the database: %w", err)
+	}
+
+	return nil
+}
@@ -1,31 +1,120 @@
 package app
 
 import (
+	"net/http"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/config"
 	"github.com/agustinrabini/items-api-project/src/main/api/dependencies"
+	"github.com/agustinrabini/items-api-project/src/main/api/graph"
 	"github.com/agustinrabini/items-api-project/src/main/api/handlers"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/problems"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/ratelimit"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/metrics"
 	"github.com/gin-gonic/gin"
 	"github.com/jopitnow/go-jopit-toolkit/goauth"
 )
 
 func RouterMapper(router *gin.Engine, h dependencies.HandlersStruct) {
 	// Health
-	health := handlers.NewHealthCheckerHandler()
-	router.GET("/ping", health.Ping)
-
-	// Items
-	router.GET("/items", handlers.LoggerHandler("GetItemsByUserID"), goauth.AuthWithFirebase(), h.Items.GetItemsByUserID)
-	router.GET("/items/:id", handlers.LoggerHandler("GetItemByID"), h.Items.GetItemByID)
-	router.GET("/items/shop/:id", handlers.LoggerHandler("GetItemsByShopID"), h.Items.GetItemsByShopID)
-	router.GET("/items/shop/:id/category/:category_id", handlers.LoggerHandler("GetItemsByShopCategoryID"), h.Items.GetItemsByShopCategoryID)
-	router.POST("/items/list", handlers.LoggerHandler("GetItemsByIDs"), h.Items.GetItemsByIDs)
-	router.POST("/items", handlers.LoggerHandler("CreateItem"), goauth.AuthWithFirebase(), h.Items.CreateItem)
-	router.PUT("/items/:id", handlers.LoggerHandler("UpdateItem"), goauth.AuthWithFirebase(), h.Items.UpdateItem)
-	router.DELETE("/items/:id", handlers.LoggerHandler("DeleteItem"), goauth.AuthWithFirebase(), h.Items.DeleteItem)
-
-	//Categories
-	router.GET("/items/category/:id_category", handlers.LoggerHandler("GetCategory"), h.Categories.Get)
-	router.GET("/items/categories", handlers.LoggerHandler("GetAllCategories"), h.Categories.GetAllCategories)
-	router.POST("/items/category", goauth.PasswordMiddleware(), handlers.LoggerHandler("CreateCategory"), h.Categories.Create)
-	router.PUT("/items/category", goauth.PasswordMiddleware(), handlers.LoggerHandler("UpdateCategory"), h.Categories.Update)
-	router.DELETE("/items/category/:id_category", goauth.PasswordMiddleware(), handlers.LoggerHandler("DeleteCategory"), h.Categories.Delete)
+	router.GET("/ping", h.Health.Ping)
+	router.GET("/health/live", h.Health.Live)
+	router.GET("/health/ready", h.Health.Ready)
+
+	// Metrics
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Error catalog, the problem types point here
+	router.GET("/errors", handlers.ErrorCatalog)
+	router.GET("/errors/:code", handlers.ErrorType)
+
+	// Rate limits, per route and caller
+	readLimit := handlers.RateLimitHandler(h.RateLimiter, ratelimit.Limit{Rate: config.ConfMap.RateLimitReadRate, Burst: config.ConfMap.RateLimitReadBurst})
+	writeLimit := handlers.RateLimitHandler(h.RateLimiter, ratelimit.Limit{Rate: config.ConfMap.RateLimitWriteRate, Burst: config.ConfMap.RateLimitWriteBurst})
+
+	// Idempotency-Key support for the POSTs that create resources
+	idempotent := handlers.IdempotencyHandler(h.Idempotency)
+
+	// Firebase auth, or the dev identity header in dev mode
+	auth := authHandler()
+
+	// GraphQL, reads are public and mutations need a Firebase token, and go
+	// through the write limit and Idempotency-Key like the REST writes
+	limits := handlers.OperationHandler(graph.IsMutation, []gin.HandlerFunc{readLimit}, []gin.HandlerFunc{writeLimit, idempotent})
+	graphQL := []gin.HandlerFunc{handlers.LoggerHandler("GraphQL"), handlers.OptionalAuthHandler(auth), limits, h.GraphQL.Serve}
+	router.GET("/graphql", graphQL...)
+	router.POST("/graphql", graphQL...)
+
+	// Admin, behind the same password as the categories
+	password := problems.Wrap(goauth.PasswordMiddleware())
+	audit := auditor(h)
+	router.GET("/admin/reconciliation", handlers.LoggerHandler("GetReconciliationReport"), password, audit("reconciliation.read", handlers.AuditTarget{Type: "reconciliation"}), h.Reconciliation.LastReport)
+	router.GET("/admin/audit", handlers.LoggerHandler("SearchAudit"), password, audit("audit.read", handlers.AuditTarget{Type: "audit"}), h.Audit.SearchAudit)
+
+	// Items, collections and categories, mounted once per API version
+	v1 := itemsRoutes(h, auth, password, readLimit, writeLimit, idempotent)
+	// v2 answers errors as problem+json and pages the items of a shop
+	v2 := v1.Override(
+		NewRoute(http.MethodGet, "/items/shop/:id", "GetItemsByShopID", readLimit, h.Items.GetItemsPageByShopID),
+	)
+
+	MountVersions(router, v1, v2)
+}
+
+// auditor builds the audit middleware of the routes, see
+// handlers.AuditHandler.
+func auditor(h dependencies.HandlersStruct) func(action string, target handlers.AuditTarget) gin.HandlerFunc {
+	return func(action string, target handlers.AuditTarget) gin.HandlerFunc {
+		return handlers.AuditHandler(h.AuditLog, action, target)
+	}
+}
+
+func authHandler() gin.HandlerFunc {
+	if config.ConfMap.DevMode {
+		return handlers.DevAuthHandler()
+	}
+	return problems.Wrap(goauth.AuthWithFirebase())
+}
+
+// itemsRoutes is the route table of v1. The later versions override it.
+func itemsRoutes(h dependencies.HandlersStruct, auth gin.HandlerFunc, password gin.HandlerFunc, readLimit gin.HandlerFunc, writeLimit gin.HandlerFunc, idempotent gin.HandlerFunc) Routes {
+	audit := auditor(h)
+	items, collections, categories := h.AuditTargets.Items, h.AuditTargets.Collections, h.AuditTargets.Categories
+
+	return Routes{
+		// Items
+		NewRoute(http.MethodGet, "/items", "GetItemsByUserID", auth, readLimit, h.Items.GetItemsByUserID),
+		NewRoute(http.MethodGet, "/items/:id", "GetItemByID", readLimit, h.Items.GetItemByID),
+		NewRoute(http.MethodGet, "/items/by-slug/:slug", "GetItemBySlug", readLimit, h.Items.GetItemBySlug),
+		NewRoute(http.MethodGet, "/items/shop/:id", "GetItemsByShopID", readLimit, h.Items.GetItemsByShopID),
+		NewRoute(http.MethodGet, "/items/shop/:id/category/:category_id", "GetItemsByShopCategoryID", readLimit, h.Items.GetItemsByShopCategoryID),
+		NewRoute(http.MethodGet, "/items/shop/:id/categories", "GetShopCategories", readLimit, h.Categories.GetShopCategories),
+		NewRoute(http.MethodPost, "/items/list", "GetItemsByIDs", readLimit, h.Items.GetItemsByIDs),
+		NewRoute(http.MethodGet, "/items/search", "SearchItems", readLimit, h.Items.SearchItems),
+		NewRoute(http.MethodPost, "/items", "CreateItem", auth, writeLimit, idempotent, audit("item.create", items), h.Items.CreateItem),
+		NewRoute(http.MethodPut, "/items/:id", "UpdateItem", auth, writeLimit, audit("item.update", items), h.Items.UpdateItem),
+		NewRoute(http.MethodDelete, "/items/:id", "DeleteItem", auth, writeLimit, audit("item.delete", items), h.Items.DeleteItem),
+		NewRoute(http.MethodPut, "/items/:id/translations/:locale", "SetItemTranslation", auth, writeLimit, audit("item.translation.set", items), h.Items.SetItemTranslation),
+		NewRoute(http.MethodDelete, "/items/:id/translations/:locale", "DeleteItemTranslation", auth, writeLimit, audit("item.translation.delete", items), h.Items.DeleteItemTranslation),
+		NewRoute(http.MethodGet, "/items/:id/revisions", "ListItemRevisions", auth, readLimit, h.Revisions.ListRevisions),
+		NewRoute(http.MethodGet, "/items/:id/revisions/:rev", "GetItemRevision", auth, readLimit, h.Revisions.GetRevision),
+		NewRoute(http.MethodPost, "/items/:id/revisions/:rev/revert", "RevertItemRevision", auth, writeLimit, audit("item.revert", items), h.Revisions.RevertRevision),
+
+		// Collections
+		NewRoute(http.MethodGet, "/items/shop/:id/collections", "GetShopCollections", readLimit, h.Collections.GetShopCollections),
+		NewRoute(http.MethodGet, "/items/collections/:id", "GetCollection", readLimit, h.Collections.GetCollection),
+		NewRoute(http.MethodPost, "/items/collections", "CreateCollection", auth, writeLimit, idempotent, audit("collection.create", collections), h.Collections.CreateCollection),
+		NewRoute(http.MethodPut, "/items/collections/:id", "UpdateCollection", auth, writeLimit, audit("collection.update", collections), h.Collections.UpdateCollection),
+		NewRoute(http.MethodPut, "/items/collections/:id/items", "SetCollectionItems", auth, writeLimit, audit("collection.items.set", collections), h.Collections.SetCollectionItems),
+		NewRoute(http.MethodDelete, "/items/collections/:id", "DeleteCollection", auth, writeLimit, audit("collection.delete", collections), h.Collections.DeleteCollection),
+
+		//Categories
+		NewRoute(http.MethodGet, "/items/category/:id_category", "GetCategory", readLimit, h.Categories.Get),
+		NewRoute(http.MethodGet, "/items/category/by-slug/:slug", "GetCategoryBySlug", readLimit, h.Categories.GetBySlug),
+		NewRoute(http.MethodGet, "/items/categories", "GetAllCategories", readLimit, h.Categories.GetAllCategories),
+		NewRoute(http.MethodPost, "/items/category", "CreateCategory", password, idempotent, audit("category.create", categories), h.Categories.Create),
+		NewRoute(http.MethodPut, "/items/category", "UpdateCategory", password, audit("category.update", categories.With(handlers.AuditBody)), h.Categories.Update),
+		NewRoute(http.MethodDelete, "/items/category/:id_category", "DeleteCategory", password, audit("category.delete", categories), h.Categories.Delete),
+		NewRoute(http.MethodPut, "/items/category/:id_category/translations/:locale", "SetCategoryTranslation", password, audit("category.translation.set", categories), h.Categories.SetTranslation),
+		NewRoute(http.MethodDelete, "/items/category/:id_category/translations/:locale", "DeleteCategoryTranslation", password, audit("category.translation.delete", categories), h.Categories.DeleteTranslation),
+	}
 }
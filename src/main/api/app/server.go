@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/config"
+	"github.com/agustinrabini/items-api-project/src/main/api/dependencies"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/logger"
+)
+
+// NewServer wraps the router in an http.Server with the configured timeouts,
+// so slow clients can't hold connections forever.
+func NewServer(handler http.Handler) *http.Server {
+	return &http.Server{
+		Handler:      handler,
+		ReadTimeout:  config.ConfMap.ServerReadTimeout,
+		WriteTimeout: config.ConfMap.ServerWriteTimeout,
+		IdleTimeout:  config.ConfMap.ServerIdleTimeout,
+	}
+}
+
+// Serve runs the server and the workers until ctx is done or the server fails.
+// On the way out it stops accepting connections, gives the in-flight requests
+// and the workers until shutdownTimeout to finish, and only then returns.
+func Serve(ctx context.Context, listener net.Listener, server *http.Server, shutdownTimeout time.Duration, workers ...dependencies.Worker) error {
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	var wg sync.WaitGroup
+	for _, worker := range workers {
+		wg.Add(1)
+		go func(worker dependencies.Worker) {
+			defer wg.Done()
+			worker.Run(workersCtx)
+		}(worker)
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(listener)
+	}()
+
+	var errs []error
+
+	select {
+	case err := <-served:
+		errs = append(errs, fmt.Errorf("error serving: %w", err))
+	case <-ctx.Done():
+		logger.Info("Shutting down, draining in-flight requests")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		// the deadline passed, drop whatever is still running
+		_ = server.Close()
+		errs = append(errs, fmt.Errorf("error draining requests: %w", err))
+	}
+
+	stopWorkers()
+	if err := wait(shutdownCtx, &wg); err != nil {
+		errs = append(errs, fmt.Errorf("error stopping workers: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func wait(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
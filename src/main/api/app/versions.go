@@ -0,0 +1,91 @@
+package app
+
+import (
+	"github.com/agustinrabini/items-api-project/src/main/api/config"
+	"github.com/agustinrabini/items-api-project/src/main/api/handlers"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/problems"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Versions of the API. The unversioned routes serve v1 until their sunset.
+const (
+	APIv1 = "v1"
+	APIv2 = "v2"
+)
+
+// Route is an endpoint of the versioned API. Handlers holds the middlewares
+// and the handler that follow LoggerHandler, which is added with Name.
+type Route struct {
+	Method   string
+	Path     string
+	Name     string
+	Handlers []gin.HandlerFunc
+}
+
+// Routes is the route table of a version. A new version starts from the
+// previous table and only overrides the routes whose contract changes, so
+// everything else keeps sharing the same handlers.
+type Routes []Route
+
+func NewRoute(method string, path string, name string, handlers ...gin.HandlerFunc) Route {
+	return Route{
+		Method:   method,
+		Path:     path,
+		Name:     name,
+		Handlers: handlers,
+	}
+}
+
+// Override returns a copy of routes where the overrides replace the routes
+// with their method and path, and are appended when there is none.
+func (routes Routes) Override(overrides ...Route) Routes {
+	result := append(Routes{}, routes...)
+
+	for _, override := range overrides {
+		replaced := false
+		for i, route := range result {
+			if route.Method == override.Method && route.Path == override.Path {
+				result[i] = override
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			result = append(result, override)
+		}
+	}
+
+	return result
+}
+
+// Without returns a copy of routes without the given one, for endpoints a
+// version drops.
+func (routes Routes) Without(method string, path string) Routes {
+	result := make(Routes, 0, len(routes))
+	for _, route := range routes {
+		if route.Method != method || route.Path != path {
+			result = append(result, route)
+		}
+	}
+
+	return result
+}
+
+// MountVersions serves v1 under /v1 and v2 under /v2, where errors are always
+// problem+json. The v1 table is also served on the bare paths for the clients
+// that predate the versions, with the Deprecation and Sunset headers.
+func MountVersions(router gin.IRouter, v1 Routes, v2 Routes) {
+	mount(router.Group("/"+APIv1), v1)
+	mount(router.Group("/"+APIv2, problems.Enforce()), v2)
+
+	deprecatedAt, sunset := config.ConfMap.LegacyRoutesDeprecation()
+	mount(router.Group("", handlers.DeprecationHandler(deprecatedAt, sunset, "/"+APIv1)), v1)
+}
+
+func mount(group *gin.RouterGroup, routes Routes) {
+	for _, route := range routes {
+		chain := append([]gin.HandlerFunc{handlers.LoggerHandler(route.Name)}, route.Handlers...)
+		group.Handle(route.Method, route.Path, chain...)
+	}
+}
@@ -1,22 +1,41 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/pkg/telemetry"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/mitchellh/mapstructure"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
+// ConfigFileEnv points to an optional YAML, TOML or JSON file. Environment
+// variables always win over the values in the file.
+const ConfigFileEnv = "CONFIG_FILE"
+
+const redacted = "******"
+
+// DateLayout is the format of the date keys, like legacy_routes_sunset.
+const DateLayout = "2006-01-02"
+
+// Span exporters accepted by tracing_exporter.
 const (
-	LogRatio     = 100
-	LogBodyRatio = 100
+	TracingExporterNone   = telemetry.ExporterNone
+	TracingExporterStdout = telemetry.ExporterStdout
+	TracingExporterOTLP   = telemetry.ExporterOTLP
 )
 
-var (
-	InternalBasePricesClient = "http://prices:8080"
-	InternalBaseShopsClient  = "http://shops:8080"
-)
+// EnvironmentProduction is the environment where dev_mode is refused.
+const EnvironmentProduction = "production"
 
 // Configuration structure
 type Configuration struct {
@@ -25,46 +44,410 @@ type Configuration struct {
 	APIRestUsername   string `mapstructure:"api_username"`
 	APIRestPassword   string `mapstructure:"api_password"`
 	APIBaseEndpoint   string `mapstructure:"api_base_endpoint"`
+	GRPCServerPort    string `mapstructure:"grpc_port"`
+	Environment       string `mapstructure:"environment"`
+	DevMode           bool   `mapstructure:"dev_mode"`
+	MigrateOnStartup  bool   `mapstructure:"migrate_on_startup"`
 	LoggingPath       string `mapstructure:"api_logpath"`
 	LoggingFile       string `mapstructure:"api_logfile"`
 	LoggingLevel      string `mapstructure:"api_loglevel"`
+	LogRatio          int    `mapstructure:"log_ratio"`
+	LogBodyRatio      int    `mapstructure:"log_body_ratio"`
 	MongoUser         string `mapstructure:"MONGO_USERNAME"`
 	MongoPassword     string `mapstructure:"MONGO_PASSWORD"`
 	MongoHost         string `mapstructure:"MONGO_HOST"`
 	MongoDataBase     string `mapstructure:"MONGO_DATABASE"`
+
+	ServerReadTimeout     time.Duration `mapstructure:"api_read_timeout"`
+	ServerWriteTimeout    time.Duration `mapstructure:"api_write_timeout"`
+	ServerIdleTimeout     time.Duration `mapstructure:"api_idle_timeout"`
+	ServerShutdownTimeout time.Duration `mapstructure:"api_shutdown_timeout"`
+
+	// TrustedProxies are the IPs or CIDRs whose X-Forwarded-For the client IP
+	// is read from; the client IP is the peer address when empty.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+
+	HealthTimeout          time.Duration `mapstructure:"health_timeout"`
+	HealthCacheTTL         time.Duration `mapstructure:"health_cache_ttl"`
+	HealthProbeDownstreams bool          `mapstructure:"health_probe_downstreams"`
+
+	TracingExporter     string  `mapstructure:"tracing_exporter"`
+	TracingServiceName  string  `mapstructure:"tracing_service_name"`
+	TracingOTLPEndpoint string  `mapstructure:"tracing_otlp_endpoint"`
+	TracingOTLPInsecure bool    `mapstructure:"tracing_otlp_insecure"`
+	TracingSampleRatio  float64 `mapstructure:"tracing_sample_ratio"`
+
+	RateLimitEnabled    bool    `mapstructure:"rate_limit_enabled"`
+	RateLimitReadRate   float64 `mapstructure:"rate_limit_read_rate"`
+	RateLimitReadBurst  int     `mapstructure:"rate_limit_read_burst"`
+	RateLimitWriteRate  float64 `mapstructure:"rate_limit_write_rate"`
+	RateLimitWriteBurst int     `mapstructure:"rate_limit_write_burst"`
+
+	IdempotencyTTL time.Duration `mapstructure:"idempotency_ttl"`
+
+	ReconciliationInterval  time.Duration `mapstructure:"reconciliation_interval"`
+	ReconciliationRepair    bool          `mapstructure:"reconciliation_repair"`
+	ReconciliationChunkSize int           `mapstructure:"reconciliation_chunk_size"`
+
+	GraphQLMaxDepth      int           `mapstructure:"graphql_max_depth"`
+	GraphQLMaxComplexity int           `mapstructure:"graphql_max_complexity"`
+	GraphQLBatchWait     time.Duration `mapstructure:"graphql_batch_wait"`
+
+	LegacyRoutesDeprecatedAt string `mapstructure:"legacy_routes_deprecated_at"`
+	LegacyRoutesSunset       string `mapstructure:"legacy_routes_sunset"`
+
+	PricesBaseURL      string        `mapstructure:"prices_base_url"`
+	PricesTimeout      time.Duration `mapstructure:"prices_timeout"`
+	PricesMaxIdleConns int           `mapstructure:"prices_max_idle_conns"`
+	ShopsBaseURL       string        `mapstructure:"shops_base_url"`
+	ShopsTimeout       time.Duration `mapstructure:"shops_timeout"`
+	ShopsMaxIdleConns  int           `mapstructure:"shops_max_idle_conns"`
+}
+
+// ConfMap Config is package struct containing conf params. It starts with the
+// defaults so packages used without Load, like the tests, get working values.
+var ConfMap = Default()
+
+func Default() Configuration {
+	return Configuration{
+		// API
+		APIRestServerHost: "127.0.0.1",
+		APIRestServerPort: ":8080",
+		APIRestUsername:   "agus",
+		APIRestPassword:   "changeme",
+		APIBaseEndpoint:   "http://nginx",
+		GRPCServerPort:    ":9090",
+		Environment:       "development",
+		DevMode:           false,
+		MigrateOnStartup:  true,
+
+		// Server
+		ServerReadTimeout:     15 * time.Second,
+		ServerWriteTimeout:    30 * time.Second,
+		ServerIdleTimeout:     60 * time.Second,
+		ServerShutdownTimeout: 20 * time.Second,
+
+		// Health
+		HealthTimeout:          2 * time.Second,
+		HealthCacheTTL:         5 * time.Second,
+		HealthProbeDownstreams: false,
+
+		// Tracing
+		TracingExporter:     TracingExporterNone,
+		TracingServiceName:  "items-api",
+		TracingOTLPEndpoint: "localhost:4318",
+		TracingOTLPInsecure: true,
+		TracingSampleRatio:  1,
+
+		// Rate limits, in requests per second per caller and route
+		RateLimitEnabled:    true,
+		RateLimitReadRate:   10,
+		RateLimitReadBurst:  20,
+		RateLimitWriteRate:  1,
+		RateLimitWriteBurst: 5,
+
+		// Idempotency keys
+		IdempotencyTTL: 24 * time.Hour,
+
+		// Price reconciliation, off unless an interval is set
+		ReconciliationInterval:  0,
+		ReconciliationRepair:    false,
+		ReconciliationChunkSize: 100,
+
+		// GraphQL
+		GraphQLMaxDepth:      8,
+		GraphQLMaxComplexity: 1000,
+		GraphQLBatchWait:     2 * time.Millisecond,
+
+		// Unversioned routes, kept as an alias of /v1 until the sunset
+		LegacyRoutesDeprecatedAt: "2026-11-01",
+		LegacyRoutesSunset:       "2027-05-01",
+
+		// LOG
+		LoggingPath:  "/var/log",
+		LoggingFile:  "api.log",
+		LoggingLevel: "trace",
+		LogRatio:     100,
+		LogBodyRatio: 100,
+
+		// Downstream APIs
+		PricesBaseURL:      "http://prices:8080",
+		PricesTimeout:      5 * time.Second,
+		PricesMaxIdleConns: 100,
+		ShopsBaseURL:       "http://shops:8080",
+		ShopsTimeout:       5 * time.Second,
+		ShopsMaxIdleConns:  100,
+	}
+}
+
+// Load reads the configuration from the defaults, the optional config file and
+// the environment, in increasing order of precedence, and validates it.
+func Load() error {
+	v := viper.New()
+
+	defaults := map[string]interface{}{}
+	if err := mapstructure.Decode(Default(), &defaults); err != nil { // coverage-ignore
+		return fmt.Errorf("unable to read the default config: %w", err)
+	}
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+
+	if file := os.Getenv(ConfigFileEnv); file != "" {
+		v.SetConfigFile(file)
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("unable to read config file %s: %w", file, err)
+		}
+	}
+
+	v.AutomaticEnv()
+
+	var conf Configuration
+	if err := v.Unmarshal(&conf); err != nil {
+		return fmt.Errorf("unable to decode the config: %w", err)
+	}
+
+	if err := conf.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	ConfMap = conf
+
+	return nil
+}
+
+// Dump prints the loaded configuration with its secrets redacted.
+func Dump(w io.Writer) {
+	spew.Fdump(w, ConfMap.Redacted())
+}
+
+// Validate reports every invalid value at once, naming the key to fix.
+func (c Configuration) Validate() error {
+	var errs []error
+
+	if c.APIRestServerPort == "" || !strings.Contains(c.APIRestServerPort, ":") {
+		errs = append(errs, fmt.Errorf("api_port must look like \":8080\", got %q", c.APIRestServerPort))
+	}
+
+	if c.GRPCServerPort == "" || !strings.Contains(c.GRPCServerPort, ":") {
+		errs = append(errs, fmt.Errorf("grpc_port must look like \":9090\", got %q", c.GRPCServerPort))
+	} else if c.GRPCServerPort == c.APIRestServerPort {
+		errs = append(errs, errors.New("grpc_port must differ from api_port"))
+	}
+
+	if c.DevMode {
+		errs = append(errs, validateDevMode(c.Environment, c.APIRestServerPort)...)
+	}
+
+	errs = append(errs, validatePositive("api_read_timeout", c.ServerReadTimeout)...)
+	errs = append(errs, validatePositive("api_write_timeout", c.ServerWriteTimeout)...)
+	errs = append(errs, validatePositive("api_idle_timeout", c.ServerIdleTimeout)...)
+	errs = append(errs, validatePositive("api_shutdown_timeout", c.ServerShutdownTimeout)...)
+
+	errs = append(errs, validateTrustedProxies(c.TrustedProxies)...)
+
+	errs = append(errs, validatePositive("health_timeout", c.HealthTimeout)...)
+
+	if c.HealthCacheTTL < 0 {
+		errs = append(errs, fmt.Errorf("health_cache_ttl can't be negative, got %s", c.HealthCacheTTL))
+	}
+
+	switch c.TracingExporter {
+	case TracingExporterNone, TracingExporterStdout:
+	case TracingExporterOTLP:
+		if c.TracingOTLPEndpoint == "" {
+			errs = append(errs, errors.New("tracing_otlp_endpoint is required with the otlp exporter"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("tracing_exporter must be none, stdout or otlp, got %q", c.TracingExporter))
+	}
+
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing_sample_ratio must be between 0 and 1, got %v", c.TracingSampleRatio))
+	}
+
+	if c.IdempotencyTTL < time.Second {
+		errs = append(errs, fmt.Errorf("idempotency_ttl must be at least 1s, got %s", c.IdempotencyTTL))
+	}
+
+	if c.ReconciliationInterval < 0 {
+		errs = append(errs, fmt.Errorf("reconciliation_interval can't be negative, got %s", c.ReconciliationInterval))
+	}
+	if c.ReconciliationChunkSize < 1 {
+		errs = append(errs, fmt.Errorf("reconciliation_chunk_size must be at least 1, got %d", c.ReconciliationChunkSize))
+	}
+
+	if c.GraphQLMaxDepth < 1 {
+		errs = append(errs, fmt.Errorf("graphql_max_depth must be at least 1, got %d", c.GraphQLMaxDepth))
+	}
+	if c.GraphQLMaxComplexity < 1 {
+		errs = append(errs, fmt.Errorf("graphql_max_complexity must be at least 1, got %d", c.GraphQLMaxComplexity))
+	}
+	errs = append(errs, validatePositive("graphql_batch_wait", c.GraphQLBatchWait)...)
+
+	errs = append(errs, validateLegacyRoutes(c.LegacyRoutesDeprecatedAt, c.LegacyRoutesSunset)...)
+
+	if c.RateLimitEnabled {
+		errs = append(errs, validateRateLimit("read", c.RateLimitReadRate, c.RateLimitReadBurst)...)
+		errs = append(errs, validateRateLimit("write", c.RateLimitWriteRate, c.RateLimitWriteBurst)...)
+	}
+
+	if _, err := log.ParseLevel(c.LoggingLevel); err != nil {
+		errs = append(errs, fmt.Errorf("api_loglevel: %w", err))
+	}
+
+	if c.LogRatio < 0 || c.LogRatio > 100 {
+		errs = append(errs, fmt.Errorf("log_ratio must be between 0 and 100, got %d", c.LogRatio))
+	}
+
+	if c.LogBodyRatio < 0 || c.LogBodyRatio > 100 {
+		errs = append(errs, fmt.Errorf("log_body_ratio must be between 0 and 100, got %d", c.LogBodyRatio))
+	}
+
+	if c.MongoHost == "" {
+		errs = append(errs, errors.New("MONGO_HOST is required"))
+	}
+
+	if c.MongoDataBase == "" {
+		errs = append(errs, errors.New("MONGO_DATABASE is required"))
+	}
+
+	errs = append(errs, validateDownstream("prices", c.PricesBaseURL, c.PricesTimeout, c.PricesMaxIdleConns)...)
+	errs = append(errs, validateDownstream("shops", c.ShopsBaseURL, c.ShopsTimeout, c.ShopsMaxIdleConns)...)
+
+	return errors.Join(errs...)
+}
+
+// Tracing is how the spans are exported, for telemetry.Setup.
+func (c Configuration) Tracing() telemetry.Config {
+	return telemetry.Config{
+		Exporter:     c.TracingExporter,
+		ServiceName:  c.TracingServiceName,
+		OTLPEndpoint: c.TracingOTLPEndpoint,
+		OTLPInsecure: c.TracingOTLPInsecure,
+		SampleRatio:  c.TracingSampleRatio,
+	}
+}
+
+// LegacyRoutesDeprecation returns when the unversioned routes were deprecated
+// and when they will be removed. Validate ensures both dates parse.
+func (c Configuration) LegacyRoutesDeprecation() (deprecatedAt time.Time, sunset time.Time) {
+	deprecatedAt, _ = time.Parse(DateLayout, c.LegacyRoutesDeprecatedAt)
+	sunset, _ = time.Parse(DateLayout, c.LegacyRoutesSunset)
+
+	return deprecatedAt, sunset
+}
+
+// Redacted returns a copy safe to print, with passwords masked.
+func (c Configuration) Redacted() Configuration {
+	if c.APIRestPassword != "" {
+		c.APIRestPassword = redacted
+	}
+
+	if c.MongoPassword != "" {
+		c.MongoPassword = redacted
+	}
+
+	// hosts may come as full connection strings with credentials
+	if u, err := url.Parse(c.MongoHost); err == nil && u.User != nil {
+		c.MongoHost = u.Redacted()
+	}
+
+	return c
 }
 
-// ConfMap Config is package struct containing conf params
-var ConfMap Configuration
+// validateDevMode keeps the dev Authorization header, which lets anyone act
+// as any user, to a local process: the API must listen on loopback only.
+func validateDevMode(environment string, address string) []error {
+	var errs []error
 
-func Load() {
-	// Setting defaults if the config not read
-	// API
-	viper.SetDefault("api_host", "127.0.0.1")
-	viper.SetDefault("api_port", ":8080")
-	viper.SetDefault("api_username", "agus")
-	viper.SetDefault("api_password", "changeme")
-	viper.SetDefault("api_base_endpoint", "http://nginx")
+	if strings.EqualFold(environment, EnvironmentProduction) {
+		errs = append(errs, errors.New("dev_mode can't be enabled in the production environment"))
+	}
 
-	// LOG
-	viper.SetDefault("api_logpath", "/var/log")
-	viper.SetDefault("api_logfile", "api.log")
-	viper.SetDefault("api_loglevel", "trace")
+	if host, _, err := net.SplitHostPort(address); err == nil && !isLoopback(host) {
+		errs = append(errs, fmt.Errorf("dev_mode requires api_port to listen on loopback, like \"127.0.0.1:8080\", got %q", address))
+	}
 
-	viper.SetDefault("MONGO_USERNAME", "")
-	viper.SetDefault("MONGO_PASSWORD", "")
-	viper.SetDefault("MONGO_HOST", "")
-	viper.SetDefault("MONGO_DATABASE", "")
+	return errs
+}
 
-	// Read the config file
-	viper.AutomaticEnv()
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
 
-	err := viper.Unmarshal(&ConfMap)
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func validateTrustedProxies(proxies []string) []error {
+	var errs []error
+	for _, proxy := range proxies {
+		if net.ParseIP(proxy) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(proxy); err != nil {
+			errs = append(errs, fmt.Errorf("trusted_proxies must be IPs or CIDRs, got %q", proxy))
+		}
+	}
+
+	return errs
+}
+
+func validateDownstream(name string, baseURL string, timeout time.Duration, maxIdleConns int) []error {
+	var errs []error
+
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("%s_base_url must be an absolute http(s) URL, got %q", name, baseURL))
+	}
+
+	errs = append(errs, validatePositive(name+"_timeout", timeout)...)
+
+	if maxIdleConns <= 0 {
+		errs = append(errs, fmt.Errorf("%s_max_idle_conns must be positive, got %d", name, maxIdleConns))
+	}
+
+	return errs
+}
+
+func validateRateLimit(name string, rate float64, burst int) []error {
+	var errs []error
+
+	if rate <= 0 {
+		errs = append(errs, fmt.Errorf("rate_limit_%s_rate must be positive, got %v", name, rate))
+	}
+
+	if burst < 1 {
+		errs = append(errs, fmt.Errorf("rate_limit_%s_burst must be at least 1, got %d", name, burst))
+	}
+
+	return errs
+}
+
+func validateLegacyRoutes(deprecatedAt string, sunset string) []error {
+	deprecation, err := time.Parse(DateLayout, deprecatedAt)
 	if err != nil {
-		log.Fatalf("Unable to decode into struct, %+v", err)
+		return []error{fmt.Errorf("legacy_routes_deprecated_at must be a date like 2006-01-02, got %q", deprecatedAt)}
 	}
 
-	spew.Dump(ConfMap)
+	removal, err := time.Parse(DateLayout, sunset)
+	if err != nil {
+		return []error{fmt.Errorf("legacy_routes_sunset must be a date like 2006-01-02, got %q", sunset)}
+	}
+
+	if !removal.After(deprecation) {
+		return []error{fmt.Errorf("legacy_routes_sunset must be after legacy_routes_deprecated_at, got %s", sunset)}
+	}
+
+	return nil
+}
+
+func validatePositive(key string, value time.Duration) []error {
+	if value <= 0 {
+		return []error{fmt.Errorf("%s must be positive, got %s", key, value)}
+	}
 
-	fmt.Println("\n All good!!")
+	return nil
 }
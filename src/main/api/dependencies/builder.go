@@ -1,6 +1,14 @@
 package dependencies
 
 import (
+	"context"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/config"
+	"github.com/agustinrabini/items-api-project/src/main/api/graph"
+	apihandlers "github.com/agustinrabini/items-api-project/src/main/api/handlers"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/ratelimit"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/scheduler"
 	"github.com/agustinrabini/items-api-project/src/main/domain/clients"
 	"github.com/agustinrabini/items-api-project/src/main/domain/handlers"
 	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
@@ -10,38 +18,133 @@ import (
 type Dependencies interface {
 	ItemsRepository() repositories.ItemsRepository
 	CategoriesRepository() repositories.CategoriesRepository
+	CollectionsRepository() repositories.CollectionsRepository
+	IdempotencyRepository() repositories.IdempotencyRepository
+	ReconciliationRepository() repositories.ReconciliationRepository
+	RevisionsRepository() repositories.RevisionsRepository
+	AuditRepository() repositories.AuditRepository
+	Ping(ctx context.Context) error
 }
 
-func GetDependencyManager() Dependencies {
-	return NewDependencyManager()
-}
+const indexesTimeout = 30 * time.Second
 
-func BuildDependencies() (HandlersStruct, error) {
-	manager := GetDependencyManager()
+// Worker is a background process started next to the HTTP server. It must
+// return once ctx is cancelled.
+type Worker interface {
+	Run(ctx context.Context)
+}
 
+func BuildDependencies(manager Dependencies) (HandlersStruct, error) {
 	// ItemsRepository
 	itemsRepository := manager.ItemsRepository()
 	categoriesRepository := manager.CategoriesRepository()
+	collectionsRepository := manager.CollectionsRepository()
+	idempotencyRepository := manager.IdempotencyRepository()
+	reconciliationRepository := manager.ReconciliationRepository()
+	revisionsRepository := manager.RevisionsRepository()
+	auditRepository := manager.AuditRepository()
+
+	indexesCtx, cancel := context.WithTimeout(context.Background(), indexesTimeout)
+	defer cancel()
+	if err := idempotencyRepository.EnsureIndexes(indexesCtx, config.ConfMap.IdempotencyTTL); err != nil {
+		return HandlersStruct{}, err
+	}
 
 	// External Clients
-	pricesClient := clients.NewPriceClient()
+	pricesClient := repositories.NewRevisionedPriceClient(clients.NewPriceClient(), itemsRepository, revisionsRepository)
 	shopsClient := clients.NewShopClient()
 
 	// Services
 	itemsService := services.NewItemsService(itemsRepository, pricesClient, shopsClient)
 	categoriesService := services.NewCategoriesService(categoriesRepository)
+	collectionsService := services.NewCollectionsService(collectionsRepository, itemsRepository, pricesClient, shopsClient)
+	reconciliationService := services.NewReconciliationService(itemsRepository, pricesClient, reconciliationRepository, config.ConfMap.ReconciliationChunkSize)
+	revisionsService := services.NewRevisionsService(itemsRepository, revisionsRepository, categoriesRepository, itemsService, pricesClient)
+	auditService := services.NewAuditService(auditRepository)
 
 	// Handlers
 	itemsHandler := handlers.NewItemsHandler(itemsService, categoriesService)
 	categoriesHandler := handlers.NewCategoriesHandler(categoriesService, itemsService)
+	collectionsHandler := handlers.NewCollectionsHandler(collectionsService)
+	reconciliationHandler := handlers.NewReconciliationHandler(reconciliationService)
+	revisionsHandler := handlers.NewRevisionsHandler(revisionsService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+
+	// GraphQL
+	graphQLHandler := graph.NewHandler(itemsRepository, pricesClient, itemsService, categoriesService, auditRepository, graph.Limits{
+		MaxDepth:      config.ConfMap.GraphQLMaxDepth,
+		MaxComplexity: config.ConfMap.GraphQLMaxComplexity,
+		BatchWait:     config.ConfMap.GraphQLBatchWait,
+	})
+
+	// Health
+	checks := []apihandlers.HealthCheck{{Name: "mongo", Required: true, Probe: manager.Ping}}
+	if config.ConfMap.HealthProbeDownstreams {
+		checks = append(checks,
+			apihandlers.HealthCheck{Name: "prices", Probe: apihandlers.URLProbe(config.ConfMap.PricesBaseURL)},
+			apihandlers.HealthCheck{Name: "shops", Probe: apihandlers.URLProbe(config.ConfMap.ShopsBaseURL)},
+		)
+	}
+	healthHandler := apihandlers.NewHealthCheckerHandler(config.ConfMap.HealthTimeout, config.ConfMap.HealthCacheTTL, checks...)
+
+	// Rate limits
+	var rateLimiter ratelimit.Store
+	if config.ConfMap.RateLimitEnabled {
+		rateLimiter = ratelimit.NewMemoryStore()
+	}
+
+	// Workers
+	var workers []Worker
+	if config.ConfMap.ReconciliationInterval > 0 {
+		repair := config.ConfMap.ReconciliationRepair
+		workers = append(workers, scheduler.Every("price reconciliation", config.ConfMap.ReconciliationInterval, func(ctx context.Context) error {
+			_, err := reconciliationService.Run(ctx, repair)
+			if err != nil {
+				return err
+			}
+			return nil
+		}))
+	}
 
 	return HandlersStruct{
-		Items:      itemsHandler,
-		Categories: categoriesHandler,
+		Health:         healthHandler,
+		RateLimiter:    rateLimiter,
+		Idempotency:    idempotencyRepository,
+		Items:          itemsHandler,
+		Revisions:      revisionsHandler,
+		Categories:     categoriesHandler,
+		Collections:    collectionsHandler,
+		Reconciliation: reconciliationHandler,
+		Audit:          auditHandler,
+		GraphQL:        graphQLHandler,
+		AuditLog:       auditRepository,
+		AuditTargets:   apihandlers.NewAuditTargets(itemsRepository, categoriesRepository, collectionsRepository),
+		Workers:        workers,
 	}, nil
 }
 
 type HandlersStruct struct {
-	Items      handlers.ItemsHandler
-	Categories handlers.CategoriesHandler
+	Health         *apihandlers.HealthCheckerHandler
+	Items          handlers.ItemsHandler
+	Revisions      handlers.RevisionsHandler
+	Categories     handlers.CategoriesHandler
+	Collections    handlers.CollectionsHandler
+	Reconciliation handlers.ReconciliationHandler
+	Audit          handlers.AuditHandler
+	GraphQL        *graph.Handler
+
+	// RateLimiter keeps the rate limit buckets, nil when they are disabled.
+	RateLimiter ratelimit.Store
+
+	// Idempotency stores the responses of the requests sent with an
+	// Idempotency-Key.
+	Idempotency repositories.IdempotencyRepository
+
+	// AuditLog keeps who called the admin and mutating routes, and
+	// AuditTargets reads the resources they change.
+	AuditLog     repositories.AuditRepository
+	AuditTargets apihandlers.AuditTargets
+
+	// Workers are started with the server and stopped on shutdown.
+	Workers []Worker
 }
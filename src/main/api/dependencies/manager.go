@@ -1,35 +1,98 @@
 package dependencies
 
 import (
+	"context"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/migrations"
 	"github.com/agustinrabini/items-api-project/src/main/api/platform/storage"
 	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
 
 	"github.com/jopitnow/go-jopit-toolkit/gonosql"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 const (
-	KvsItemsCollection      = "items"
-	KvsCategoriesCollection = "categories"
+	KvsItemsCollection       = "items"
+	KvsCategoriesCollection  = "categories"
+	KvsCollectionsCollection = "collections"
+	KvsIdempotencyCollection = "idempotency_keys"
+	KvsMigrationsCollection  = "schema_migrations"
+	KvsReconciliationReports = "reconciliation_reports"
+	KvsLocksCollection       = "locks"
+	KvsRevisionsCollection   = "item_revisions"
+	KvsAuditCollection       = "audit_log"
 )
 
 type DependencyManager struct {
 	*gonosql.Data
 }
 
-func NewDependencyManager() DependencyManager {
+func NewDependencyManager() (DependencyManager, error) {
 	db := storage.NewNoSQL()
 	if db.Error != nil {
-		panic(db.Error)
+		return DependencyManager{}, db.Error
 	}
 	return DependencyManager{
 		db,
+	}, nil
+}
+
+func (m DependencyManager) IdempotencyRepository() repositories.IdempotencyRepository {
+	return repositories.NewIdempotencyRepository(m.NewCollection(KvsIdempotencyCollection))
+}
+
+func (m DependencyManager) ReconciliationRepository() repositories.ReconciliationRepository {
+	return repositories.NewReconciliationRepository(m.NewCollection(KvsReconciliationReports), m.NewCollection(KvsLocksCollection))
+}
+
+// Ping checks the database answers, used by the readiness endpoint.
+func (m DependencyManager) Ping(ctx context.Context) error {
+	return m.DB.Ping(ctx, readpref.Primary())
+}
+
+// Close disconnects the database client, waiting for in-progress operations
+// until ctx is done.
+func (m DependencyManager) Close(ctx context.Context) error {
+	if m.Data == nil || m.DB == nil {
+		return nil
 	}
+	return m.DB.Disconnect(ctx)
 }
 
+// ItemsRepository records the revisions of the items it changes.
 func (m DependencyManager) ItemsRepository() repositories.ItemsRepository {
-	return repositories.NewItemsRepository(m.NewCollection(KvsItemsCollection))
+	return repositories.NewRevisionedItemsRepository(
+		repositories.NewItemsRepository(m.NewCollection(KvsItemsCollection)),
+		m.RevisionsRepository(),
+	)
+}
+
+func (m DependencyManager) AuditRepository() repositories.AuditRepository {
+	return repositories.NewAuditRepository(m.NewCollection(KvsAuditCollection))
+}
+
+func (m DependencyManager) RevisionsRepository() repositories.RevisionsRepository {
+	return repositories.NewRevisionsRepository(m.NewCollection(KvsRevisionsCollection))
 }
 
 func (m DependencyManager) CategoriesRepository() repositories.CategoriesRepository {
 	return repositories.NewCategoriesRepository(m.NewCollection(KvsCategoriesCollection))
 }
+
+func (m DependencyManager) CollectionsRepository() repositories.CollectionsRepository {
+	return repositories.NewCollectionsRepository(m.NewCollection(KvsCollectionsCollection))
+}
+
+// Migrator applies the migrations of the schema, see migrations.Catalog.
+func (m DependencyManager) Migrator() (*migrations.Migrator, error) {
+	return migrations.NewMigrator(
+		migrations.NewMongoHistory(m.NewCollection(KvsMigrationsCollection)),
+		migrations.Catalog(migrations.Collections{
+			Items:       m.NewCollection(KvsItemsCollection),
+			Categories:  m.NewCollection(KvsCategoriesCollection),
+			Collections: m.NewCollection(KvsCollectionsCollection),
+			Revisions:   m.NewCollection(KvsRevisionsCollection),
+			Audit:       m.NewCollection(KvsAuditCollection),
+		})...,
+	)
+}
@@ -0,0 +1,8 @@
+// Package docs holds one Swagger spec per API version. Operations shared by
+// every version are in both; tag an operation v1 or v2 to keep it in that
+// version's spec only. The ping, health and errors endpoints are left out as
+// they are not served under the version base paths.
+package docs
+
+//go:generate swag init -d ../../../.. -g src/main/api/docs/v1.go -o . --instanceName v1 --tags !v2,!ping,!health,!errors
+//go:generate swag init -d ../../../.. -g src/main/api/docs/v2.go -o . --instanceName v2 --tags !v1,!ping,!health,!errors
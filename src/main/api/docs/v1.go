@@ -0,0 +1,18 @@
+package docs
+
+// General info of the v1 spec, also served on the deprecated unversioned paths.
+
+// @title API Items
+// @version 1.0
+// @description This is a api items.
+// @description Errors use the apierrors shape unless the request accepts application/problem+json.
+// @termsOfService http://swagger.io/terms/
+
+// @contact.name Agustin Rabini
+// @contact.url http://www.swagger.io/support
+// @contact.email agustinrabini99@gmail.com
+
+// @license.name Apache 2.0
+// @license.url http://www.apache.org/licenses/LICENSE-2.0.html
+
+// @BasePath /v1
@@ -0,0 +1,18 @@
+package docs
+
+// General info of the v2 spec.
+
+// @title API Items
+// @version 2.0
+// @description This is a api items.
+// @description Errors are always application/problem+json, see GET /errors for the codes, and GET /items/shop/{id} answers a page of the items like the search.
+// @termsOfService http://swagger.io/terms/
+
+// @contact.name Agustin Rabini
+// @contact.url http://www.swagger.io/support
+// @contact.email agustinrabini99@gmail.com
+
+// @license.name Apache 2.0
+// @license.url http://www.apache.org/licenses/LICENSE-2.0.html
+
+// @BasePath /v2
@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/actor"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/logger"
+	"github.com/jopitnow/go-jopit-toolkit/tracing"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const auditSaveTimeout = 5 * time.Second
+
+type auditRequestKey struct{}
+
+// auditRequest is what the audit log keeps of the HTTP request of a mutation.
+type auditRequest struct {
+	method string
+	path   string
+	ip     string
+}
+
+func withAuditRequest(ctx context.Context, c *gin.Context) context.Context {
+	return context.WithValue(ctx, auditRequestKey{}, auditRequest{method: c.Request.Method, path: c.Request.URL.Path, ip: c.ClientIP()})
+}
+
+// audit appends the entry of a mutation of the item to the audit log, with
+// the actions of the REST routes, so a change is in the log whichever API
+// made it. before is the state of the item before the mutation; the state
+// after is read when it succeeded. An error saving the entry is logged.
+func (r *Resolver) audit(ctx context.Context, action string, itemID string, before map[string]interface{}, err error) {
+	if r.Audit == nil {
+		return
+	}
+
+	request, _ := ctx.Value(auditRequestKey{}).(auditRequest)
+	entry := models.AuditEntry{
+		Actor:      actor.From(ctx),
+		Action:     action,
+		TargetType: "item",
+		TargetID:   itemID,
+		Method:     request.method,
+		Path:       request.path,
+		IP:         request.ip,
+		Status:     http.StatusOK,
+		Before:     before,
+	}
+	if requestID := ctx.Value(tracing.XtraceHeaderKey); requestID != nil {
+		entry.RequestID = fmt.Sprint(requestID)
+	}
+
+	var apiErr apierrors.ApiError
+	switch {
+	case errors.As(err, &apiErr):
+		entry.Status = apiErr.Status()
+	case err != nil:
+		entry.Status = http.StatusInternalServerError
+	default:
+		entry.After = r.itemState(ctx, itemID)
+	}
+
+	// the request may be canceled by now, the entry must be saved anyway
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditSaveTimeout)
+	defer cancel()
+
+	entry.CreatedAt = time.Now().UTC()
+	if saveErr := r.Audit.Save(saveCtx, entry); saveErr != nil {
+		logger.Error("Error saving the audit entry of "+action, saveErr)
+	}
+}
+
+// itemState reads the item for the audit log, nil when it doesn't exist or
+// there is no audit log.
+func (r *Resolver) itemState(ctx context.Context, itemID string) map[string]interface{} {
+	if r.Audit == nil || !primitive.IsValidObjectID(itemID) {
+		return nil
+	}
+
+	item, apiErr := r.Repository.Get(ctx, itemID)
+	if apiErr != nil {
+		if apiErr.Status() != http.StatusNotFound {
+			logger.Error("Error reading the audit state of item "+itemID, apiErr)
+		}
+		return nil
+	}
+
+	return models.AuditState(item)
+}
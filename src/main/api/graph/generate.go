@@ -0,0 +1,3 @@
+package graph
+
+//go:generate gqlgen generate
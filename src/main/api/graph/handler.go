@@ -0,0 +1,154 @@
+// Package graph serves the storefront GraphQL API. The schema is
+// schema.graphqls; generated/ and model/ are generated from it with gqlgen.
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/graph/generated"
+	"github.com/agustinrabini/items-api-project/src/main/api/graph/loaders"
+	"github.com/agustinrabini/items-api-project/src/main/domain/clients"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	"github.com/agustinrabini/items-api-project/src/main/domain/services"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/actor"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/gin-gonic/gin"
+	"github.com/jopitnow/go-jopit-toolkit/goauth"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/logger"
+	"github.com/jopitnow/go-jopit-toolkit/tracing"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Limits bound what a single request can ask for.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+
+	// BatchWait is how long the loaders wait for more keys before fetching.
+	BatchWait time.Duration
+}
+
+type Handler struct {
+	server     *handler.Server
+	repository repositories.ItemsRepository
+	prices     clients.PriceClient
+	batchWait  time.Duration
+}
+
+// NewHandler serves the GraphQL API. The item mutations are appended to
+// audit, the audit log of the REST routes, when it isn't nil.
+func NewHandler(repository repositories.ItemsRepository, prices clients.PriceClient, itemsService services.ItemsService, categoriesService services.CategoriesService, audit repositories.AuditRepository, limits Limits) *Handler {
+	schema := generated.NewExecutableSchema(generated.Config{
+		Resolvers: &Resolver{
+			Repository:        repository,
+			ItemsService:      itemsService,
+			CategoriesService: categoriesService,
+			Audit:             audit,
+		},
+		Complexity: complexity(),
+	})
+
+	server := handler.New(schema)
+	server.AddTransport(transport.GET{})
+	server.AddTransport(transport.POST{})
+	server.SetQueryCache(lru.New(1000))
+	server.Use(extension.Introspection{})
+	server.Use(DepthLimit{Max: limits.MaxDepth})
+	server.Use(extension.FixedComplexityLimit(limits.MaxComplexity))
+	server.SetErrorPresenter(presentError)
+	server.SetRecoverFunc(recoverPanic)
+
+	return &Handler{
+		server:     server,
+		repository: repository,
+		prices:     prices,
+		batchWait:  limits.BatchWait,
+	}
+}
+
+// Serve runs the operation with its own loaders. The caller is known when
+// the Firebase middleware authenticated the request.
+func (h *Handler) Serve(c *gin.Context) {
+	xTraceId, _ := c.Get("X-Trace-ID")
+	ctx := context.WithValue(c.Request.Context(), tracing.XtraceHeaderKey, xTraceId)
+	ctx = context.WithValue(ctx, goauth.FirebaseAuthHeader, c.GetHeader("Authorization"))
+
+	if userID, err := goauth.GetUserId(c); err == nil {
+		ctx = withUser(ctx, userID)
+		ctx = actor.With(ctx, userID)
+	}
+
+	ctx = withAuditRequest(ctx, c)
+	ctx = loaders.NewContext(ctx, loaders.New(h.repository, h.prices, h.batchWait))
+
+	h.server.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+}
+
+// presentError keeps the message of the ApiErrors and adds their catalog code
+// and HTTP status as extensions.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+
+	var apiErr apierrors.ApiError
+	if errors.As(err, &apiErr) {
+		presented.Message = apiErr.Message()
+		presented.Extensions = map[string]interface{}{
+			"code":   apiErr.Code(),
+			"status": apiErr.Status(),
+		}
+	}
+
+	return presented
+}
+
+func recoverPanic(ctx context.Context, r interface{}) error {
+	logger.Error("Panic resolving GraphQL", fmt.Errorf("%v", r))
+	return errors.New("internal error")
+}
+
+// IsMutation tells whether the operation the request runs is a mutation,
+// reading it like the GET and POST transports do. Requests it can't parse
+// are left to the server, which answers their errors.
+func IsMutation(c *gin.Context) bool {
+	query, operationName := c.Query("query"), c.Query("operationName")
+
+	if c.Request.Method == http.MethodPost {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return false
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		var params struct {
+			Query         string `json:"query"`
+			OperationName string `json:"operationName"`
+		}
+		if err = json.Unmarshal(body, &params); err != nil {
+			return false
+		}
+		query, operationName = params.Query, params.OperationName
+	}
+
+	document, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return false
+	}
+
+	operation := document.Operations.ForName(operationName)
+	return operation != nil && operation.Operation == ast.Mutation
+}
@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/graph/loaders"
+	"github.com/agustinrabini/items-api-project/src/main/api/graph/model"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/problems"
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+	"github.com/agustinrabini/items-api-project/src/main/domain/utils"
+)
+
+// page reads one page of items and primes the loader with them.
+func (r *Resolver) page(ctx context.Context, query models.ItemsQuery) (*model.ItemPage, error) {
+	if query.Limit < 1 || query.Limit > models.MaxItemsPageSize {
+		return nil, catalog.BadRequest.New(fmt.Sprintf("limit must be between 1 and %d", models.MaxItemsPageSize))
+	}
+	if query.Offset < 0 {
+		return nil, catalog.BadRequest.New("offset can't be negative")
+	}
+
+	page, apiErr := r.Repository.Search(ctx, query)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	loaders.FromContext(ctx).PrimeItems(page.Items)
+
+	return &model.ItemPage{
+		Items:   page.Items,
+		Total:   int(page.Total),
+		HasMore: page.HasMore(query),
+	}, nil
+}
+
+// itemRequest builds the body the REST API takes, checking the category the
+// same way the items handler does.
+func (r *Resolver) itemRequest(ctx context.Context, userID string, input model.ItemInput) (dto.ItemDTO, error) {
+	if apiErr := utils.ValidateHexID([]string{input.Category.ID}); apiErr != nil {
+		return dto.ItemDTO{}, apiErr
+	}
+
+	category, apiErr := r.CategoriesService.Get(ctx, input.Category.ID)
+	if apiErr != nil {
+		return dto.ItemDTO{}, apiErr
+	}
+	if category.Name != input.Category.Name {
+		return dto.ItemDTO{}, catalog.ValidationFailed.New("the category name does not match the name of category "+category.ID, problems.FieldError{
+			Path:   "input.category.name",
+			Code:   "mismatch",
+			Detail: "must be the name of category " + category.ID,
+		})
+	}
+
+	request := dto.ItemDTO{
+		Name:        input.Name,
+		Description: input.Description,
+		UserID:      userID,
+		Category: dto.CategoryDTO{
+			ID:       category.ID,
+			Name:     category.Name,
+			NameI18n: category.NameI18n,
+			Slug:     category.Slug,
+		},
+		Price: dto.PriceDTO{
+			Amount: input.Price.Amount,
+			Currency: dto.CurrencyDTO{
+				Symbol:           input.Price.Currency.Symbol,
+				DecimalDivider:   input.Price.Currency.DecimalDivider,
+				ThousandsDivider: input.Price.Currency.ThousandsDivider,
+			},
+		},
+		Attributes: dto.AttributesDTO{},
+	}
+
+	if input.Status != nil {
+		request.Status = *input.Status
+	}
+	if input.Price.Currency.ID != nil {
+		request.Price.Currency.ID = *input.Price.Currency.ID
+	}
+
+	for _, image := range input.Images {
+		request.Images = append(request.Images, dto.ImageDTO(image))
+	}
+
+	for _, attribute := range input.Attributes {
+		request.Attributes[attribute.Name] = attribute.Value
+	}
+
+	for _, eligible := range input.Eligible {
+		options := make([]dto.OptionDTO, 0, len(eligible.Options))
+		for _, option := range eligible.Options {
+			options = append(options, dto.OptionDTO(option))
+		}
+
+		request.Eligible = append(request.Eligible, dto.EligibleDTO{
+			Title:      eligible.Title,
+			Type:       eligible.Type,
+			IsRequired: eligible.IsRequired,
+			Options:    options,
+		})
+	}
+
+	return request, nil
+}
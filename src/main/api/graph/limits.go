@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"context"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/agustinrabini/items-api-project/src/main/api/graph/generated"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const errDepthLimit = "DEPTH_LIMIT_EXCEEDED"
+
+// DepthLimit rejects the operations nesting more than Max fields before any
+// resolver runs. Introspection fields don't count, so tools can still load
+// the schema.
+type DepthLimit struct {
+	Max int
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = DepthLimit{}
+
+func (DepthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (DepthLimit) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (d DepthLimit) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	depth := selectionDepth(rc.Operation.SelectionSet)
+	if depth <= d.Max {
+		return nil
+	}
+
+	err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, d.Max)
+	err.Extensions = map[string]interface{}{"code": errDepthLimit}
+
+	return err
+}
+
+func selectionDepth(selections ast.SelectionSet) int {
+	max := 0
+
+	for _, selection := range selections {
+		depth := 0
+
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name, "__") {
+				continue
+			}
+			depth = 1 + selectionDepth(selection.SelectionSet)
+		case *ast.InlineFragment:
+			depth = selectionDepth(selection.SelectionSet)
+		case *ast.FragmentSpread:
+			if selection.Definition != nil {
+				depth = selectionDepth(selection.Definition.SelectionSet)
+			}
+		}
+
+		if depth > max {
+			max = depth
+		}
+	}
+
+	return max
+}
+
+// complexity makes the lists cost as much as the items they can return, so
+// the complexity limit bounds the work of a request.
+func complexity() generated.ComplexityRoot {
+	var root generated.ComplexityRoot
+
+	root.Query.Items = func(childComplexity int, ids []string) int {
+		return 1 + len(ids)*childComplexity
+	}
+	root.Query.ShopItems = func(childComplexity int, shopID string, limit int, offset int) int {
+		return 1 + limit*childComplexity
+	}
+	root.Query.Search = func(childComplexity int, query string, shopID *string, limit int, offset int) int {
+		return 1 + limit*childComplexity
+	}
+
+	return root
+}
@@ -0,0 +1,93 @@
+// Package loaders batches the item and price lookups of one GraphQL request,
+// so resolving N items costs one query to the items collection and one call
+// to the prices service instead of N of each.
+package loaders
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/clients"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+
+	"github.com/vikstrous/dataloadgen"
+)
+
+type contextKey struct{}
+
+// Loaders are built for each request and cache what they load until it ends.
+// A missing item or price loads as nil, not as an error.
+type Loaders struct {
+	Items  *dataloadgen.Loader[string, *models.Item]
+	Prices *dataloadgen.Loader[string, *models.Price]
+}
+
+// New batches the keys asked for within wait of each other.
+func New(repository repositories.ItemsRepository, prices clients.PriceClient, wait time.Duration) *Loaders {
+	return &Loaders{
+		Items:  dataloadgen.NewLoader(itemsFetcher(repository), dataloadgen.WithWait(wait)),
+		Prices: dataloadgen.NewLoader(pricesFetcher(prices), dataloadgen.WithWait(wait)),
+	}
+}
+
+func NewContext(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, contextKey{}, loaders)
+}
+
+func FromContext(ctx context.Context) *Loaders {
+	return ctx.Value(contextKey{}).(*Loaders)
+}
+
+// PrimeItems caches items already read by other queries, so asking for them
+// by id later in the request doesn't read them again.
+func (l *Loaders) PrimeItems(items []models.Item) {
+	for i := range items {
+		l.Items.Prime(items[i].ID, &items[i])
+	}
+}
+
+func itemsFetcher(repository repositories.ItemsRepository) func(ctx context.Context, ids []string) ([]*models.Item, []error) {
+	return func(ctx context.Context, ids []string) ([]*models.Item, []error) {
+		result := make([]*models.Item, len(ids))
+
+		items, err := repository.GetByIDs(ctx, ids)
+		if err != nil && err.Status() != http.StatusNotFound {
+			return nil, []error{err}
+		}
+
+		byID := make(map[string]*models.Item, len(items.Items))
+		for i := range items.Items {
+			byID[items.Items[i].ID] = &items.Items[i]
+		}
+
+		for i, id := range ids {
+			result[i] = byID[id]
+		}
+
+		return result, nil
+	}
+}
+
+func pricesFetcher(prices clients.PriceClient) func(ctx context.Context, itemIDs []string) ([]*models.Price, []error) {
+	return func(ctx context.Context, itemIDs []string) ([]*models.Price, []error) {
+		result := make([]*models.Price, len(itemIDs))
+
+		response, err := prices.GetItemsPrices(ctx, itemIDs)
+		if err != nil && err.Status() != http.StatusNotFound {
+			return nil, []error{err}
+		}
+
+		byItemID := make(map[string]*models.Price, len(response.Prices))
+		for i := range response.Prices {
+			byItemID[response.Prices[i].ItemID] = &response.Prices[i]
+		}
+
+		for i, itemID := range itemIDs {
+			result[i] = byItemID[itemID]
+		}
+
+		return result, nil
+	}
+}
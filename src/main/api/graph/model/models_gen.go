@@ -0,0 +1,64 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+import (
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+)
+
+type Attribute struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type AttributeInput struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type CategoryInput struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type CurrencyInput struct {
+	ID               *string `json:"id,omitempty"`
+	Symbol           string  `json:"symbol"`
+	DecimalDivider   string  `json:"decimalDivider"`
+	ThousandsDivider string  `json:"thousandsDivider"`
+}
+
+type EligibleInput struct {
+	Title      string   `json:"title"`
+	Type       string   `json:"type"`
+	IsRequired bool     `json:"isRequired"`
+	Options    []string `json:"options"`
+}
+
+type ItemInput struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Status      *string          `json:"status,omitempty"`
+	Category    *CategoryInput   `json:"category"`
+	Price       *PriceInput      `json:"price"`
+	Images      []string         `json:"images,omitempty"`
+	Attributes  []AttributeInput `json:"attributes,omitempty"`
+	Eligible    []EligibleInput  `json:"eligible,omitempty"`
+}
+
+type ItemPage struct {
+	Items   []models.Item `json:"items"`
+	Total   int           `json:"total"`
+	HasMore bool          `json:"hasMore"`
+}
+
+type Mutation struct {
+}
+
+type PriceInput struct {
+	Amount   float64        `json:"amount"`
+	Currency *CurrencyInput `json:"currency"`
+}
+
+type Query struct {
+}
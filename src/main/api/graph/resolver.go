@@ -0,0 +1,56 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	"github.com/agustinrabini/items-api-project/src/main/domain/services"
+
+	"github.com/vikstrous/dataloadgen"
+)
+
+type userKey struct{}
+
+// Resolver reads the items through the repository and the request loaders,
+// and writes them through ItemsService, like the REST handlers, recording
+// the writes in the Audit log.
+type Resolver struct {
+	Repository        repositories.ItemsRepository
+	ItemsService      services.ItemsService
+	CategoriesService services.CategoriesService
+	Audit             repositories.AuditRepository
+}
+
+func withUser(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userKey{}, userID)
+}
+
+// requireUser returns the id of the user authenticated by the Firebase
+// middleware, which only runs when the request has a token.
+func requireUser(ctx context.Context) (string, error) {
+	userID, _ := ctx.Value(userKey{}).(string)
+	if userID == "" {
+		return "", catalog.Unauthorized.New("mutations need a Firebase token")
+	}
+
+	return userID, nil
+}
+
+// firstError unwraps the errors of a batch, which all come from the same
+// fetch.
+func firstError(err error) error {
+	var batch dataloadgen.ErrorSlice
+	if !errors.As(err, &batch) {
+		return err
+	}
+
+	for _, err := range batch {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
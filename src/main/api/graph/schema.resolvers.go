@@ -0,0 +1,187 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.43
+
+import (
+	"context"
+	"sort"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/graph/generated"
+	"github.com/agustinrabini/items-api-project/src/main/api/graph/loaders"
+	"github.com/agustinrabini/items-api-project/src/main/api/graph/model"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Options is the resolver for the options field.
+func (r *eligibleResolver) Options(ctx context.Context, obj *models.Eligible) ([]string, error) {
+	options := make([]string, 0, len(obj.Options))
+	for _, option := range obj.Options {
+		options = append(options, string(option))
+	}
+
+	return options, nil
+}
+
+// Price is the resolver for the price field.
+func (r *itemResolver) Price(ctx context.Context, obj *models.Item) (*models.Price, error) {
+	return loaders.FromContext(ctx).Prices.Load(ctx, obj.ID)
+}
+
+// Images is the resolver for the images field.
+func (r *itemResolver) Images(ctx context.Context, obj *models.Item) ([]string, error) {
+	images := make([]string, 0, len(obj.Images))
+	for _, image := range obj.Images {
+		images = append(images, string(image))
+	}
+
+	return images, nil
+}
+
+// Attributes is the resolver for the attributes field.
+func (r *itemResolver) Attributes(ctx context.Context, obj *models.Item) ([]model.Attribute, error) {
+	attributes := make([]model.Attribute, 0, len(obj.Attributes))
+	for name, value := range obj.Attributes {
+		attributes = append(attributes, model.Attribute{Name: name, Value: value})
+	}
+
+	sort.Slice(attributes, func(i, j int) bool { return attributes[i].Name < attributes[j].Name })
+
+	return attributes, nil
+}
+
+// CreateItem is the resolver for the createItem field.
+func (r *mutationResolver) CreateItem(ctx context.Context, input model.ItemInput) (itemID string, err error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { r.audit(ctx, "item.create", itemID, nil, err) }()
+
+	request, err := r.itemRequest(ctx, userID, input)
+	if err != nil {
+		return "", err
+	}
+
+	insertedID, apiErr := r.ItemsService.CreateItem(ctx, request)
+	if apiErr != nil {
+		return "", apiErr
+	}
+
+	return insertedID.(primitive.ObjectID).Hex(), nil
+}
+
+// UpdateItem is the resolver for the updateItem field.
+func (r *mutationResolver) UpdateItem(ctx context.Context, id string, input model.ItemInput) (updated bool, err error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	before := r.itemState(ctx, id)
+	defer func() { r.audit(ctx, "item.update", id, before, err) }()
+
+	if apiErr := utils.ValidateHexID([]string{id}); apiErr != nil {
+		return false, apiErr
+	}
+
+	request, err := r.itemRequest(ctx, userID, input)
+	if err != nil {
+		return false, err
+	}
+
+	if apiErr := r.ItemsService.Update(ctx, id, request); apiErr != nil {
+		return false, apiErr
+	}
+
+	return true, nil
+}
+
+// DeleteItem is the resolver for the deleteItem field.
+func (r *mutationResolver) DeleteItem(ctx context.Context, id string) (deleted bool, err error) {
+	if _, err = requireUser(ctx); err != nil {
+		return false, err
+	}
+
+	before := r.itemState(ctx, id)
+	defer func() { r.audit(ctx, "item.delete", id, before, err) }()
+
+	if apiErr := utils.ValidateHexID([]string{id}); apiErr != nil {
+		return false, apiErr
+	}
+
+	if apiErr := r.ItemsService.Delete(ctx, id); apiErr != nil {
+		return false, apiErr
+	}
+
+	return true, nil
+}
+
+// Item is the resolver for the item field.
+func (r *queryResolver) Item(ctx context.Context, id string) (*models.Item, error) {
+	if apiErr := utils.ValidateHexID([]string{id}); apiErr != nil {
+		return nil, apiErr
+	}
+
+	return loaders.FromContext(ctx).Items.Load(ctx, id)
+}
+
+// Items is the resolver for the items field.
+func (r *queryResolver) Items(ctx context.Context, ids []string) ([]*models.Item, error) {
+	if apiErr := utils.ValidateHexID(ids); apiErr != nil {
+		return nil, apiErr
+	}
+
+	items, err := loaders.FromContext(ctx).Items.LoadAll(ctx, ids)
+	if err != nil {
+		return nil, firstError(err)
+	}
+
+	return items, nil
+}
+
+// ShopItems is the resolver for the shopItems field.
+func (r *queryResolver) ShopItems(ctx context.Context, shopID string, limit int, offset int) (*model.ItemPage, error) {
+	return r.page(ctx, models.ItemsQuery{ShopID: shopID, Limit: limit, Offset: offset})
+}
+
+// Categories is the resolver for the categories field.
+func (r *queryResolver) Categories(ctx context.Context) ([]models.Category, error) {
+	categories, apiErr := r.CategoriesService.GetAllCategories(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return categories, nil
+}
+
+// Search is the resolver for the search field.
+func (r *queryResolver) Search(ctx context.Context, query string, shopID *string, limit int, offset int) (*model.ItemPage, error) {
+	itemsQuery := models.ItemsQuery{Text: query, Limit: limit, Offset: offset}
+	if shopID != nil {
+		itemsQuery.ShopID = *shopID
+	}
+
+	return r.page(ctx, itemsQuery)
+}
+
+// Eligible returns generated.EligibleResolver implementation.
+func (r *Resolver) Eligible() generated.EligibleResolver { return &eligibleResolver{r} }
+
+// Item returns generated.ItemResolver implementation.
+func (r *Resolver) Item() generated.ItemResolver { return &itemResolver{r} }
+
+// Mutation returns generated.MutationResolver implementation.
+func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
+
+// Query returns generated.QueryResolver implementation.
+func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
+
+type eligibleResolver struct{ *Resolver }
+type itemResolver struct{ *Resolver }
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
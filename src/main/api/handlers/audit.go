@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/problems"
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/actor"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jopitnow/go-jopit-toolkit/goauth"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/logger"
+	"github.com/jopitnow/go-jopit-toolkit/tracing"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// AdminActorHeader names the person behind a request authenticated with
+	// the shared admin password, as the password alone can't tell. Anyone
+	// with the password can send any name, so it is only recorded as the
+	// claimed actor.
+	AdminActorHeader = "X-Admin-Actor"
+
+	// AdminActor is the actor of the requests authenticated with the admin
+	// password.
+	AdminActor = "admin"
+
+	adminActorMaxLength = 100
+	auditSaveTimeout    = 5 * time.Second
+
+	// auditMaxBodySize bounds the request body the audit reads, larger ones
+	// are refused.
+	auditMaxBodySize = 1 << 20
+)
+
+// AuditTarget tells the audit log what kind of resource a route changes and
+// how to read it.
+type AuditTarget struct {
+	Type string
+
+	// ID returns the ID of the resource of the request, from its path or
+	// body. Empty when the request creates it or has no resource.
+	ID func(c *gin.Context, body []byte) string
+
+	// State reads the resource, nil when it doesn't exist. Nil for the
+	// targets without state.
+	State func(ctx context.Context, id string) (interface{}, apierrors.ApiError)
+}
+
+// AuditHandler appends an entry to the audit log for every request of the
+// route, with the state of the target before and, when it succeeds, after
+// the request. A request that creates a resource takes its ID from the
+// response, a JSON string or an object with an id; its state is the request
+// body when the resource can't be read. It must go after the auth so the
+// actor is known, and sets it in the request context for the handlers. The
+// entry is saved after the response, and an error saving it is logged.
+func AuditHandler(repository repositories.AuditRepository, action string, target AuditTarget) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if repository == nil {
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, auditMaxBodySize))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			problems.Abort(c, catalog.PayloadTooLarge.New(fmt.Sprintf("the request body can't be larger than %d bytes", tooLarge.Limit)))
+			return
+		}
+		if err != nil {
+			apiErr := apierrors.NewBadRequestApiError("error reading the request body")
+			problems.Abort(c, apiErr)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		name, claimed := auditActor(c)
+		entry := models.AuditEntry{
+			Actor:        name,
+			ClaimedActor: claimed,
+			Action:       action,
+			TargetType:   target.Type,
+			Method:       c.Request.Method,
+			Path:         c.Request.URL.Path,
+			IP:           c.ClientIP(),
+		}
+		if requestID, exists := c.Get(tracing.XtraceHeaderKey); exists && requestID != nil {
+			entry.RequestID = fmt.Sprint(requestID)
+		}
+		if target.ID != nil {
+			entry.TargetID = target.ID(c, body)
+		}
+		if entry.TargetID != "" {
+			entry.Before = auditState(c.Request.Context(), target, entry.TargetID)
+		}
+
+		// the item revisions name the same actor
+		c.Request = c.Request.WithContext(actor.With(c.Request.Context(), entry.Actor))
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		// the request context may be gone by now, the entry must be saved anyway
+		ctx, cancel := context.WithTimeout(context.Background(), auditSaveTimeout)
+		defer cancel()
+
+		entry.Status = recorder.Status()
+		entry.CreatedAt = time.Now().UTC()
+
+		if entry.Status < http.StatusBadRequest {
+			created := entry.TargetID == "" && c.Request.Method == http.MethodPost
+			if created {
+				entry.TargetID = createdID(recorder.body.Bytes())
+			}
+			if entry.TargetID != "" {
+				entry.After = auditState(ctx, target, entry.TargetID)
+			}
+			if created && entry.After == nil && len(body) > 0 {
+				_ = json.Unmarshal(body, &entry.After)
+			}
+		}
+
+		if apiErr := repository.Save(ctx, entry); apiErr != nil {
+			logger.Error("Error saving the audit entry of "+action, apiErr)
+		}
+	}
+}
+
+// AuditPath returns the ID of the request from the path parameter name.
+func AuditPath(name string) func(c *gin.Context, body []byte) string {
+	return func(c *gin.Context, body []byte) string {
+		return c.Param(name)
+	}
+}
+
+// AuditBody returns the ID of the request from the id of its JSON body.
+func AuditBody(c *gin.Context, body []byte) string {
+	var resource struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(body, &resource)
+
+	return resource.ID
+}
+
+// auditActor is the user of the Firebase token or, for the admin password,
+// AdminActor with the name the AdminActorHeader claims.
+func auditActor(c *gin.Context) (string, string) {
+	if userID, err := goauth.GetUserId(c); err == nil && userID != "" {
+		return userID, ""
+	}
+
+	claimed := strings.TrimSpace(c.GetHeader(AdminActorHeader))
+	if len(claimed) > adminActorMaxLength {
+		claimed = claimed[:adminActorMaxLength]
+	}
+
+	return AdminActor, claimed
+}
+
+func auditState(ctx context.Context, target AuditTarget, id string) map[string]interface{} {
+	if target.State == nil {
+		return nil
+	}
+
+	resource, apiErr := target.State(ctx, id)
+	if apiErr != nil {
+		if apiErr.Status() != http.StatusNotFound && apiErr.Status() != http.StatusBadRequest {
+			logger.Error("Error reading the audit state of "+target.Type+" "+id, apiErr)
+		}
+		return nil
+	}
+
+	return models.AuditState(resource)
+}
+
+// createdID reads the ID from the response of a create.
+func createdID(response []byte) string {
+	var id string
+	if json.Unmarshal(response, &id) == nil {
+		return id
+	}
+
+	var resource struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(response, &resource)
+
+	return resource.ID
+}
+
+// AuditTargets are the resources the audited routes change.
+type AuditTargets struct {
+	Items       AuditTarget
+	Categories  AuditTarget
+	Collections AuditTarget
+}
+
+// NewAuditTargets reads the state of the resources from the repositories,
+// without the prices of the items, which live in the prices API.
+func NewAuditTargets(items repositories.ItemsRepository, categories repositories.CategoriesRepository, collections repositories.CollectionsRepository) AuditTargets {
+	return AuditTargets{
+		Items: AuditTarget{Type: "item", ID: AuditPath("id"), State: func(ctx context.Context, id string) (interface{}, apierrors.ApiError) {
+			if !primitive.IsValidObjectID(id) {
+				return nil, nil
+			}
+			return items.Get(ctx, id)
+		}},
+		Categories: AuditTarget{Type: "category", ID: AuditPath("id_category"), State: func(ctx context.Context, id string) (interface{}, apierrors.ApiError) {
+			if !primitive.IsValidObjectID(id) {
+				return nil, nil
+			}
+			return categories.Get(ctx, id)
+		}},
+		Collections: AuditTarget{Type: "collection", ID: AuditPath("id"), State: func(ctx context.Context, id string) (interface{}, apierrors.ApiError) {
+			if !primitive.IsValidObjectID(id) {
+				return nil, nil
+			}
+			return collections.Get(ctx, id)
+		}},
+	}
+}
+
+// With returns the target reading the ID of the requests with id instead.
+func (t AuditTarget) With(id func(c *gin.Context, body []byte) string) AuditTarget {
+	t.ID = id
+	return t
+}
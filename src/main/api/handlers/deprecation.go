@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeprecationHandler flags the routes of a deprecated API version with the
+// Deprecation and Sunset headers, and links each one to the same path under
+// successor. A zero sunset omits the Sunset header.
+func DeprecationHandler(deprecatedAt time.Time, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		if !sunset.IsZero() {
+			c.Header("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+		c.Header("Link", fmt.Sprintf("<%s%s>; rel=\"successor-version\"", strings.TrimSuffix(successor, "/"), c.Request.URL.Path))
+
+		metrics.DeprecatedRequests.WithLabelValues(c.FullPath()).Inc()
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/problems"
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorCatalog lists the error codes the API may answer with.
+// @Summary Error catalog
+// @Description Every problem type with its code, title and status
+// @Tags errors
+// @Produce  json
+// @Success 200 {object} []catalog.Definition
+// @Router /errors [get]
+func ErrorCatalog(c *gin.Context) {
+	c.JSON(http.StatusOK, catalog.All())
+}
+
+// ErrorType describes the problem type of one code.
+// @Summary Error type
+// @Description The code, title and status of a problem type
+// @Tags errors
+// @Produce  json
+// @Param code path string true "Error code"
+// @Success 200 {object} catalog.Definition
+// @Router /errors/{code} [get]
+func ErrorType(c *gin.Context) {
+	definition, ok := catalog.Lookup(c.Param("code"))
+	if !ok {
+		problems.Respond(c, catalog.NotFound.New("unknown error code "+c.Param("code")))
+		return
+	}
+
+	c.JSON(http.StatusOK, definition)
+}
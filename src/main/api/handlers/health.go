@@ -1,16 +1,72 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	HealthStatusUp       = "up"
+	HealthStatusDown     = "down"
+	HealthStatusDegraded = "degraded"
+)
+
+// HealthCheck probes one dependency. When a required check fails the service
+// is reported as not ready; optional ones only degrade the report.
+type HealthCheck struct {
+	Name     string
+	Required bool
+	Probe    func(ctx context.Context) error
+}
+
+// HealthReport is the body of the readiness endpoint.
+type HealthReport struct {
+	Status    string             `json:"status"`
+	CheckedAt time.Time          `json:"checked_at"`
+	Checks    []DependencyStatus `json:"checks"`
+}
+
+type DependencyStatus struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Required  bool   `json:"required"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
 // HealthCheckerHandler struct provides the handler for a health check endpoint.
-type HealthCheckerHandler struct{}
+type HealthCheckerHandler struct {
+	checks   []HealthCheck
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu      sync.Mutex
+	last    *HealthReport
+	running *healthRun
+}
+
+// healthRun is a probe in progress, shared by the callers arriving while it
+// runs. cached is false when the report can't be reused.
+type healthRun struct {
+	done   chan struct{}
+	report HealthReport
+	cached bool
+}
 
-func NewHealthCheckerHandler() HealthCheckerHandler {
-	return HealthCheckerHandler{}
+// NewHealthCheckerHandler runs every check with the given timeout and keeps
+// the result for cacheTTL, so orchestrators polling readiness don't reach the
+// downstreams on every call.
+func NewHealthCheckerHandler(timeout time.Duration, cacheTTL time.Duration, checks ...HealthCheck) *HealthCheckerHandler {
+	return &HealthCheckerHandler{
+		checks:   checks,
+		timeout:  timeout,
+		cacheTTL: cacheTTL,
+	}
 }
 
 // Ping is the handler of test app
@@ -20,6 +76,158 @@ func NewHealthCheckerHandler() HealthCheckerHandler {
 // @Produce  json
 // @Success 200
 // @Router /ping [get]
-func (h HealthCheckerHandler) Ping(c *gin.Context) {
+func (h *HealthCheckerHandler) Ping(c *gin.Context) {
 	c.String(http.StatusOK, "pong")
 }
+
+// Live tells whether the process is up, without looking at its dependencies.
+// @Summary Liveness
+// @Description Returns 200 while the process is able to serve requests
+// @Tags health
+// @Produce  json
+// @Success 200
+// @Router /health/live [get]
+func (h *HealthCheckerHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": HealthStatusUp})
+}
+
+// Ready tells whether the service can take traffic, checking its dependencies.
+// @Summary Readiness
+// @Description Reports the status and latency of each dependency. Returns 503 when a required one is down
+// @Tags health
+// @Produce  json
+// @Success 200 {object} handlers.HealthReport
+// @Failure 503 {object} handlers.HealthReport
+// @Router /health/ready [get]
+func (h *HealthCheckerHandler) Ready(c *gin.Context) {
+	report := h.Check(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status == HealthStatusDown {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, report)
+}
+
+// Check returns the cached report while it is fresh, or probes every
+// dependency concurrently. Concurrent callers wait for the same probe, which
+// runs without holding the lock. A report probed under a context cancelled by
+// its caller is returned to that caller only, as its failures say nothing of
+// the dependencies.
+func (h *HealthCheckerHandler) Check(ctx context.Context) HealthReport {
+	for {
+		h.mu.Lock()
+		if h.last != nil && time.Since(h.last.CheckedAt) < h.cacheTTL {
+			report := *h.last
+			h.mu.Unlock()
+			return report
+		}
+
+		if run := h.running; run != nil {
+			h.mu.Unlock()
+
+			select {
+			case <-run.done:
+				if run.cached {
+					return run.report
+				}
+			case <-ctx.Done():
+				return h.run(ctx)
+			}
+			continue
+		}
+
+		run := &healthRun{done: make(chan struct{})}
+		h.running = run
+		h.mu.Unlock()
+
+		run.report = h.run(ctx)
+		run.cached = ctx.Err() == nil
+
+		h.mu.Lock()
+		if run.cached {
+			h.last = &run.report
+		}
+		h.running = nil
+		h.mu.Unlock()
+		close(run.done)
+
+		return run.report
+	}
+}
+
+func (h *HealthCheckerHandler) run(ctx context.Context) HealthReport {
+	report := HealthReport{
+		Status:    HealthStatusUp,
+		CheckedAt: time.Now(),
+		Checks:    make([]DependencyStatus, len(h.checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			report.Checks[i] = h.probe(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for _, check := range report.Checks {
+		if check.Status == HealthStatusUp {
+			continue
+		}
+		if check.Required {
+			report.Status = HealthStatusDown
+		} else if report.Status == HealthStatusUp {
+			report.Status = HealthStatusDegraded
+		}
+	}
+
+	return report
+}
+
+func (h *HealthCheckerHandler) probe(ctx context.Context, check HealthCheck) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Probe(ctx)
+
+	status := DependencyStatus{
+		Name:      check.Name,
+		Status:    HealthStatusUp,
+		Required:  check.Required,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		status.Status = HealthStatusDown
+		status.Error = err.Error()
+	}
+
+	return status
+}
+
+// URLProbe checks that baseURL answers its /ping endpoint with a non 5xx code.
+func URLProbe(baseURL string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/ping", nil)
+		if err != nil {
+			return err
+		}
+
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return err
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("unexpected status %d", response.StatusCode)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/problems"
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jopitnow/go-jopit-toolkit/goauth"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/logger"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	idempotencyKeyMaxLength   = 255
+	idempotencyRequestTimeout = 5 * time.Second
+)
+
+// IdempotencyHandler makes a POST safe to retry when the client sends an
+// Idempotency-Key: the first response is stored and replayed on repeats, and
+// reusing the key with another body is rejected with a 422. Keys are scoped by
+// route, shared by its versions, and Firebase user, so it must go after
+// AuthWithFirebase. Requests without the header go through untouched.
+func IdempotencyHandler(repository repositories.IdempotencyRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || repository == nil {
+			return
+		}
+
+		if len(key) > idempotencyKeyMaxLength {
+			apiErr := apierrors.NewBadRequestApiError("the Idempotency-Key header can't be longer than 255 characters")
+			problems.Abort(c, apiErr)
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			apiErr := apierrors.NewBadRequestApiError("error reading the request body")
+			problems.Abort(c, apiErr)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		userID, _ := goauth.GetUserId(c)
+		record := models.IdempotencyRecord{
+			Key:         routeKey(c) + "|" + userID + "|" + key,
+			Fingerprint: fingerprint(c, body),
+			CreatedAt:   time.Now(),
+		}
+
+		stored, reserved, apiErr := repository.Reserve(c.Request.Context(), record)
+		if apiErr != nil {
+			problems.Abort(c, apiErr)
+			return
+		}
+
+		if !reserved {
+			replay(c, stored, record.Fingerprint)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		// a panicking handler releases the key too, or it would stay in
+		// progress until the TTL drops it
+		completed := false
+		defer func() {
+			if !completed {
+				release(repository, record.Key)
+			}
+		}()
+
+		c.Next()
+
+		// server errors are not stored so the client can retry them
+		if recorder.Status() >= http.StatusInternalServerError {
+			return
+		}
+		completed = true
+
+		// the request context may be gone by now, the outcome must be saved anyway
+		ctx, cancel := context.WithTimeout(context.Background(), idempotencyRequestTimeout)
+		defer cancel()
+
+		if apiErr := repository.Complete(ctx, record.Key, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes()); apiErr != nil {
+			logger.Error("Error storing idempotent response", apiErr)
+		}
+	}
+}
+
+func release(repository repositories.IdempotencyRepository, key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), idempotencyRequestTimeout)
+	defer cancel()
+
+	if apiErr := repository.Release(ctx, key); apiErr != nil {
+		logger.Error("Error releasing idempotency key", apiErr)
+	}
+}
+
+func replay(c *gin.Context, stored models.IdempotencyRecord, fingerprint string) {
+	if stored.Fingerprint != fingerprint {
+		apiErr := catalog.IdempotencyKeyReused.New("the Idempotency-Key was already used with a different request")
+		problems.Abort(c, apiErr)
+		return
+	}
+
+	if !stored.Completed {
+		apiErr := catalog.IdempotencyKeyInProgress.New("a request with this Idempotency-Key is still in progress")
+		problems.Abort(c, apiErr)
+		return
+	}
+
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(stored.Status, stored.ContentType, stored.Body)
+	c.Abort()
+}
+
+// fingerprint hashes the route, its parameters and the body, leaving the
+// version prefix of the path out so the versions of a route match.
+func fingerprint(c *gin.Context, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + " " + routeKey(c)))
+	for _, param := range c.Params {
+		hash.Write([]byte(" " + param.Key + "=" + param.Value))
+	}
+	hash.Write([]byte("\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the body written to the client.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
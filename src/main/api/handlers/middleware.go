@@ -1,16 +1,113 @@
 package handlers
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/agustinrabini/items-api-project/src/main/api/config"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/devservices"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/problems"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/metrics"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/telemetry"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jopitnow/go-jopit-toolkit/goauth"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
 	"github.com/jopitnow/go-jopit-toolkit/goutils/logger"
+	"github.com/jopitnow/go-jopit-toolkit/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// devUserKey is where goauth.GetUserId reads the user from.
+const devUserKey = "user_id"
+
+// routeNameKey holds the name LoggerHandler got, which is the same for a route
+// under every API version.
+const routeNameKey = "route_name"
+
 func LoggerHandler(requestName string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		reqLogger := logger.NewRequestLogger(c, requestName, config.LogRatio, config.LogBodyRatio)
+		start := time.Now()
+		c.Set(routeNameKey, requestName)
+
+		request, span := telemetry.StartServer(c.Request, requestName)
+		c.Request = request
+
+		// keep X-Trace-ID working for the clients and logs that use it; when
+		// nobody set one the trace ID takes its place
+		xTraceID, exists := c.Get(tracing.XtraceHeaderKey)
+		if !exists && span.SpanContext().HasTraceID() {
+			xTraceID = span.SpanContext().TraceID().String()
+			c.Set(tracing.XtraceHeaderKey, xTraceID)
+		}
+		if xTraceID != nil {
+			span.SetAttributes(attribute.String("x_trace_id", fmt.Sprint(xTraceID)))
+		}
+
+		reqLogger := logger.NewRequestLogger(c, requestName, config.ConfMap.LogRatio, config.ConfMap.LogBodyRatio)
 		c.Next()
 		reqLogger.LogResponse(c)
+
+		telemetry.EndServer(span, c.Writer.Status())
+		metrics.ObserveRequest(requestName, c.Request.Method, c.Writer.Status(), start)
+	}
+}
+
+// OptionalAuthHandler runs auth only for the requests that send a token, so
+// an endpoint can serve anonymous callers and still reject invalid tokens.
+func OptionalAuthHandler(auth gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(goauth.FirebaseAuthHeader) == "" {
+			c.Next()
+			return
+		}
+
+		auth(c)
+	}
+}
+
+// OperationHandler runs the write middlewares for the requests isWrite tells
+// apart, like the GraphQL mutations sharing a route with the queries, and the
+// read ones for the rest. Writes get their own route key, so their rate limit
+// and idempotency keys don't mix with the reads'.
+func OperationHandler(isWrite func(c *gin.Context) bool, read []gin.HandlerFunc, write []gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		chain := read
+		if isWrite(c) {
+			c.Set(routeNameKey, routeKey(c)+"|write")
+			chain = write
+		}
+
+		for _, handler := range chain {
+			handler(c)
+			if c.IsAborted() {
+				return
+			}
+		}
+	}
+}
+
+// DevAuthHandler replaces AuthWithFirebase in dev mode: the user is taken
+// from an "Authorization: Dev <user>" header, without any token check.
+func DevAuthHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := devservices.UserID(c.GetHeader(goauth.FirebaseAuthHeader))
+		if err != nil {
+			problems.Abort(c, apierrors.NewUnauthorizedApiError(err.Error()))
+			return
+		}
+
+		c.Set(devUserKey, userID)
+		c.Next()
+	}
+}
+
+// routeKey identifies the route of the request for the per route state, like
+// rate limits and idempotency keys. It is the route name, so /items, /v1/items
+// and /v2/items share it, or the path pattern on routes without one.
+func routeKey(c *gin.Context) string {
+	if name := c.GetString(routeNameKey); name != "" {
+		return name
 	}
+	return c.FullPath()
 }
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/problems"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/ratelimit"
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jopitnow/go-jopit-toolkit/goauth"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/logger"
+)
+
+// RateLimitHandler gives each caller a token bucket per route, shared by its
+// versions. The caller is the Firebase user when there is one, so it must go
+// after AuthWithFirebase on authenticated routes, and the client IP otherwise.
+// A nil store disables it.
+func RateLimitHandler(store ratelimit.Store, limit ratelimit.Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if store == nil {
+			return
+		}
+
+		result, err := store.Take(c.Request.Context(), rateLimitKey(c), limit)
+		if err != nil {
+			// a broken backend shouldn't take the API down with it
+			logger.Error("Error reading the rate limit", err)
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+
+			apiErr := catalog.TooManyRequests.New(fmt.Sprintf("too many requests, retry in %d seconds", retryAfter))
+			problems.Abort(c, apiErr)
+		}
+	}
+}
+
+func rateLimitKey(c *gin.Context) string {
+	if userID, err := goauth.GetUserId(c); err == nil && userID != "" {
+		return routeKey(c) + "|user:" + userID
+	}
+	return routeKey(c) + "|ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
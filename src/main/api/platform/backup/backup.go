@@ -0,0 +1,485 @@
+// Package backup writes collections of the database to a compressed archive
+// and restores them, whole or only the documents of one shop.
+//
+// The archive is a gzipped tar with manifest.json first and then one file per
+// collection, with its documents as BSON, like mongodump writes them, or as
+// canonical extended JSON, one per line. The manifest keeps the count and the
+// SHA-256 of every file, and Restore checks them all before writing anything.
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Formats of the collection files.
+const (
+	FormatBSON = "bson"
+	FormatJSON = "json"
+)
+
+const (
+	manifestName    = "manifest.json"
+	manifestVersion = 1
+
+	// restoreBatch is how many documents Restore writes at once.
+	restoreBatch = 500
+
+	// maxLineSize bounds an extended JSON document, a BSON one is 16MB at
+	// most but its JSON may be larger.
+	maxLineSize = 64 << 20
+)
+
+// Store is the database side of the backups.
+type Store interface {
+	// Each calls fn with every document of the collection, sorted by _id.
+	// The document is only valid during the call.
+	Each(ctx context.Context, collection string, fn func(doc bson.Raw) error) error
+	// Replace writes the documents over the ones with the same _id, inserting
+	// the missing ones.
+	Replace(ctx context.Context, collection string, docs []bson.Raw) error
+	// Remove deletes the documents matching filter and returns how many.
+	Remove(ctx context.Context, collection string, filter bson.D) (int64, error)
+}
+
+// Manifest describes an archive.
+type Manifest struct {
+	Version     int                  `json:"version"`
+	CreatedAt   time.Time            `json:"created_at"`
+	Database    string               `json:"database"`
+	Format      string               `json:"format"`
+	Snapshot    bool                 `json:"snapshot"`
+	Collections []CollectionManifest `json:"collections"`
+}
+
+// CollectionManifest is the file of one collection in the archive.
+type CollectionManifest struct {
+	Name   string `json:"name"`
+	File   string `json:"file"`
+	Count  int64  `json:"count"`
+	SHA256 string `json:"sha256"`
+}
+
+// Options of a backup. Database and Snapshot are only recorded in the
+// manifest; whether the reads share a snapshot depends on the Store.
+type Options struct {
+	Database    string
+	Format      string
+	Snapshot    bool
+	Collections []string
+}
+
+// RestoreOptions of a restore. With ShopID only the documents whose shop_id
+// is ShopID are restored, so the collections without one, like categories,
+// are left as they are. Drop removes, after restoring, the documents that are
+// not in the archive, only those of ShopID when given, so the ones created
+// after the backup go too.
+type RestoreOptions struct {
+	ShopID string
+	Drop   bool
+	DryRun bool
+}
+
+// RestoreResult tells what Restore wrote, or would write on a dry run.
+type RestoreResult struct {
+	DryRun      bool                 `json:"dry_run"`
+	ShopID      string               `json:"shop_id,omitempty"`
+	Manifest    Manifest             `json:"manifest"`
+	Collections []RestoredCollection `json:"collections"`
+}
+
+// RestoredCollection counts the documents of a collection that were restored,
+// skipped because they belong to another shop, and removed by Drop because
+// the archive doesn't have them.
+type RestoredCollection struct {
+	Name     string `json:"name"`
+	Restored int64  `json:"restored"`
+	Skipped  int64  `json:"skipped"`
+	Removed  int64  `json:"removed"`
+}
+
+// Backup writes the archive of the collections to w. The collection files are
+// staged in temporary files, since the manifest with their checksums goes
+// first.
+func Backup(ctx context.Context, store Store, w io.Writer, opts Options) (Manifest, error) {
+	extension, err := fileExtension(opts.Format)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	manifest := Manifest{
+		Version:     manifestVersion,
+		CreatedAt:   time.Now().UTC(),
+		Database:    opts.Database,
+		Format:      opts.Format,
+		Snapshot:    opts.Snapshot,
+		Collections: []CollectionManifest{},
+	}
+
+	var files []*os.File
+	defer func() {
+		for _, file := range files {
+			removeTemp(file)
+		}
+	}()
+
+	for _, name := range opts.Collections {
+		file, err := os.CreateTemp("", "items-backup-*")
+		if err != nil {
+			return Manifest{}, err
+		}
+		files = append(files, file)
+
+		hash := sha256.New()
+		out := bufio.NewWriter(io.MultiWriter(file, hash))
+
+		var count int64
+		err = store.Each(ctx, name, func(doc bson.Raw) error {
+			count++
+			return encode(out, doc, opts.Format)
+		})
+		if err != nil {
+			return Manifest{}, fmt.Errorf("error reading the collection %s: %w", name, err)
+		}
+		if err = out.Flush(); err != nil {
+			return Manifest{}, err
+		}
+
+		manifest.Collections = append(manifest.Collections, CollectionManifest{
+			Name:   name,
+			File:   name + extension,
+			Count:  count,
+			SHA256: hex.EncodeToString(hash.Sum(nil)),
+		})
+	}
+
+	compressed := gzip.NewWriter(w)
+	archive := tar.NewWriter(compressed)
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return Manifest{}, err
+	}
+	if err = addFile(archive, manifestName, int64(len(content)), manifest.CreatedAt, bytes.NewReader(content)); err != nil {
+		return Manifest{}, err
+	}
+
+	for idx, file := range files {
+		size, err := file.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return Manifest{}, err
+		}
+		if _, err = file.Seek(0, io.SeekStart); err != nil {
+			return Manifest{}, err
+		}
+		if err = addFile(archive, manifest.Collections[idx].File, size, manifest.CreatedAt, file); err != nil {
+			return Manifest{}, err
+		}
+	}
+
+	if err = archive.Close(); err != nil {
+		return Manifest{}, err
+	}
+	if err = compressed.Close(); err != nil {
+		return Manifest{}, err
+	}
+
+	return manifest, nil
+}
+
+// Restore writes the documents of the archive read from r. It checks the
+// whole archive first and fails without writing when any file doesn't match
+// the manifest. The writes are not atomic: a restore that fails halfway can be
+// run again, since the documents are replaced by _id. Drop only removes once
+// the collection is restored, so a failed restore never leaves it emptied.
+func Restore(ctx context.Context, store Store, r io.Reader, opts RestoreOptions) (RestoreResult, error) {
+	manifest, files, err := extract(r)
+	defer func() {
+		for _, file := range files {
+			removeTemp(file)
+		}
+	}()
+	if err != nil {
+		return RestoreResult{}, err
+	}
+
+	result := RestoreResult{DryRun: opts.DryRun, ShopID: opts.ShopID, Manifest: manifest, Collections: []RestoredCollection{}}
+
+	archived := make(map[string]map[string]bool, len(manifest.Collections))
+	for _, collection := range manifest.Collections {
+		restored := RestoredCollection{Name: collection.Name}
+		ids := map[string]bool{}
+
+		var count int64
+		err = decode(files[collection.File], manifest.Format, func(doc bson.Raw) error {
+			count++
+			if matchesShop(doc, opts.ShopID) {
+				restored.Restored++
+				ids[doc.Lookup("_id").String()] = true
+			} else {
+				restored.Skipped++
+			}
+			return nil
+		})
+		if err != nil {
+			return RestoreResult{}, fmt.Errorf("error reading %s: %w", collection.File, err)
+		}
+
+		if count != collection.Count {
+			return RestoreResult{}, fmt.Errorf("%s has %d documents and the manifest says %d", collection.File, count, collection.Count)
+		}
+
+		archived[collection.Name] = ids
+		result.Collections = append(result.Collections, restored)
+	}
+
+	for idx, collection := range manifest.Collections {
+		if !opts.DryRun {
+			if err = restoreCollection(ctx, store, collection.Name, files[collection.File], manifest.Format, opts.ShopID); err != nil {
+				return result, fmt.Errorf("error restoring %s: %w", collection.Name, err)
+			}
+		}
+
+		if !opts.Drop {
+			continue
+		}
+
+		stale, err := staleIDs(ctx, store, collection.Name, archived[collection.Name], opts.ShopID)
+		if err != nil {
+			return result, fmt.Errorf("error reading the documents of %s: %w", collection.Name, err)
+		}
+		if opts.DryRun {
+			result.Collections[idx].Removed = int64(len(stale))
+			continue
+		}
+
+		for start := 0; start < len(stale); start += restoreBatch {
+			batch := stale[start:min(start+restoreBatch, len(stale))]
+			removed, err := store.Remove(ctx, collection.Name, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: batch}}}})
+			result.Collections[idx].Removed += removed
+			if err != nil {
+				return result, fmt.Errorf("error removing the documents of %s: %w", collection.Name, err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// staleIDs returns the _id of the documents of the collection, those of
+// shopID when given, that are not in archived.
+func staleIDs(ctx context.Context, store Store, collection string, archived map[string]bool, shopID string) (bson.A, error) {
+	stale := bson.A{}
+	err := store.Each(ctx, collection, func(doc bson.Raw) error {
+		id := doc.Lookup("_id")
+		if matchesShop(doc, shopID) && !archived[id.String()] {
+			stale = append(stale, bson.RawValue{Type: id.Type, Value: append([]byte(nil), id.Value...)})
+		}
+		return nil
+	})
+
+	return stale, err
+}
+
+func restoreCollection(ctx context.Context, store Store, name string, file *os.File, format string, shopID string) error {
+	var batch []bson.Raw
+	err := decode(file, format, func(doc bson.Raw) error {
+		if !matchesShop(doc, shopID) {
+			return nil
+		}
+
+		batch = append(batch, doc)
+		if len(batch) < restoreBatch {
+			return nil
+		}
+
+		err := store.Replace(ctx, name, batch)
+		batch = nil
+		return err
+	})
+	if err != nil || len(batch) == 0 {
+		return err
+	}
+
+	return store.Replace(ctx, name, batch)
+}
+
+// extract copies the files of the archive to temporary files, checking them
+// against the manifest. The files are returned by name even on errors, for
+// the caller to remove them.
+func extract(r io.Reader) (Manifest, map[string]*os.File, error) {
+	files := map[string]*os.File{}
+
+	compressed, err := gzip.NewReader(r)
+	if err != nil {
+		return Manifest{}, files, fmt.Errorf("the backup is not a gzip file: %w", err)
+	}
+	defer compressed.Close()
+	archive := tar.NewReader(compressed)
+
+	header, err := archive.Next()
+	if err != nil || header.Name != manifestName {
+		return Manifest{}, files, fmt.Errorf("the backup must start with %s", manifestName)
+	}
+
+	var manifest Manifest
+	if err = json.NewDecoder(archive).Decode(&manifest); err != nil {
+		return Manifest{}, files, fmt.Errorf("error reading %s: %w", manifestName, err)
+	}
+	if manifest.Version != manifestVersion {
+		return Manifest{}, files, fmt.Errorf("backups of version %d are not supported", manifest.Version)
+	}
+	if _, err = fileExtension(manifest.Format); err != nil {
+		return Manifest{}, files, err
+	}
+
+	expected := make(map[string]CollectionManifest, len(manifest.Collections))
+	for _, collection := range manifest.Collections {
+		expected[collection.File] = collection
+	}
+
+	for {
+		header, err = archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Manifest{}, files, err
+		}
+
+		collection, found := expected[header.Name]
+		if !found {
+			return Manifest{}, files, fmt.Errorf("%s is not in the manifest", header.Name)
+		}
+		if _, taken := files[header.Name]; taken {
+			return Manifest{}, files, fmt.Errorf("%s is twice in the backup", header.Name)
+		}
+
+		file, err := os.CreateTemp("", "items-restore-*")
+		if err != nil {
+			return Manifest{}, files, err
+		}
+		files[header.Name] = file
+
+		hash := sha256.New()
+		if _, err = io.Copy(io.MultiWriter(file, hash), archive); err != nil {
+			return Manifest{}, files, fmt.Errorf("error reading %s: %w", header.Name, err)
+		}
+		if sum := hex.EncodeToString(hash.Sum(nil)); sum != collection.SHA256 {
+			return Manifest{}, files, fmt.Errorf("the checksum of %s is %s and the manifest says %s", header.Name, sum, collection.SHA256)
+		}
+	}
+
+	for name := range expected {
+		if _, found := files[name]; !found {
+			return Manifest{}, files, fmt.Errorf("%s is missing from the backup", name)
+		}
+	}
+
+	return manifest, files, nil
+}
+
+func encode(w io.Writer, doc bson.Raw, format string) error {
+	if format == FormatBSON {
+		_, err := w.Write(doc)
+		return err
+	}
+
+	line, err := bson.MarshalExtJSON(doc, true, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(line, '\n'))
+	return err
+}
+
+// decode calls fn with every document of the file, from its start.
+func decode(file *os.File, format string, fn func(doc bson.Raw) error) error {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if format == FormatBSON {
+		reader := bufio.NewReader(file)
+		for {
+			doc, err := bson.NewFromIOReader(reader)
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err = fn(doc); err != nil {
+				return err
+			}
+		}
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxLineSize)
+	for scanner.Scan() {
+		var doc bson.Raw
+		if err := bson.UnmarshalExtJSON(scanner.Bytes(), true, &doc); err != nil {
+			return err
+		}
+		if err := fn(doc); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func matchesShop(doc bson.Raw, shopID string) bool {
+	if shopID == "" {
+		return true
+	}
+
+	value, err := doc.LookupErr("shop_id")
+	if err != nil {
+		return false
+	}
+
+	id, ok := value.StringValueOK()
+	return ok && id == shopID
+}
+
+func fileExtension(format string) (string, error) {
+	switch format {
+	case FormatBSON:
+		return ".bson", nil
+	case FormatJSON:
+		return ".jsonl", nil
+	default:
+		return "", fmt.Errorf("the format must be %s or %s, got %q", FormatBSON, FormatJSON, format)
+	}
+}
+
+func addFile(archive *tar.Writer, name string, size int64, modified time.Time, content io.Reader) error {
+	header := &tar.Header{Name: name, Mode: 0o644, Size: size, ModTime: modified, Typeflag: tar.TypeReg}
+	if err := archive.WriteHeader(header); err != nil {
+		return err
+	}
+
+	_, err := io.Copy(archive, content)
+	return err
+}
+
+func removeTemp(file *os.File) {
+	_ = file.Close()
+	_ = os.Remove(file.Name())
+}
@@ -0,0 +1,77 @@
+package backup
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoStore struct {
+	database *mongo.Database
+	session  mongo.Session
+}
+
+// NewMongoStore reads and writes the collections of database. With snapshot
+// every read sees the data as it was at the first one, so a backup is
+// consistent across collections; that needs a replica set on MongoDB 5.0 or
+// later, and the reads fail with SnapshotTooOld once the snapshot is older than
+// minSnapshotHistoryWindowInSeconds, 300 by default. The returned func ends
+// the snapshot.
+func NewMongoStore(database *mongo.Database, snapshot bool) (Store, func(), error) {
+	store := &mongoStore{database: database}
+	if !snapshot {
+		return store, func() {}, nil
+	}
+
+	session, err := database.Client().StartSession(options.Session().SetSnapshot(true))
+	if err != nil {
+		return nil, nil, err
+	}
+	store.session = session
+
+	return store, func() { session.EndSession(context.Background()) }, nil
+}
+
+func (s *mongoStore) Each(ctx context.Context, collection string, fn func(doc bson.Raw) error) error {
+	if s.session != nil {
+		ctx = mongo.NewSessionContext(ctx, s.session)
+	}
+
+	cursor, err := s.database.Collection(collection).Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		if err = fn(cursor.Current); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+func (s *mongoStore) Replace(ctx context.Context, collection string, docs []bson.Raw) error {
+	writes := make([]mongo.WriteModel, 0, len(docs))
+	for _, doc := range docs {
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: doc.Lookup("_id")}}).
+			SetReplacement(doc).
+			SetUpsert(true))
+	}
+
+	_, err := s.database.Collection(collection).BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (s *mongoStore) Remove(ctx context.Context, collection string, filter bson.D) (int64, error) {
+	result, err := s.database.Collection(collection).DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,85 @@
+// Package devservices runs in memory the downstream APIs the items API
+// depends on, prices and shops, so it can be started on a laptop with only a
+// local Mongo. Callers identify themselves with an "Authorization: Dev
+// <user>" header instead of a Firebase token.
+package devservices
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/logger"
+)
+
+// AuthScheme is the scheme of the dev Authorization header.
+const AuthScheme = "Dev"
+
+const shutdownTimeout = 5 * time.Second
+
+var ErrInvalidAuthorization = errors.New(`the Authorization header must look like "Dev <user>"`)
+
+// UserID returns the user of a dev Authorization header.
+func UserID(authorization string) (string, error) {
+	scheme, user, found := strings.Cut(strings.TrimSpace(authorization), " ")
+	if !found || !strings.EqualFold(scheme, AuthScheme) || strings.TrimSpace(user) == "" {
+		return "", ErrInvalidAuthorization
+	}
+
+	return strings.TrimSpace(user), nil
+}
+
+// ShopID is the shop of a dev user. It is derived from the user, so the
+// items saved in Mongo keep their shop across restarts.
+func ShopID(userID string) string {
+	sum := sha256.Sum256([]byte(userID))
+	return hex.EncodeToString(sum[:12])
+}
+
+// Worker serves one of the fake APIs next to the items API and shuts it
+// down with the other workers.
+type Worker struct {
+	name     string
+	server   *http.Server
+	listener net.Listener
+}
+
+func NewWorker(name string, handler http.Handler, listener net.Listener) *Worker {
+	return &Worker{name: name, server: &http.Server{Handler: handler}, listener: listener}
+}
+
+// URL is where the fake API is served.
+func (w *Worker) URL() string {
+	return "http://" + w.listener.Addr().String()
+}
+
+// Close releases the port of a worker that never ran, like when the startup
+// fails after the dev services are listening. It is a no-op after Run.
+func (w *Worker) Close() {
+	_ = w.listener.Close()
+}
+
+func (w *Worker) Run(ctx context.Context) {
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := w.server.Shutdown(shutdownCtx); err != nil {
+			logger.Error("Error stopping the dev "+w.name+" service", err)
+		}
+	}()
+
+	if err := w.server.Serve(w.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("Error serving the dev "+w.name+" service", err)
+	}
+
+	<-stopped
+}
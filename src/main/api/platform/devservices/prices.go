@@ -0,0 +1,156 @@
+package devservices
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Prices is the fake prices API, with the endpoints clients.PriceClient
+// calls. Prices are kept by item, so they are lost on restart while the
+// items stay in Mongo.
+type Prices struct {
+	mu     sync.RWMutex
+	byItem map[string]models.Price
+}
+
+func NewPrices() *Prices {
+	return &Prices{byItem: map[string]models.Price{}}
+}
+
+func (p *Prices) Handler() http.Handler {
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	router.GET("/ping", ping)
+	router.GET("/prices/item/:id", p.getByItem)
+	router.GET("/prices/items", p.listItems)
+	router.POST("/prices/items", p.getByItems)
+	router.POST("/prices", p.create)
+	router.PUT("/prices/:id", p.update)
+	router.DELETE("/prices/item/:id", p.deleteByItem)
+
+	return router
+}
+
+func (p *Prices) getByItem(c *gin.Context) {
+	p.mu.RLock()
+	price, found := p.byItem[c.Param("id")]
+	p.mu.RUnlock()
+
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"message": "price not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, price)
+}
+
+// getByItems leaves out the items without price.
+func (p *Prices) getByItems(c *gin.Context) {
+	var input dto.RequestItemsList
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	prices := models.Prices{Prices: []models.Price{}}
+
+	p.mu.RLock()
+	for _, itemID := range input.ItemsIDs {
+		if price, found := p.byItem[itemID]; found {
+			prices.Prices = append(prices.Prices, price)
+		}
+	}
+	p.mu.RUnlock()
+
+	c.JSON(http.StatusOK, prices)
+}
+
+// listItems pages through the items with a price, sorted.
+func (p *Prices) listItems(c *gin.Context) {
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if offset < 0 || limit < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "offset can't be negative and limit must be at least 1"})
+		return
+	}
+
+	p.mu.RLock()
+	itemIDs := make([]string, 0, len(p.byItem))
+	for itemID := range p.byItem {
+		itemIDs = append(itemIDs, itemID)
+	}
+	p.mu.RUnlock()
+
+	sort.Strings(itemIDs)
+	page := models.PricedItems{ItemIDs: []string{}, Total: int64(len(itemIDs))}
+	if offset < len(itemIDs) {
+		page.ItemIDs = itemIDs[offset:min(offset+limit, len(itemIDs))]
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func (p *Prices) create(c *gin.Context) {
+	var price models.Price
+	if err := c.ShouldBindJSON(&price); err != nil || price.ItemID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "the price needs an item_id"})
+		return
+	}
+
+	price.ID = primitive.NewObjectID().Hex()
+
+	p.mu.Lock()
+	p.byItem[price.ItemID] = price
+	p.mu.Unlock()
+
+	c.JSON(http.StatusCreated, price)
+}
+
+func (p *Prices) update(c *gin.Context) {
+	var price models.Price
+	if err := c.ShouldBindJSON(&price); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	current, found := p.byItem[price.ItemID]
+	if !found || current.ID != c.Param("id") {
+		c.JSON(http.StatusNotFound, gin.H{"message": "price not found"})
+		return
+	}
+
+	price.ID = current.ID
+	p.byItem[price.ItemID] = price
+
+	c.JSON(http.StatusOK, price)
+}
+
+func (p *Prices) deleteByItem(c *gin.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, found := p.byItem[c.Param("id")]; !found {
+		c.JSON(http.StatusNotFound, gin.H{"message": "price not found"})
+		return
+	}
+
+	delete(p.byItem, c.Param("id"))
+
+	c.Status(http.StatusNoContent)
+}
+
+func ping(c *gin.Context) {
+	c.String(http.StatusOK, "pong")
+}
@@ -0,0 +1,38 @@
+package devservices
+
+import (
+	"net/http"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jopitnow/go-jopit-toolkit/goauth"
+)
+
+// Shops is the fake shops API, with the endpoint clients.ShopClient calls.
+// Every dev user has a shop, see ShopID.
+type Shops struct{}
+
+func NewShops() *Shops {
+	return &Shops{}
+}
+
+func (s *Shops) Handler() http.Handler {
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	router.GET("/ping", ping)
+	router.GET("/shops", s.getByUser)
+
+	return router
+}
+
+func (s *Shops) getByUser(c *gin.Context) {
+	userID, err := UserID(c.GetHeader(goauth.FirebaseAuthHeader))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Shop{ID: ShopID(userID)})
+}
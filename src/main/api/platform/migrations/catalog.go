@@ -0,0 +1,155 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// indexNotFoundCode is the Mongo error code of dropping a missing index.
+const indexNotFoundCode = 27
+
+// Collections are the collections the migrations change.
+type Collections struct {
+	Items       *mongo.Collection
+	Categories  *mongo.Collection
+	Collections *mongo.Collection
+	Revisions   *mongo.Collection
+	Audit       *mongo.Collection
+}
+
+// Catalog is every migration of the API. New ones are appended with the next
+// version; applied ones must not change.
+func Catalog(c Collections) []Migration {
+	return []Migration{
+		indexes(1, "items_indexes", c.Items,
+			// also serves the queries by shop_id alone, as its prefix
+			index("shop_id_category", bson.D{{Key: "shop_id", Value: 1}, {Key: "category._id", Value: 1}}),
+			index("user_id", bson.D{{Key: "user_id", Value: 1}}),
+			index("category_id", bson.D{{Key: "category._id", Value: 1}}),
+			index("slug", bson.D{{Key: "slug", Value: 1}}),
+			index("slug_history", bson.D{{Key: "slug_history", Value: 1}}),
+		),
+		indexes(2, "categories_indexes", c.Categories,
+			index("name", bson.D{{Key: "name", Value: 1}}),
+			index("slug", bson.D{{Key: "slug", Value: 1}}),
+			index("slug_history", bson.D{{Key: "slug_history", Value: 1}}),
+		),
+		indexes(3, "collections_indexes", c.Collections,
+			index("shop_id_position_name", bson.D{{Key: "shop_id", Value: 1}, {Key: "position", Value: 1}, {Key: "name", Value: 1}}),
+		),
+		{
+			Version: 4,
+			Name:    "items_defaults",
+			Up: func(ctx context.Context) error {
+				return backfillItemsDefaults(ctx, c.Items)
+			},
+			// the defaults are valid values, reverting leaves them
+		},
+		// unique, as it numbers the revisions of each item
+		indexes(5, "revisions_indexes", c.Revisions,
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "item_id", Value: 1}, {Key: "revision", Value: 1}},
+				Options: options.Index().SetName("item_id_revision").SetUnique(true),
+			},
+		),
+		indexes(6, "audit_indexes", c.Audit,
+			index("created_at", bson.D{{Key: "created_at", Value: -1}}),
+			index("actor_created_at", bson.D{{Key: "actor", Value: 1}, {Key: "created_at", Value: -1}}),
+			index("action_created_at", bson.D{{Key: "action", Value: 1}, {Key: "created_at", Value: -1}}),
+			index("target_created_at", bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "created_at", Value: -1}}),
+		),
+		// closes the race of two items checking the same free slug; legacy
+		// items without a slug are left out. Fails while duplicates exist.
+		replaceIndex(7, "items_unique_slug", c.Items, index("slug", bson.D{{Key: "slug", Value: 1}}),
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "slug", Value: 1}},
+				Options: options.Index().SetName("slug_unique").SetUnique(true).
+					SetPartialFilterExpression(bson.D{{Key: "slug", Value: bson.D{{Key: "$gt", Value: ""}}}}),
+			},
+		),
+	}
+}
+
+func index(name string, keys bson.D) mongo.IndexModel {
+	return mongo.IndexModel{Keys: keys, Options: options.Index().SetName(name)}
+}
+
+// indexes creates the given indexes and drops them on Down. Creating an index
+// that already exists with the same keys and name does nothing.
+func indexes(version int, name string, collection *mongo.Collection, specs ...mongo.IndexModel) Migration {
+	return Migration{
+		Version: version,
+		Name:    name,
+		indexes: &indexSet{collection: collection, specs: specs},
+		Up: func(ctx context.Context) error {
+			_, err := collection.Indexes().CreateMany(ctx, specs)
+			return err
+		},
+		Down: func(ctx context.Context) error {
+			for _, spec := range specs {
+				if err := dropIndex(ctx, collection, *spec.Options.Name); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// replaceIndex swaps the old index for the new one on Up and back on Down.
+// The new one is created first, so the collection is never left unindexed.
+func replaceIndex(version int, name string, collection *mongo.Collection, old mongo.IndexModel, replacement mongo.IndexModel) Migration {
+	swap := func(ctx context.Context, from mongo.IndexModel, to mongo.IndexModel) error {
+		if _, err := collection.Indexes().CreateOne(ctx, to); err != nil {
+			return err
+		}
+		return dropIndex(ctx, collection, *from.Options.Name)
+	}
+
+	return Migration{
+		Version: version,
+		Name:    name,
+		indexes: &indexSet{collection: collection, specs: []mongo.IndexModel{replacement}, dropped: []string{*old.Options.Name}},
+		Up: func(ctx context.Context) error {
+			return swap(ctx, old, replacement)
+		},
+		Down: func(ctx context.Context) error {
+			return swap(ctx, replacement, old)
+		},
+	}
+}
+
+// dropIndex drops the named index, if it exists.
+func dropIndex(ctx context.Context, collection *mongo.Collection, name string) error {
+	_, err := collection.Indexes().DropOne(ctx, name)
+
+	var commandErr mongo.CommandError
+	if err != nil && !(errors.As(err, &commandErr) && commandErr.Code == indexNotFoundCode) {
+		return err
+	}
+	return nil
+}
+
+// backfillItemsDefaults sets the status and eligible of the items saved
+// before they were required.
+func backfillItemsDefaults(ctx context.Context, items *mongo.Collection) error {
+	_, err := items.UpdateMany(ctx,
+		bson.M{"$or": []bson.M{{"status": bson.M{"$exists": false}}, {"status": ""}}},
+		bson.M{"$set": bson.M{"status": models.ItemStatusActive}},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = items.UpdateMany(ctx,
+		bson.M{"eligible": nil},
+		bson.M{"$set": bson.M{"eligible": bson.A{}}},
+	)
+	return err
+}
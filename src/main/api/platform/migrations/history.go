@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoHistory struct {
+	collection *mongo.Collection
+}
+
+// NewMongoHistory keeps the history in collection, one document per applied
+// migration with the version as _id.
+func NewMongoHistory(collection *mongo.Collection) History {
+	return &mongoHistory{collection: collection}
+}
+
+func (h *mongoHistory) Applied(ctx context.Context) ([]Record, error) {
+	cursor, err := h.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	if err = cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// Add ignores the duplicates: they come from another instance that applied
+// the same migration at the same time.
+func (h *mongoHistory) Add(ctx context.Context, record Record) error {
+	_, err := h.collection.InsertOne(ctx, record)
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (h *mongoHistory) Remove(ctx context.Context, version int) error {
+	_, err := h.collection.DeleteOne(ctx, bson.M{"_id": version})
+	return err
+}
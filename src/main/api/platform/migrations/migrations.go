@@ -0,0 +1,224 @@
+// Package migrations applies versioned changes to the database schema, like
+// indexes and backfills, and records the applied ones in the
+// schema_migrations collection. Migrations run in version order, at startup
+// or with items-admin, and can be reverted from the last one.
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/logger"
+)
+
+// Migration is one versioned change. Up must be safe to run again, because a
+// migration that fails halfway is not recorded and runs again next time, and
+// because several instances may start at once. Down may be nil when there is
+// nothing to revert.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context) error
+	Down    func(ctx context.Context) error
+
+	// indexes are the indexes the migration creates, see Reindex.
+	indexes *indexSet
+}
+
+// Record is an applied migration, as kept in the history.
+type Record struct {
+	Version   int       `json:"version" bson:"_id"`
+	Name      string    `json:"name" bson:"name"`
+	AppliedAt time.Time `json:"applied_at" bson:"applied_at"`
+}
+
+// History stores which migrations were applied.
+type History interface {
+	// Applied returns the records sorted by version.
+	Applied(ctx context.Context) ([]Record, error)
+	Add(ctx context.Context, record Record) error
+	Remove(ctx context.Context, version int) error
+}
+
+// Status tells whether a migration was applied. Unknown is set for the
+// records of migrations this build doesn't have, e.g. after a rollback.
+type Status struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	Unknown   bool       `json:"unknown,omitempty"`
+}
+
+type Migrator struct {
+	history    History
+	migrations []Migration
+}
+
+// NewMigrator sorts migrations by version and fails when two share one or a
+// version is not positive.
+func NewMigrator(history History, migrations ...Migration) (*Migrator, error) {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	for idx, migration := range sorted {
+		if migration.Version <= 0 {
+			return nil, fmt.Errorf("migration %q must have a positive version", migration.Name)
+		}
+		if idx > 0 && sorted[idx-1].Version == migration.Version {
+			return nil, fmt.Errorf("migrations %q and %q share the version %d", sorted[idx-1].Name, migration.Name, migration.Version)
+		}
+		if migration.Up == nil {
+			return nil, fmt.Errorf("migration %d %s has no Up", migration.Version, migration.Name)
+		}
+	}
+
+	return &Migrator{history: history, migrations: sorted}, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if record, found := applied[migration.Version]; found {
+			status.Applied = true
+			status.AppliedAt = &record.AppliedAt
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, record := range applied {
+		record := record
+		statuses = append(statuses, Status{Version: record.Version, Name: record.Name, Applied: true, AppliedAt: &record.AppliedAt, Unknown: true})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// PlanUp returns the pending migrations up to target, all of them when target
+// is 0, in the order Up would apply them.
+func (m *Migrator) PlanUp(ctx context.Context, target int) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if target > 0 && migration.Version > target {
+			break
+		}
+		if _, found := applied[migration.Version]; !found {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// Up applies the pending migrations up to target, all of them when target is
+// 0, and returns the applied ones. It stops at the first failure.
+func (m *Migrator) Up(ctx context.Context, target int) ([]Migration, error) {
+	pending, err := m.PlanUp(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range pending {
+		if err = migration.Up(ctx); err != nil {
+			return done, fmt.Errorf("error applying migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+
+		record := Record{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().UTC()}
+		if err = m.history.Add(ctx, record); err != nil {
+			return done, fmt.Errorf("error recording migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+
+		logger.Infof("Migration %d %s applied", migration.Version, migration.Name)
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// PlanDown returns the last steps applied migrations, newest first, in the
+// order Down would revert them. It fails when one of them is unknown to this
+// build.
+func (m *Migrator) PlanDown(ctx context.Context, steps int) ([]Migration, error) {
+	records, err := m.history.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[int]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	var plan []Migration
+	for idx := len(records) - 1; idx >= 0 && len(plan) < steps; idx-- {
+		migration, found := known[records[idx].Version]
+		if !found {
+			return nil, fmt.Errorf("migration %d %s is not known by this build, revert it with the build that applied it", records[idx].Version, records[idx].Name)
+		}
+		plan = append(plan, migration)
+	}
+
+	return plan, nil
+}
+
+// Down reverts the last steps applied migrations and returns the reverted
+// ones. It stops at the first failure.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	plan, err := m.PlanDown(ctx, steps)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range plan {
+		if migration.Down != nil {
+			if err = migration.Down(ctx); err != nil {
+				return done, fmt.Errorf("error reverting migration %d %s: %w", migration.Version, migration.Name, err)
+			}
+		}
+
+		if err = m.history.Remove(ctx, migration.Version); err != nil {
+			return done, fmt.Errorf("error recording the revert of migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+
+		logger.Infof("Migration %d %s reverted", migration.Version, migration.Name)
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int]Record, error) {
+	records, err := m.history.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]Record, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,87 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Statuses of the indexes reported by Reindex.
+const (
+	IndexPresent = "present"
+	IndexCreated = "created"
+	IndexMissing = "missing"
+)
+
+type indexSet struct {
+	collection *mongo.Collection
+	specs      []mongo.IndexModel
+	// dropped are the indexes of earlier migrations this one replaced
+	dropped []string
+}
+
+// IndexStatus is the state of one of the indexes created by the migrations.
+type IndexStatus struct {
+	Collection string `json:"collection"`
+	Index      string `json:"index"`
+	Status     string `json:"status"`
+}
+
+// Reindex creates again the indexes of the applied migrations that are
+// missing, e.g. dropped by hand. With dryRun it only reports them. Indexes of
+// pending migrations are left to Up.
+func (m *Migrator) Reindex(ctx context.Context, dryRun bool) ([]IndexStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the indexes replaced by an applied migration are not missing
+	replaced := map[string]bool{}
+	for _, migration := range m.migrations {
+		if _, found := applied[migration.Version]; found && migration.indexes != nil {
+			for _, name := range migration.indexes.dropped {
+				replaced[migration.indexes.collection.Name()+"."+name] = true
+			}
+		}
+	}
+
+	statuses := []IndexStatus{}
+	for _, migration := range m.migrations {
+		if _, found := applied[migration.Version]; !found || migration.indexes == nil {
+			continue
+		}
+
+		collection := migration.indexes.collection
+		specifications, err := collection.Indexes().ListSpecifications(ctx)
+		if err != nil {
+			return statuses, fmt.Errorf("error listing the indexes of %s: %w", collection.Name(), err)
+		}
+
+		existing := make(map[string]bool, len(specifications))
+		for _, specification := range specifications {
+			existing[specification.Name] = true
+		}
+
+		for _, spec := range migration.indexes.specs {
+			if replaced[collection.Name()+"."+*spec.Options.Name] {
+				continue
+			}
+
+			status := IndexStatus{Collection: collection.Name(), Index: *spec.Options.Name, Status: IndexPresent}
+			if !existing[status.Index] {
+				status.Status = IndexMissing
+				if !dryRun {
+					if _, err = collection.Indexes().CreateOne(ctx, spec); err != nil {
+						return statuses, fmt.Errorf("error creating the index %s of %s: %w", status.Index, status.Collection, err)
+					}
+					status.Status = IndexCreated
+				}
+			}
+			statuses = append(statuses, status)
+		}
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,98 @@
+package problems
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+func init() {
+	// report the json names of the fields instead of the Go ones
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// BindError describes why binding.JSON rejected a body, with one field error
+// per invalid member when the decoder or the validator tell which.
+func BindError(err error) apierrors.ApiError {
+	var (
+		validationErrs validator.ValidationErrors
+		typeErr        *json.UnmarshalTypeError
+		syntaxErr      *json.SyntaxError
+	)
+
+	switch {
+	case errors.As(err, &validationErrs):
+		fields := make([]interface{}, 0, len(validationErrs))
+		details := make([]string, 0, len(validationErrs))
+		for _, validationErr := range validationErrs {
+			field := FieldError{
+				Path:   jsonPath(validationErr.Namespace()),
+				Code:   validationErr.Tag(),
+				Detail: validationDetail(validationErr),
+			}
+			fields = append(fields, field)
+			details = append(details, field.Path+" "+field.Detail)
+		}
+		return catalog.ValidationFailed.New("invalid request body: "+strings.Join(details, "; "), fields...)
+
+	case errors.As(err, &typeErr):
+		field := FieldError{
+			Path:   "$." + typeErr.Field,
+			Code:   "invalid_type",
+			Detail: fmt.Sprintf("must be a %s, got %s", typeErr.Type, typeErr.Value),
+		}
+		return catalog.ValidationFailed.New("invalid request body: "+field.Path+" "+field.Detail, field)
+
+	case errors.As(err, &syntaxErr):
+		return catalog.ValidationFailed.New(fmt.Sprintf("the request body is not valid JSON at offset %d", syntaxErr.Offset))
+
+	case errors.Is(err, io.EOF):
+		return catalog.ValidationFailed.New("the request body is empty")
+	}
+
+	return catalog.ValidationFailed.New("invalid request body: " + err.Error())
+}
+
+// jsonPath turns a validator namespace, ItemDTO.attributes[0].name, into a
+// JSON path, $.attributes[0].name.
+func jsonPath(namespace string) string {
+	if i := strings.IndexByte(namespace, '.'); i >= 0 {
+		return "$" + namespace[i:]
+	}
+	return "$"
+}
+
+func validationDetail(err validator.FieldError) string {
+	switch err.Tag() {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		return "must be at least " + err.Param()
+	case "max", "lte":
+		return "must be at most " + err.Param()
+	case "oneof":
+		return "must be one of " + err.Param()
+	}
+
+	return "failed the " + err.Tag() + " validation"
+}
@@ -0,0 +1,203 @@
+package problems
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/jopitnow/go-jopit-toolkit/tracing"
+)
+
+// MIMEProblemJSON is the media type of RFC 7807 problem details.
+const MIMEProblemJSON = "application/problem+json"
+
+const enforceKey = "problems.enforce"
+
+// Problem is an RFC 7807 problem detail, extended with the catalog code, the
+// trace ID and the invalid fields.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	TraceID  string       `json:"trace_id,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError points at an invalid member of the request body with a JSON
+// path, like $.attributes[0].name.
+type FieldError struct {
+	Path   string `json:"path"`
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+}
+
+// New converts err into a problem for the request being served.
+func New(c *gin.Context, err apierrors.ApiError) Problem {
+	definition := definitionFor(err)
+
+	problem := Problem{
+		Type:     definition.Type,
+		Title:    definition.Title,
+		Status:   err.Status(),
+		Detail:   err.Message(),
+		Instance: c.Request.URL.RequestURI(),
+		Code:     definition.Code,
+	}
+
+	if traceID, exists := c.Get(tracing.XtraceHeaderKey); exists && traceID != nil {
+		problem.TraceID = fmt.Sprint(traceID)
+	}
+
+	// other causes carry internal details that are only logged
+	for _, cause := range err.Cause() {
+		if fieldErr, ok := cause.(FieldError); ok {
+			problem.Errors = append(problem.Errors, fieldErr)
+		}
+	}
+
+	return problem
+}
+
+// definitionFor describes errors whose code is not in the catalog, like the
+// ones built by the toolkit middlewares, from their status alone.
+func definitionFor(err apierrors.ApiError) catalog.Definition {
+	if definition, ok := catalog.Lookup(err.Code()); ok {
+		return definition
+	}
+
+	return catalog.Definition{
+		Type:   "about:blank",
+		Code:   err.Code(),
+		Title:  http.StatusText(err.Status()),
+		Status: err.Status(),
+		Legacy: err.Code(),
+	}
+}
+
+// Respond writes err as problem+json when the client asks for it in Accept,
+// and in the apierrors shape otherwise, with the error value it had before
+// the catalog, so existing clients keep working.
+func Respond(c *gin.Context, err apierrors.ApiError) {
+	c.Header("Vary", "Accept")
+
+	if !c.GetBool(enforceKey) && c.NegotiateFormat(binding.MIMEJSON, MIMEProblemJSON) != MIMEProblemJSON {
+		c.JSON(err.Status(), catalog.Legacy(err))
+		return
+	}
+
+	c.Header("Content-Type", MIMEProblemJSON)
+	c.JSON(err.Status(), New(c, err))
+}
+
+// Abort responds like Respond and stops the handler chain.
+func Abort(c *gin.Context, err apierrors.ApiError) {
+	Respond(c, err)
+	c.Abort()
+}
+
+// Enforce makes Respond always answer problem+json, for the API versions that
+// never had the apierrors shape.
+func Enforce() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(enforceKey, true)
+	}
+}
+
+// Wrap runs a middleware that answers errors on its own, like the toolkit
+// AuthWithFirebase and PasswordMiddleware, and answers them through Respond.
+// It runs on a copy of the context, writing to a buffer: the error it writes
+// is decoded from the apierrors shape, and otherwise the keys and request it
+// sets are carried over before the chain goes on.
+func Wrap(middleware gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		buffer := &bufferedWriter{ResponseWriter: c.Writer, header: http.Header{}}
+		probe := c.Copy()
+		probe.Writer = buffer
+
+		middleware(probe)
+
+		if buffer.Status() >= http.StatusBadRequest {
+			Abort(c, decode(buffer))
+			return
+		}
+
+		for key, value := range probe.Keys {
+			c.Set(key, value)
+		}
+		c.Request = probe.Request
+	}
+}
+
+// decode reads the apierrors shape written by a toolkit middleware, falling
+// back to the status when the body is something else.
+func decode(buffer *bufferedWriter) apierrors.ApiError {
+	var body struct {
+		Message string              `json:"message"`
+		Error   string              `json:"error"`
+		Cause   apierrors.CauseList `json:"cause"`
+	}
+	if err := json.Unmarshal(buffer.body.Bytes(), &body); err != nil || body.Error == "" {
+		message := strings.TrimSpace(buffer.body.String())
+		if message == "" {
+			message = http.StatusText(buffer.Status())
+		}
+		return apierrors.NewApiError(message, strings.ToLower(strings.ReplaceAll(http.StatusText(buffer.Status()), " ", "_")), buffer.Status(), apierrors.CauseList{})
+	}
+
+	return apierrors.NewApiError(body.Message, body.Error, buffer.Status(), body.Cause)
+}
+
+// bufferedWriter keeps the response of a wrapped middleware instead of
+// sending it.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.status != 0
+}
@@ -0,0 +1,40 @@
+// Package scheduler runs background jobs on a fixed interval next to the
+// server.
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/logger"
+)
+
+// Worker runs its job every interval, the first time one interval after it
+// starts. A failed run is logged and the next one runs as usual. It returns
+// once ctx is cancelled, after the run in progress, whose ctx is cancelled
+// too.
+type Worker struct {
+	name     string
+	interval time.Duration
+	job      func(ctx context.Context) error
+}
+
+func Every(name string, interval time.Duration, job func(ctx context.Context) error) *Worker {
+	return &Worker{name: name, interval: interval, job: job}
+}
+
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.job(ctx); err != nil && ctx.Err() == nil {
+				logger.Error("Error running the scheduled "+w.name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package rpc
+
+import (
+	"github.com/agustinrabini/items-api-project/src/main/api/rpc/itemspb"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+)
+
+func toItem(item models.Item) *itemspb.Item {
+	images := make([]string, 0, len(item.Images))
+	for _, image := range item.Images {
+		images = append(images, string(image))
+	}
+
+	eligibles := make([]*itemspb.Eligible, 0, len(item.Eligible))
+	for _, eligible := range item.Eligible {
+		eligibles = append(eligibles, toEligible(eligible))
+	}
+
+	return &itemspb.Item{
+		Id:     item.ID,
+		Name:   item.Name,
+		Slug:   item.Slug,
+		ShopId: item.ShopID,
+		UserId: item.UserID,
+		Category: &itemspb.Category{
+			Id:   item.Category.ID,
+			Name: item.Category.Name,
+			Slug: item.Category.Slug,
+		},
+		Price:       toPrice(item.Price),
+		Description: item.Description,
+		Status:      item.Status,
+		Images:      images,
+		Attributes:  item.Attributes,
+		Eligible:    eligibles,
+	}
+}
+
+func toEligible(eligible models.Eligible) *itemspb.Eligible {
+	options := make([]string, 0, len(eligible.Options))
+	for _, option := range eligible.Options {
+		options = append(options, string(option))
+	}
+
+	return &itemspb.Eligible{
+		Id:         eligible.ID,
+		Title:      eligible.Title,
+		Type:       eligible.Type,
+		IsRequired: eligible.IsRequired,
+		Options:    options,
+	}
+}
+
+func toPrice(price models.Price) *itemspb.Price {
+	return &itemspb.Price{
+		Id:     price.ID,
+		ItemId: price.ItemID,
+		Amount: price.Amount,
+		Currency: &itemspb.Currency{
+			Id:               price.Currency.ID,
+			Symbol:           price.Currency.Symbol,
+			DecimalDivider:   price.Currency.DecimalDivider,
+			ThousandsDivider: price.Currency.ThousandsDivider,
+		},
+	}
+}
+
+func toSelections(selections []*itemspb.Selection) []models.Selection {
+	result := make([]models.Selection, 0, len(selections))
+	for _, selection := range selections {
+		result = append(result, models.Selection{
+			EligibleID: selection.GetEligibleId(),
+			Option:     selection.GetOption(),
+		})
+	}
+
+	return result
+}
+
+func toViolations(violations []models.SelectionViolation) []*itemspb.Violation {
+	result := make([]*itemspb.Violation, 0, len(violations))
+	for _, violation := range violations {
+		result = append(result, &itemspb.Violation{
+			EligibleId: violation.EligibleID,
+			Code:       violation.Code,
+			Detail:     violation.Detail,
+		})
+	}
+
+	return result
+}
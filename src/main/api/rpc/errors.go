@@ -0,0 +1,45 @@
+package rpc
+
+import (
+	"net/http"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of the ErrorInfo attached to every error, whose
+// reason is the catalog code also returned by the REST API.
+const ErrorDomain = "items-api"
+
+var codesByStatus = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnprocessableEntity: codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.AlreadyExists,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusBadGateway:          codes.Unavailable,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+	http.StatusGatewayTimeout:      codes.DeadlineExceeded,
+}
+
+// statusFrom turns an ApiError into the gRPC status of its HTTP status. Any
+// other failure is Internal.
+func statusFrom(err apierrors.ApiError) error {
+	code, ok := codesByStatus[err.Status()]
+	if !ok {
+		code = codes.Internal
+	}
+
+	st := status.New(code, err.Message())
+
+	detailed, detailsErr := st.WithDetails(&errdetails.ErrorInfo{Reason: err.Code(), Domain: ErrorDomain})
+	if detailsErr != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
@@ -0,0 +1,69 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/logger"
+	"github.com/jopitnow/go-jopit-toolkit/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// traceIDUnary puts the x-trace-id metadata in the context, where the clients
+// of prices and shops look for it, as the REST handlers do with the header.
+func traceIDUnary(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(withTraceID(ctx), request)
+}
+
+func traceIDStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &contextStream{ServerStream: stream, ctx: withTraceID(stream.Context())})
+}
+
+func withTraceID(ctx context.Context) context.Context {
+	values := metadata.ValueFromIncomingContext(ctx, strings.ToLower(tracing.XtraceHeaderKey))
+	if len(values) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, tracing.XtraceHeaderKey, values[0])
+}
+
+// recoverUnary answers Internal instead of taking the whole process down, as
+// the gin recovery middleware does for the REST API.
+func recoverUnary(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (response interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(info.FullMethod, r)
+		}
+	}()
+
+	return handler(ctx, request)
+}
+
+func recoverStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(info.FullMethod, r)
+		}
+	}()
+
+	return handler(srv, stream)
+}
+
+func recovered(method string, r interface{}) error {
+	logger.Error(fmt.Sprintf("Panic serving %s", method), fmt.Errorf("%v", r))
+	return status.Error(codes.Internal, "internal error")
+}
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,260 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: items/v1/items.proto
+
+package itemspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ItemsService_GetItem_FullMethodName           = "/items.v1.ItemsService/GetItem"
+	ItemsService_BatchGetItems_FullMethodName     = "/items.v1.ItemsService/BatchGetItems"
+	ItemsService_ListItemsByShop_FullMethodName   = "/items.v1.ItemsService/ListItemsByShop"
+	ItemsService_ValidateSelection_FullMethodName = "/items.v1.ItemsService/ValidateSelection"
+)
+
+// ItemsServiceClient is the client API for ItemsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ItemsServiceClient interface {
+	// GetItem returns an item with its price. NOT_FOUND when it doesn't exist.
+	GetItem(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*GetItemResponse, error)
+	// BatchGetItems returns the items found, with their prices, and lists the
+	// requested IDs that were not, instead of failing the whole batch.
+	BatchGetItems(ctx context.Context, in *BatchGetItemsRequest, opts ...grpc.CallOption) (*BatchGetItemsResponse, error)
+	// ListItemsByShop streams the items of a shop, one message per item.
+	ListItemsByShop(ctx context.Context, in *ListItemsByShopRequest, opts ...grpc.CallOption) (ItemsService_ListItemsByShopClient, error)
+	// ValidateSelection checks the options chosen for an item against its
+	// eligibles before it is added to a cart or an order.
+	ValidateSelection(ctx context.Context, in *ValidateSelectionRequest, opts ...grpc.CallOption) (*ValidateSelectionResponse, error)
+}
+
+type itemsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewItemsServiceClient(cc grpc.ClientConnInterface) ItemsServiceClient {
+	return &itemsServiceClient{cc}
+}
+
+func (c *itemsServiceClient) GetItem(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*GetItemResponse, error) {
+	out := new(GetItemResponse)
+	err := c.cc.Invoke(ctx, ItemsService_GetItem_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemsServiceClient) BatchGetItems(ctx context.Context, in *BatchGetItemsRequest, opts ...grpc.CallOption) (*BatchGetItemsResponse, error) {
+	out := new(BatchGetItemsResponse)
+	err := c.cc.Invoke(ctx, ItemsService_BatchGetItems_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemsServiceClient) ListItemsByShop(ctx context.Context, in *ListItemsByShopRequest, opts ...grpc.CallOption) (ItemsService_ListItemsByShopClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ItemsService_ServiceDesc.Streams[0], ItemsService_ListItemsByShop_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &itemsServiceListItemsByShopClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ItemsService_ListItemsByShopClient interface {
+	Recv() (*ListItemsByShopResponse, error)
+	grpc.ClientStream
+}
+
+type itemsServiceListItemsByShopClient struct {
+	grpc.ClientStream
+}
+
+func (x *itemsServiceListItemsByShopClient) Recv() (*ListItemsByShopResponse, error) {
+	m := new(ListItemsByShopResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *itemsServiceClient) ValidateSelection(ctx context.Context, in *ValidateSelectionRequest, opts ...grpc.CallOption) (*ValidateSelectionResponse, error) {
+	out := new(ValidateSelectionResponse)
+	err := c.cc.Invoke(ctx, ItemsService_ValidateSelection_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ItemsServiceServer is the server API for ItemsService service.
+// All implementations must embed UnimplementedItemsServiceServer
+// for forward compatibility
+type ItemsServiceServer interface {
+	// GetItem returns an item with its price. NOT_FOUND when it doesn't exist.
+	GetItem(context.Context, *GetItemRequest) (*GetItemResponse, error)
+	// BatchGetItems returns the items found, with their prices, and lists the
+	// requested IDs that were not, instead of failing the whole batch.
+	BatchGetItems(context.Context, *BatchGetItemsRequest) (*BatchGetItemsResponse, error)
+	// ListItemsByShop streams the items of a shop, one message per item.
+	ListItemsByShop(*ListItemsByShopRequest, ItemsService_ListItemsByShopServer) error
+	// ValidateSelection checks the options chosen for an item against its
+	// eligibles before it is added to a cart or an order.
+	ValidateSelection(context.Context, *ValidateSelectionRequest) (*ValidateSelectionResponse, error)
+	mustEmbedUnimplementedItemsServiceServer()
+}
+
+// UnimplementedItemsServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedItemsServiceServer struct {
+}
+
+func (UnimplementedItemsServiceServer) GetItem(context.Context, *GetItemRequest) (*GetItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetItem not implemented")
+}
+func (UnimplementedItemsServiceServer) BatchGetItems(context.Context, *BatchGetItemsRequest) (*BatchGetItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetItems not implemented")
+}
+func (UnimplementedItemsServiceServer) ListItemsByShop(*ListItemsByShopRequest, ItemsService_ListItemsByShopServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListItemsByShop not implemented")
+}
+func (UnimplementedItemsServiceServer) ValidateSelection(context.Context, *ValidateSelectionRequest) (*ValidateSelectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateSelection not implemented")
+}
+func (UnimplementedItemsServiceServer) mustEmbedUnimplementedItemsServiceServer() {}
+
+// UnsafeItemsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ItemsServiceServer will
+// result in compilation errors.
+type UnsafeItemsServiceServer interface {
+	mustEmbedUnimplementedItemsServiceServer()
+}
+
+func RegisterItemsServiceServer(s grpc.ServiceRegistrar, srv ItemsServiceServer) {
+	s.RegisterService(&ItemsService_ServiceDesc, srv)
+}
+
+func _ItemsService_GetItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemsServiceServer).GetItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemsService_GetItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemsServiceServer).GetItem(ctx, req.(*GetItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemsService_BatchGetItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemsServiceServer).BatchGetItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemsService_BatchGetItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemsServiceServer).BatchGetItems(ctx, req.(*BatchGetItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemsService_ListItemsByShop_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListItemsByShopRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ItemsServiceServer).ListItemsByShop(m, &itemsServiceListItemsByShopServer{stream})
+}
+
+type ItemsService_ListItemsByShopServer interface {
+	Send(*ListItemsByShopResponse) error
+	grpc.ServerStream
+}
+
+type itemsServiceListItemsByShopServer struct {
+	grpc.ServerStream
+}
+
+func (x *itemsServiceListItemsByShopServer) Send(m *ListItemsByShopResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ItemsService_ValidateSelection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateSelectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemsServiceServer).ValidateSelection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemsService_ValidateSelection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemsServiceServer).ValidateSelection(ctx, req.(*ValidateSelectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ItemsService_ServiceDesc is the grpc.ServiceDesc for ItemsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ItemsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "items.v1.ItemsService",
+	HandlerType: (*ItemsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetItem",
+			Handler:    _ItemsService_GetItem_Handler,
+		},
+		{
+			MethodName: "BatchGetItems",
+			Handler:    _ItemsService_BatchGetItems_Handler,
+		},
+		{
+			MethodName: "ValidateSelection",
+			Handler:    _ItemsService_ValidateSelection_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListItemsByShop",
+			Handler:       _ItemsService_ListItemsByShop_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "items/v1/items.proto",
+}
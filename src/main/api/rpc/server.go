@@ -0,0 +1,176 @@
+// Package rpc serves the items over gRPC for the internal consumers (cart,
+// orders), next to the REST API. The contract lives in proto/ and the
+// generated code in itemspb/.
+package rpc
+
+//go:generate buf generate proto
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/rpc/itemspb"
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/services"
+	"github.com/agustinrabini/items-api-project/src/main/domain/utils"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// listPageSize is how many items ListItemsByShop reads, and prices, at once.
+const listPageSize = 100
+
+// ItemsServer implements itemspb.ItemsServiceServer on top of ItemsService,
+// so both APIs answer the same for the same item.
+type ItemsServer struct {
+	itemspb.UnimplementedItemsServiceServer
+
+	Service services.ItemsService
+}
+
+// NewServer registers the items service, the standard health service and
+// reflection (for grpcurl) in a server traced like the REST handlers.
+func NewServer(service services.ItemsService) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(recoverUnary, traceIDUnary),
+		grpc.ChainStreamInterceptor(recoverStream, traceIDStream),
+	)
+
+	itemspb.RegisterItemsServiceServer(server, &ItemsServer{Service: service})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(itemspb.ItemsService_ServiceDesc.ServiceName, grpc_health_v1.HealthCheckResponse_SERVING)
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+
+	return server
+}
+
+func (s *ItemsServer) GetItem(ctx context.Context, request *itemspb.GetItemRequest) (*itemspb.GetItemResponse, error) {
+	if err := utils.ValidateHexID([]string{request.GetId()}); err != nil {
+		return nil, statusFrom(err)
+	}
+
+	item, err := s.Service.Get(ctx, request.GetId())
+	if err != nil {
+		return nil, statusFrom(err)
+	}
+
+	return &itemspb.GetItemResponse{Item: toItem(item)}, nil
+}
+
+// BatchGetItems answers the found items in the order they were asked for and
+// names the rest in missing_ids, replacing the "integrity" header of
+// POST /items/list. Items without a price count as missing, as they do there.
+func (s *ItemsServer) BatchGetItems(ctx context.Context, request *itemspb.BatchGetItemsRequest) (*itemspb.BatchGetItemsResponse, error) {
+	ids := unique(request.GetIds())
+	if len(ids) == 0 {
+		return &itemspb.BatchGetItemsResponse{}, nil
+	}
+
+	if err := utils.ValidateHexID(ids); err != nil {
+		return nil, statusFrom(err)
+	}
+
+	// a 404, from the items or the prices, means none of them can be returned
+	items, err := s.Service.GetItemsByIDs(ctx, models.ItemsIds{Items: ids})
+	if err != nil && err.Status() == http.StatusNotFound {
+		return &itemspb.BatchGetItemsResponse{MissingIds: ids}, nil
+	}
+	if err != nil {
+		return nil, statusFrom(err)
+	}
+
+	found := make(map[string]models.Item, len(items.Items))
+	for _, item := range items.Items {
+		found[item.ID] = item
+	}
+
+	response := &itemspb.BatchGetItemsResponse{}
+	for _, id := range ids {
+		item, ok := found[id]
+		if !ok {
+			response.MissingIds = append(response.MissingIds, id)
+			continue
+		}
+		response.Items = append(response.Items, toItem(item))
+	}
+
+	return response, nil
+}
+
+// ListItemsByShop sends one message per item, reading the shop a page at a
+// time, so consumers can start working before the whole shop is transferred
+// and the server never holds it. A shop without items is an empty stream.
+func (s *ItemsServer) ListItemsByShop(request *itemspb.ListItemsByShopRequest, stream itemspb.ItemsService_ListItemsByShopServer) error {
+	if request.GetShopId() == "" {
+		return statusFrom(catalog.BadRequest.New("shop_id is required"))
+	}
+
+	if err := utils.ValidateHexID([]string{request.GetShopId()}); err != nil {
+		return statusFrom(err)
+	}
+
+	query := models.ItemsQuery{ShopID: request.GetShopId(), Limit: listPageSize}
+	for {
+		page, err := s.Service.Search(stream.Context(), query)
+		if err != nil {
+			return statusFrom(err)
+		}
+
+		for _, item := range page.Items {
+			if err := stream.Send(&itemspb.ListItemsByShopResponse{Item: toItem(item)}); err != nil {
+				return err
+			}
+		}
+
+		if len(page.Items) == 0 || !page.HasMore(query) {
+			return nil
+		}
+		query.Offset += len(page.Items)
+	}
+}
+
+// ValidateSelection checks the options chosen for an item before it goes to a
+// cart, returning every violation and the current price.
+func (s *ItemsServer) ValidateSelection(ctx context.Context, request *itemspb.ValidateSelectionRequest) (*itemspb.ValidateSelectionResponse, error) {
+	if err := utils.ValidateHexID([]string{request.GetItemId()}); err != nil {
+		return nil, statusFrom(err)
+	}
+
+	item, err := s.Service.Get(ctx, request.GetItemId())
+	if err != nil {
+		return nil, statusFrom(err)
+	}
+
+	violations := item.ValidateSelection(toSelections(request.GetSelections()))
+
+	return &itemspb.ValidateSelectionResponse{
+		Valid:      len(violations) == 0,
+		Violations: toViolations(violations),
+		Price:      toPrice(item.Price),
+	}, nil
+}
+
+// unique drops the repeated ids keeping the first occurrence.
+func unique(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	result := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+
+	return result
+}
@@ -0,0 +1,36 @@
+package rpc
+
+import (
+	"context"
+	"net"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/logger"
+	"google.golang.org/grpc"
+)
+
+// Worker runs the gRPC server next to the REST one. It is stopped with the
+// other workers, after the REST requests are drained, and lets the in-flight
+// calls finish.
+type Worker struct {
+	server   *grpc.Server
+	listener net.Listener
+}
+
+func NewWorker(server *grpc.Server, listener net.Listener) *Worker {
+	return &Worker{server: server, listener: listener}
+}
+
+func (w *Worker) Run(ctx context.Context) {
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		w.server.GracefulStop()
+	}()
+
+	if err := w.server.Serve(w.listener); err != nil {
+		logger.Error("Error serving gRPC", err)
+	}
+
+	<-stopped
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/dependencies"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/backup"
+)
+
+// backupCollections are the collections of the catalog a backup keeps.
+var backupCollections = []string{
+	dependencies.KvsItemsCollection,
+	dependencies.KvsCategoriesCollection,
+	dependencies.KvsCollectionsCollection,
+}
+
+func backupDatabase(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	file := flags.String("file", "", "archive to write, e.g. items.tar.gz")
+	format := flags.String("format", backup.FormatBSON, "format of the documents, bson or json (canonical extended JSON)")
+	noSnapshot := flags.Bool("no-snapshot", false, "read without a snapshot, for servers that are not a replica set on MongoDB 5.0+ or backups that outlast minSnapshotHistoryWindowInSeconds (300s by default) and fail with SnapshotTooOld")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *file == "" {
+		fmt.Fprintln(stderr, "--file is required")
+		return 2
+	}
+
+	manager, closeDB, err := connect()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer closeDB()
+
+	store, endSnapshot, err := backup.NewMongoStore(manager.Database, !*noSnapshot)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer endSnapshot()
+
+	out, err := os.Create(*file)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	manifest, err := backup.Backup(context.Background(), store, out, backup.Options{
+		Database:    manager.Database.Name(),
+		Format:      *format,
+		Snapshot:    !*noSnapshot,
+		Collections: backupCollections,
+	})
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// a partial archive must not pass for a backup
+		_ = os.Remove(*file)
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	report(stdout, map[string]interface{}{"file": *file, "manifest": manifest})
+
+	return 0
+}
+
+func restoreDatabase(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	file := flags.String("file", "", "archive written by backup")
+	database := flags.String("database", "", "database to restore into (defaults to MONGO_DATABASE)")
+	shopID := flags.String("shop", "", "restore only the items and collections of this shop")
+	drop := flags.Bool("drop", false, "after restoring, remove the documents that are not in the backup, only the shop ones with --shop")
+	dryRun := flags.Bool("dry-run", false, "check the backup and report what would be restored")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *file == "" {
+		fmt.Fprintln(stderr, "--file is required")
+		return 2
+	}
+
+	in, err := os.Open(*file)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer in.Close()
+
+	manager, closeDB, err := connect()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer closeDB()
+
+	target := manager.Database
+	if *database != "" {
+		target = manager.DB.Database(*database)
+	}
+
+	store, _, err := backup.NewMongoStore(target, false)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	result, err := backup.Restore(context.Background(), store, in, backup.RestoreOptions{ShopID: *shopID, Drop: *drop, DryRun: *dryRun})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	report(stdout, map[string]interface{}{"database": target.Name(), "result": result})
+
+	return 0
+}
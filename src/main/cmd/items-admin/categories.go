@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/clients"
+	"github.com/agustinrabini/items-api-project/src/main/domain/services"
+)
+
+func runCategories(subcommand string, args []string, stdout io.Writer, stderr io.Writer) int {
+	switch subcommand {
+	case "import":
+		return importCategories(args, stdout, stderr)
+	case "export":
+		return exportCategories(args, stdout, stderr)
+	default:
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+}
+
+func importCategories(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("categories import", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	file := flags.String("file", "", "JSON or YAML file with the categories")
+	format := flags.String("format", "", "file format, json or yaml (defaults to the file extension)")
+	dryRun := flags.Bool("dry-run", false, "report the changes without writing them")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *file == "" {
+		fmt.Fprintln(stderr, "--file is required")
+		return 2
+	}
+
+	categories, err := readCategories(*file, *format)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	manager, closeDB, err := connect()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer closeDB()
+
+	ctx := adminContext()
+	categoriesService := services.NewCategoriesService(manager.CategoriesRepository())
+	itemsService := services.NewItemsService(manager.ItemsRepository(), clients.NewPriceClient(), clients.NewShopClient())
+
+	result, apiErr := categoriesService.Import(ctx, categories, *dryRun)
+	if apiErr != nil {
+		fmt.Fprintln(stderr, apiErr.Error())
+		return 1
+	}
+
+	// keep the copy embedded in the items in sync, as the API does on updates
+	if !*dryRun {
+		for _, category := range result.Updated {
+			apiErr = itemsService.UpdateItemsCategories(ctx, category)
+			if apiErr != nil && apiErr.Status() != http.StatusNotFound {
+				fmt.Fprintln(stderr, apiErr.Error())
+				return 1
+			}
+		}
+	}
+
+	report(stdout, result)
+
+	return 0
+}
+
+func exportCategories(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("categories export", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	file := flags.String("file", "", "file to write the categories to")
+	format := flags.String("format", "", "file format, json or yaml (defaults to the file extension)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *file == "" {
+		fmt.Fprintln(stderr, "--file is required")
+		return 2
+	}
+
+	manager, closeDB, err := connect()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer closeDB()
+
+	categories, apiErr := services.NewCategoriesService(manager.CategoriesRepository()).Export(context.Background())
+	if apiErr != nil {
+		fmt.Fprintln(stderr, apiErr.Error())
+		return 1
+	}
+
+	if err := writeCategories(*file, *format, categories); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	report(stdout, map[string]interface{}{"file": *file, "exported": len(categories)})
+
+	return 0
+}
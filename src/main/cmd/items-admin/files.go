@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	formatJSON = "json"
+	formatYAML = "yaml"
+)
+
+func fileFormat(path string, format string) (string, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+
+	switch format {
+	case "json":
+		return formatJSON, nil
+	case "yaml", "yml":
+		return formatYAML, nil
+	default:
+		return "", fmt.Errorf("unknown file format %q, use json or yaml", format)
+	}
+}
+
+// readCategories accepts both the export layout ({"categories": [...]}) and a
+// bare list. Categories are flat, so unknown fields such as a parent or
+// attributes are rejected instead of being dropped on import.
+func readCategories(path string, format string) ([]models.Category, error) {
+	content, err := readDocument(path, format)
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []models.Category
+	if isList(content) {
+		err = decodeStrict(content, &categories)
+	} else {
+		var file dto.CategoriesDTO
+		err = decodeStrict(content, &file)
+		categories = file.CategoryDTO
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", path, err)
+	}
+
+	return categories, nil
+}
+
+// readItems accepts both the export layout ({"shop_id": ..., "items": [...]})
+// and a bare list.
+func readItems(path string, format string) ([]models.Item, error) {
+	content, err := readDocument(path, format)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []models.Item
+	if isList(content) {
+		err = json.Unmarshal(content, &items)
+	} else {
+		var file dto.ItemsExportDTO
+		err = json.Unmarshal(content, &file)
+		items = file.Items
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", path, err)
+	}
+
+	return items, nil
+}
+
+// readDocument returns the content of the file as JSON. YAML is converted
+// first so the models keep a single set of field names.
+func readDocument(path string, format string) ([]byte, error) {
+	format, err := fileFormat(path, format)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	if format == formatYAML {
+		var document interface{}
+		if err = yaml.Unmarshal(content, &document); err != nil {
+			return nil, fmt.Errorf("error decoding %s: %w", path, err)
+		}
+
+		content, err = json.Marshal(document)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding %s: %w", path, err)
+		}
+	}
+
+	return content, nil
+}
+
+// decodeStrict fails on the fields the target does not have.
+func decodeStrict(content []byte, target interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(target)
+}
+
+func isList(content []byte) bool {
+	trimmed := bytes.TrimSpace(content)
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+func writeCategories(path string, format string, categories []models.Category) error {
+	return writeDocument(path, format, dto.CategoriesDTO{CategoryDTO: categories})
+}
+
+func writeDocument(path string, format string, document interface{}) error {
+	format, err := fileFormat(path, format)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if format == formatYAML {
+		var generic interface{}
+		if err = json.Unmarshal(content, &generic); err != nil {
+			return err
+		}
+
+		content, err = yaml.Marshal(generic)
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(path, content, 0o644)
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/config"
+	"github.com/agustinrabini/items-api-project/src/main/api/dependencies"
+	"github.com/agustinrabini/items-api-project/src/main/domain/clients"
+	"github.com/agustinrabini/items-api-project/src/main/domain/services"
+)
+
+func newAdminService(manager dependencies.DependencyManager) services.AdminService {
+	return services.NewAdminService(manager.ItemsRepository(), manager.CategoriesRepository(), clients.NewPriceClient())
+}
+
+func reindex(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("reindex", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dryRun := flags.Bool("dry-run", false, "report the missing indexes without creating them")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	manager, closeDB, err := connect()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer closeDB()
+
+	migrator, err := manager.Migrator()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	indexes, err := migrator.Reindex(context.Background(), *dryRun)
+	report(stdout, map[string]interface{}{"dry_run": *dryRun, "indexes": indexes})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+func recountCategories(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("recount-categories", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dryRun := flags.Bool("dry-run", false, "report the counts without fixing the items")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	manager, closeDB, err := connect()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer closeDB()
+
+	result, apiErr := newAdminService(manager).RecountCategories(adminContext(), *dryRun)
+	if apiErr != nil {
+		fmt.Fprintln(stderr, apiErr.Error())
+		return 1
+	}
+
+	report(stdout, result)
+
+	return 0
+}
+
+func exportItems(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	shopID := flags.String("shop", "", "shop whose items are exported")
+	file := flags.String("file", "", "file to write the items to")
+	format := flags.String("format", "", "file format, json or yaml (defaults to the file extension)")
+	dryRun := flags.Bool("dry-run", false, "count the items without writing the file")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *shopID == "" || *file == "" {
+		fmt.Fprintln(stderr, "--shop and --file are required")
+		return 2
+	}
+
+	manager, closeDB, err := connect()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer closeDB()
+
+	export, apiErr := newAdminService(manager).ExportShop(context.Background(), *shopID)
+	if apiErr != nil {
+		fmt.Fprintln(stderr, apiErr.Error())
+		return 1
+	}
+
+	if !*dryRun {
+		if err := writeDocument(*file, *format, export); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+
+	report(stdout, map[string]interface{}{"dry_run": *dryRun, "file": *file, "shop_id": *shopID, "exported": len(export.Items)})
+
+	return 0
+}
+
+func importItems(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	file := flags.String("file", "", "JSON or YAML file with the items, as written by export")
+	format := flags.String("format", "", "file format, json or yaml (defaults to the file extension)")
+	dryRun := flags.Bool("dry-run", false, "report the changes without writing them")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *file == "" {
+		fmt.Fprintln(stderr, "--file is required")
+		return 2
+	}
+
+	items, err := readItems(*file, *format)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	manager, closeDB, err := connect()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer closeDB()
+
+	result, apiErr := newAdminService(manager).ImportItems(adminContext(), items, *dryRun)
+	if apiErr != nil {
+		fmt.Fprintln(stderr, apiErr.Error())
+		return 1
+	}
+
+	report(stdout, result)
+
+	return 0
+}
+
+func purgeTrash(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("purge-trash", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dryRun := flags.Bool("dry-run", false, "list the items with status deleted without purging them")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	manager, closeDB, err := connect()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer closeDB()
+
+	result, apiErr := newAdminService(manager).PurgeTrash(adminContext(), *dryRun)
+	report(stdout, result)
+	if apiErr != nil {
+		fmt.Fprintln(stderr, apiErr.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func reassignCategory(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("reassign-category", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	from := flags.String("from", "", "category the items are moved from, which may not exist anymore")
+	to := flags.String("to", "", "category the items are moved to")
+	dryRun := flags.Bool("dry-run", false, "list the items without moving them")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *from == "" || *to == "" {
+		fmt.Fprintln(stderr, "--from and --to are required")
+		return 2
+	}
+
+	manager, closeDB, err := connect()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer closeDB()
+
+	result, apiErr := newAdminService(manager).ReassignCategory(adminContext(), *from, *to, *dryRun)
+	report(stdout, result)
+	if apiErr != nil {
+		fmt.Fprintln(stderr, apiErr.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func reconcilePrices(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("reconcile-prices", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	repair := flags.Bool("repair", false, "mark the items without a price as unpriced and delete the prices of the trash and of the removed items")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	manager, closeDB, err := connect()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer closeDB()
+
+	service := services.NewReconciliationService(manager.ItemsRepository(), clients.NewPriceClient(), manager.ReconciliationRepository(), config.ConfMap.ReconciliationChunkSize)
+
+	result, apiErr := service.Run(context.Background(), *repair)
+	if apiErr != nil {
+		fmt.Fprintln(stderr, apiErr.Error())
+		return 1
+	}
+
+	report(stdout, result)
+
+	return 0
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/config"
+	"github.com/agustinrabini/items-api-project/src/main/api/dependencies"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/actor"
+)
+
+const usage = `usage: items-admin <command> [subcommand] [flags]
+
+Commands print their result as JSON on stdout; the ones that write accept
+--dry-run to report what they would change, except reconcile-prices, which
+only writes with --repair.
+
+commands:
+  categories import --file <path> [--format json|yaml] [--dry-run]
+  categories export --file <path> [--format json|yaml]
+  migrate up [--to <version>] [--dry-run]
+  migrate down [--steps <n>] [--dry-run]
+  migrate status
+  reindex [--dry-run]
+  recount-categories [--dry-run]
+  export --shop <id> --file <path> [--format json|yaml] [--dry-run]
+  import --file <path> [--format json|yaml] [--dry-run]
+  purge-trash [--dry-run]
+  reassign-category --from <category> --to <category> [--dry-run]
+  reconcile-prices [--repair]
+  backup --file <path> [--format bson|json] [--no-snapshot]
+  restore --file <path> [--database <name>] [--shop <id>] [--drop] [--dry-run]
+`
+
+// items-admin runs maintenance tasks against the items database, using the
+// same configuration and repositories as the API.
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) < 1 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	switch args[0] {
+	case "categories", "migrate":
+		if len(args) < 2 {
+			fmt.Fprint(stderr, usage)
+			return 2
+		}
+		if args[0] == "categories" {
+			return runCategories(args[1], args[2:], stdout, stderr)
+		}
+		return runMigrate(args[1], args[2:], stdout, stderr)
+	case "reindex":
+		return reindex(args[1:], stdout, stderr)
+	case "recount-categories":
+		return recountCategories(args[1:], stdout, stderr)
+	case "export":
+		return exportItems(args[1:], stdout, stderr)
+	case "import":
+		return importItems(args[1:], stdout, stderr)
+	case "purge-trash":
+		return purgeTrash(args[1:], stdout, stderr)
+	case "reassign-category":
+		return reassignCategory(args[1:], stdout, stderr)
+	case "reconcile-prices":
+		return reconcilePrices(args[1:], stdout, stderr)
+	case "backup":
+		return backupDatabase(args[1:], stdout, stderr)
+	case "restore":
+		return restoreDatabase(args[1:], stdout, stderr)
+	default:
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+}
+
+// connect loads the API configuration and opens the database. The config is
+// dumped to stderr so stdout only carries the command output.
+func connect() (dependencies.DependencyManager, func(), error) {
+	if err := config.Load(); err != nil {
+		return dependencies.DependencyManager{}, nil, err
+	}
+	config.Dump(os.Stderr)
+
+	manager, err := dependencies.NewDependencyManager()
+	if err != nil {
+		return dependencies.DependencyManager{}, nil, fmt.Errorf("error connecting to the database: %w", err)
+	}
+
+	return manager, func() {
+		if err := manager.Close(context.Background()); err != nil {
+			fmt.Fprintln(os.Stderr, "error disconnecting database:", err)
+		}
+	}, nil
+}
+
+// adminContext is the context of the commands that change items, so their
+// revisions name items-admin as the actor.
+func adminContext() context.Context {
+	return actor.With(context.Background(), actor.Admin)
+}
+
+// report writes the result of a command as indented JSON so it can be read by
+// people and scripts alike.
+func report(w io.Writer, result interface{}) {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(result)
+}
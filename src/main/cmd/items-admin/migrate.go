@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/migrations"
+)
+
+// migrationResult is the output of migrate up and down.
+type migrationResult struct {
+	DryRun     bool            `json:"dry_run"`
+	Migrations []migrationStep `json:"migrations"`
+}
+
+type migrationStep struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+}
+
+func runMigrate(subcommand string, args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("migrate "+subcommand, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var to, steps *int
+	var dryRun *bool
+	switch subcommand {
+	case "up":
+		to = flags.Int("to", 0, "version to migrate up to (defaults to the last one)")
+		dryRun = flags.Bool("dry-run", false, "list the migrations without applying them")
+	case "down":
+		steps = flags.Int("steps", 1, "number of migrations to revert")
+		dryRun = flags.Bool("dry-run", false, "list the migrations without reverting them")
+	case "status":
+	default:
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if steps != nil && *steps < 1 {
+		fmt.Fprintln(stderr, "--steps must be at least 1")
+		return 2
+	}
+
+	manager, closeDB, err := connect()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer closeDB()
+
+	migrator, err := manager.Migrator()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	ctx := context.Background()
+
+	if subcommand == "status" {
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+
+		report(stdout, map[string]interface{}{"migrations": statuses})
+		return 0
+	}
+
+	var done []migrations.Migration
+	switch {
+	case subcommand == "up" && *dryRun:
+		done, err = migrator.PlanUp(ctx, *to)
+	case subcommand == "up":
+		done, err = migrator.Up(ctx, *to)
+	case *dryRun:
+		done, err = migrator.PlanDown(ctx, *steps)
+	default:
+		done, err = migrator.Down(ctx, *steps)
+	}
+
+	// report what was done even when a migration failed halfway
+	result := migrationResult{DryRun: *dryRun, Migrations: []migrationStep{}}
+	for _, migration := range done {
+		result.Migrations = append(result.Migrations, migrationStep{Version: migration.Version, Name: migration.Name})
+	}
+	report(stdout, result)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,139 @@
+package catalog
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+// TypeBaseURI prefixes the code of every catalog entry to build its problem
+// type. GET /errors/{code} describes it.
+const TypeBaseURI = "/errors/"
+
+// Definition is an entry of the error catalog. Codes are part of the API
+// contract: clients branch on them, so they are never renamed or reused.
+// Legacy is the error value the apierrors shape answered with before the
+// catalog, which it keeps answering with.
+type Definition struct {
+	Type   string `json:"type"`
+	Code   string `json:"code"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Legacy string `json:"legacy_error"`
+}
+
+var catalog = map[string]Definition{}
+
+var (
+	BadRequest       = define("bad_request", http.StatusBadRequest, "Bad request")
+	ValidationFailed = renamed("validation_failed", "bad_request", http.StatusBadRequest, "The request is not valid")
+	InvalidID        = renamed("invalid_id", "bad_request", http.StatusBadRequest, "Invalid identifier")
+	InvalidSlug      = renamed("invalid_slug", "bad_request", http.StatusBadRequest, "Invalid slug")
+	InvalidLocale    = renamed("invalid_locale", "bad_request", http.StatusBadRequest, "Invalid locale")
+	Unauthorized     = define("unauthorized", http.StatusUnauthorized, "Authentication required")
+	Forbidden        = define("forbidden", http.StatusForbidden, "Not allowed")
+	NotFound         = define("not_found", http.StatusNotFound, "Resource not found")
+	Conflict         = define("conflict", http.StatusConflict, "Conflict with the current state")
+	CategoryExists   = renamed("category_exists", "category alredy exists. ", http.StatusConflict, "Category already exists")
+	CategoryInUse    = define("category_in_use", http.StatusConflict, "Category in use")
+
+	IdempotencyKeyInProgress = define("idempotency_key_in_progress", http.StatusConflict, "Request in progress")
+	IdempotencyKeyReused     = define("idempotency_key_reused", http.StatusUnprocessableEntity, "Idempotency key reused")
+
+	PayloadTooLarge = define("payload_too_large", http.StatusRequestEntityTooLarge, "Request body too large")
+	TooManyRequests = define("too_many_requests", http.StatusTooManyRequests, "Too many requests")
+	Internal        = define("internal_server_error", http.StatusInternalServerError, "Internal server error")
+	// answered with the status the prices API returned
+	PricesService = renamed("prices_service_error", "error hitting price api", http.StatusBadGateway, "Prices service error")
+)
+
+func define(code string, status int, title string) Definition {
+	return renamed(code, code, status, title)
+}
+
+// renamed defines the entries whose apierrors shape had another error value.
+func renamed(code string, legacy string, status int, title string) Definition {
+	definition := Definition{
+		Type:   TypeBaseURI + code,
+		Code:   code,
+		Title:  title,
+		Status: status,
+		Legacy: legacy,
+	}
+	catalog[code] = definition
+
+	return definition
+}
+
+// New builds an ApiError with the code and status of the definition. Field
+// errors passed as causes are listed in the errors member of the problem.
+// The error is a pointer, so sentinel errors can be compared with ==.
+func (d Definition) New(detail string, causes ...interface{}) apierrors.ApiError {
+	return &Error{
+		ApiError: apierrors.NewApiError(detail, d.Code, d.Status, apierrors.CauseList(append([]interface{}{}, causes...))),
+		legacy:   d.Legacy,
+	}
+}
+
+// WithLegacy returns the definition answering with another legacy error value,
+// for the errors that had their own.
+func (d Definition) WithLegacy(legacy string) Definition {
+	d.Legacy = legacy
+	return d
+}
+
+// Error is an ApiError of the catalog. Code is the catalog code and
+// LegacyCode the error value of the apierrors shape.
+type Error struct {
+	apierrors.ApiError
+	legacy string
+}
+
+func (e Error) LegacyCode() string {
+	return e.legacy
+}
+
+// MarshalJSON writes the apierrors shape with the legacy error value.
+func (e Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Legacy(e))
+}
+
+// LegacyCode returns the error value of err in the apierrors shape.
+func LegacyCode(err apierrors.ApiError) string {
+	if catalogErr, ok := err.(*Error); ok {
+		return catalogErr.LegacyCode()
+	}
+	if definition, ok := Lookup(err.Code()); ok {
+		return definition.Legacy
+	}
+
+	return err.Code()
+}
+
+// Legacy returns err with the error value of the apierrors shape, for the
+// clients that don't know the catalog codes.
+func Legacy(err apierrors.ApiError) apierrors.ApiError {
+	return apierrors.NewApiError(err.Message(), LegacyCode(err), err.Status(), err.Cause())
+}
+
+// Lookup returns the catalog entry of code.
+func Lookup(code string) (Definition, bool) {
+	definition, ok := catalog[code]
+	return definition, ok
+}
+
+// All returns every entry sorted by code.
+func All() []Definition {
+	definitions := make([]Definition, 0, len(catalog))
+	for _, definition := range catalog {
+		definitions = append(definitions, definition)
+	}
+
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Code < definitions[j].Code
+	})
+
+	return definitions
+}
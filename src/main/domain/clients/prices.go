@@ -5,9 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"time"
 
 	"github.com/agustinrabini/items-api-project/src/main/api/config"
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
 	"github.com/agustinrabini/items-api-project/src/main/domain/models"
 	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
 	"github.com/jopitnow/go-jopit-toolkit/goauth"
@@ -20,6 +20,8 @@ import (
 const (
 	PricesBaseEndpoint = "/prices"
 	PricesItemsPrices  = "/items"
+
+	pricesClientName = "prices"
 )
 
 var errorPricingService error = fmt.Errorf("error at Prices Services. URL: ")
@@ -28,6 +30,7 @@ var errorPricingServiceUnmarshal error = fmt.Errorf("Unexpected error unmarshall
 type PriceClient interface {
 	GetPriceByItemID(ctx context.Context, itemID string) (models.Price, apierrors.ApiError)
 	GetItemsPrices(ctx context.Context, itemsIDs []string) (models.Prices, apierrors.ApiError)
+	ListPricedItems(ctx context.Context, offset int, limit int) (models.PricedItems, apierrors.ApiError)
 	CreatePrice(ctx context.Context, price *models.Price) apierrors.ApiError
 	UpdatePrice(ctx context.Context, price *models.Price) apierrors.ApiError
 	DeletePrice(ctx context.Context, priceID string) apierrors.ApiError
@@ -39,12 +42,12 @@ type priceClient struct {
 
 func NewPriceClient() PriceClient {
 	builder := &rest.RequestBuilder{
-		BaseURL:        config.InternalBasePricesClient,
-		Timeout:        5 * time.Second,
+		BaseURL:        config.ConfMap.PricesBaseURL,
+		Timeout:        config.ConfMap.PricesTimeout,
 		ContentType:    rest.JSON,
 		EnableCache:    false,
 		DisableTimeout: false,
-		CustomPool:     &rest.CustomPool{MaxIdleConnsPerHost: 100},
+		CustomPool:     &rest.CustomPool{MaxIdleConnsPerHost: config.ConfMap.PricesMaxIdleConns},
 		FollowRedirect: true,
 		MetricsConfig:  rest.MetricsReportConfig{TargetId: "prices-api"},
 	}
@@ -59,7 +62,9 @@ func (client priceClient) GetPriceByItemID(ctx context.Context, itemID string) (
 	headers.Add("X-Trace-Id", fmt.Sprint(ctx.Value(tracing.XtraceHeaderKey)))
 
 	endpoint := fmt.Sprintf("%s/item/%s", PricesBaseEndpoint, itemID)
+	ctx, end := startCall(ctx, pricesClientName, "GetPriceByItemID", headers)
 	response := client.Builder.Get(endpoint, rest.Context(ctx), rest.Headers(headers))
+	end(response)
 
 	if response.Response == nil {
 		return models.Price{}, apierrors.NewInternalServerApiError(fmt.Sprintf("unexpected error getting price, url: %s", endpoint), response.Err)
@@ -98,14 +103,16 @@ func (client priceClient) GetItemsPrices(ctx context.Context, ids []string) (mod
 
 	endpoint := fmt.Sprintf("%s%s", PricesBaseEndpoint, PricesItemsPrices)
 
+	ctx, end := startCall(ctx, pricesClientName, "GetItemsPrices", traceHeader)
 	response := client.Builder.Post(endpoint, req, rest.Context(ctx), rest.Headers(traceHeader)) //, rest.Headers(authHeader)
+	end(response)
 
 	if response.Response == nil || (response.StatusCode != http.StatusNotFound && response.StatusCode != http.StatusOK) {
 		return models.Prices{}, apierrors.NewInternalServerApiError(fmt.Sprint(errorPricingService, endpoint), response.Err)
 	}
 
 	if response.StatusCode == http.StatusNotFound {
-		return models.Prices{}, apierrors.NewNotFoundApiError("no prtice found")
+		return models.Prices{}, apierrors.NewNotFoundApiError("price not found")
 	}
 
 	rawBody = response.Bytes()
@@ -121,13 +128,42 @@ func (client priceClient) GetItemsPrices(ctx context.Context, ids []string) (mod
 	return models.Prices{}, apierrors.NewNotFoundApiError("price not found")
 }
 
+// ListPricedItems returns a page of the items with a price, which finds the
+// prices of the items that are not in the collection anymore. It expects
+// GET /prices/items?offset=&limit= to answer {"item_ids": [...], "total": n},
+// the IDs sorted so the pages don't overlap; a prices API without that route
+// answers an error.
+func (client priceClient) ListPricedItems(ctx context.Context, offset int, limit int) (models.PricedItems, apierrors.ApiError) {
+	var headers = http.Header{}
+	var page models.PricedItems
+
+	headers.Add("X-Trace-Id", fmt.Sprint(ctx.Value(tracing.XtraceHeaderKey)))
+
+	endpoint := fmt.Sprintf("%s%s?offset=%d&limit=%d", PricesBaseEndpoint, PricesItemsPrices, offset, limit)
+	ctx, end := startCall(ctx, pricesClientName, "ListPricedItems", headers)
+	response := client.Builder.Get(endpoint, rest.Context(ctx), rest.Headers(headers))
+	end(response)
+
+	if response.Response == nil || response.StatusCode != http.StatusOK {
+		return models.PricedItems{}, apierrors.NewInternalServerApiError(fmt.Sprint(errorPricingService, endpoint), response.Err)
+	}
+
+	if err := json.Unmarshal(response.Bytes(), &page); err != nil {
+		return models.PricedItems{}, apierrors.NewInternalServerApiError(errorPricingServiceUnmarshal.Error()+string(response.Bytes()), err)
+	}
+
+	return page, nil
+}
+
 func (client priceClient) CreatePrice(ctx context.Context, price *models.Price) apierrors.ApiError {
 	var headers = http.Header{}
 	var response *rest.Response
 
 	headers.Add("X-Trace-Id", fmt.Sprint(ctx.Value(tracing.XtraceHeaderKey)))
 
+	ctx, end := startCall(ctx, pricesClientName, "CreatePrice", headers)
 	response = client.Builder.Post(PricesBaseEndpoint, price, rest.Context(ctx), rest.Headers(headers))
+	end(response)
 
 	if response.Response == nil {
 		return apierrors.NewInternalServerApiError(fmt.Sprintf("unexpected error creating price, url: %s", PricesBaseEndpoint), response.Err)
@@ -148,7 +184,9 @@ func (client priceClient) UpdatePrice(ctx context.Context, price *models.Price)
 	headers.Add("Authorization", fmt.Sprint(ctx.Value(goauth.FirebaseAuthHeader)))
 
 	endpoint := fmt.Sprintf("%s/%s", PricesBaseEndpoint, price.ID)
+	ctx, end := startCall(ctx, pricesClientName, "UpdatePrice", headers)
 	response = client.Builder.Put(endpoint, price, rest.Context(ctx), rest.Headers(headers))
+	end(response)
 
 	if response.Response == nil {
 		return apierrors.NewInternalServerApiError(fmt.Sprintf("unexpected error updating price, url: %s", endpoint), response.Err)
@@ -175,7 +213,9 @@ func (client priceClient) DeletePrice(ctx context.Context, itemID string) apierr
 	headers.Add("Authorization", fmt.Sprint(ctx.Value(goauth.FirebaseAuthHeader)))
 
 	endpoint := fmt.Sprintf("%s/item/%s", PricesBaseEndpoint, itemID)
+	ctx, end := startCall(ctx, pricesClientName, "DeletePrice", headers)
 	response = client.Builder.Delete(endpoint, rest.Context(ctx), rest.Headers(headers))
+	end(response)
 
 	if response.Response == nil {
 		return apierrors.NewInternalServerApiError(fmt.Sprintf("unexpected error updating price, url: %s", endpoint), response.Err)
@@ -186,7 +226,8 @@ func (client priceClient) DeletePrice(ctx context.Context, itemID string) apierr
 	}
 
 	if response.StatusCode != http.StatusNoContent {
-		return apierrors.NewApiError(fmt.Sprintf("error updating price with state %d, url: %s", response.StatusCode, endpoint), "error hitting price api", response.StatusCode, apierrors.CauseList{})
+		// keeps the status the prices API answered with
+		return apierrors.NewApiError(fmt.Sprintf("error updating price with state %d, url: %s", response.StatusCode, endpoint), catalog.PricesService.Code, response.StatusCode, apierrors.CauseList{})
 	}
 
 	return nil
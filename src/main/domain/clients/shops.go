@@ -5,18 +5,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"time"
 
 	"github.com/agustinrabini/items-api-project/src/main/api/config"
 	"github.com/agustinrabini/items-api-project/src/main/domain/models"
 	"github.com/jopitnow/go-jopit-toolkit/goauth"
 	"github.com/jopitnow/go-jopit-toolkit/rest"
+	"github.com/jopitnow/go-jopit-toolkit/tracing"
 
 	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
 )
 
 const (
 	ShopsBaseEndpoint = "/shops"
+
+	shopsClientName = "shops"
 )
 
 type ShopClient interface {
@@ -29,12 +31,12 @@ type shopClient struct {
 
 func NewShopClient() ShopClient {
 	builder := &rest.RequestBuilder{
-		BaseURL:        config.InternalBaseShopsClient,
-		Timeout:        5 * time.Second,
+		BaseURL:        config.ConfMap.ShopsBaseURL,
+		Timeout:        config.ConfMap.ShopsTimeout,
 		ContentType:    rest.JSON,
 		EnableCache:    false,
 		DisableTimeout: false,
-		CustomPool:     &rest.CustomPool{MaxIdleConnsPerHost: 100},
+		CustomPool:     &rest.CustomPool{MaxIdleConnsPerHost: config.ConfMap.ShopsMaxIdleConns},
 		FollowRedirect: true,
 		MetricsConfig:  rest.MetricsReportConfig{TargetId: "shops-api"},
 	}
@@ -47,9 +49,11 @@ func (client shopClient) GetShopByUserID(ctx context.Context) (models.Shop, apie
 	var shop models.Shop
 
 	headers.Add("Authorization", fmt.Sprint(ctx.Value(goauth.FirebaseAuthHeader)))
-	headers.Add("X-Trace-ID", fmt.Sprint(ctx.Value("X-Trace-ID")))
+	headers.Add("X-Trace-ID", fmt.Sprint(ctx.Value(tracing.XtraceHeaderKey)))
 
-	response := client.Builder.Get(ShopsBaseEndpoint, rest.Headers(headers))
+	ctx, end := startCall(ctx, shopsClientName, "GetShopByUserID", headers)
+	response := client.Builder.Get(ShopsBaseEndpoint, rest.Context(ctx), rest.Headers(headers))
+	end(response)
 
 	if response.Response == nil {
 		return models.Shop{}, apierrors.NewInternalServerApiError(fmt.Sprint("unexpected error getting shop, url: "+ShopsBaseEndpoint), response.Err)
@@ -0,0 +1,34 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/pkg/metrics"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/telemetry"
+
+	"github.com/jopitnow/go-jopit-toolkit/rest"
+)
+
+// startCall opens the span of a downstream call and adds its traceparent to
+// headers. The returned func ends the span and records the call metrics.
+func startCall(ctx context.Context, client string, operation string, headers http.Header) (context.Context, func(response *rest.Response)) {
+	start := time.Now()
+	ctx, span := telemetry.StartClient(ctx, client+"."+operation, headers)
+
+	return ctx, func(response *rest.Response) {
+		status := statusCode(response)
+		telemetry.EndClient(span, status)
+		metrics.ObserveClient(client, operation, status, start)
+	}
+}
+
+// statusCode returns the status of a downstream response, or 0 when the
+// request failed before getting one.
+func statusCode(response *rest.Response) int {
+	if response.Response == nil {
+		return 0
+	}
+	return response.StatusCode
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/problems"
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+	"github.com/agustinrabini/items-api-project/src/main/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+type AuditHandler struct {
+	Service services.AuditService
+}
+
+func NewAuditHandler(service services.AuditService) AuditHandler {
+	return AuditHandler{
+		Service: service,
+	}
+}
+
+// SearchAudit godoc
+// @Summary Search the audit log
+// @Description Get a page of the audit log, newest first. The target is a type, like category, or a type and ID, like category/65f1c2
+// @Tags Admin
+// @Produce  json
+// @Param actor query string false "Actor, a user ID or admin"
+// @Param claimed_actor query string false "Name an admin claimed with the X-Admin-Actor header"
+// @Param action query string false "Action, like category.update"
+// @Param target query string false "Target type, optionally followed by /ID"
+// @Param from query string false "Oldest entry, RFC 3339"
+// @Param to query string false "Newest entry, RFC 3339"
+// @Param limit query int false "Page size, up to 500"
+// @Param offset query int false "Entries to skip"
+// @Success 200 {object} dto.AuditPageDTO
+// @Router /admin/audit [get]
+func (h AuditHandler) SearchAudit(c *gin.Context) {
+	query := models.AuditQuery{
+		Actor:        c.Query("actor"),
+		ClaimedActor: c.Query("claimed_actor"),
+		Action:       c.Query("action"),
+	}
+	query.TargetType, query.TargetID, _ = strings.Cut(c.Query("target"), "/")
+
+	var err apierrors.ApiError
+	if query.From, err = timeQuery(c, "from"); err != nil {
+		problems.Respond(c, err)
+		return
+	}
+	if query.To, err = timeQuery(c, "to"); err != nil {
+		problems.Respond(c, err)
+		return
+	}
+	if query.Limit, err = intQuery(c, "limit", models.DefaultAuditPageSize, 1, models.MaxAuditPageSize); err != nil {
+		problems.Respond(c, err)
+		return
+	}
+	if query.Offset, err = intQuery(c, "offset", 0, 0, math.MaxInt32); err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	page, err := h.Service.Search(c.Request.Context(), query)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.AuditPageDTO{
+		Entries: page.Entries,
+		Total:   page.Total,
+		Limit:   query.Limit,
+		Offset:  query.Offset,
+	})
+}
+
+func timeQuery(c *gin.Context, name string) (time.Time, apierrors.ApiError) {
+	raw := c.Query(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, catalog.BadRequest.New(name + " must be an RFC 3339 time, like 2026-10-19T15:04:05Z")
+	}
+
+	return value.UTC(), nil
+}
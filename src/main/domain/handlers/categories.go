@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/problems"
 	"github.com/agustinrabini/items-api-project/src/main/domain/models"
 	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
 	"github.com/agustinrabini/items-api-project/src/main/domain/services"
@@ -28,16 +30,35 @@ func NewCategoriesHandler(service services.CategoriesService, itemsService servi
 
 // GetAllCategories godoc
 // @Summary Categories
-// @Description Get All Categories Items
+// @Description Get All Categories Items, optionally with the amount of items using each one
 // @Tags Categories
 // @Accept  json
 // @Produce  json
-// @Success 200 {object} []domain.Category
+// @Param with_counts query bool false "Include items count"
+// @Success 200 {object} []models.Category
 // @Router /items/categories [get]
 func (h CategoriesHandler) GetAllCategories(c *gin.Context) {
-	categories, err := h.Service.GetAllCategories(c)
+	categories, err := h.Service.GetAllCategories(c.Request.Context())
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
+		return
+	}
+
+	locale := requestLocale(c)
+	for i := range categories {
+		categories[i].Localize(locale)
+	}
+
+	if withCounts, _ := strconv.ParseBool(c.Query("with_counts")); withCounts {
+		counts, err := h.ItemsService.CountByCategory(c.Request.Context(), "")
+		if err != nil {
+			problems.Respond(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.CategoriesCountDTO{
+			CategoryCountDTO: mergeCategoriesCounts(categories, counts),
+		})
 		return
 	}
 
@@ -53,33 +74,71 @@ func (h CategoriesHandler) GetAllCategories(c *gin.Context) {
 // @Tags Categories
 // @Accept  json
 // @Produce  json
-// @Param item body domain.Category true "Add Category Item"
+// @Param item body models.Category true "Add Category Item"
 // @Success 200
 // @Router /items/categories [post]
 func (h CategoriesHandler) Create(c *gin.Context) {
 	var input models.Category
 
 	if err := binding.JSON.Bind(c.Request, &input); err != nil {
-		apiErr := apierrors.NewBadRequestApiError(err.Error())
-		c.JSON(apiErr.Status(), apiErr)
+		apiErr := problems.BindError(err)
+		problems.Respond(c, apiErr)
 		return
 	}
 
 	if input.Name == "" {
 		apiErr := apierrors.NewBadRequestApiError("category name must not be nil")
-		c.JSON(apiErr.Status(), apiErr)
+		problems.Respond(c, apiErr)
+		return
+	}
+
+	if err := utils.ValidateTranslations("name_i18n", input.NameI18n); err != nil {
+		problems.Respond(c, err)
 		return
 	}
 
-	err := h.Service.Create(c, input)
+	err := h.Service.Create(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 
 	c.Status(http.StatusCreated)
 }
 
+// GetShopCategories godoc
+// @Summary Shop Categories
+// @Description Get the categories used by the items of a shop with the amount of items in each one
+// @Tags Categories
+// @Produce  json
+// @Param id path string true "Shop ID"
+// @Success 200 {object} dto.CategoriesCountDTO
+// @Router /items/shop/{id}/categories [get]
+func (h CategoriesHandler) GetShopCategories(c *gin.Context) {
+	shopID := c.Param("id")
+
+	err := utils.ValidateHexID([]string{shopID})
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	counts, err := h.ItemsService.CountByCategory(c.Request.Context(), shopID)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	locale := requestLocale(c)
+	for i := range counts {
+		counts[i].Localize(locale)
+	}
+
+	c.JSON(http.StatusOK, dto.CategoriesCountDTO{
+		CategoryCountDTO: counts,
+	})
+}
+
 // Get GetCategory godoc
 // @Summary Get Category
 // @Description Get Category
@@ -87,23 +146,59 @@ func (h CategoriesHandler) Create(c *gin.Context) {
 // @Param id_category path string true "Category ID"
 // @Accept  json
 // @Produce  json
-// @Success 200 {object} domain.Category
-// @Router /items/category/:id_category [get]
+// @Success 200 {object} models.Category
+// @Router /items/category/{id_category} [get]
 func (h CategoriesHandler) Get(c *gin.Context) {
 	categoryID := c.Param("id_category")
 
 	err := utils.ValidateHexID([]string{categoryID})
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 
-	category, err := h.Service.Get(c, categoryID)
+	category, err := h.Service.Get(c.Request.Context(), categoryID)
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 
+	category.Localize(requestLocale(c))
+
+	c.JSON(http.StatusOK, category)
+}
+
+// GetBySlug GetCategoryBySlug godoc
+// @Summary Get Category by slug
+// @Description Get Category by its slug, old slugs answer 301 with the current one
+// @Tags Categories
+// @Param slug path string true "Category slug"
+// @Produce  json
+// @Success 200 {object} models.Category
+// @Success 301 {object} models.SlugRedirect
+// @Router /items/category/by-slug/{slug} [get]
+func (h CategoriesHandler) GetBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+
+	err := utils.ValidateSlug(slug)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	category, err := h.Service.GetBySlug(c.Request.Context(), slug)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	if category.Slug != slug {
+		redirectToSlug(c, category.Slug, "/items/category/by-slug/")
+		return
+	}
+
+	category.Localize(requestLocale(c))
+
 	c.JSON(http.StatusOK, category)
 }
 
@@ -114,25 +209,25 @@ func (h CategoriesHandler) Get(c *gin.Context) {
 // @Produce  json
 // @Param id_category path string true "Category ID"
 // @Success 200
-// @Router /items/category/:id_category [delete]
+// @Router /items/category/{id_category} [delete]
 func (h CategoriesHandler) Delete(c *gin.Context) {
 	categoryID := c.Param("id_category")
 
 	err := utils.ValidateHexID([]string{categoryID})
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 
-	items, err := h.ItemsService.GetByCategoryID(c, categoryID)
+	itemsCount, err := h.ItemsService.CountByCategoryID(c.Request.Context(), categoryID)
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 
-	err = h.Service.Delete(c, items, categoryID)
+	err = h.Service.Delete(c.Request.Context(), itemsCount, categoryID)
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 
@@ -146,41 +241,165 @@ func (h CategoriesHandler) Delete(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param id_category path string true "Category ID"
-// @Param item body domain.Category true "Update item"
+// @Param item body models.Category true "Update item"
 // @Success 200
 // @Router /items/category [put]
 func (h CategoriesHandler) Update(c *gin.Context) {
 	var input models.Category
 
 	if err := binding.JSON.Bind(c.Request, &input); err != nil {
-		apiErr := apierrors.NewBadRequestApiError(err.Error())
-		c.JSON(apiErr.Status(), apiErr)
+		apiErr := problems.BindError(err)
+		problems.Respond(c, apiErr)
 		return
 	}
 
 	if input.Name == "" {
 		apiErr := apierrors.NewBadRequestApiError("category name must not be nil")
-		c.JSON(apiErr.Status(), apiErr)
+		problems.Respond(c, apiErr)
+		return
+	}
+
+	if err := utils.ValidateTranslations("name_i18n", input.NameI18n); err != nil {
+		problems.Respond(c, err)
 		return
 	}
 
 	err := utils.ValidateHexID([]string{input.ID})
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 
-	err = h.Service.Update(c, input)
+	category, err := h.Service.Update(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 
-	err = h.ItemsService.UpdateItemsCategories(c, input)
+	// the request context names the actor of the item revisions
+	err = h.ItemsService.UpdateItemsCategories(c.Request.Context(), category)
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// SetTranslation SetCategoryTranslation godoc
+// @Summary Set Category translation
+// @Description Create or replace the name of a category for a locale
+// @Tags Categories
+// @Accept  json
+// @Produce  json
+// @Param id_category path string true "Category ID"
+// @Param locale path string true "Locale"
+// @Param translation body dto.CategoryTranslationDTO true "Translation"
+// @Success 204
+// @Router /items/category/{id_category}/translations/{locale} [put]
+func (h CategoriesHandler) SetTranslation(c *gin.Context) {
+	var input dto.CategoryTranslationDTO
+
+	if err := binding.JSON.Bind(c.Request, &input); err != nil {
+		apiErr := problems.BindError(err)
+		problems.Respond(c, apiErr)
+		return
+	}
+
+	categoryID := c.Param("id_category")
+	err := utils.ValidateHexID([]string{categoryID})
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	locale := c.Param("locale")
+	err = utils.ValidateLocale(locale)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	category, err := h.Service.SetTranslation(c.Request.Context(), categoryID, locale, input.Name)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	err = h.updateItemsCategories(c, category)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteTranslation DeleteCategoryTranslation godoc
+// @Summary Delete Category translation
+// @Description Delete the name of a category for a locale
+// @Tags Categories
+// @Produce  json
+// @Param id_category path string true "Category ID"
+// @Param locale path string true "Locale"
+// @Success 204
+// @Router /items/category/{id_category}/translations/{locale} [delete]
+func (h CategoriesHandler) DeleteTranslation(c *gin.Context) {
+	categoryID := c.Param("id_category")
+	err := utils.ValidateHexID([]string{categoryID})
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	locale := c.Param("locale")
+	err = utils.ValidateLocale(locale)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	category, err := h.Service.DeleteTranslation(c.Request.Context(), categoryID, locale)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	err = h.updateItemsCategories(c, category)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// updateItemsCategories cascades the category to the items embedding it. A
+// category without items is not an error here.
+func (h CategoriesHandler) updateItemsCategories(c *gin.Context, category models.Category) apierrors.ApiError {
+	err := h.ItemsService.UpdateItemsCategories(c.Request.Context(), category)
+	if err != nil && err.Status() != http.StatusNotFound {
+		return err
+	}
+
+	return nil
+}
+
+// mergeCategoriesCounts attaches the aggregated counts to every category,
+// keeping categories without items with a zero count.
+func mergeCategoriesCounts(categories []models.Category, counts []models.CategoryCount) []models.CategoryCount {
+	countsByID := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		countsByID[count.ID] = count.ItemsCount
+	}
+
+	result := make([]models.CategoryCount, 0, len(categories))
+	for _, category := range categories {
+		result = append(result, models.CategoryCount{
+			Category:   category,
+			ItemsCount: countsByID[category.ID],
+		})
+	}
+
+	return result
+}
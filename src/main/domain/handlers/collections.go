@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/problems"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+	"github.com/agustinrabini/items-api-project/src/main/domain/services"
+	"github.com/agustinrabini/items-api-project/src/main/domain/utils"
+
+	"github.com/jopitnow/go-jopit-toolkit/goauth"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/jopitnow/go-jopit-toolkit/tracing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type CollectionsHandler struct {
+	Service services.CollectionsService
+}
+
+func NewCollectionsHandler(service services.CollectionsService) CollectionsHandler {
+	return CollectionsHandler{
+		Service: service,
+	}
+}
+
+// GetCollection godoc
+// @Summary Get collection
+// @Description Get a shop collection with its items in the collection order
+// @Tags Collections
+// @Produce  json
+// @Param id path string true "Collection ID"
+// @Success 200 {object} models.CollectionWithItems
+// @Router /items/collections/{id} [get]
+func (h CollectionsHandler) GetCollection(c *gin.Context) {
+	xTraceId, _ := c.Get("X-Trace-ID")
+	ctx := context.WithValue(c.Request.Context(), tracing.XtraceHeaderKey, xTraceId)
+
+	collectionID := c.Param("id")
+	err := utils.ValidateHexID([]string{collectionID})
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	response, err := h.Service.Get(ctx, collectionID)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	locale := requestLocale(c)
+	response.Localize(locale)
+	for i := range response.Items {
+		response.Items[i].Localize(locale)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetShopCollections godoc
+// @Summary Get shop collections
+// @Description Get the collections of a shop ordered by position
+// @Tags Collections
+// @Produce  json
+// @Param id path string true "Shop ID"
+// @Success 200 {object} []models.Collection
+// @Router /items/shop/{id}/collections [get]
+func (h CollectionsHandler) GetShopCollections(c *gin.Context) {
+	xTraceId, _ := c.Get("X-Trace-ID")
+	ctx := context.WithValue(c.Request.Context(), tracing.XtraceHeaderKey, xTraceId)
+
+	shopID := c.Param("id")
+	err := utils.ValidateHexID([]string{shopID})
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	collections, err := h.Service.GetByShopID(ctx, shopID)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	locale := requestLocale(c)
+	for i := range collections {
+		collections[i].Localize(locale)
+	}
+
+	c.JSON(http.StatusOK, collections)
+}
+
+// CreateCollection godoc
+// @Summary Create collection
+// @Description Create a collection in the shop of the authenticated seller
+// @Tags Collections
+// @Accept  json
+// @Produce  json
+// @Param collection body dto.CollectionDTO true "Add collection"
+// @Success 201
+// @Router /items/collections [post]
+func (h CollectionsHandler) CreateCollection(c *gin.Context) {
+	var input dto.CollectionDTO
+
+	xTraceId, _ := c.Get("X-Trace-ID")
+	ctx := context.WithValue(c.Request.Context(), tracing.XtraceHeaderKey, xTraceId)
+	ctx = context.WithValue(ctx, goauth.FirebaseAuthHeader, c.GetHeader("Authorization"))
+
+	if err := binding.JSON.Bind(c.Request, &input); err != nil {
+		apiErr := problems.BindError(err)
+		problems.Respond(c, apiErr)
+		return
+	}
+
+	userID, err1 := goauth.GetUserId(c)
+	if err1 != nil {
+		apiErr := apierrors.NewUnauthorizedApiError(err1.Error())
+		problems.Respond(c, apiErr)
+		return
+	}
+
+	response, apiErr := h.Service.Create(ctx, userID, input)
+	if apiErr != nil {
+		problems.Respond(c, apiErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// UpdateCollection godoc
+// @Summary Update collection
+// @Description Update the name and position of a collection
+// @Tags Collections
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Collection ID"
+// @Param collection body dto.CollectionDTO true "Update collection"
+// @Success 204
+// @Router /items/collections/{id} [put]
+func (h CollectionsHandler) UpdateCollection(c *gin.Context) {
+	var input dto.CollectionDTO
+
+	xTraceId, _ := c.Get("X-Trace-ID")
+	ctx := context.WithValue(c.Request.Context(), tracing.XtraceHeaderKey, xTraceId)
+
+	if err := binding.JSON.Bind(c.Request, &input); err != nil {
+		apiErr := problems.BindError(err)
+		problems.Respond(c, apiErr)
+		return
+	}
+
+	userID, err1 := goauth.GetUserId(c)
+	if err1 != nil {
+		apiErr := apierrors.NewUnauthorizedApiError(err1.Error())
+		problems.Respond(c, apiErr)
+		return
+	}
+
+	collectionID := c.Param("id")
+	err := utils.ValidateHexID([]string{collectionID})
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	err = h.Service.Update(ctx, userID, collectionID, input)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetCollectionItems godoc
+// @Summary Set collection items
+// @Description Replace the ordered list of items of a collection
+// @Tags Collections
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Collection ID"
+// @Param items body models.ItemsIds true "Ordered items"
+// @Success 204
+// @Router /items/collections/{id}/items [put]
+func (h CollectionsHandler) SetCollectionItems(c *gin.Context) {
+	var input models.ItemsIds
+
+	xTraceId, _ := c.Get("X-Trace-ID")
+	ctx := context.WithValue(c.Request.Context(), tracing.XtraceHeaderKey, xTraceId)
+
+	if err := binding.JSON.Bind(c.Request, &input); err != nil {
+		apiErr := problems.BindError(err)
+		problems.Respond(c, apiErr)
+		return
+	}
+
+	userID, err1 := goauth.GetUserId(c)
+	if err1 != nil {
+		apiErr := apierrors.NewUnauthorizedApiError(err1.Error())
+		problems.Respond(c, apiErr)
+		return
+	}
+
+	collectionID := c.Param("id")
+	err := utils.ValidateHexID(append([]string{collectionID}, input.Items...))
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	if input.Items == nil {
+		input.Items = []string{}
+	}
+
+	err = h.Service.SetItems(ctx, userID, collectionID, input.Items)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteCollection godoc
+// @Summary Delete collection
+// @Description Delete a collection, its items are not modified
+// @Tags Collections
+// @Produce  json
+// @Param id path string true "Collection ID"
+// @Success 204
+// @Router /items/collections/{id} [delete]
+func (h CollectionsHandler) DeleteCollection(c *gin.Context) {
+	xTraceId, _ := c.Get("X-Trace-ID")
+	ctx := context.WithValue(c.Request.Context(), tracing.XtraceHeaderKey, xTraceId)
+
+	userID, err1 := goauth.GetUserId(c)
+	if err1 != nil {
+		apiErr := apierrors.NewUnauthorizedApiError(err1.Error())
+		problems.Respond(c, apiErr)
+		return
+	}
+
+	collectionID := c.Param("id")
+	err := utils.ValidateHexID([]string{collectionID})
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	err = h.Service.Delete(ctx, userID, collectionID)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -2,12 +2,18 @@ package handlers
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/problems"
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
 	"github.com/agustinrabini/items-api-project/src/main/domain/models"
 	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
 	"github.com/agustinrabini/items-api-project/src/main/domain/services"
 	"github.com/agustinrabini/items-api-project/src/main/domain/utils"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/actor"
 
 	"github.com/jopitnow/go-jopit-toolkit/goauth"
 	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
@@ -37,7 +43,7 @@ func NewItemsHandler(service services.ItemsService, categoriesService services.C
 // @Accept  json
 // @Produce  json
 // @Param id path string true "Item ID"
-// @Success 200 {object} domain.Item
+// @Success 200 {object} models.Item
 // @Router /items/{id} [get]
 func (h ItemsHandler) GetItemByID(c *gin.Context) {
 	xTraceId, _ := c.Get("X-Trace-ID")
@@ -46,16 +52,54 @@ func (h ItemsHandler) GetItemByID(c *gin.Context) {
 	itemID := c.Param("id")
 	err := utils.ValidateHexID([]string{itemID})
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 
 	response, err := h.Service.Get(ctx, itemID)
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 
+	response.Localize(requestLocale(c))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetItemBySlug godoc
+// @Summary Get Item by slug
+// @Description Get Item by its slug, old slugs answer 301 with the current one
+// @Tags Items
+// @Produce  json
+// @Param slug path string true "Item slug"
+// @Success 200 {object} models.Item
+// @Success 301 {object} models.SlugRedirect
+// @Router /items/by-slug/{slug} [get]
+func (h ItemsHandler) GetItemBySlug(c *gin.Context) {
+	xTraceId, _ := c.Get("X-Trace-ID")
+	ctx := context.WithValue(c.Request.Context(), tracing.XtraceHeaderKey, xTraceId)
+
+	slug := c.Param("slug")
+	err := utils.ValidateSlug(slug)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	response, err := h.Service.GetBySlug(ctx, slug)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	if response.Slug != slug {
+		redirectToSlug(c, response.Slug, "/items/by-slug/")
+		return
+	}
+
+	response.Localize(requestLocale(c))
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -65,7 +109,7 @@ func (h ItemsHandler) GetItemByID(c *gin.Context) {
 // @Tags Items
 // @Accept  json
 // @Produce  json
-// @Success 200 {object} domain.Items
+// @Success 200 {object} models.Items
 // @Router /items [get]
 func (h ItemsHandler) GetItemsByUserID(c *gin.Context) {
 	xTraceId, _ := c.Get("X-Trace-ID")
@@ -75,27 +119,29 @@ func (h ItemsHandler) GetItemsByUserID(c *gin.Context) {
 	userID, err1 := goauth.GetUserId(c)
 	if err1 != nil {
 		apiErr := apierrors.NewUnauthorizedApiError(err1.Error())
-		c.JSON(apiErr.Status(), apiErr)
+		problems.Respond(c, apiErr)
 		return
 	}
 
 	response, apiErr := h.Service.GetItemsByUserID(ctx, userID)
 	if apiErr != nil {
-		c.JSON(apiErr.Status(), apiErr)
+		problems.Respond(c, apiErr)
 		return
 	}
 
+	response.Localize(requestLocale(c))
+
 	c.JSON(http.StatusOK, response)
 }
 
 // GetItemsByShopID godoc
 // @Summary Get details of items by shop ID
 // @Description Get details of items
-// @Tags Items
+// @Tags Items,v1
 // @Accept  json
 // @Produce  json
 // @Param id path string true "Shop ID"
-// @Success 200 {object} domain.Items
+// @Success 200 {object} models.Items
 // @Router /items/shop/{id} [get]
 func (h ItemsHandler) GetItemsByShopID(c *gin.Context) {
 	xTraceId, _ := c.Get("X-Trace-ID")
@@ -104,19 +150,64 @@ func (h ItemsHandler) GetItemsByShopID(c *gin.Context) {
 	shopID := c.Param("id")
 	err := utils.ValidateHexID([]string{shopID})
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 
 	response, err := h.Service.GetItemsByShopID(ctx, shopID)
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 
+	response.Localize(requestLocale(c))
+
 	c.JSON(http.StatusOK, response)
 }
 
+// GetItemsPageByShopID godoc
+// @Summary Get a page of the items of a shop
+// @Description Get the items of a shop a page at a time, in the envelope of the search
+// @Tags Items,v2
+// @Produce  json
+// @Param id path string true "Shop ID"
+// @Param limit query int false "Page size, up to 100" default(20)
+// @Param offset query int false "Items to skip" default(0)
+// @Success 200 {object} dto.ItemsPageDTO
+// @Router /items/shop/{id} [get]
+func (h ItemsHandler) GetItemsPageByShopID(c *gin.Context) {
+	xTraceId, _ := c.Get("X-Trace-ID")
+	ctx := context.WithValue(c.Request.Context(), tracing.XtraceHeaderKey, xTraceId)
+
+	query := models.ItemsQuery{ShopID: c.Param("id")}
+
+	err := utils.ValidateHexID([]string{query.ShopID})
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	if err = pageQuery(c, &query); err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	page, err := h.Service.Search(ctx, query)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	page.Localize(requestLocale(c))
+
+	c.JSON(http.StatusOK, dto.ItemsPageDTO{
+		Items:  page.Items,
+		Total:  page.Total,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	})
+}
+
 // GetItemsByShopCategoryID godoc
 // @Summary Get details of items by shop ID and category ID
 // @Description Get details of items
@@ -125,8 +216,8 @@ func (h ItemsHandler) GetItemsByShopID(c *gin.Context) {
 // @Produce  json
 // @Param id path string true "Shop ID"
 // @Param category_id path string true "Category ID"
-// @Success 200 {object} domain.Items
-// @Router /items/shop/:id/category/:category_id [get]
+// @Success 200 {object} models.Items
+// @Router /items/shop/{id}/category/{category_id} [get]
 func (h ItemsHandler) GetItemsByShopCategoryID(c *gin.Context) {
 	xTraceId, _ := c.Get("X-Trace-ID")
 	ctx := context.WithValue(c.Request.Context(), tracing.XtraceHeaderKey, xTraceId)
@@ -135,13 +226,13 @@ func (h ItemsHandler) GetItemsByShopCategoryID(c *gin.Context) {
 	categoryID := c.Param("category_id")
 	err := utils.ValidateHexID([]string{shopID, categoryID})
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 
 	itemsResponse, err := h.Service.GetItemsByShopCategoryID(ctx, shopID, categoryID)
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 
@@ -150,6 +241,8 @@ func (h ItemsHandler) GetItemsByShopCategoryID(c *gin.Context) {
 		return
 	}
 
+	itemsResponse.Localize(requestLocale(c))
+
 	c.JSON(http.StatusOK, itemsResponse)
 }
 
@@ -159,8 +252,8 @@ func (h ItemsHandler) GetItemsByShopCategoryID(c *gin.Context) {
 // @Tags Items
 // @Accept  json
 // @Produce  json
-// @Param items body domain.ItemsIds true "Add items"
-// @Success 200 {object} domain.Items
+// @Param items body models.ItemsIds true "Add items"
+// @Success 200 {object} models.Items
 // @Router /items/list [post]
 func (h ItemsHandler) GetItemsByIDs(c *gin.Context) {
 	var input models.ItemsIds
@@ -169,20 +262,20 @@ func (h ItemsHandler) GetItemsByIDs(c *gin.Context) {
 	ctx := context.WithValue(c.Request.Context(), tracing.XtraceHeaderKey, xTraceId)
 
 	if err := binding.JSON.Bind(c.Request, &input); err != nil {
-		apiErr := apierrors.NewGenericErrorMessageDecoder(err)
-		c.JSON(apiErr.Status(), apiErr)
+		apiErr := problems.BindError(err)
+		problems.Respond(c, apiErr)
 		return
 	}
 
 	err := utils.ValidateHexID(input.Items)
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 
 	response, apiErr := h.Service.GetItemsByIDs(ctx, input)
 	if apiErr != nil {
-		c.JSON(apiErr.Status(), apiErr)
+		problems.Respond(c, apiErr)
 		return
 	}
 
@@ -190,9 +283,59 @@ func (h ItemsHandler) GetItemsByIDs(c *gin.Context) {
 		c.Header("integrity", "false")
 	}
 
+	response.Localize(requestLocale(c))
+
 	c.JSON(http.StatusOK, response)
 }
 
+// SearchItems godoc
+// @Summary Search items
+// @Description Items whose name or description contain q, ignoring case, a page at a time
+// @Tags Items
+// @Produce  json
+// @Param q query string false "Text to look for"
+// @Param shop_id query string false "Shop ID"
+// @Param limit query int false "Page size, up to 100" default(20)
+// @Param offset query int false "Items to skip" default(0)
+// @Success 200 {object} dto.ItemsPageDTO
+// @Router /items/search [get]
+func (h ItemsHandler) SearchItems(c *gin.Context) {
+	xTraceId, _ := c.Get("X-Trace-ID")
+	ctx := context.WithValue(c.Request.Context(), tracing.XtraceHeaderKey, xTraceId)
+
+	query := models.ItemsQuery{
+		ShopID: c.Query("shop_id"),
+		Text:   c.Query("q"),
+	}
+
+	if query.ShopID != "" {
+		if err := utils.ValidateHexID([]string{query.ShopID}); err != nil {
+			problems.Respond(c, err)
+			return
+		}
+	}
+
+	if err := pageQuery(c, &query); err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	page, err := h.Service.Search(ctx, query)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	page.Localize(requestLocale(c))
+
+	c.JSON(http.StatusOK, dto.ItemsPageDTO{
+		Items:  page.Items,
+		Total:  page.Total,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	})
+}
+
 // CreateItem godoc
 // @Summary Create Item
 // @Description Create item in db
@@ -210,33 +353,40 @@ func (h ItemsHandler) CreateItem(c *gin.Context) {
 	ctx = context.WithValue(ctx, goauth.FirebaseAuthHeader, c.GetHeader("Authorization"))
 
 	if err := binding.JSON.Bind(c.Request, &input); err != nil {
-		apiErr := apierrors.NewGenericErrorMessageDecoder(err)
-		c.JSON(apiErr.Status(), apiErr)
+		apiErr := problems.BindError(err)
+		problems.Respond(c, apiErr)
 		return
 	}
 
 	userID, err1 := goauth.GetUserId(c)
 	if err1 != nil {
 		apiErr := apierrors.NewUnauthorizedApiError(err1.Error())
-		c.JSON(apiErr.Status(), apiErr)
+		problems.Respond(c, apiErr)
 		return
 	}
 	input.UserID = userID
 
+	if apiErr := validateItemTranslations(input); apiErr != nil {
+		problems.Respond(c, apiErr)
+		return
+	}
+
 	//validate if the category exists
-	catcheck, err := h.CategoriesService.Get(c, input.Category.ID)
+	catcheck, err := h.CategoriesService.Get(ctx, input.Category.ID)
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 	if catcheck.Name != input.Category.Name {
-		c.JSON(http.StatusBadRequest, apierrors.NewApiError("category name does not match with the existing cat for "+catcheck.ID, "bad_request", http.StatusBadRequest, apierrors.CauseList{}))
+		problems.Respond(c, categoryMismatch(catcheck.ID))
 		return
 	}
+	input.Category.NameI18n = catcheck.NameI18n
+	input.Category.Slug = catcheck.Slug
 
 	response, apiErr := h.Service.CreateItem(ctx, input)
 	if apiErr != nil {
-		c.JSON(apiErr.Status(), apiErr)
+		problems.Respond(c, apiErr)
 		return
 	}
 
@@ -245,7 +395,7 @@ func (h ItemsHandler) CreateItem(c *gin.Context) {
 
 // UpdateItem godoc
 // @Summary Update item
-// @Description Update item by ID
+// @Description Update item by ID. The status deleted puts it in the trash, which items-admin purge-trash empties
 // @Tags Items
 // @Accept  json
 // @Produce  json
@@ -260,41 +410,49 @@ func (h ItemsHandler) UpdateItem(c *gin.Context) {
 	ctx := context.WithValue(c.Request.Context(), tracing.XtraceHeaderKey, xTraceId)
 
 	if err := binding.JSON.Bind(c.Request, &input); err != nil {
-		apiErr := apierrors.NewGenericErrorMessageDecoder(err)
-		c.JSON(apiErr.Status(), apiErr)
+		apiErr := problems.BindError(err)
+		problems.Respond(c, apiErr)
 		return
 	}
 
 	userID, err1 := goauth.GetUserId(c)
 	if err1 != nil {
 		apiErr := apierrors.NewUnauthorizedApiError(err1.Error())
-		c.JSON(apiErr.Status(), apiErr)
+		problems.Respond(c, apiErr)
 		return
 	}
 	input.UserID = userID
+	ctx = actor.With(ctx, userID)
 
 	itemID := c.Param("id")
 	apierr := utils.ValidateHexID([]string{itemID, input.Category.ID})
 	if apierr != nil {
-		c.JSON(apierr.Status(), apierr)
+		problems.Respond(c, apierr)
+		return
+	}
+
+	if apierr = validateItemTranslations(input); apierr != nil {
+		problems.Respond(c, apierr)
 		return
 	}
 
 	//validate if the category exists
-	catcheck, err := h.CategoriesService.Get(c, input.Category.ID)
+	catcheck, err := h.CategoriesService.Get(ctx, input.Category.ID)
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 	if catcheck.Name != input.Category.Name {
-		c.JSON(http.StatusBadRequest, apierrors.NewApiError("category name does not match with the existing cat for "+catcheck.ID, "bad_request", http.StatusBadRequest, apierrors.CauseList{}))
+		problems.Respond(c, categoryMismatch(catcheck.ID))
 		return
 	}
+	input.Category.NameI18n = catcheck.NameI18n
+	input.Category.Slug = catcheck.Slug
 
 	apiErr := h.Service.Update(ctx, itemID, input)
 	if apiErr != nil {
 		logger.Error("error update items by id ", apiErr)
-		c.JSON(apiErr.Status(), apiErr)
+		problems.Respond(c, apiErr)
 		return
 	}
 
@@ -303,7 +461,7 @@ func (h ItemsHandler) UpdateItem(c *gin.Context) {
 
 // DeleteItem godoc
 // @Summary Delete item
-// @Description Delete item by ID
+// @Description Delete item and its price by ID for good. To put it in the trash instead, update its status to deleted
 // @Tags Items
 // @Accept  json
 // @Produce  json
@@ -320,15 +478,158 @@ func (h ItemsHandler) DeleteItem(c *gin.Context) {
 	itemID := c.Param("id")
 	err := utils.ValidateHexID([]string{itemID})
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
 		return
 	}
 
 	err = h.Service.Delete(ctx, itemID)
 	if err != nil {
-		c.JSON(err.Status(), err)
+		problems.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetItemTranslation godoc
+// @Summary Set item translation
+// @Description Create or replace the name and description of an item for a locale
+// @Tags Items
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Item ID"
+// @Param locale path string true "Locale"
+// @Param translation body dto.ItemTranslationDTO true "Translation"
+// @Success 204
+// @Router /items/{id}/translations/{locale} [put]
+func (h ItemsHandler) SetItemTranslation(c *gin.Context) {
+	var input dto.ItemTranslationDTO
+
+	xTraceId, _ := c.Get("X-Trace-ID")
+	ctx := context.WithValue(c.Request.Context(), tracing.XtraceHeaderKey, xTraceId)
+
+	if err := binding.JSON.Bind(c.Request, &input); err != nil {
+		apiErr := problems.BindError(err)
+		problems.Respond(c, apiErr)
+		return
+	}
+
+	userID, err1 := goauth.GetUserId(c)
+	if err1 != nil {
+		apiErr := apierrors.NewUnauthorizedApiError(err1.Error())
+		problems.Respond(c, apiErr)
+		return
+	}
+	ctx = actor.With(ctx, userID)
+
+	itemID := c.Param("id")
+	err := utils.ValidateHexID([]string{itemID})
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	locale := c.Param("locale")
+	err = utils.ValidateLocale(locale)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	translation := models.ItemTranslation{Name: input.Name, Description: input.Description}
+
+	err = h.Service.SetTranslation(ctx, userID, itemID, locale, translation)
+	if err != nil {
+		problems.Respond(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+// DeleteItemTranslation godoc
+// @Summary Delete item translation
+// @Description Delete the name and description of an item for a locale
+// @Tags Items
+// @Produce  json
+// @Param id path string true "Item ID"
+// @Param locale path string true "Locale"
+// @Success 204
+// @Router /items/{id}/translations/{locale} [delete]
+func (h ItemsHandler) DeleteItemTranslation(c *gin.Context) {
+	xTraceId, _ := c.Get("X-Trace-ID")
+	ctx := context.WithValue(c.Request.Context(), tracing.XtraceHeaderKey, xTraceId)
+
+	userID, err1 := goauth.GetUserId(c)
+	if err1 != nil {
+		apiErr := apierrors.NewUnauthorizedApiError(err1.Error())
+		problems.Respond(c, apiErr)
+		return
+	}
+	ctx = actor.With(ctx, userID)
+
+	itemID := c.Param("id")
+	err := utils.ValidateHexID([]string{itemID})
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	locale := c.Param("locale")
+	err = utils.ValidateLocale(locale)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	err = h.Service.DeleteTranslation(ctx, userID, itemID, locale)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func categoryMismatch(categoryID string) apierrors.ApiError {
+	return catalog.ValidationFailed.New("the category name does not match the name of category "+categoryID, problems.FieldError{
+		Path:   "$.category.name",
+		Code:   "mismatch",
+		Detail: "must be the name of category " + categoryID,
+	})
+}
+
+// validateItemTranslations checks the locales of the translations the body
+// sets, which like the translation endpoints can't be the default one.
+func validateItemTranslations(input dto.ItemDTO) apierrors.ApiError {
+	if err := utils.ValidateTranslations("name_i18n", input.NameI18n); err != nil {
+		return err
+	}
+
+	return utils.ValidateTranslations("description_i18n", input.DescriptionI18n)
+}
+
+// intQuery reads an optional integer query param between min and max.
+// pageQuery reads the limit and offset query parameters into query.
+func pageQuery(c *gin.Context, query *models.ItemsQuery) apierrors.ApiError {
+	var err apierrors.ApiError
+	if query.Limit, err = intQuery(c, "limit", models.DefaultItemsPageSize, 1, models.MaxItemsPageSize); err != nil {
+		return err
+	}
+	query.Offset, err = intQuery(c, "offset", 0, 0, math.MaxInt32)
+	return err
+}
+
+func intQuery(c *gin.Context, name string, def int, min int, max int) (int, apierrors.ApiError) {
+	raw := c.Query(name)
+	if raw == "" {
+		return def, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < min || value > max {
+		return 0, catalog.BadRequest.New(fmt.Sprintf("%s must be an integer between %d and %d", name, min, max))
+	}
+
+	return value, nil
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"github.com/agustinrabini/items-api-project/src/main/domain/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestLocale resolves the locale of a read request from the lang query
+// parameter or the Accept-Language header and echoes it in Content-Language.
+func requestLocale(c *gin.Context) string {
+	locale := utils.ResolveLocale(c.GetHeader(utils.AcceptLanguageHeader), c.Query(utils.LangQueryParam))
+	c.Header(utils.ContentLanguageKey, locale)
+
+	return locale
+}
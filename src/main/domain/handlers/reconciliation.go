@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/problems"
+	"github.com/agustinrabini/items-api-project/src/main/domain/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReconciliationHandler struct {
+	Service services.ReconciliationService
+}
+
+func NewReconciliationHandler(service services.ReconciliationService) ReconciliationHandler {
+	return ReconciliationHandler{
+		Service: service,
+	}
+}
+
+// LastReport godoc
+// @Summary Price reconciliation report
+// @Description Get the report of the last run of the price reconciliation, scheduled or from items-admin
+// @Tags Admin
+// @Produce  json
+// @Success 200 {object} models.ReconciliationReport
+// @Router /admin/reconciliation [get]
+func (h ReconciliationHandler) LastReport(c *gin.Context) {
+	report, err := h.Service.LastReport(c.Request.Context())
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/problems"
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/agustinrabini/items-api-project/src/main/domain/services"
+	"github.com/agustinrabini/items-api-project/src/main/domain/utils"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/actor"
+
+	"github.com/jopitnow/go-jopit-toolkit/goauth"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/jopitnow/go-jopit-toolkit/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errorInvalidRevision = catalog.BadRequest.New("the revision must be a positive number")
+
+type RevisionsHandler struct {
+	Service services.RevisionsService
+}
+
+func NewRevisionsHandler(service services.RevisionsService) RevisionsHandler {
+	return RevisionsHandler{
+		Service: service,
+	}
+}
+
+// ListRevisions godoc
+// @Summary List item revisions
+// @Description List the revisions of an item of the user, oldest first, with the fields each one changed
+// @Tags Items
+// @Produce  json
+// @Param id path string true "Item ID"
+// @Success 200 {array} models.Revision
+// @Router /items/{id}/revisions [get]
+func (h RevisionsHandler) ListRevisions(c *gin.Context) {
+	userID, err1 := goauth.GetUserId(c)
+	if err1 != nil {
+		apiErr := apierrors.NewUnauthorizedApiError(err1.Error())
+		problems.Respond(c, apiErr)
+		return
+	}
+
+	itemID := c.Param("id")
+	err := utils.ValidateHexID([]string{itemID})
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	revisions, err := h.Service.List(c.Request.Context(), userID, itemID)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// GetRevision godoc
+// @Summary Get item revision
+// @Description Get a revision of an item of the user, with the item and price as they were
+// @Tags Items
+// @Produce  json
+// @Param id path string true "Item ID"
+// @Param rev path int true "Revision"
+// @Success 200 {object} models.Revision
+// @Router /items/{id}/revisions/{rev} [get]
+func (h RevisionsHandler) GetRevision(c *gin.Context) {
+	userID, err1 := goauth.GetUserId(c)
+	if err1 != nil {
+		apiErr := apierrors.NewUnauthorizedApiError(err1.Error())
+		problems.Respond(c, apiErr)
+		return
+	}
+
+	itemID, number, err := revisionParams(c)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	revision, err := h.Service.Get(c.Request.Context(), userID, itemID, number)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+// RevertRevision godoc
+// @Summary Revert item to a revision
+// @Description Update an item of the user to its state and price at a revision, recorded as a new revision
+// @Tags Items
+// @Produce  json
+// @Param id path string true "Item ID"
+// @Param rev path int true "Revision"
+// @Success 204
+// @Router /items/{id}/revisions/{rev}/revert [post]
+func (h RevisionsHandler) RevertRevision(c *gin.Context) {
+	xTraceId, _ := c.Get("X-Trace-ID")
+	ctx := context.WithValue(c.Request.Context(), tracing.XtraceHeaderKey, xTraceId)
+	ctx = context.WithValue(ctx, goauth.FirebaseAuthHeader, c.GetHeader("Authorization"))
+
+	userID, err1 := goauth.GetUserId(c)
+	if err1 != nil {
+		apiErr := apierrors.NewUnauthorizedApiError(err1.Error())
+		problems.Respond(c, apiErr)
+		return
+	}
+	ctx = actor.With(ctx, userID)
+
+	itemID, number, err := revisionParams(c)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	err = h.Service.Revert(ctx, userID, itemID, number)
+	if err != nil {
+		problems.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func revisionParams(c *gin.Context) (string, int, apierrors.ApiError) {
+	itemID := c.Param("id")
+	if err := utils.ValidateHexID([]string{itemID}); err != nil {
+		return "", 0, err
+	}
+
+	number, err := strconv.Atoi(c.Param("rev"))
+	if err != nil || number < 1 {
+		return "", 0, errorInvalidRevision
+	}
+
+	return itemID, number, nil
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// redirectToSlug answers a lookup made with an old slug with a permanent
+// redirect to the canonical one, keeping the query string (e.g. lang).
+func redirectToSlug(c *gin.Context, slug string, path string) {
+	location := path + slug
+	if c.Request.URL.RawQuery != "" {
+		location += "?" + c.Request.URL.RawQuery
+	}
+
+	c.Header("Location", location)
+	c.JSON(http.StatusMovedPermanently, models.SlugRedirect{Slug: slug, Location: location})
+}
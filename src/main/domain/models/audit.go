@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Page sizes of the audit log queries.
+const (
+	DefaultAuditPageSize = 50
+	MaxAuditPageSize     = 500
+)
+
+// AuditEntry records a request to an admin or mutating endpoint: who made it,
+// what it did and to which resource, and the state of the resource before
+// and after it. ClaimedActor is the name an admin sent along the shared
+// password, which nothing verifies. Entries are never changed or deleted.
+type AuditEntry struct {
+	ID           string                 `json:"id" bson:"_id,omitempty"`
+	Actor        string                 `json:"actor" bson:"actor"`
+	ClaimedActor string                 `json:"claimed_actor,omitempty" bson:"claimed_actor,omitempty"`
+	Action       string                 `json:"action" bson:"action"`
+	TargetType   string                 `json:"target_type" bson:"target_type"`
+	TargetID     string                 `json:"target_id,omitempty" bson:"target_id,omitempty"`
+	RequestID    string                 `json:"request_id,omitempty" bson:"request_id,omitempty"`
+	Method       string                 `json:"method" bson:"method"`
+	Path         string                 `json:"path" bson:"path"`
+	Status       int                    `json:"status" bson:"status"`
+	IP           string                 `json:"ip" bson:"ip"`
+	Before       map[string]interface{} `json:"before,omitempty" bson:"before,omitempty"`
+	After        map[string]interface{} `json:"after,omitempty" bson:"after,omitempty"`
+	CreatedAt    time.Time              `json:"created_at" bson:"created_at"`
+}
+
+// AuditQuery filters and pages the audit log, newest entries first. Empty
+// fields don't filter; From and To bound CreatedAt, both included.
+type AuditQuery struct {
+	Actor        string
+	ClaimedActor string
+	Action       string
+	TargetType   string
+	TargetID     string
+	From         time.Time
+	To           time.Time
+	Limit        int
+	Offset       int
+}
+
+// AuditPage is one page of the entries matching an AuditQuery, and how many
+// match in total.
+type AuditPage struct {
+	Entries []AuditEntry `json:"entries"`
+	Total   int64        `json:"total"`
+}
+
+// AuditState is the state of a resource as its JSON, so the log keeps what
+// the API shows of it. Nil is kept as no state.
+func AuditState(resource interface{}) map[string]interface{} {
+	if resource == nil {
+		return nil
+	}
+
+	content, err := json.Marshal(resource)
+	if err != nil {
+		return nil
+	}
+
+	var state map[string]interface{}
+	if err = json.Unmarshal(content, &state); err != nil {
+		return nil
+	}
+
+	return state
+}
@@ -0,0 +1,38 @@
+package models
+
+type Collection struct {
+	ID       string       `json:"id" bson:"_id,omitempty"`
+	ShopID   string       `json:"shop_id" bson:"shop_id"`
+	UserID   string       `json:"user_id" bson:"user_id"`
+	Name     string       `json:"name" bson:"name"`
+	NameI18n Translations `json:"name_i18n,omitempty" bson:"name_i18n,omitempty"`
+	Position int          `json:"position" bson:"position"`
+	ItemIDs  []string     `json:"item_ids" bson:"item_ids"`
+}
+
+type CollectionWithItems struct {
+	Collection
+	Items []Item `json:"items"`
+}
+
+func (c *Collection) Localize(locale string) {
+	c.Name = c.NameI18n.Resolve(locale, c.Name)
+}
+
+// SortItems returns the items following the order of the collection. Items
+// that no longer exist are skipped.
+func (c *Collection) SortItems(items Items) []Item {
+	byID := make(map[string]Item, len(items.Items))
+	for _, item := range items.Items {
+		byID[item.ID] = item
+	}
+
+	sorted := make([]Item, 0, len(c.ItemIDs))
+	for _, id := range c.ItemIDs {
+		if item, ok := byID[id]; ok {
+			sorted = append(sorted, item)
+		}
+	}
+
+	return sorted
+}
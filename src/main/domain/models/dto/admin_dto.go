@@ -0,0 +1,64 @@
+package dto
+
+import "github.com/agustinrabini/items-api-project/src/main/domain/models"
+
+// CategoriesRecountDTO reports how many items each category has. Resynced
+// categories had their copy in the items out of date; Orphans are the
+// categories the items point to that no longer exist.
+type CategoriesRecountDTO struct {
+	DryRun     bool                 `json:"dry_run"`
+	Categories []CategoryRecountDTO `json:"categories"`
+	Orphans    []CategoryRecountDTO `json:"orphans"`
+}
+
+type CategoryRecountDTO struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Items    int64  `json:"items"`
+	Resynced bool   `json:"resynced,omitempty"`
+}
+
+// ItemsExportDTO is the file written by items-admin export and read by
+// items-admin import.
+type ItemsExportDTO struct {
+	ShopID string        `json:"shop_id"`
+	Items  []models.Item `json:"items"`
+}
+
+// ItemsImportDTO reports what importing an items file did, or would do when
+// DryRun is set.
+type ItemsImportDTO struct {
+	DryRun    bool            `json:"dry_run"`
+	Created   []ItemImportDTO `json:"created"`
+	Updated   []ItemImportDTO `json:"updated"`
+	Unchanged []ItemImportDTO `json:"unchanged"`
+}
+
+// ItemImportDTO is one imported item. SourceID is its ID in the file, which
+// differs from ID when the item was created.
+type ItemImportDTO struct {
+	ID       string `json:"id,omitempty"`
+	SourceID string `json:"source_id,omitempty"`
+	Name     string `json:"name"`
+}
+
+type ItemsPurgeDTO struct {
+	DryRun bool            `json:"dry_run"`
+	Purged []ItemPurgedDTO `json:"purged"`
+}
+
+// ItemPurgedDTO is a deleted item. PriceError is set when its price could
+// not be deleted, which leaves an orphan price behind.
+type ItemPurgedDTO struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	ShopID     string `json:"shop_id"`
+	PriceError string `json:"price_error,omitempty"`
+}
+
+type CategoryReassignDTO struct {
+	DryRun bool            `json:"dry_run"`
+	From   string          `json:"from"`
+	To     models.Category `json:"to"`
+	Items  []string        `json:"items"`
+}
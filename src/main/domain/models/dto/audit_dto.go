@@ -0,0 +1,11 @@
+package dto
+
+import "github.com/agustinrabini/items-api-project/src/main/domain/models"
+
+// AuditPageDTO is one page of the audit log.
+type AuditPageDTO struct {
+	Entries []models.AuditEntry `json:"entries"`
+	Total   int64               `json:"total"`
+	Limit   int                 `json:"limit"`
+	Offset  int                 `json:"offset"`
+}
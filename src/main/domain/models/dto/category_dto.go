@@ -3,10 +3,29 @@ package dto
 import "github.com/agustinrabini/items-api-project/src/main/domain/models"
 
 type CategoryDTO struct {
-	ID   string `json:"id,omitempty"`
+	ID       string            `json:"id,omitempty"`
+	Name     string            `json:"name" binding:"required"`
+	NameI18n map[string]string `json:"name_i18n,omitempty"`
+	Slug     string            `json:"slug,omitempty"`
+}
+
+type CategoryTranslationDTO struct {
 	Name string `json:"name" binding:"required"`
 }
 
 type CategoriesDTO struct {
 	CategoryDTO []models.Category `json:"categories"`
 }
+
+type CategoriesCountDTO struct {
+	CategoryCountDTO []models.CategoryCount `json:"categories"`
+}
+
+// CategoriesImportDTO reports what importing a categories file did, or would
+// do when DryRun is set.
+type CategoriesImportDTO struct {
+	DryRun    bool              `json:"dry_run"`
+	Created   []models.Category `json:"created"`
+	Updated   []models.Category `json:"updated"`
+	Unchanged []models.Category `json:"unchanged"`
+}
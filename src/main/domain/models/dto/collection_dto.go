@@ -0,0 +1,7 @@
+package dto
+
+type CollectionDTO struct {
+	Name     string            `json:"name" binding:"required"`
+	NameI18n map[string]string `json:"name_i18n,omitempty"`
+	Position int               `json:"position"`
+}
@@ -8,15 +8,30 @@ import (
 )
 
 type ItemDTO struct {
-	Name        string        `json:"name" binding:"required"`
-	Description string        `json:"description" binding:"required"`
-	UserID      string        `json:"user_id"`
-	Status      string        `json:"status"`
-	Category    CategoryDTO   `json:"category" binding:"required"`
-	Price       PriceDTO      `json:"price" binding:"required"`
-	Images      []ImageDTO    `json:"images"`
-	Attributes  AttributesDTO `json:"attributes"`
-	Eligible    []EligibleDTO `json:"eligible"`
+	Name            string            `json:"name" binding:"required"`
+	NameI18n        map[string]string `json:"name_i18n,omitempty"`
+	Description     string            `json:"description" binding:"required"`
+	DescriptionI18n map[string]string `json:"description_i18n,omitempty"`
+	UserID          string            `json:"user_id"`
+	Status          string            `json:"status"`
+	Category        CategoryDTO       `json:"category" binding:"required"`
+	Price           PriceDTO          `json:"price" binding:"required"`
+	Images          []ImageDTO        `json:"images"`
+	Attributes      AttributesDTO     `json:"attributes"`
+	Eligible        []EligibleDTO     `json:"eligible"`
+}
+
+type ItemTranslationDTO struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// ItemsPageDTO is one page of the items search.
+type ItemsPageDTO struct {
+	Items  []models.Item `json:"items"`
+	Total  int64         `json:"total"`
+	Limit  int           `json:"limit"`
+	Offset int           `json:"offset"`
 }
 
 type ImageDTO string
@@ -33,3 +48,16 @@ func (i ItemDTO) ToItem() (models.Item, error) {
 	}
 	return item, nil
 }
+
+// NewItemDTO builds the request that updates an item to the given state and
+// price, the inverse of ToItem.
+func NewItemDTO(item models.Item, price models.Price) (ItemDTO, error) {
+	item.Price = price
+
+	request := ItemDTO{}
+	if err := mapstructure.Decode(item, &request); err != nil {
+		return ItemDTO{}, err
+	}
+
+	return request, nil
+}
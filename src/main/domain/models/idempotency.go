@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// IdempotencyRecord is what is kept of a request sent with an Idempotency-Key:
+// a fingerprint of the request and, once it finished, its response.
+type IdempotencyRecord struct {
+	Key         string    `bson:"_id"`
+	Fingerprint string    `bson:"fingerprint"`
+	Completed   bool      `bson:"completed"`
+	Status      int       `bson:"status,omitempty"`
+	ContentType string    `bson:"content_type,omitempty"`
+	Body        []byte    `bson:"body,omitempty"`
+	CreatedAt   time.Time `bson:"created_at"`
+}
@@ -9,22 +9,29 @@ type Items struct {
 }
 
 type Item struct {
-	ID          string     `json:"id" bson:"_id,omitempty"`
-	Name        string     `json:"name" binding:"required" bson:"name,$set,omitempty"`
-	ShopID      string     `json:"shop_id" bson:"shop_id,$set,omitempty"`
-	UserID      string     `json:"user_id" bson:"user_id,$set,omitempty" binding:"required"`
-	Category    Category   `json:"category" binding:"required" bson:"category,$set,omitempty"`
-	Price       Price      `json:"price" bson:"-"`
-	Description string     `json:"description" bson:"description,$set,omitempty"`
-	Status      string     `json:"status" bson:"status,omitempty" default:"active"`
-	Images      []Image    `json:"images" bson:"images,$set,omitempty"`
-	Attributes  Attributes `json:"attributes" bson:"attributes,$set,omitempty"`
-	Eligible    []Eligible `json:"eligible,omitempty" bson:"eligible,$set,omitempty"`
+	ID              string       `json:"id" bson:"_id,omitempty"`
+	Name            string       `json:"name" binding:"required" bson:"name,$set,omitempty"`
+	NameI18n        Translations `json:"name_i18n,omitempty" bson:"name_i18n,$set,omitempty"`
+	Slug            string       `json:"slug,omitempty" bson:"slug,$set,omitempty"`
+	SlugHistory     []string     `json:"-" bson:"slug_history,$set,omitempty"`
+	ShopID          string       `json:"shop_id" bson:"shop_id,$set,omitempty"`
+	UserID          string       `json:"user_id" bson:"user_id,$set,omitempty" binding:"required"`
+	Category        Category     `json:"category" binding:"required" bson:"category,$set,omitempty"`
+	Price           Price        `json:"price" bson:"-"`
+	Description     string       `json:"description" bson:"description,$set,omitempty"`
+	DescriptionI18n Translations `json:"description_i18n,omitempty" bson:"description_i18n,$set,omitempty"`
+	Status          string       `json:"status" bson:"status,omitempty" default:"active"`
+	Images          []Image      `json:"images" bson:"images,$set,omitempty"`
+	Attributes      Attributes   `json:"attributes" bson:"attributes,$set,omitempty"`
+	Eligible        []Eligible   `json:"eligible,omitempty" bson:"eligible,$set,omitempty"`
 }
 
 type Category struct {
-	ID   string `json:"id" bson:"_id,$set,omitempty"`
-	Name string `json:"name" binding:"required" bson:"name,$set,required"`
+	ID          string       `json:"id" bson:"_id,$set,omitempty"`
+	Name        string       `json:"name" binding:"required" bson:"name,$set,required"`
+	NameI18n    Translations `json:"name_i18n,omitempty" bson:"name_i18n,$set,omitempty"`
+	Slug        string       `json:"slug,omitempty" bson:"slug,$set,omitempty"`
+	SlugHistory []string     `json:"-" bson:"slug_history,$set,omitempty"`
 }
 
 type Eligible struct {
@@ -82,3 +89,8 @@ func (i *Items) GetItemsIds() []string {
 
 	return ids
 }
+
+type CategoryCount struct {
+	Category   `bson:",inline"`
+	ItemsCount int64 `json:"items_count" bson:"items_count"`
+}
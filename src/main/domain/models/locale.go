@@ -0,0 +1,54 @@
+package models
+
+const DefaultLocale = "es"
+
+var SupportedLocales = []string{"es", "pt"}
+
+// Translations maps a locale code (e.g. "pt") to the translated value. The
+// default locale is never stored here, it lives in the base field.
+type Translations map[string]string
+
+type ItemTranslation struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func IsSupportedLocale(locale string) bool {
+	for _, l := range SupportedLocales {
+		if l == locale {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resolve returns the translation for the locale, falling back to the base
+// value when the locale is the default one or has no translation.
+func (t Translations) Resolve(locale string, base string) string {
+	if locale == DefaultLocale {
+		return base
+	}
+
+	if value, ok := t[locale]; ok && value != "" {
+		return value
+	}
+
+	return base
+}
+
+func (c *Category) Localize(locale string) {
+	c.Name = c.NameI18n.Resolve(locale, c.Name)
+}
+
+func (i *Item) Localize(locale string) {
+	i.Name = i.NameI18n.Resolve(locale, i.Name)
+	i.Description = i.DescriptionI18n.Resolve(locale, i.Description)
+	i.Category.Localize(locale)
+}
+
+func (i *Items) Localize(locale string) {
+	for idx := range i.Items {
+		i.Items[idx].Localize(locale)
+	}
+}
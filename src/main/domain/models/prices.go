@@ -17,3 +17,9 @@ type Currency struct {
 type Prices struct {
 	Prices []Price `json:"prices"`
 }
+
+// PricedItems is a page of the items that have a price, sorted by item.
+type PricedItems struct {
+	ItemIDs []string `json:"item_ids"`
+	Total   int64    `json:"total"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Reasons of the findings of a ReconciliationReport.
+const (
+	ReconciliationMissingPrice = "missing_price"
+	ReconciliationOrphanPrice  = "orphan_price"
+	ReconciliationPriceBack    = "price_back"
+	// the item of the price is not in the collection anymore
+	ReconciliationPriceWithoutItem = "price_without_item"
+)
+
+// ReconciliationReport is the result of a run of the price reconciliation,
+// which compares the items with the prices API.
+type ReconciliationReport struct {
+	ID           string                  `json:"id" bson:"_id,omitempty"`
+	Repair       bool                    `json:"repair" bson:"repair"`
+	StartedAt    time.Time               `json:"started_at" bson:"started_at"`
+	FinishedAt   time.Time               `json:"finished_at" bson:"finished_at"`
+	ItemsChecked int                     `json:"items_checked" bson:"items_checked"`
+	Findings     []ReconciliationFinding `json:"findings" bson:"findings"`
+	// PriceScanError is why the prices without item couldn't be scanned,
+	// which leaves them out of the findings.
+	PriceScanError string `json:"price_scan_error,omitempty" bson:"price_scan_error,omitempty"`
+}
+
+// ReconciliationFinding is an item whose price doesn't match its status, or
+// a price without item, whose shop and status are empty. Repaired tells whether
+// the run fixed it, and Error why it couldn't.
+type ReconciliationFinding struct {
+	Reason   string `json:"reason" bson:"reason"`
+	ItemID   string `json:"item_id" bson:"item_id"`
+	ShopID   string `json:"shop_id" bson:"shop_id"`
+	Status   string `json:"status" bson:"status"`
+	PriceID  string `json:"price_id,omitempty" bson:"price_id,omitempty"`
+	Repaired bool   `json:"repaired" bson:"repaired"`
+	Error    string `json:"error,omitempty" bson:"error,omitempty"`
+}
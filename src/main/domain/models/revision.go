@@ -0,0 +1,126 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Actions of the revisions.
+const (
+	// RevisionInitial keeps the state of an item before its first recorded
+	// change, its price only known when that change is one of the price.
+	RevisionInitial      = "initial"
+	RevisionUpdate       = "update"
+	RevisionStatus       = "status"
+	RevisionCategorySync = "category_sync"
+	RevisionTranslation  = "translation"
+	RevisionPrice        = "price"
+)
+
+// Revision is the state of an item after one of its changes, with the fields
+// that changed. Revisions are numbered from 1 per item and never change.
+type Revision struct {
+	ItemID    string        `json:"item_id" bson:"item_id"`
+	Number    int           `json:"revision" bson:"revision"`
+	Action    string        `json:"action" bson:"action"`
+	Actor     string        `json:"actor,omitempty" bson:"actor,omitempty"`
+	CreatedAt time.Time     `json:"created_at" bson:"created_at"`
+	Changes   []FieldChange `json:"changes" bson:"changes"`
+	Item      *Item         `json:"item,omitempty" bson:"item,omitempty"`
+	Price     *Price        `json:"price,omitempty" bson:"price,omitempty"`
+}
+
+// FieldChange is a field of the item that changed, as a dotted path of its
+// JSON, e.g. description, images.0 or price.amount. A missing Before or After
+// means the field was added or removed.
+type FieldChange struct {
+	Field  string      `json:"field" bson:"field"`
+	Before interface{} `json:"before,omitempty" bson:"before,omitempty"`
+	After  interface{} `json:"after,omitempty" bson:"after,omitempty"`
+}
+
+// DiffItems compares two states of an item field by field, the prices
+// included when both are known. IDs are left out.
+func DiffItems(before Item, beforePrice *Price, after Item, afterPrice *Price) []FieldChange {
+	old, current := flatItem(before), flatItem(after)
+	if beforePrice != nil && afterPrice != nil {
+		flatten("price", *beforePrice, old)
+		flatten("price", *afterPrice, current)
+	}
+	delete(old, "price.id")
+	delete(current, "price.id")
+	delete(old, "price.item_id")
+	delete(current, "price.item_id")
+
+	fields := map[string]bool{}
+	for field := range old {
+		fields[field] = true
+	}
+	for field := range current {
+		fields[field] = true
+	}
+
+	var changes []FieldChange
+	for field := range fields {
+		if !reflect.DeepEqual(old[field], current[field]) {
+			changes = append(changes, FieldChange{Field: field, Before: old[field], After: current[field]})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+
+	return changes
+}
+
+// flatItem flattens the item without its ID and price, whose fields are all
+// empty then.
+func flatItem(item Item) map[string]interface{} {
+	item.ID = ""
+	item.Price = Price{}
+
+	flat := map[string]interface{}{}
+	flatten("", item, flat)
+
+	return flat
+}
+
+// flatten adds the leaves of the JSON of value to flat by their dotted path.
+// Empty values are left out, as they are not stored.
+func flatten(prefix string, value interface{}, flat map[string]interface{}) {
+	content, _ := json.Marshal(value)
+
+	var decoded interface{}
+	_ = json.Unmarshal(content, &decoded)
+
+	flattenValue(prefix, decoded, flat)
+}
+
+func flattenValue(path string, value interface{}, flat map[string]interface{}) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range typed {
+			flattenValue(join(path, key), nested, flat)
+		}
+	case []interface{}:
+		for idx, nested := range typed {
+			flattenValue(join(path, fmt.Sprint(idx)), nested, flat)
+		}
+	case nil:
+	default:
+		if typed != "" && typed != false && typed != float64(0) {
+			flat[path] = typed
+		}
+	}
+}
+
+func join(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
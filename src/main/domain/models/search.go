@@ -0,0 +1,35 @@
+package models
+
+// Page sizes of the paginated item queries.
+const (
+	DefaultItemsPageSize = 20
+	MaxItemsPageSize     = 100
+)
+
+// ItemsQuery filters and pages the items. Text matches the name or the
+// description, ignoring case; an empty ShopID or Text doesn't filter.
+type ItemsQuery struct {
+	ShopID string
+	Text   string
+	Limit  int
+	Offset int
+}
+
+// ItemsPage is one page of the items matching an ItemsQuery, and how many
+// match in total.
+type ItemsPage struct {
+	Items []Item `json:"items"`
+	Total int64  `json:"total"`
+}
+
+// Localize resolves the translated fields of every item of the page.
+func (p *ItemsPage) Localize(locale string) {
+	for idx := range p.Items {
+		p.Items[idx].Localize(locale)
+	}
+}
+
+// HasMore tells whether there are items after the page.
+func (p ItemsPage) HasMore(query ItemsQuery) bool {
+	return int64(query.Offset+len(p.Items)) < p.Total
+}
@@ -0,0 +1,109 @@
+package models
+
+import "fmt"
+
+// Statuses of the items. Only active items can be sold. Deleted ones are in
+// the trash until items-admin purge-trash removes them; DELETE /items/:id
+// doesn't use the trash, it removes the item and its price. Unpriced ones were
+// marked by the price reconciliation and can't be bought until their price
+// exists again.
+const (
+	ItemStatusActive   = "active"
+	ItemStatusDeleted  = "deleted"
+	ItemStatusUnpriced = "unpriced"
+)
+
+// Codes of the violations reported by Item.ValidateSelection.
+const (
+	ViolationItemUnavailable  = "item_unavailable"
+	ViolationUnknownEligible  = "unknown_eligible"
+	ViolationInvalidOption    = "invalid_option"
+	ViolationRepeatedEligible = "repeated_eligible"
+	ViolationMissingRequired  = "missing_required"
+)
+
+// Selection is the option a buyer chose for one of the eligibles of an item.
+type Selection struct {
+	EligibleID string `json:"eligible_id"`
+	Option     string `json:"option"`
+}
+
+type SelectionViolation struct {
+	EligibleID string `json:"eligible_id,omitempty"`
+	Code       string `json:"code"`
+	Detail     string `json:"detail"`
+}
+
+// ValidateSelection reports every problem of selections at once: the item not
+// being active, eligibles it doesn't have or chosen twice, options it doesn't
+// offer and required eligibles left out. No violations means the item can be
+// bought with that selection.
+func (i Item) ValidateSelection(selections []Selection) []SelectionViolation {
+	var violations []SelectionViolation
+
+	if i.Status != "" && i.Status != ItemStatusActive {
+		violations = append(violations, SelectionViolation{
+			Code:   ViolationItemUnavailable,
+			Detail: fmt.Sprintf("the item is %s", i.Status),
+		})
+	}
+
+	eligibles := make(map[string]Eligible, len(i.Eligible))
+	for _, eligible := range i.Eligible {
+		eligibles[eligible.ID] = eligible
+	}
+
+	chosen := make(map[string]bool, len(selections))
+	for _, selection := range selections {
+		eligible, ok := eligibles[selection.EligibleID]
+		if !ok {
+			violations = append(violations, SelectionViolation{
+				EligibleID: selection.EligibleID,
+				Code:       ViolationUnknownEligible,
+				Detail:     "the item has no such eligible",
+			})
+			continue
+		}
+
+		if chosen[selection.EligibleID] {
+			violations = append(violations, SelectionViolation{
+				EligibleID: selection.EligibleID,
+				Code:       ViolationRepeatedEligible,
+				Detail:     fmt.Sprintf("%s was chosen more than once", eligible.Title),
+			})
+			continue
+		}
+		chosen[selection.EligibleID] = true
+
+		if !eligible.Offers(selection.Option) {
+			violations = append(violations, SelectionViolation{
+				EligibleID: selection.EligibleID,
+				Code:       ViolationInvalidOption,
+				Detail:     fmt.Sprintf("%q is not an option of %s", selection.Option, eligible.Title),
+			})
+		}
+	}
+
+	for _, eligible := range i.Eligible {
+		if eligible.IsRequired && !chosen[eligible.ID] {
+			violations = append(violations, SelectionViolation{
+				EligibleID: eligible.ID,
+				Code:       ViolationMissingRequired,
+				Detail:     fmt.Sprintf("%s is required", eligible.Title),
+			})
+		}
+	}
+
+	return violations
+}
+
+// Offers tells whether option is one of the options of the eligible.
+func (e Eligible) Offers(option string) bool {
+	for _, offered := range e.Options {
+		if string(offered) == option {
+			return true
+		}
+	}
+
+	return false
+}
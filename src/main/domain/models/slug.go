@@ -0,0 +1,37 @@
+package models
+
+// SlugRedirect is returned when a resource is requested with one of its
+// previous slugs, pointing the client to the canonical one.
+type SlugRedirect struct {
+	Slug     string `json:"slug"`
+	Location string `json:"location"`
+}
+
+// SetSlug changes the slug of the category keeping the previous one in the
+// history, so old URLs keep resolving.
+func (c *Category) SetSlug(slug string) {
+	c.Slug, c.SlugHistory = nextSlug(c.Slug, slug, c.SlugHistory)
+}
+
+// SetSlug changes the slug of the item keeping the previous one in the history.
+func (i *Item) SetSlug(slug string) {
+	i.Slug, i.SlugHistory = nextSlug(i.Slug, slug, i.SlugHistory)
+}
+
+func nextSlug(current string, next string, history []string) (string, []string) {
+	if current == next {
+		return current, history
+	}
+
+	updated := make([]string, 0, len(history)+1)
+	for _, old := range history {
+		if old != next {
+			updated = append(updated, old)
+		}
+	}
+	if current != "" {
+		updated = append(updated, current)
+	}
+
+	return next, updated
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	AuditDatabaseError = "[%s] Error in DB"
+
+	auditRepositoryName = "audit"
+)
+
+// AuditRepository is the audit log. It is append-only: there is no way to
+// change or delete an entry through it.
+type AuditRepository interface {
+	Save(ctx context.Context, entry models.AuditEntry) apierrors.ApiError
+	Search(ctx context.Context, query models.AuditQuery) (models.AuditPage, apierrors.ApiError)
+}
+
+type auditRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewAuditRepository(collection *mongo.Collection) AuditRepository {
+	return &auditRepository{Collection: collection}
+}
+
+func (storage *auditRepository) Save(ctx context.Context, entry models.AuditEntry) apierrors.ApiError {
+	ctx, end := observe(ctx, auditRepositoryName, "Save")
+	defer end()
+
+	if _, err := storage.Collection.InsertOne(ctx, entry); err != nil {
+		return apierrors.NewInternalServerApiError(fmt.Sprintf(AuditDatabaseError, "Save"), err)
+	}
+
+	return nil
+}
+
+// Search returns a page of the entries matching query, newest first. An
+// empty page is not an error.
+func (storage *auditRepository) Search(ctx context.Context, query models.AuditQuery) (models.AuditPage, apierrors.ApiError) {
+	ctx, end := observe(ctx, auditRepositoryName, "Search")
+	defer end()
+
+	filter := bson.M{}
+	if query.Actor != "" {
+		filter["actor"] = query.Actor
+	}
+	if query.ClaimedActor != "" {
+		filter["claimed_actor"] = query.ClaimedActor
+	}
+	if query.Action != "" {
+		filter["action"] = query.Action
+	}
+	if query.TargetType != "" {
+		filter["target_type"] = query.TargetType
+	}
+	if query.TargetID != "" {
+		filter["target_id"] = query.TargetID
+	}
+	if !query.From.IsZero() || !query.To.IsZero() {
+		createdAt := bson.M{}
+		if !query.From.IsZero() {
+			createdAt["$gte"] = query.From
+		}
+		if !query.To.IsZero() {
+			createdAt["$lte"] = query.To
+		}
+		filter["created_at"] = createdAt
+	}
+
+	total, err := storage.Collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return models.AuditPage{}, apierrors.NewInternalServerApiError(fmt.Sprintf(AuditDatabaseError, "Search"), err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"created_at": -1}).
+		SetSkip(int64(query.Offset)).
+		SetLimit(int64(query.Limit))
+
+	cursor, err := storage.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return models.AuditPage{}, apierrors.NewInternalServerApiError(fmt.Sprintf(AuditDatabaseError, "Search"), err)
+	}
+
+	entries := []models.AuditEntry{}
+	if err = cursor.All(ctx, &entries); err != nil {
+		return models.AuditPage{}, apierrors.NewInternalServerApiError(fmt.Sprintf(AuditDatabaseError, "Search"), err)
+	}
+
+	return models.AuditPage{Entries: entries, Total: total}, nil
+}
@@ -16,6 +16,8 @@ import (
 
 const (
 	CategoriesDatabaseError = "[%s] Error in DB"
+
+	categoriesRepositoryName = "categories"
 )
 
 var CategoriesItemNotFoundError = apierrors.NewNotFoundApiError("categories not found")
@@ -26,6 +28,12 @@ type CategoriesRepository interface {
 	Create(ctx context.Context, input models.Category) (interface{}, apierrors.ApiError)
 	Update(ctx context.Context, input models.Category) (int64, apierrors.ApiError)
 	Delete(ctx context.Context, categoryID string) (int64, apierrors.ApiError)
+
+	SetTranslation(ctx context.Context, categoryID string, locale string, name string) apierrors.ApiError
+	DeleteTranslation(ctx context.Context, categoryID string, locale string) apierrors.ApiError
+
+	GetBySlug(ctx context.Context, slug string) (models.Category, apierrors.ApiError)
+	SlugExists(ctx context.Context, slug string, excludeID string) (bool, apierrors.ApiError)
 }
 
 type categoriesRepository struct {
@@ -37,6 +45,9 @@ func NewCategoriesRepository(Collection *mongo.Collection) CategoriesRepository
 }
 
 func (storage *categoriesRepository) Get(ctx context.Context, categoryID string) (models.Category, apierrors.ApiError) {
+	ctx, end := observe(ctx, categoriesRepositoryName, "Get")
+	defer end()
+
 	var category models.Category
 
 	result, err := gonosql.Get(ctx, storage.Collection, categoryID)
@@ -61,6 +72,9 @@ func (storage *categoriesRepository) Get(ctx context.Context, categoryID string)
 }
 
 func (storage *categoriesRepository) GetAllCategories(ctx context.Context) ([]models.Category, apierrors.ApiError) {
+	ctx, end := observe(ctx, categoriesRepositoryName, "GetAllCategories")
+	defer end()
+
 	var categories []models.Category
 
 	filter := bson.M{}
@@ -78,6 +92,9 @@ func (storage *categoriesRepository) GetAllCategories(ctx context.Context) ([]mo
 }
 
 func (storage *categoriesRepository) Create(ctx context.Context, input models.Category) (interface{}, apierrors.ApiError) {
+	ctx, end := observe(ctx, categoriesRepositoryName, "Create")
+	defer end()
+
 	result, err := gonosql.InsertOne(ctx, storage.Collection, input)
 	if err != nil {
 		return nil, apierrors.NewInternalServerApiError(fmt.Sprintf(CategoriesDatabaseError, "Save"), err)
@@ -91,15 +108,27 @@ func (storage *categoriesRepository) Create(ctx context.Context, input models.Ca
 }
 
 func (storage *categoriesRepository) Update(ctx context.Context, input models.Category) (int64, apierrors.ApiError) {
+	ctx, end := observe(ctx, categoriesRepositoryName, "Update")
+	defer end()
+
 	primitiveID, err := primitive.ObjectIDFromHex(input.ID)
 	if err != nil {
 		return -1, apierrors.NewInternalServerApiError(fmt.Sprintf(CategoriesDatabaseError, "Update"), err)
 	}
 
+	set := bson.M{
+		"name": input.Name,
+	}
+	if input.NameI18n != nil {
+		set["name_i18n"] = input.NameI18n
+	}
+	if input.Slug != "" {
+		set["slug"] = input.Slug
+		set["slug_history"] = input.SlugHistory
+	}
+
 	update := bson.M{
-		"$set": bson.M{
-			"name": input.Name,
-		},
+		"$set": set,
 	}
 
 	result, err := storage.Collection.UpdateOne(ctx, bson.M{"_id": primitiveID}, update)
@@ -115,6 +144,9 @@ func (storage *categoriesRepository) Update(ctx context.Context, input models.Ca
 }
 
 func (storage *categoriesRepository) Delete(ctx context.Context, categoryID string) (int64, apierrors.ApiError) {
+	ctx, end := observe(ctx, categoriesRepositoryName, "Delete")
+	defer end()
+
 	result, err := gonosql.Delete(ctx, storage.Collection, categoryID)
 	if err != nil {
 		return -1, apierrors.NewInternalServerApiError(fmt.Sprintf(CategoriesDatabaseError, "Delete"), err)
@@ -126,3 +158,80 @@ func (storage *categoriesRepository) Delete(ctx context.Context, categoryID stri
 
 	return result.DeletedCount, nil
 }
+
+func (storage *categoriesRepository) SetTranslation(ctx context.Context, categoryID string, locale string, name string) apierrors.ApiError {
+	ctx, end := observe(ctx, categoriesRepositoryName, "SetTranslation")
+	defer end()
+
+	return storage.updateTranslation(ctx, categoryID, "SetTranslation", bson.M{
+		"$set": bson.M{"name_i18n." + locale: name},
+	})
+}
+
+func (storage *categoriesRepository) DeleteTranslation(ctx context.Context, categoryID string, locale string) apierrors.ApiError {
+	ctx, end := observe(ctx, categoriesRepositoryName, "DeleteTranslation")
+	defer end()
+
+	return storage.updateTranslation(ctx, categoryID, "DeleteTranslation", bson.M{
+		"$unset": bson.M{"name_i18n." + locale: ""},
+	})
+}
+
+func (storage *categoriesRepository) updateTranslation(ctx context.Context, categoryID string, operation string, update bson.M) apierrors.ApiError {
+	primitiveID, err := primitive.ObjectIDFromHex(categoryID)
+	if err != nil {
+		return apierrors.NewInternalServerApiError(fmt.Sprintf(CategoriesDatabaseError, operation), err)
+	}
+
+	result, err := storage.Collection.UpdateOne(ctx, bson.M{"_id": primitiveID}, update)
+	if err != nil {
+		return apierrors.NewInternalServerApiError(fmt.Sprintf(CategoriesDatabaseError, operation), err)
+	}
+
+	if result.MatchedCount == 0 {
+		return CategoriesItemNotFoundError
+	}
+
+	return nil
+}
+
+// GetBySlug looks the category up by its current slug or any of its previous ones.
+func (storage *categoriesRepository) GetBySlug(ctx context.Context, slug string) (models.Category, apierrors.ApiError) {
+	ctx, end := observe(ctx, categoriesRepositoryName, "GetBySlug")
+	defer end()
+
+	var category models.Category
+
+	result := storage.Collection.FindOne(ctx, slugFilter(slug))
+	if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+		return models.Category{}, CategoriesItemNotFoundError
+	}
+
+	if result.Err() != nil {
+		return models.Category{}, apierrors.NewInternalServerApiError(fmt.Sprintf(CategoriesDatabaseError, "GetBySlug"), result.Err())
+	}
+
+	err := result.Decode(&category)
+	if err != nil {
+		return models.Category{}, apierrors.NewInternalServerApiError(fmt.Sprintf(CategoriesDatabaseError, "GetBySlug"), err)
+	}
+
+	return category, nil
+}
+
+func (storage *categoriesRepository) SlugExists(ctx context.Context, slug string, excludeID string) (bool, apierrors.ApiError) {
+	ctx, end := observe(ctx, categoriesRepositoryName, "SlugExists")
+	defer end()
+
+	filter, err := slugExistsFilter(slug, excludeID)
+	if err != nil {
+		return false, apierrors.NewInternalServerApiError(fmt.Sprintf(CategoriesDatabaseError, "SlugExists"), err)
+	}
+
+	count, err := storage.Collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return false, apierrors.NewInternalServerApiError(fmt.Sprintf(CategoriesDatabaseError, "SlugExists"), err)
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+
+	"github.com/jopitnow/go-jopit-toolkit/gonosql"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	CollectionsDatabaseError = "[%s] Error in DB"
+
+	collectionsRepositoryName = "collections"
+)
+
+var CollectionNotFoundError = apierrors.NewNotFoundApiError("collection not found")
+
+type CollectionsRepository interface {
+	Get(ctx context.Context, collectionID string) (models.Collection, apierrors.ApiError)
+	GetByShopID(ctx context.Context, shopID string) ([]models.Collection, apierrors.ApiError)
+	Create(ctx context.Context, collection models.Collection) (interface{}, apierrors.ApiError)
+	Update(ctx context.Context, collection models.Collection) apierrors.ApiError
+	SetItems(ctx context.Context, collectionID string, itemIDs []string) apierrors.ApiError
+	Delete(ctx context.Context, collectionID string) (int64, apierrors.ApiError)
+}
+
+type collectionsRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewCollectionsRepository(collection *mongo.Collection) CollectionsRepository {
+	return &collectionsRepository{Collection: collection}
+}
+
+func (storage *collectionsRepository) Get(ctx context.Context, collectionID string) (models.Collection, apierrors.ApiError) {
+	ctx, end := observe(ctx, collectionsRepositoryName, "Get")
+	defer end()
+
+	var collection models.Collection
+
+	result, err := gonosql.Get(ctx, storage.Collection, collectionID)
+	if err != nil {
+		return models.Collection{}, apierrors.NewInternalServerApiError(fmt.Sprintf(CollectionsDatabaseError, "Get"), err)
+	}
+
+	if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+		return models.Collection{}, CollectionNotFoundError
+	}
+
+	if result.Err() != nil { // coverage-ignore
+		return models.Collection{}, apierrors.NewInternalServerApiError(fmt.Sprintf(CollectionsDatabaseError, "Get"), result.Err())
+	}
+
+	err = result.Decode(&collection)
+	if err != nil {
+		return models.Collection{}, apierrors.NewInternalServerApiError(fmt.Sprintf(CollectionsDatabaseError, "Get"), err)
+	}
+
+	return collection, nil
+}
+
+func (storage *collectionsRepository) GetByShopID(ctx context.Context, shopID string) ([]models.Collection, apierrors.ApiError) {
+	ctx, end := observe(ctx, collectionsRepositoryName, "GetByShopID")
+	defer end()
+
+	var collections []models.Collection
+
+	opts := options.Find().SetSort(primitive.D{{Key: "position", Value: 1}, {Key: "name", Value: 1}})
+
+	cursor, err := storage.Collection.Find(ctx, bson.M{"shop_id": shopID}, opts)
+	if err != nil {
+		return nil, apierrors.NewInternalServerApiError(fmt.Sprintf(CollectionsDatabaseError, "GetByShopID"), err)
+	}
+
+	if err = cursor.All(ctx, &collections); err != nil {
+		return nil, apierrors.NewInternalServerApiError(fmt.Sprintf(CollectionsDatabaseError, "GetByShopID"), err)
+	}
+
+	return collections, nil
+}
+
+func (storage *collectionsRepository) Create(ctx context.Context, collection models.Collection) (interface{}, apierrors.ApiError) {
+	ctx, end := observe(ctx, collectionsRepositoryName, "Create")
+	defer end()
+
+	result, err := gonosql.InsertOne(ctx, storage.Collection, collection)
+	if err != nil {
+		return nil, apierrors.NewInternalServerApiError(fmt.Sprintf(CollectionsDatabaseError, "Save"), err)
+	}
+
+	if result.InsertedID == nil || result.InsertedID == "" { // coverage-ignore
+		return -1, apierrors.NewInternalServerApiError(fmt.Sprintf(CollectionsDatabaseError, "Save"), errors.New("collection not created"))
+	}
+
+	return result.InsertedID, nil
+}
+
+func (storage *collectionsRepository) Update(ctx context.Context, collection models.Collection) apierrors.ApiError {
+	ctx, end := observe(ctx, collectionsRepositoryName, "Update")
+	defer end()
+
+	return storage.updateOne(ctx, collection.ID, "Update", bson.M{"$set": bson.M{
+		"name":      collection.Name,
+		"name_i18n": collection.NameI18n,
+		"position":  collection.Position,
+	}})
+}
+
+func (storage *collectionsRepository) SetItems(ctx context.Context, collectionID string, itemIDs []string) apierrors.ApiError {
+	ctx, end := observe(ctx, collectionsRepositoryName, "SetItems")
+	defer end()
+
+	return storage.updateOne(ctx, collectionID, "SetItems", bson.M{"$set": bson.M{
+		"item_ids": itemIDs,
+	}})
+}
+
+func (storage *collectionsRepository) Delete(ctx context.Context, collectionID string) (int64, apierrors.ApiError) {
+	ctx, end := observe(ctx, collectionsRepositoryName, "Delete")
+	defer end()
+
+	result, err := gonosql.Delete(ctx, storage.Collection, collectionID)
+	if err != nil {
+		return -1, apierrors.NewInternalServerApiError(fmt.Sprintf(CollectionsDatabaseError, "Delete"), err)
+	}
+
+	if result.DeletedCount == 0 {
+		return -1, CollectionNotFoundError
+	}
+
+	return result.DeletedCount, nil
+}
+
+func (storage *collectionsRepository) updateOne(ctx context.Context, collectionID string, operation string, update bson.M) apierrors.ApiError {
+	primitiveID, err := primitive.ObjectIDFromHex(collectionID)
+	if err != nil {
+		return apierrors.NewInternalServerApiError(fmt.Sprintf(CollectionsDatabaseError, operation), err)
+	}
+
+	result, err := storage.Collection.UpdateOne(ctx, bson.M{"_id": primitiveID}, update)
+	if err != nil {
+		return apierrors.NewInternalServerApiError(fmt.Sprintf(CollectionsDatabaseError, operation), err)
+	}
+
+	if result.MatchedCount == 0 {
+		return CollectionNotFoundError
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	driverbson "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	IdempotencyDatabaseError = "[%s] Error in DB"
+
+	idempotencyRepositoryName = "idempotency"
+	idempotencyTTLIndex       = "created_at_ttl"
+
+	// indexOptionsConflictCode is the Mongo error code of creating an index
+	// that exists with other options.
+	indexOptionsConflictCode = 85
+)
+
+type IdempotencyRepository interface {
+	// Reserve stores a new, not completed record. When the key is already
+	// taken it returns the stored record and false instead.
+	Reserve(ctx context.Context, record models.IdempotencyRecord) (models.IdempotencyRecord, bool, apierrors.ApiError)
+	Complete(ctx context.Context, key string, status int, contentType string, body []byte) apierrors.ApiError
+	Release(ctx context.Context, key string) apierrors.ApiError
+	EnsureIndexes(ctx context.Context, ttl time.Duration) apierrors.ApiError
+}
+
+type idempotencyRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewIdempotencyRepository(collection *mongo.Collection) IdempotencyRepository {
+	return &idempotencyRepository{Collection: collection}
+}
+
+func (storage *idempotencyRepository) Reserve(ctx context.Context, record models.IdempotencyRecord) (models.IdempotencyRecord, bool, apierrors.ApiError) {
+	ctx, end := observe(ctx, idempotencyRepositoryName, "Reserve")
+	defer end()
+
+	_, err := storage.Collection.InsertOne(ctx, record)
+	if err == nil {
+		return record, true, nil
+	}
+
+	if !mongo.IsDuplicateKeyError(err) {
+		return models.IdempotencyRecord{}, false, apierrors.NewInternalServerApiError(fmt.Sprintf(IdempotencyDatabaseError, "Reserve"), err)
+	}
+
+	var stored models.IdempotencyRecord
+
+	err = storage.Collection.FindOne(ctx, bson.M{"_id": record.Key}).Decode(&stored)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// released between the insert and the read, the caller may retry
+		return models.IdempotencyRecord{}, false, catalog.Conflict.New("the idempotency key was released, retry the request")
+	}
+
+	if err != nil {
+		return models.IdempotencyRecord{}, false, apierrors.NewInternalServerApiError(fmt.Sprintf(IdempotencyDatabaseError, "Reserve"), err)
+	}
+
+	return stored, false, nil
+}
+
+func (storage *idempotencyRepository) Complete(ctx context.Context, key string, status int, contentType string, body []byte) apierrors.ApiError {
+	ctx, end := observe(ctx, idempotencyRepositoryName, "Complete")
+	defer end()
+
+	update := bson.M{"$set": bson.M{
+		"completed":    true,
+		"status":       status,
+		"content_type": contentType,
+		"body":         body,
+	}}
+
+	_, err := storage.Collection.UpdateOne(ctx, bson.M{"_id": key}, update)
+	if err != nil {
+		return apierrors.NewInternalServerApiError(fmt.Sprintf(IdempotencyDatabaseError, "Complete"), err)
+	}
+
+	return nil
+}
+
+func (storage *idempotencyRepository) Release(ctx context.Context, key string) apierrors.ApiError {
+	ctx, end := observe(ctx, idempotencyRepositoryName, "Release")
+	defer end()
+
+	_, err := storage.Collection.DeleteOne(ctx, bson.M{"_id": key})
+	if err != nil {
+		return apierrors.NewInternalServerApiError(fmt.Sprintf(IdempotencyDatabaseError, "Release"), err)
+	}
+
+	return nil
+}
+
+// EnsureIndexes creates the TTL index that makes Mongo drop the records ttl
+// after they were created. When the index exists with another TTL it is
+// changed in place with collMod, as creating it again fails.
+func (storage *idempotencyRepository) EnsureIndexes(ctx context.Context, ttl time.Duration) apierrors.ApiError {
+	ctx, end := observe(ctx, idempotencyRepositoryName, "EnsureIndexes")
+	defer end()
+
+	index := mongo.IndexModel{
+		Keys:    bson.M{"created_at": 1},
+		Options: options.Index().SetName(idempotencyTTLIndex).SetExpireAfterSeconds(int32(ttl.Seconds())),
+	}
+
+	_, err := storage.Collection.Indexes().CreateOne(ctx, index)
+
+	var commandErr mongo.CommandError
+	if errors.As(err, &commandErr) && commandErr.Code == indexOptionsConflictCode {
+		command := driverbson.D{
+			{Key: "collMod", Value: storage.Collection.Name()},
+			{Key: "index", Value: driverbson.M{"name": idempotencyTTLIndex, "expireAfterSeconds": int32(ttl.Seconds())}},
+		}
+		err = storage.Collection.Database().RunCommand(ctx, command).Err()
+	}
+	if err != nil {
+		return apierrors.NewInternalServerApiError(fmt.Sprintf(IdempotencyDatabaseError, "EnsureIndexes"), err)
+	}
+
+	return nil
+}
@@ -5,21 +5,31 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
 
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
 	"github.com/agustinrabini/items-api-project/src/main/domain/models"
 	"github.com/jopitnow/go-jopit-toolkit/gonosql"
 	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"gopkg.in/mgo.v2/bson"
 )
 
 const (
 	ItemsDatabaseError = "[%s] Error in DB"
+
+	itemsRepositoryName = "items"
 )
 
 var ItemNotFoundError = apierrors.NewNotFoundApiError("item not found")
 var ItemsNotFoundError = apierrors.NewNotFoundApiError("items not found")
 
+// SlugTakenError is returned by Save and Update when another item got the
+// slug since it was checked, as the slug index is unique.
+var SlugTakenError = catalog.Conflict.New("the slug is taken")
+
 type ItemsRepository interface {
 	Get(ctx context.Context, itemID string) (models.Item, apierrors.ApiError)
 	GetByUserID(ctx context.Context, userID string) (models.Items, apierrors.ApiError)
@@ -32,6 +42,16 @@ type ItemsRepository interface {
 
 	UpdateItemsCategories(ctx context.Context, category *models.Category) apierrors.ApiError
 	GetByCategoryID(ctx context.Context, categoryID string) ([]models.Item, apierrors.ApiError)
+
+	SetTranslation(ctx context.Context, itemID string, locale string, translation models.ItemTranslation) apierrors.ApiError
+	DeleteTranslation(ctx context.Context, itemID string, locale string) apierrors.ApiError
+
+	CountByCategory(ctx context.Context, shopID string) ([]models.CategoryCount, apierrors.ApiError)
+	CountByCategoryID(ctx context.Context, categoryID string) (int64, apierrors.ApiError)
+
+	GetBySlug(ctx context.Context, slug string) (models.Item, apierrors.ApiError)
+	Search(ctx context.Context, query models.ItemsQuery) (models.ItemsPage, apierrors.ApiError)
+	SlugExists(ctx context.Context, slug string, excludeID string) (bool, apierrors.ApiError)
 }
 
 type itemsRepository struct {
@@ -43,6 +63,9 @@ func NewItemsRepository(collection *mongo.Collection) ItemsRepository {
 }
 
 func (storage *itemsRepository) Get(ctx context.Context, itemID string) (models.Item, apierrors.ApiError) {
+	ctx, end := observe(ctx, itemsRepositoryName, "Get")
+	defer end()
+
 	var model models.Item
 
 	cursor, err := gonosql.Get(ctx, storage.Collection, itemID)
@@ -67,6 +90,9 @@ func (storage *itemsRepository) Get(ctx context.Context, itemID string) (models.
 }
 
 func (storage *itemsRepository) GetByUserID(ctx context.Context, userID string) (models.Items, apierrors.ApiError) {
+	ctx, end := observe(ctx, itemsRepositoryName, "GetByUserID")
+	defer end()
+
 	var items []models.Item
 
 	cursor, err := gonosql.GetByKey(ctx, storage.Collection, "user_id", userID)
@@ -86,6 +112,9 @@ func (storage *itemsRepository) GetByUserID(ctx context.Context, userID string)
 }
 
 func (storage *itemsRepository) GetByShopID(ctx context.Context, shopID string) (models.Items, apierrors.ApiError) {
+	ctx, end := observe(ctx, itemsRepositoryName, "GetByShopID")
+	defer end()
+
 	var items []models.Item
 
 	cursor, err := gonosql.GetByKey(ctx, storage.Collection, "shop_id", shopID)
@@ -105,6 +134,9 @@ func (storage *itemsRepository) GetByShopID(ctx context.Context, shopID string)
 }
 
 func (storage *itemsRepository) GetByShopCategoryID(ctx context.Context, shopID string, categoryID string) (models.Items, apierrors.ApiError) {
+	ctx, end := observe(ctx, itemsRepositoryName, "GetByShopCategoryID")
+	defer end()
+
 	var items []models.Item
 	var filter = bson.M{"shop_id": shopID, "category._id": categoryID}
 
@@ -125,6 +157,9 @@ func (storage *itemsRepository) GetByShopCategoryID(ctx context.Context, shopID
 }
 
 func (storage *itemsRepository) GetByIDs(ctx context.Context, itemsIDs []string) (models.Items, apierrors.ApiError) {
+	ctx, end := observe(ctx, itemsRepositoryName, "GetByIDs")
+	defer end()
+
 	var items []models.Item
 
 	cursor, err := gonosql.GetByIDs(ctx, storage.Collection, itemsIDs)
@@ -144,7 +179,14 @@ func (storage *itemsRepository) GetByIDs(ctx context.Context, itemsIDs []string)
 }
 
 func (storage *itemsRepository) Save(ctx context.Context, item models.Item) (interface{}, apierrors.ApiError) {
+	ctx, end := observe(ctx, itemsRepositoryName, "Save")
+	defer end()
+
 	result, err := gonosql.InsertOne(ctx, storage.Collection, item)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, SlugTakenError
+	}
+
 	if err != nil {
 		return nil, apierrors.NewInternalServerApiError(fmt.Sprintf(ItemsDatabaseError, "Save"), err)
 	}
@@ -157,7 +199,14 @@ func (storage *itemsRepository) Save(ctx context.Context, item models.Item) (int
 }
 
 func (storage *itemsRepository) Update(ctx context.Context, itemID string, updateItem *models.Item) (int64, apierrors.ApiError) {
+	ctx, end := observe(ctx, itemsRepositoryName, "Update")
+	defer end()
+
 	result, err := gonosql.Update(ctx, storage.Collection, itemID, updateItem)
+	if mongo.IsDuplicateKeyError(err) {
+		return -1, SlugTakenError
+	}
+
 	if err != nil {
 		return -1, apierrors.NewInternalServerApiError(fmt.Sprintf(ItemsDatabaseError, "Update"), err)
 	}
@@ -170,6 +219,9 @@ func (storage *itemsRepository) Update(ctx context.Context, itemID string, updat
 }
 
 func (storage *itemsRepository) Delete(ctx context.Context, itemID string) (int64, apierrors.ApiError) {
+	ctx, end := observe(ctx, itemsRepositoryName, "Delete")
+	defer end()
+
 	result, err := gonosql.Delete(ctx, storage.Collection, itemID)
 	if err != nil {
 		return -1, apierrors.NewInternalServerApiError(fmt.Sprintf(ItemsDatabaseError, "Delete"), err)
@@ -183,12 +235,21 @@ func (storage *itemsRepository) Delete(ctx context.Context, itemID string) (int6
 }
 
 func (storage *itemsRepository) UpdateItemsCategories(ctx context.Context, category *models.Category) apierrors.ApiError {
+	ctx, end := observe(ctx, itemsRepositoryName, "UpdateItemsCategories")
+	defer end()
 
 	filter := bson.M{"category._id": category.ID}
-	update := bson.M{"$set": bson.M{
+	set := bson.M{
 		"category._id":  category.ID,
 		"category.name": category.Name,
-	}}
+	}
+	if category.NameI18n != nil {
+		set["category.name_i18n"] = category.NameI18n
+	}
+	if category.Slug != "" {
+		set["category.slug"] = category.Slug
+	}
+	update := bson.M{"$set": set}
 
 	res, err := storage.Collection.UpdateMany(ctx, filter, update)
 	if err != nil {
@@ -203,6 +264,8 @@ func (storage *itemsRepository) UpdateItemsCategories(ctx context.Context, categ
 }
 
 func (storage *itemsRepository) GetByCategoryID(ctx context.Context, categoryID string) ([]models.Item, apierrors.ApiError) {
+	ctx, end := observe(ctx, itemsRepositoryName, "GetByCategoryID")
+	defer end()
 
 	var model []models.Item
 
@@ -224,3 +287,197 @@ func (storage *itemsRepository) GetByCategoryID(ctx context.Context, categoryID
 
 	return model, nil
 }
+
+func (storage *itemsRepository) SetTranslation(ctx context.Context, itemID string, locale string, translation models.ItemTranslation) apierrors.ApiError {
+	ctx, end := observe(ctx, itemsRepositoryName, "SetTranslation")
+	defer end()
+
+	primitiveID, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		return apierrors.NewInternalServerApiError(fmt.Sprintf(ItemsDatabaseError, "SetTranslation"), err)
+	}
+
+	set := bson.M{"name_i18n." + locale: translation.Name}
+	update := bson.M{"$set": set}
+
+	if translation.Description != "" {
+		set["description_i18n."+locale] = translation.Description
+	} else {
+		update["$unset"] = bson.M{"description_i18n." + locale: ""}
+	}
+
+	result, err := storage.Collection.UpdateOne(ctx, bson.M{"_id": primitiveID}, update)
+	if err != nil {
+		return apierrors.NewInternalServerApiError(fmt.Sprintf(ItemsDatabaseError, "SetTranslation"), err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ItemNotFoundError
+	}
+
+	return nil
+}
+
+func (storage *itemsRepository) DeleteTranslation(ctx context.Context, itemID string, locale string) apierrors.ApiError {
+	ctx, end := observe(ctx, itemsRepositoryName, "DeleteTranslation")
+	defer end()
+
+	primitiveID, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		return apierrors.NewInternalServerApiError(fmt.Sprintf(ItemsDatabaseError, "DeleteTranslation"), err)
+	}
+
+	update := bson.M{"$unset": bson.M{
+		"name_i18n." + locale:        "",
+		"description_i18n." + locale: "",
+	}}
+
+	result, err := storage.Collection.UpdateOne(ctx, bson.M{"_id": primitiveID}, update)
+	if err != nil {
+		return apierrors.NewInternalServerApiError(fmt.Sprintf(ItemsDatabaseError, "DeleteTranslation"), err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ItemNotFoundError
+	}
+
+	return nil
+}
+
+// CountByCategory aggregates the amount of active items per category,
+// restricted to a shop when shopID is not empty. Legacy items without a status
+// count as active; deleted and unpriced ones aren't counted. Category names
+// come from the copy embedded in the items, which UpdateItemsCategories keeps
+// in sync.
+func (storage *itemsRepository) CountByCategory(ctx context.Context, shopID string) ([]models.CategoryCount, apierrors.ApiError) {
+	ctx, end := observe(ctx, itemsRepositoryName, "CountByCategory")
+	defer end()
+
+	var counts []models.CategoryCount
+
+	match := bson.M{"$or": []bson.M{
+		{"status": models.ItemStatusActive},
+		{"status": bson.M{"$exists": false}},
+		{"status": ""},
+	}}
+	if shopID != "" {
+		match["shop_id"] = shopID
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$group": bson.M{
+			"_id":         "$category._id",
+			"name":        bson.M{"$first": "$category.name"},
+			"name_i18n":   bson.M{"$first": "$category.name_i18n"},
+			"slug":        bson.M{"$first": "$category.slug"},
+			"items_count": bson.M{"$sum": 1},
+		}},
+		{"$sort": bson.M{"name": 1}},
+	}
+
+	cursor, err := storage.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, apierrors.NewInternalServerApiError(fmt.Sprintf(ItemsDatabaseError, "CountByCategory"), err)
+	}
+
+	if err = cursor.All(ctx, &counts); err != nil {
+		return nil, apierrors.NewInternalServerApiError(fmt.Sprintf(ItemsDatabaseError, "CountByCategory"), err)
+	}
+
+	return counts, nil
+}
+
+// CountByCategoryID counts every item of the category, whatever its status:
+// deleted and unpriced items still embed the category, so it can't be removed
+// while they exist.
+func (storage *itemsRepository) CountByCategoryID(ctx context.Context, categoryID string) (int64, apierrors.ApiError) {
+	ctx, end := observe(ctx, itemsRepositoryName, "CountByCategoryID")
+	defer end()
+
+	count, err := storage.Collection.CountDocuments(ctx, bson.M{"category._id": categoryID})
+	if err != nil {
+		return -1, apierrors.NewInternalServerApiError(fmt.Sprintf(ItemsDatabaseError, "CountByCategoryID"), err)
+	}
+
+	return count, nil
+}
+
+// GetBySlug looks the item up by its current slug or any of its previous ones.
+func (storage *itemsRepository) GetBySlug(ctx context.Context, slug string) (models.Item, apierrors.ApiError) {
+	ctx, end := observe(ctx, itemsRepositoryName, "GetBySlug")
+	defer end()
+
+	var item models.Item
+
+	result := storage.Collection.FindOne(ctx, slugFilter(slug))
+	if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+		return models.Item{}, ItemNotFoundError
+	}
+
+	if result.Err() != nil {
+		return models.Item{}, apierrors.NewInternalServerApiError(fmt.Sprintf(ItemsDatabaseError, "GetBySlug"), result.Err())
+	}
+
+	err := result.Decode(&item)
+	if err != nil {
+		return models.Item{}, apierrors.NewInternalServerApiError(fmt.Sprintf(ItemsDatabaseError, "GetBySlug"), err)
+	}
+
+	return item, nil
+}
+
+func (storage *itemsRepository) SlugExists(ctx context.Context, slug string, excludeID string) (bool, apierrors.ApiError) {
+	ctx, end := observe(ctx, itemsRepositoryName, "SlugExists")
+	defer end()
+
+	filter, err := slugExistsFilter(slug, excludeID)
+	if err != nil {
+		return false, apierrors.NewInternalServerApiError(fmt.Sprintf(ItemsDatabaseError, "SlugExists"), err)
+	}
+
+	count, err := storage.Collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return false, apierrors.NewInternalServerApiError(fmt.Sprintf(ItemsDatabaseError, "SlugExists"), err)
+	}
+
+	return count > 0, nil
+}
+
+// Search returns a page of the items matching query, sorted by creation. An
+// empty page is not an error.
+func (storage *itemsRepository) Search(ctx context.Context, query models.ItemsQuery) (models.ItemsPage, apierrors.ApiError) {
+	ctx, end := observe(ctx, itemsRepositoryName, "Search")
+	defer end()
+
+	filter := bson.M{}
+	if query.ShopID != "" {
+		filter["shop_id"] = query.ShopID
+	}
+	if query.Text != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(query.Text), Options: "i"}
+		filter["$or"] = []bson.M{{"name": pattern}, {"description": pattern}}
+	}
+
+	total, err := storage.Collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return models.ItemsPage{}, apierrors.NewInternalServerApiError(fmt.Sprintf(ItemsDatabaseError, "Search"), err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"_id": 1}).
+		SetSkip(int64(query.Offset)).
+		SetLimit(int64(query.Limit))
+
+	cursor, err := storage.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return models.ItemsPage{}, apierrors.NewInternalServerApiError(fmt.Sprintf(ItemsDatabaseError, "Search"), err)
+	}
+
+	items := []models.Item{}
+	if err = cursor.All(ctx, &items); err != nil {
+		return models.ItemsPage{}, apierrors.NewInternalServerApiError(fmt.Sprintf(ItemsDatabaseError, "Search"), err)
+	}
+
+	return models.ItemsPage{Items: items, Total: total}, nil
+}
@@ -0,0 +1,219 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type categoriesRepository struct {
+	mu         sync.RWMutex
+	categories documents
+}
+
+func NewCategoriesRepository() repositories.CategoriesRepository {
+	return &categoriesRepository{}
+}
+
+func (storage *categoriesRepository) Get(ctx context.Context, categoryID string) (models.Category, apierrors.ApiError) {
+	primitiveID, err := primitive.ObjectIDFromHex(categoryID)
+	if err != nil {
+		return models.Category{}, categoriesError("Get", err)
+	}
+
+	storage.mu.RLock()
+	defer storage.mu.RUnlock()
+
+	idx := storage.categories.indexOf(primitiveID)
+	if idx == -1 {
+		return models.Category{}, repositories.CategoriesItemNotFoundError
+	}
+
+	var category models.Category
+	if err = bson.Unmarshal(storage.categories.list[idx], &category); err != nil {
+		return models.Category{}, categoriesError("Get", err)
+	}
+
+	return category, nil
+}
+
+func (storage *categoriesRepository) GetAllCategories(ctx context.Context) ([]models.Category, apierrors.ApiError) {
+	return storage.find(func(models.Category) bool {
+		return true
+	})
+}
+
+func (storage *categoriesRepository) Create(ctx context.Context, input models.Category) (interface{}, apierrors.ApiError) {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	id, err := storage.categories.insert(input)
+	if err != nil {
+		return nil, categoriesError("Save", err)
+	}
+
+	return id, nil
+}
+
+// Update sets the name, the translations when given and the slugs when
+// given. Like the Mongo repository, it fails with a not found error when the
+// category is already as requested.
+func (storage *categoriesRepository) Update(ctx context.Context, input models.Category) (int64, apierrors.ApiError) {
+	primitiveID, err := primitive.ObjectIDFromHex(input.ID)
+	if err != nil {
+		return -1, categoriesError("Update", err)
+	}
+
+	fields := bson.D{{Key: "name", Value: input.Name}}
+	if input.NameI18n != nil {
+		fields = append(fields, bson.E{Key: "name_i18n", Value: input.NameI18n})
+	}
+	if input.Slug != "" {
+		fields = append(fields, bson.E{Key: "slug", Value: input.Slug}, bson.E{Key: "slug_history", Value: input.SlugHistory})
+	}
+
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	idx := storage.categories.indexOf(primitiveID)
+	if idx == -1 {
+		return -1, apierrors.NewNotFoundApiError(fmt.Sprintf(repositories.CategoriesDatabaseError, "Update"))
+	}
+
+	changed, err := storage.categories.set(idx, fields)
+	if err != nil {
+		return -1, categoriesError("Update", err)
+	}
+
+	if !changed {
+		return -1, apierrors.NewNotFoundApiError(fmt.Sprintf(repositories.CategoriesDatabaseError, "Update"))
+	}
+
+	return 1, nil
+}
+
+func (storage *categoriesRepository) Delete(ctx context.Context, categoryID string) (int64, apierrors.ApiError) {
+	primitiveID, err := primitive.ObjectIDFromHex(categoryID)
+	if err != nil {
+		return -1, categoriesError("Delete", err)
+	}
+
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	idx := storage.categories.indexOf(primitiveID)
+	if idx == -1 {
+		return -1, apierrors.NewNotFoundApiError(fmt.Sprintf(repositories.CategoriesDatabaseError, "Delete"))
+	}
+
+	storage.categories.remove(idx)
+	return 1, nil
+}
+
+func (storage *categoriesRepository) SetTranslation(ctx context.Context, categoryID string, locale string, name string) apierrors.ApiError {
+	return storage.updateTranslation(categoryID, "SetTranslation", func(category *models.Category) {
+		if category.NameI18n == nil {
+			category.NameI18n = models.Translations{}
+		}
+		category.NameI18n[locale] = name
+	})
+}
+
+func (storage *categoriesRepository) DeleteTranslation(ctx context.Context, categoryID string, locale string) apierrors.ApiError {
+	return storage.updateTranslation(categoryID, "DeleteTranslation", func(category *models.Category) {
+		delete(category.NameI18n, locale)
+	})
+}
+
+func (storage *categoriesRepository) updateTranslation(categoryID string, operation string, update func(*models.Category)) apierrors.ApiError {
+	primitiveID, err := primitive.ObjectIDFromHex(categoryID)
+	if err != nil {
+		return categoriesError(operation, err)
+	}
+
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	idx := storage.categories.indexOf(primitiveID)
+	if idx == -1 {
+		return repositories.CategoriesItemNotFoundError
+	}
+
+	var category models.Category
+	if err = bson.Unmarshal(storage.categories.list[idx], &category); err != nil {
+		return categoriesError(operation, err)
+	}
+
+	update(&category)
+
+	if _, err = storage.categories.replace(idx, category); err != nil {
+		return categoriesError(operation, err)
+	}
+
+	return nil
+}
+
+// GetBySlug looks the category up by its current slug or any of its previous ones.
+func (storage *categoriesRepository) GetBySlug(ctx context.Context, slug string) (models.Category, apierrors.ApiError) {
+	categories, apiErr := storage.find(func(category models.Category) bool {
+		return hasSlug(category.Slug, category.SlugHistory, slug)
+	})
+	if apiErr != nil {
+		return models.Category{}, apiErr
+	}
+
+	if len(categories) == 0 {
+		return models.Category{}, repositories.CategoriesItemNotFoundError
+	}
+
+	return categories[0], nil
+}
+
+func (storage *categoriesRepository) SlugExists(ctx context.Context, slug string, excludeID string) (bool, apierrors.ApiError) {
+	if excludeID != "" {
+		if _, err := primitive.ObjectIDFromHex(excludeID); err != nil {
+			return false, categoriesError("SlugExists", err)
+		}
+	}
+
+	categories, apiErr := storage.find(func(category models.Category) bool {
+		return category.ID != excludeID && hasSlug(category.Slug, category.SlugHistory, slug)
+	})
+	if apiErr != nil {
+		return false, apiErr
+	}
+
+	return len(categories) > 0, nil
+}
+
+// find returns the stored categories that match, in insertion order, and nil
+// when none does.
+func (storage *categoriesRepository) find(match func(models.Category) bool) ([]models.Category, apierrors.ApiError) {
+	storage.mu.RLock()
+	defer storage.mu.RUnlock()
+
+	var categories []models.Category
+	for _, raw := range storage.categories.list {
+		var category models.Category
+		if err := bson.Unmarshal(raw, &category); err != nil {
+			return nil, categoriesError("Find", err)
+		}
+
+		if match(category) {
+			categories = append(categories, category)
+		}
+	}
+
+	return categories, nil
+}
+
+func categoriesError(operation string, err error) apierrors.ApiError {
+	return apierrors.NewInternalServerApiError(fmt.Sprintf(repositories.CategoriesDatabaseError, operation), err)
+}
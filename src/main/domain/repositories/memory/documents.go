@@ -0,0 +1,146 @@
+// Package memory implements the repositories in memory, with the same
+// semantics as their Mongo implementations: same errors, filters and partial
+// updates. They are meant for tests and local runs, and are safe for
+// concurrent use.
+package memory
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// documents keeps the BSON documents of a collection in insertion order, the
+// natural order Mongo returns them in. Storing them encoded gives the same
+// field handling as Mongo (omitempty, bson:"-") and copies on every read and
+// write, so callers can't change the stored data. It is not safe for
+// concurrent use, the repositories lock around it.
+type documents struct {
+	list []bson.Raw
+}
+
+// insert stores model and returns its _id, a new ObjectID when model has
+// none.
+func (d *documents) insert(model interface{}) (interface{}, error) {
+	doc, err := toD(model)
+	if err != nil {
+		return nil, err
+	}
+
+	id, found := lookup(doc, "_id")
+	if !found {
+		id = primitive.NewObjectID()
+		doc = append(bson.D{{Key: "_id", Value: id}}, doc...)
+	}
+
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	d.list = append(d.list, raw)
+	return id, nil
+}
+
+// indexOf returns the position of the document with the given _id, or -1.
+func (d *documents) indexOf(id primitive.ObjectID) int {
+	for idx, raw := range d.list {
+		if value, ok := raw.Lookup("_id").ObjectIDOK(); ok && value == id {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// taken tells whether a document other than the one at except has the given
+// string value in field, like the duplicate key error of a unique index.
+func (d *documents) taken(field string, value string, except int) bool {
+	for idx, raw := range d.list {
+		if stored, ok := raw.Lookup(field).StringValueOK(); ok && stored == value && idx != except {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *documents) remove(idx int) {
+	d.list = append(d.list[:idx], d.list[idx+1:]...)
+}
+
+// set applies a $set of the given fields to the document at idx and tells
+// whether it changed, like the ModifiedCount of Mongo.
+func (d *documents) set(idx int, fields bson.D) (bool, error) {
+	doc, err := toD(d.list[idx])
+	if err != nil {
+		return false, err
+	}
+
+	for _, field := range fields {
+		if field.Key == "_id" {
+			continue
+		}
+		doc = put(doc, field.Key, field.Value)
+	}
+
+	return d.store(idx, doc)
+}
+
+// replace stores model at idx, keeping the _id of the stored document.
+func (d *documents) replace(idx int, model interface{}) (bool, error) {
+	doc, err := toD(model)
+	if err != nil {
+		return false, err
+	}
+
+	doc = put(doc, "_id", d.list[idx].Lookup("_id"))
+	return d.store(idx, doc)
+}
+
+func (d *documents) store(idx int, doc bson.D) (bool, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return false, err
+	}
+
+	changed := string(raw) != string(d.list[idx])
+	d.list[idx] = raw
+	return changed, nil
+}
+
+func toD(value interface{}) (bson.D, error) {
+	raw, err := bson.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc bson.D
+	if err = bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func lookup(doc bson.D, key string) (interface{}, bool) {
+	for _, field := range doc {
+		if field.Key == key {
+			return field.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// put sets key in doc, in place when it is already there so the encoded
+// document only changes when the value does.
+func put(doc bson.D, key string, value interface{}) bson.D {
+	for idx := range doc {
+		if doc[idx].Key == key {
+			doc[idx].Value = value
+			return doc
+		}
+	}
+
+	return append(doc, bson.E{Key: key, Value: value})
+}
@@ -0,0 +1,422 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type itemsRepository struct {
+	mu    sync.RWMutex
+	items documents
+}
+
+func NewItemsRepository() repositories.ItemsRepository {
+	return &itemsRepository{}
+}
+
+func (storage *itemsRepository) Get(ctx context.Context, itemID string) (models.Item, apierrors.ApiError) {
+	primitiveID, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		return models.Item{}, itemsError("Get", err)
+	}
+
+	storage.mu.RLock()
+	defer storage.mu.RUnlock()
+
+	idx := storage.items.indexOf(primitiveID)
+	if idx == -1 {
+		return models.Item{}, repositories.ItemNotFoundError
+	}
+
+	var item models.Item
+	if err = bson.Unmarshal(storage.items.list[idx], &item); err != nil {
+		return models.Item{}, itemsError("Get", err)
+	}
+
+	return item, nil
+}
+
+func (storage *itemsRepository) GetByUserID(ctx context.Context, userID string) (models.Items, apierrors.ApiError) {
+	return storage.getItems(func(item models.Item) bool {
+		return item.UserID == userID
+	})
+}
+
+func (storage *itemsRepository) GetByShopID(ctx context.Context, shopID string) (models.Items, apierrors.ApiError) {
+	return storage.getItems(func(item models.Item) bool {
+		return item.ShopID == shopID
+	})
+}
+
+func (storage *itemsRepository) GetByShopCategoryID(ctx context.Context, shopID string, categoryID string) (models.Items, apierrors.ApiError) {
+	return storage.getItems(func(item models.Item) bool {
+		return item.ShopID == shopID && item.Category.ID == categoryID
+	})
+}
+
+func (storage *itemsRepository) GetByIDs(ctx context.Context, itemsIDs []string) (models.Items, apierrors.ApiError) {
+	ids := make(map[string]bool, len(itemsIDs))
+	for _, itemID := range itemsIDs {
+		if _, err := primitive.ObjectIDFromHex(itemID); err != nil {
+			return models.Items{}, itemsError("Get", err)
+		}
+		ids[itemID] = true
+	}
+
+	return storage.getItems(func(item models.Item) bool {
+		return ids[item.ID]
+	})
+}
+
+// getItems answers the queries that fail with ItemsNotFoundError when
+// nothing matches.
+func (storage *itemsRepository) getItems(match func(models.Item) bool) (models.Items, apierrors.ApiError) {
+	items, apiErr := storage.find(match)
+	if apiErr != nil {
+		return models.Items{}, apiErr
+	}
+
+	if len(items) == 0 {
+		return models.Items{}, repositories.ItemsNotFoundError
+	}
+
+	return models.Items{Items: items}, nil
+}
+
+func (storage *itemsRepository) Save(ctx context.Context, item models.Item) (interface{}, apierrors.ApiError) {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	if item.Slug != "" && storage.items.taken("slug", item.Slug, -1) {
+		return nil, repositories.SlugTakenError
+	}
+
+	id, err := storage.items.insert(item)
+	if err != nil {
+		return nil, itemsError("Save", err)
+	}
+
+	return id, nil
+}
+
+// Update sets the fields of updateItem that are not empty, like the $set of
+// the Mongo repository.
+func (storage *itemsRepository) Update(ctx context.Context, itemID string, updateItem *models.Item) (int64, apierrors.ApiError) {
+	primitiveID, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		return -1, itemsError("Update", err)
+	}
+
+	fields, err := toD(updateItem)
+	if err != nil {
+		return -1, itemsError("Update", err)
+	}
+
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	idx := storage.items.indexOf(primitiveID)
+	if idx == -1 {
+		return -1, apierrors.NewNotFoundApiError(fmt.Sprintf(repositories.ItemsDatabaseError, "Update"))
+	}
+
+	if updateItem.Slug != "" && storage.items.taken("slug", updateItem.Slug, idx) {
+		return -1, repositories.SlugTakenError
+	}
+
+	changed, err := storage.items.set(idx, fields)
+	if err != nil {
+		return -1, itemsError("Update", err)
+	}
+
+	if !changed {
+		return 0, nil
+	}
+
+	return 1, nil
+}
+
+func (storage *itemsRepository) Delete(ctx context.Context, itemID string) (int64, apierrors.ApiError) {
+	primitiveID, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		return -1, itemsError("Delete", err)
+	}
+
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	idx := storage.items.indexOf(primitiveID)
+	if idx == -1 {
+		return -1, apierrors.NewNotFoundApiError(fmt.Sprintf(repositories.ItemsDatabaseError, "Delete"))
+	}
+
+	storage.items.remove(idx)
+	return 1, nil
+}
+
+func (storage *itemsRepository) UpdateItemsCategories(ctx context.Context, category *models.Category) apierrors.ApiError {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	matched := 0
+	for idx := range storage.items.list {
+		var item models.Item
+		if err := bson.Unmarshal(storage.items.list[idx], &item); err != nil {
+			return itemsError("UpdateItemsCategories", err)
+		}
+
+		if item.Category.ID != category.ID {
+			continue
+		}
+
+		item.Category.Name = category.Name
+		if category.NameI18n != nil {
+			item.Category.NameI18n = category.NameI18n
+		}
+		if category.Slug != "" {
+			item.Category.Slug = category.Slug
+		}
+
+		if _, err := storage.items.replace(idx, item); err != nil {
+			return itemsError("UpdateItemsCategories", err)
+		}
+		matched++
+	}
+
+	if matched == 0 {
+		return apierrors.NewApiError(fmt.Sprintf(repositories.ItemsDatabaseError, "UpdateItemsCategories"), "no update", http.StatusNotFound, apierrors.CauseList{})
+	}
+
+	return nil
+}
+
+func (storage *itemsRepository) GetByCategoryID(ctx context.Context, categoryID string) ([]models.Item, apierrors.ApiError) {
+	items, apiErr := storage.find(func(item models.Item) bool {
+		return item.Category.ID == categoryID
+	})
+	if apiErr != nil {
+		return []models.Item{}, apiErr
+	}
+
+	return items, nil
+}
+
+func (storage *itemsRepository) SetTranslation(ctx context.Context, itemID string, locale string, translation models.ItemTranslation) apierrors.ApiError {
+	return storage.updateItem(itemID, "SetTranslation", func(item *models.Item) {
+		if item.NameI18n == nil {
+			item.NameI18n = models.Translations{}
+		}
+		item.NameI18n[locale] = translation.Name
+
+		if translation.Description == "" {
+			delete(item.DescriptionI18n, locale)
+			return
+		}
+
+		if item.DescriptionI18n == nil {
+			item.DescriptionI18n = models.Translations{}
+		}
+		item.DescriptionI18n[locale] = translation.Description
+	})
+}
+
+func (storage *itemsRepository) DeleteTranslation(ctx context.Context, itemID string, locale string) apierrors.ApiError {
+	return storage.updateItem(itemID, "DeleteTranslation", func(item *models.Item) {
+		delete(item.NameI18n, locale)
+		delete(item.DescriptionI18n, locale)
+	})
+}
+
+// updateItem applies update to the stored item, failing with
+// ItemNotFoundError when there is none with itemID.
+func (storage *itemsRepository) updateItem(itemID string, operation string, update func(*models.Item)) apierrors.ApiError {
+	primitiveID, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		return itemsError(operation, err)
+	}
+
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	idx := storage.items.indexOf(primitiveID)
+	if idx == -1 {
+		return repositories.ItemNotFoundError
+	}
+
+	var item models.Item
+	if err = bson.Unmarshal(storage.items.list[idx], &item); err != nil {
+		return itemsError(operation, err)
+	}
+
+	update(&item)
+
+	if _, err = storage.items.replace(idx, item); err != nil {
+		return itemsError(operation, err)
+	}
+
+	return nil
+}
+
+// CountByCategory groups the active items, and the ones without a status, by
+// the category embedded in them, taking its name from the first item, and
+// sorts the groups by name.
+func (storage *itemsRepository) CountByCategory(ctx context.Context, shopID string) ([]models.CategoryCount, apierrors.ApiError) {
+	items, apiErr := storage.find(func(item models.Item) bool {
+		active := item.Status == "" || item.Status == models.ItemStatusActive
+		return active && (shopID == "" || item.ShopID == shopID)
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	var counts []models.CategoryCount
+	positions := map[string]int{}
+	for _, item := range items {
+		position, found := positions[item.Category.ID]
+		if !found {
+			position = len(counts)
+			positions[item.Category.ID] = position
+			counts = append(counts, models.CategoryCount{Category: models.Category{
+				ID:       item.Category.ID,
+				Name:     item.Category.Name,
+				NameI18n: item.Category.NameI18n,
+				Slug:     item.Category.Slug,
+			}})
+		}
+		counts[position].ItemsCount++
+	}
+
+	sort.SliceStable(counts, func(i, j int) bool {
+		return counts[i].Name < counts[j].Name
+	})
+
+	return counts, nil
+}
+
+func (storage *itemsRepository) CountByCategoryID(ctx context.Context, categoryID string) (int64, apierrors.ApiError) {
+	items, apiErr := storage.find(func(item models.Item) bool {
+		return item.Category.ID == categoryID
+	})
+	if apiErr != nil {
+		return -1, apiErr
+	}
+
+	return int64(len(items)), nil
+}
+
+// GetBySlug looks the item up by its current slug or any of its previous ones.
+func (storage *itemsRepository) GetBySlug(ctx context.Context, slug string) (models.Item, apierrors.ApiError) {
+	items, apiErr := storage.find(func(item models.Item) bool {
+		return hasSlug(item.Slug, item.SlugHistory, slug)
+	})
+	if apiErr != nil {
+		return models.Item{}, apiErr
+	}
+
+	if len(items) == 0 {
+		return models.Item{}, repositories.ItemNotFoundError
+	}
+
+	return items[0], nil
+}
+
+func (storage *itemsRepository) SlugExists(ctx context.Context, slug string, excludeID string) (bool, apierrors.ApiError) {
+	if excludeID != "" {
+		if _, err := primitive.ObjectIDFromHex(excludeID); err != nil {
+			return false, itemsError("SlugExists", err)
+		}
+	}
+
+	items, apiErr := storage.find(func(item models.Item) bool {
+		return item.ID != excludeID && hasSlug(item.Slug, item.SlugHistory, slug)
+	})
+	if apiErr != nil {
+		return false, apiErr
+	}
+
+	return len(items) > 0, nil
+}
+
+// Search returns a page of the items matching query, sorted by creation. An
+// empty page is not an error.
+func (storage *itemsRepository) Search(ctx context.Context, query models.ItemsQuery) (models.ItemsPage, apierrors.ApiError) {
+	text := strings.ToLower(query.Text)
+	matches, apiErr := storage.find(func(item models.Item) bool {
+		if query.ShopID != "" && item.ShopID != query.ShopID {
+			return false
+		}
+		return strings.Contains(strings.ToLower(item.Name), text) || strings.Contains(strings.ToLower(item.Description), text)
+	})
+	if apiErr != nil {
+		return models.ItemsPage{}, apiErr
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].ID < matches[j].ID
+	})
+
+	items := []models.Item{}
+	if query.Offset < len(matches) {
+		items = matches[query.Offset:]
+	}
+	if query.Limit > 0 && len(items) > query.Limit {
+		items = items[:query.Limit]
+	}
+
+	return models.ItemsPage{Items: items, Total: int64(len(matches))}, nil
+}
+
+// find returns the stored items that match, in insertion order, and nil
+// when none does.
+func (storage *itemsRepository) find(match func(models.Item) bool) ([]models.Item, apierrors.ApiError) {
+	storage.mu.RLock()
+	defer storage.mu.RUnlock()
+
+	var items []models.Item
+	for _, raw := range storage.items.list {
+		var item models.Item
+		if err := bson.Unmarshal(raw, &item); err != nil {
+			return nil, itemsError("Find", err)
+		}
+
+		if match(item) {
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+func itemsError(operation string, err error) apierrors.ApiError {
+	return apierrors.NewInternalServerApiError(fmt.Sprintf(repositories.ItemsDatabaseError, operation), err)
+}
+
+// hasSlug mirrors the slug filter of the Mongo repositories.
+func hasSlug(current string, history []string, slug string) bool {
+	if slug == "" {
+		return false
+	}
+
+	if current == slug {
+		return true
+	}
+
+	for _, previous := range history {
+		if previous == slug {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type revisionsRepository struct {
+	mu        sync.RWMutex
+	revisions []bson.Raw
+}
+
+func NewRevisionsRepository() repositories.RevisionsRepository {
+	return &revisionsRepository{}
+}
+
+func (storage *revisionsRepository) Save(ctx context.Context, revision models.Revision) apierrors.ApiError {
+	raw, err := bson.Marshal(revision)
+	if err != nil {
+		return revisionsError("Save", err)
+	}
+
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	// the unique index on item_id and revision
+	if _, found := storage.find(revision.ItemID, revision.Number); found {
+		return repositories.RevisionTakenError
+	}
+
+	storage.revisions = append(storage.revisions, raw)
+	return nil
+}
+
+func (storage *revisionsRepository) Get(ctx context.Context, itemID string, number int) (models.Revision, apierrors.ApiError) {
+	storage.mu.RLock()
+	defer storage.mu.RUnlock()
+
+	raw, found := storage.find(itemID, number)
+	if !found {
+		return models.Revision{}, repositories.RevisionNotFoundError
+	}
+
+	return decodeRevision("Get", raw)
+}
+
+func (storage *revisionsRepository) Last(ctx context.Context, itemID string) (models.Revision, apierrors.ApiError) {
+	storage.mu.RLock()
+	defer storage.mu.RUnlock()
+
+	var last bson.Raw
+	for _, raw := range storage.revisions {
+		if raw.Lookup("item_id").StringValue() != itemID {
+			continue
+		}
+		if last == nil || raw.Lookup("revision").AsInt64() > last.Lookup("revision").AsInt64() {
+			last = raw
+		}
+	}
+
+	if last == nil {
+		return models.Revision{}, repositories.RevisionNotFoundError
+	}
+
+	return decodeRevision("Last", last)
+}
+
+func (storage *revisionsRepository) List(ctx context.Context, itemID string) ([]models.Revision, apierrors.ApiError) {
+	storage.mu.RLock()
+	defer storage.mu.RUnlock()
+
+	revisions := []models.Revision{}
+	for _, raw := range storage.revisions {
+		if raw.Lookup("item_id").StringValue() != itemID {
+			continue
+		}
+
+		revision, err := decodeRevision("List", raw)
+		if err != nil {
+			return nil, err
+		}
+
+		// the projection of the Mongo repository
+		revision.Item, revision.Price = nil, nil
+		revisions = append(revisions, revision)
+	}
+
+	sort.SliceStable(revisions, func(i, j int) bool {
+		return revisions[i].Number < revisions[j].Number
+	})
+
+	return revisions, nil
+}
+
+func (storage *revisionsRepository) find(itemID string, number int) (bson.Raw, bool) {
+	for _, raw := range storage.revisions {
+		if raw.Lookup("item_id").StringValue() == itemID && raw.Lookup("revision").AsInt64() == int64(number) {
+			return raw, true
+		}
+	}
+
+	return nil, false
+}
+
+func decodeRevision(operation string, raw bson.Raw) (models.Revision, apierrors.ApiError) {
+	var revision models.Revision
+	if err := bson.Unmarshal(raw, &revision); err != nil {
+		return models.Revision{}, revisionsError(operation, err)
+	}
+
+	return revision, nil
+}
+
+func revisionsError(operation string, err error) apierrors.ApiError {
+	return apierrors.NewInternalServerApiError(fmt.Sprintf(repositories.RevisionsDatabaseError, operation), err)
+}
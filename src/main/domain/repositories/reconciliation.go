@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	ReconciliationDatabaseError = "[%s] Error in DB"
+
+	reconciliationRepositoryName = "reconciliation"
+
+	// reconciliationLeaseID is the document of the lease in the locks
+	// collection.
+	reconciliationLeaseID = "price_reconciliation"
+)
+
+var ReconciliationReportNotFoundError = apierrors.NewNotFoundApiError("the price reconciliation didn't run yet")
+
+type ReconciliationRepository interface {
+	Save(ctx context.Context, report models.ReconciliationReport) apierrors.ApiError
+	// Last returns the report of the latest finished run.
+	Last(ctx context.Context) (models.ReconciliationReport, apierrors.ApiError)
+	// AcquireLease takes or extends the lease of the runs for owner until ttl
+	// from now. It returns false while another owner holds a lease that
+	// didn't expire, so only one replica runs at once.
+	AcquireLease(ctx context.Context, owner string, ttl time.Duration) (bool, apierrors.ApiError)
+	ReleaseLease(ctx context.Context, owner string) apierrors.ApiError
+}
+
+type reconciliationRepository struct {
+	Collection *mongo.Collection
+	Locks      *mongo.Collection
+}
+
+// NewReconciliationRepository keeps the reports in collection and the lease
+// of the runs in locks.
+func NewReconciliationRepository(collection *mongo.Collection, locks *mongo.Collection) ReconciliationRepository {
+	return &reconciliationRepository{Collection: collection, Locks: locks}
+}
+
+func (storage *reconciliationRepository) Save(ctx context.Context, report models.ReconciliationReport) apierrors.ApiError {
+	ctx, end := observe(ctx, reconciliationRepositoryName, "Save")
+	defer end()
+
+	_, err := storage.Collection.InsertOne(ctx, report)
+	if err != nil {
+		return apierrors.NewInternalServerApiError(fmt.Sprintf(ReconciliationDatabaseError, "Save"), err)
+	}
+
+	return nil
+}
+
+func (storage *reconciliationRepository) Last(ctx context.Context) (models.ReconciliationReport, apierrors.ApiError) {
+	ctx, end := observe(ctx, reconciliationRepositoryName, "Last")
+	defer end()
+
+	var report models.ReconciliationReport
+
+	opts := options.FindOne().SetSort(bson.M{"finished_at": -1})
+	err := storage.Collection.FindOne(ctx, bson.M{}, opts).Decode(&report)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.ReconciliationReport{}, ReconciliationReportNotFoundError
+	}
+
+	if err != nil {
+		return models.ReconciliationReport{}, apierrors.NewInternalServerApiError(fmt.Sprintf(ReconciliationDatabaseError, "Last"), err)
+	}
+
+	return report, nil
+}
+
+// AcquireLease upserts the lease when it is free, expired or already owned.
+// When another owner holds it the filter matches nothing and the upsert
+// fails on the _id.
+func (storage *reconciliationRepository) AcquireLease(ctx context.Context, owner string, ttl time.Duration) (bool, apierrors.ApiError) {
+	ctx, end := observe(ctx, reconciliationRepositoryName, "AcquireLease")
+	defer end()
+
+	now := time.Now().UTC()
+	filter := bson.M{
+		"_id": reconciliationLeaseID,
+		"$or": []bson.M{{"owner": owner}, {"expires_at": bson.M{"$lte": now}}},
+	}
+	update := bson.M{"$set": bson.M{"owner": owner, "expires_at": now.Add(ttl)}}
+
+	_, err := storage.Locks.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, apierrors.NewInternalServerApiError(fmt.Sprintf(ReconciliationDatabaseError, "AcquireLease"), err)
+	}
+
+	return true, nil
+}
+
+func (storage *reconciliationRepository) ReleaseLease(ctx context.Context, owner string) apierrors.ApiError {
+	ctx, end := observe(ctx, reconciliationRepositoryName, "ReleaseLease")
+	defer end()
+
+	_, err := storage.Locks.DeleteOne(ctx, bson.M{"_id": reconciliationLeaseID, "owner": owner})
+	if err != nil {
+		return apierrors.NewInternalServerApiError(fmt.Sprintf(ReconciliationDatabaseError, "ReleaseLease"), err)
+	}
+
+	return nil
+}
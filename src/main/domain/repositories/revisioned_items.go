@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/actor"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/logger"
+)
+
+// revisionAttempts bounds the retries when two changes of an item race for a
+// revision number.
+const revisionAttempts = 3
+
+type revisionedItemsRepository struct {
+	ItemsRepository
+	revisions RevisionsRepository
+}
+
+// NewRevisionedItemsRepository records a revision of every item that Update,
+// UpdateItemsCategories or the translations change, with the actor of the
+// context. The prices are in another API, so their changes are recorded by
+// NewRevisionedPriceClient. The first change of an item also records its
+// previous state. A revision that can't be recorded is logged and doesn't
+// fail the change, which is already done by then.
+func NewRevisionedItemsRepository(items ItemsRepository, revisions RevisionsRepository) ItemsRepository {
+	return &revisionedItemsRepository{ItemsRepository: items, revisions: revisions}
+}
+
+// Update keeps the price of the previous revision, as the one of updateItem
+// is only written by the prices API afterwards.
+func (storage *revisionedItemsRepository) Update(ctx context.Context, itemID string, updateItem *models.Item) (int64, apierrors.ApiError) {
+	before, err := storage.ItemsRepository.Get(ctx, itemID)
+	if err != nil {
+		return storage.ItemsRepository.Update(ctx, itemID, updateItem)
+	}
+
+	modified, err := storage.ItemsRepository.Update(ctx, itemID, updateItem)
+	if err != nil {
+		return modified, err
+	}
+
+	storage.record(ctx, before, nil, nil, models.RevisionUpdate)
+
+	return modified, nil
+}
+
+func (storage *revisionedItemsRepository) UpdateItemsCategories(ctx context.Context, category *models.Category) apierrors.ApiError {
+	before, err := storage.ItemsRepository.GetByCategoryID(ctx, category.ID)
+	if err != nil {
+		return storage.ItemsRepository.UpdateItemsCategories(ctx, category)
+	}
+
+	if err = storage.ItemsRepository.UpdateItemsCategories(ctx, category); err != nil {
+		return err
+	}
+
+	for _, item := range before {
+		storage.record(ctx, item, nil, nil, models.RevisionCategorySync)
+	}
+
+	return nil
+}
+
+func (storage *revisionedItemsRepository) SetTranslation(ctx context.Context, itemID string, locale string, translation models.ItemTranslation) apierrors.ApiError {
+	return storage.translate(ctx, itemID, func() apierrors.ApiError {
+		return storage.ItemsRepository.SetTranslation(ctx, itemID, locale, translation)
+	})
+}
+
+func (storage *revisionedItemsRepository) DeleteTranslation(ctx context.Context, itemID string, locale string) apierrors.ApiError {
+	return storage.translate(ctx, itemID, func() apierrors.ApiError {
+		return storage.ItemsRepository.DeleteTranslation(ctx, itemID, locale)
+	})
+}
+
+func (storage *revisionedItemsRepository) translate(ctx context.Context, itemID string, change func() apierrors.ApiError) apierrors.ApiError {
+	before, err := storage.ItemsRepository.Get(ctx, itemID)
+	if err != nil {
+		return change()
+	}
+
+	if err = change(); err != nil {
+		return err
+	}
+
+	storage.record(ctx, before, nil, nil, models.RevisionTranslation)
+
+	return nil
+}
+
+// record appends the revision of the change of the item from before and
+// beforePrice, unless nothing changed. A nil beforePrice is the price of the
+// previous revision, and a nil afterPrice is beforePrice.
+func (storage *revisionedItemsRepository) record(ctx context.Context, before models.Item, beforePrice *models.Price, afterPrice *models.Price, action string) {
+	var err apierrors.ApiError
+	for attempt := 0; attempt < revisionAttempts; attempt++ {
+		err = storage.recordOnce(ctx, before, beforePrice, afterPrice, action)
+		if err != RevisionTakenError {
+			break
+		}
+	}
+
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error recording the revision of the item %s", before.ID), err)
+	}
+}
+
+func (storage *revisionedItemsRepository) recordOnce(ctx context.Context, before models.Item, beforePrice *models.Price, afterPrice *models.Price, action string) apierrors.ApiError {
+	after, err := storage.ItemsRepository.Get(ctx, before.ID)
+	if err != nil {
+		return err
+	}
+
+	last, err := storage.revisions.Last(ctx, before.ID)
+	if err != nil && err.Status() != http.StatusNotFound {
+		return err
+	}
+	first := err != nil
+
+	if beforePrice == nil {
+		beforePrice = last.Price
+	}
+	if afterPrice == nil {
+		afterPrice = beforePrice
+	}
+
+	changes := models.DiffItems(before, beforePrice, after, afterPrice)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if action == models.RevisionUpdate && onlyStatus(changes) {
+		action = models.RevisionStatus
+	}
+
+	now := time.Now().UTC()
+	if first {
+		last = models.Revision{ItemID: before.ID, Number: 1, Action: models.RevisionInitial, CreatedAt: now, Changes: []models.FieldChange{}, Item: &before, Price: beforePrice}
+		if err = storage.revisions.Save(ctx, last); err != nil {
+			return err
+		}
+	}
+
+	return storage.revisions.Save(ctx, models.Revision{
+		ItemID:    before.ID,
+		Number:    last.Number + 1,
+		Action:    action,
+		Actor:     actor.From(ctx),
+		CreatedAt: now,
+		Changes:   changes,
+		Item:      &after,
+		Price:     afterPrice,
+	})
+}
+
+func onlyStatus(changes []models.FieldChange) bool {
+	for _, change := range changes {
+		if change.Field != "status" {
+			return false
+		}
+	}
+	return true
+}
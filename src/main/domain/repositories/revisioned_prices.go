@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/clients"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+type revisionedPriceClient struct {
+	clients.PriceClient
+	items *revisionedItemsRepository
+}
+
+// NewRevisionedPriceClient records a revision of the item whose price
+// UpdatePrice changes, once the prices API took the change, like
+// NewRevisionedItemsRepository does for the rest of the item. The previous
+// price is read from the prices API, so the first change of a price is
+// recorded too.
+func NewRevisionedPriceClient(prices clients.PriceClient, items ItemsRepository, revisions RevisionsRepository) clients.PriceClient {
+	return &revisionedPriceClient{
+		PriceClient: prices,
+		items:       &revisionedItemsRepository{ItemsRepository: items, revisions: revisions},
+	}
+}
+
+func (client *revisionedPriceClient) UpdatePrice(ctx context.Context, price *models.Price) apierrors.ApiError {
+	before, err := client.PriceClient.GetPriceByItemID(ctx, price.ItemID)
+	if err != nil {
+		return client.PriceClient.UpdatePrice(ctx, price)
+	}
+
+	item, err := client.items.Get(ctx, price.ItemID)
+	if err != nil {
+		return client.PriceClient.UpdatePrice(ctx, price)
+	}
+
+	if err = client.PriceClient.UpdatePrice(ctx, price); err != nil {
+		return err
+	}
+
+	after := *price
+	client.items.record(ctx, item, &before, &after, models.RevisionPrice)
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	RevisionsDatabaseError = "[%s] Error in DB"
+
+	revisionsRepositoryName = "revisions"
+)
+
+var RevisionNotFoundError = apierrors.NewNotFoundApiError("revision not found")
+
+// RevisionTakenError is returned by Save when another change of the item got
+// the revision number first.
+var RevisionTakenError = catalog.Conflict.New("the revision number is taken")
+
+type RevisionsRepository interface {
+	// Save appends the revision; its number must be the next one of the item.
+	Save(ctx context.Context, revision models.Revision) apierrors.ApiError
+	Get(ctx context.Context, itemID string, number int) (models.Revision, apierrors.ApiError)
+	// Last returns the latest revision of the item.
+	Last(ctx context.Context, itemID string) (models.Revision, apierrors.ApiError)
+	// List returns the revisions of the item, oldest first, without the item
+	// and price they kept.
+	List(ctx context.Context, itemID string) ([]models.Revision, apierrors.ApiError)
+}
+
+type revisionsRepository struct {
+	Collection *mongo.Collection
+}
+
+// NewRevisionsRepository relies on the unique index on item_id and revision
+// of the migrations to number the revisions.
+func NewRevisionsRepository(collection *mongo.Collection) RevisionsRepository {
+	return &revisionsRepository{Collection: collection}
+}
+
+func (storage *revisionsRepository) Save(ctx context.Context, revision models.Revision) apierrors.ApiError {
+	ctx, end := observe(ctx, revisionsRepositoryName, "Save")
+	defer end()
+
+	_, err := storage.Collection.InsertOne(ctx, revision)
+	if mongo.IsDuplicateKeyError(err) {
+		return RevisionTakenError
+	}
+
+	if err != nil {
+		return apierrors.NewInternalServerApiError(fmt.Sprintf(RevisionsDatabaseError, "Save"), err)
+	}
+
+	return nil
+}
+
+func (storage *revisionsRepository) Get(ctx context.Context, itemID string, number int) (models.Revision, apierrors.ApiError) {
+	ctx, end := observe(ctx, revisionsRepositoryName, "Get")
+	defer end()
+
+	return storage.findOne(ctx, "Get", bson.M{"item_id": itemID, "revision": number}, options.FindOne())
+}
+
+func (storage *revisionsRepository) Last(ctx context.Context, itemID string) (models.Revision, apierrors.ApiError) {
+	ctx, end := observe(ctx, revisionsRepositoryName, "Last")
+	defer end()
+
+	return storage.findOne(ctx, "Last", bson.M{"item_id": itemID}, options.FindOne().SetSort(bson.M{"revision": -1}))
+}
+
+func (storage *revisionsRepository) List(ctx context.Context, itemID string) ([]models.Revision, apierrors.ApiError) {
+	ctx, end := observe(ctx, revisionsRepositoryName, "List")
+	defer end()
+
+	opts := options.Find().
+		SetSort(bson.M{"revision": 1}).
+		SetProjection(bson.M{"item": 0, "price": 0})
+
+	cursor, err := storage.Collection.Find(ctx, bson.M{"item_id": itemID}, opts)
+	if err != nil {
+		return nil, apierrors.NewInternalServerApiError(fmt.Sprintf(RevisionsDatabaseError, "List"), err)
+	}
+
+	revisions := []models.Revision{}
+	if err = cursor.All(ctx, &revisions); err != nil {
+		return nil, apierrors.NewInternalServerApiError(fmt.Sprintf(RevisionsDatabaseError, "List"), err)
+	}
+
+	return revisions, nil
+}
+
+func (storage *revisionsRepository) findOne(ctx context.Context, operation string, filter bson.M, opts *options.FindOneOptions) (models.Revision, apierrors.ApiError) {
+	var revision models.Revision
+
+	err := storage.Collection.FindOne(ctx, filter, opts).Decode(&revision)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.Revision{}, RevisionNotFoundError
+	}
+
+	if err != nil {
+		return models.Revision{}, apierrors.NewInternalServerApiError(fmt.Sprintf(RevisionsDatabaseError, operation), err)
+	}
+
+	return revision, nil
+}
@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// slugFilter matches documents whose current or previous slugs include slug.
+func slugFilter(slug string) bson.M {
+	return bson.M{"$or": []bson.M{
+		{"slug": slug},
+		{"slug_history": slug},
+	}}
+}
+
+// slugExistsFilter is slugFilter without the document being renamed, so it
+// can take back one of its own previous slugs.
+func slugExistsFilter(slug string, excludeID string) (bson.M, error) {
+	filter := slugFilter(slug)
+	if excludeID == "" {
+		return filter, nil
+	}
+
+	primitiveID, err := primitive.ObjectIDFromHex(excludeID)
+	if err != nil {
+		return nil, err
+	}
+	filter["_id"] = bson.M{"$ne": primitiveID}
+
+	return filter, nil
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/pkg/metrics"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// observe opens the span of a repository method and returns the func that
+// ends it and records the Mongo latency, meant to be deferred.
+func observe(ctx context.Context, repository string, operation string) (context.Context, func()) {
+	start := time.Now()
+	ctx, span := telemetry.Start(ctx, "mongo."+repository+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "mongodb"),
+			attribute.String("db.collection.name", repository),
+		),
+	)
+
+	return ctx, func() {
+		span.End()
+		metrics.ObserveMongo(repository, operation, start)
+	}
+}
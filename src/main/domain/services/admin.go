@@ -0,0 +1,388 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/clients"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	"github.com/agustinrabini/items-api-project/src/main/domain/utils"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/telemetry"
+
+	"github.com/creasty/defaults"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// adminPageSize is how many items the tasks that go through the whole
+// collection read at once.
+const adminPageSize = 500
+
+// AdminService runs the maintenance tasks of items-admin. The tasks that
+// write report what they would change without writing when dryRun is set.
+type AdminService interface {
+	RecountCategories(ctx context.Context, dryRun bool) (dto.CategoriesRecountDTO, apierrors.ApiError)
+	ExportShop(ctx context.Context, shopID string) (dto.ItemsExportDTO, apierrors.ApiError)
+	ImportItems(ctx context.Context, items []models.Item, dryRun bool) (dto.ItemsImportDTO, apierrors.ApiError)
+	PurgeTrash(ctx context.Context, dryRun bool) (dto.ItemsPurgeDTO, apierrors.ApiError)
+	ReassignCategory(ctx context.Context, fromID string, toID string, dryRun bool) (dto.CategoryReassignDTO, apierrors.ApiError)
+}
+
+type adminService struct {
+	items        repositories.ItemsRepository
+	categories   repositories.CategoriesRepository
+	pricesClient clients.PriceClient
+}
+
+func NewAdminService(items repositories.ItemsRepository, categories repositories.CategoriesRepository, pricesClient clients.PriceClient) AdminService {
+	return &adminService{items: items, categories: categories, pricesClient: pricesClient}
+}
+
+// RecountCategories counts the items of every category and brings the copy
+// of the category embedded in the items up to date when it drifted, e.g.
+// after an update whose cascade failed.
+func (s *adminService) RecountCategories(ctx context.Context, dryRun bool) (dto.CategoriesRecountDTO, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "AdminService.RecountCategories")
+	defer span.End()
+
+	report := dto.CategoriesRecountDTO{DryRun: dryRun, Categories: []dto.CategoryRecountDTO{}, Orphans: []dto.CategoryRecountDTO{}}
+
+	categories, err := s.categories.GetAllCategories(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	counts, err := s.items.CountByCategory(ctx, "")
+	if err != nil {
+		return report, err
+	}
+
+	embedded := make(map[string]models.CategoryCount, len(counts))
+	for _, count := range counts {
+		embedded[count.ID] = count
+	}
+
+	for _, category := range categories {
+		count, found := embedded[category.ID]
+		delete(embedded, category.ID)
+
+		recount := dto.CategoryRecountDTO{ID: category.ID, Name: category.Name, Items: count.ItemsCount}
+		if found {
+			recount.Resynced, err = s.categoryDrifted(ctx, category)
+			if err != nil {
+				return report, err
+			}
+		}
+		if recount.Resynced {
+			if !dryRun {
+				err = s.items.UpdateItemsCategories(ctx, &category)
+				if err != nil {
+					return report, err
+				}
+			}
+		}
+		report.Categories = append(report.Categories, recount)
+	}
+
+	for _, count := range counts {
+		if _, orphan := embedded[count.ID]; orphan {
+			report.Orphans = append(report.Orphans, dto.CategoryRecountDTO{ID: count.ID, Name: count.Name, Items: count.ItemsCount})
+		}
+	}
+
+	return report, nil
+}
+
+// categoryDrifted tells whether any item of the category has an outdated copy
+// of it.
+func (s *adminService) categoryDrifted(ctx context.Context, category models.Category) (bool, apierrors.ApiError) {
+	items, err := s.items.GetByCategoryID(ctx, category.ID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, item := range items {
+		if !sameCategoryCopy(category, item.Category) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// sameCategoryCopy compares the fields UpdateItemsCategories copies to the
+// items.
+func sameCategoryCopy(category models.Category, copied models.Category) bool {
+	if category.Name != copied.Name || category.Slug != copied.Slug {
+		return false
+	}
+
+	if len(category.NameI18n) == 0 && len(copied.NameI18n) == 0 {
+		return true
+	}
+
+	return reflect.DeepEqual(category.NameI18n, copied.NameI18n)
+}
+
+// ExportShop returns every item of the shop, without prices, an empty shop
+// included.
+func (s *adminService) ExportShop(ctx context.Context, shopID string) (dto.ItemsExportDTO, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "AdminService.ExportShop")
+	defer span.End()
+
+	items, err := s.items.GetByShopID(ctx, shopID)
+	if err != nil && err.Status() != http.StatusNotFound {
+		return dto.ItemsExportDTO{}, err
+	}
+
+	if items.Items == nil {
+		items.Items = []models.Item{}
+	}
+
+	return dto.ItemsExportDTO{ShopID: shopID, Items: items.Items}, nil
+}
+
+// ImportItems updates the items of the input that still exist and creates
+// the others with a new ID and slug. Every item must name an existing
+// category, whose current copy replaces the one in the input; nothing is
+// written when one doesn't. A dry run compares the existing items with the
+// input to tell the updated from the unchanged ones.
+func (s *adminService) ImportItems(ctx context.Context, input []models.Item, dryRun bool) (dto.ItemsImportDTO, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "AdminService.ImportItems")
+	defer span.End()
+
+	report := dto.ItemsImportDTO{DryRun: dryRun, Created: []dto.ItemImportDTO{}, Updated: []dto.ItemImportDTO{}, Unchanged: []dto.ItemImportDTO{}}
+
+	categories := map[string]models.Category{}
+	for _, item := range input {
+		if item.Name == "" || item.ShopID == "" || item.UserID == "" || item.Category.ID == "" {
+			return report, apierrors.NewBadRequestApiError(fmt.Sprintf("item %q must have a name, shop_id, user_id and category", item.ID))
+		}
+		if err := utils.ValidateTranslations("name_i18n", item.NameI18n); err != nil {
+			return report, err
+		}
+		if err := utils.ValidateTranslations("description_i18n", item.DescriptionI18n); err != nil {
+			return report, err
+		}
+
+		if _, found := categories[item.Category.ID]; found {
+			continue
+		}
+
+		category, err := s.categories.Get(ctx, item.Category.ID)
+		if err != nil {
+			if err.Status() == http.StatusNotFound {
+				return report, apierrors.NewBadRequestApiError(fmt.Sprintf("item %q has the unknown category %s", item.ID, item.Category.ID))
+			}
+			return report, err
+		}
+		categories[category.ID] = categoryCopy(category)
+	}
+
+	for _, item := range input {
+		sourceID := item.ID
+		item.Category = categories[item.Category.ID]
+		item.Price = models.Price{}
+
+		var stored models.Item
+		exists := false
+		if primitive.IsValidObjectID(sourceID) {
+			var err apierrors.ApiError
+			stored, err = s.items.Get(ctx, sourceID)
+			if err != nil && err.Status() != http.StatusNotFound {
+				return report, err
+			}
+			exists = err == nil
+		}
+
+		if exists {
+			// the slugs stay as they are, the file may be older than them
+			item.ID, item.Slug, item.SlugHistory = "", "", nil
+
+			var modified int64
+			var err apierrors.ApiError
+			if dryRun {
+				modified, err = wouldModify(stored, item)
+			} else {
+				modified, err = s.items.Update(ctx, sourceID, &item)
+			}
+			if err != nil {
+				return report, err
+			}
+
+			imported := dto.ItemImportDTO{ID: sourceID, SourceID: sourceID, Name: item.Name}
+			if modified == 0 {
+				report.Unchanged = append(report.Unchanged, imported)
+			} else {
+				report.Updated = append(report.Updated, imported)
+			}
+			continue
+		}
+
+		item.ID, item.SlugHistory = "", nil
+		imported := dto.ItemImportDTO{SourceID: sourceID, Name: item.Name}
+		if !dryRun {
+			if err := defaults.Set(&item); err != nil { // coverage-ignore
+				return report, apierrors.NewInternalServerApiError("error settling defaults", err)
+			}
+			item.Validate()
+
+			var id interface{}
+			err := writeWithSlug(ctx, s.items, item.Name, "", func(slug string) apierrors.ApiError {
+				item.Slug = slug
+
+				var err apierrors.ApiError
+				id, err = s.items.Save(ctx, item)
+				return err
+			})
+			if err != nil {
+				return report, err
+			}
+			imported.ID = id.(primitive.ObjectID).Hex()
+		}
+		report.Created = append(report.Created, imported)
+	}
+
+	return report, nil
+}
+
+// PurgeTrash deletes the items with the deleted status, set by an update or an
+// import, and their prices. A price that can't be deleted is reported and
+// doesn't stop the purge. Items deleted through DELETE /items/:id never reach
+// the trash, they are removed right away.
+func (s *adminService) PurgeTrash(ctx context.Context, dryRun bool) (dto.ItemsPurgeDTO, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "AdminService.PurgeTrash")
+	defer span.End()
+
+	report := dto.ItemsPurgeDTO{DryRun: dryRun, Purged: []dto.ItemPurgedDTO{}}
+
+	// read them all first, deleting while paging would skip items
+	trash, err := s.allItems(ctx, func(item models.Item) bool {
+		return item.Status == models.ItemStatusDeleted
+	})
+	if err != nil {
+		return report, err
+	}
+
+	for _, item := range trash {
+		purged := dto.ItemPurgedDTO{ID: item.ID, Name: item.Name, ShopID: item.ShopID}
+		if !dryRun {
+			_, err = s.items.Delete(ctx, item.ID)
+			if err != nil && err.Status() != http.StatusNotFound {
+				return report, err
+			}
+
+			if err = s.pricesClient.DeletePrice(ctx, item.ID); err != nil && err.Status() != http.StatusNotFound {
+				purged.PriceError = err.Message()
+			}
+		}
+		report.Purged = append(report.Purged, purged)
+	}
+
+	return report, nil
+}
+
+// ReassignCategory moves the items of the category fromID, which may not
+// exist anymore, to the category toID.
+func (s *adminService) ReassignCategory(ctx context.Context, fromID string, toID string, dryRun bool) (dto.CategoryReassignDTO, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "AdminService.ReassignCategory")
+	defer span.End()
+
+	report := dto.CategoryReassignDTO{DryRun: dryRun, From: fromID, Items: []string{}}
+
+	if fromID == toID {
+		return report, apierrors.NewBadRequestApiError("the categories to reassign from and to must differ")
+	}
+
+	to, err := s.categories.Get(ctx, toID)
+	if err != nil {
+		return report, err
+	}
+	report.To = categoryCopy(to)
+
+	items, err := s.items.GetByCategoryID(ctx, fromID)
+	if err != nil {
+		return report, err
+	}
+
+	for _, item := range items {
+		if !dryRun {
+			_, err = s.items.Update(ctx, item.ID, &models.Item{Category: report.To})
+			if err != nil {
+				return report, err
+			}
+		}
+		report.Items = append(report.Items, item.ID)
+	}
+
+	return report, nil
+}
+
+// wouldModify reports whether Update would modify the stored item, as the
+// ModifiedCount of Mongo: the update sets its non-empty fields, so it
+// modifies the item when one of them differs from the stored value. Both are
+// compared decoded, as maps like the attributes are encoded in random order.
+func wouldModify(stored models.Item, update models.Item) (int64, apierrors.ApiError) {
+	storedFields, err := itemFields(stored)
+	if err != nil { // coverage-ignore
+		return 0, apierrors.NewInternalServerApiError("error encoding the stored item", err)
+	}
+
+	updateFields, err := itemFields(update)
+	if err != nil { // coverage-ignore
+		return 0, apierrors.NewInternalServerApiError("error encoding the imported item", err)
+	}
+
+	for key, value := range updateFields {
+		if key != "_id" && !reflect.DeepEqual(storedFields[key], value) {
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// itemFields returns the fields of item as stored, with the embedded
+// documents as bson.M too.
+func itemFields(item models.Item) (bson.M, error) {
+	raw, err := bson.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields bson.M
+	err = bson.Unmarshal(raw, &fields)
+	return fields, err
+}
+
+// allItems goes through the whole items collection and returns the matching
+// items.
+func (s *adminService) allItems(ctx context.Context, match func(models.Item) bool) ([]models.Item, apierrors.ApiError) {
+	var matches []models.Item
+	for offset := 0; ; offset += adminPageSize {
+		page, err := s.items.Search(ctx, models.ItemsQuery{Limit: adminPageSize, Offset: offset})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.Items {
+			if match(item) {
+				matches = append(matches, item)
+			}
+		}
+
+		if !page.HasMore(models.ItemsQuery{Limit: adminPageSize, Offset: offset}) {
+			return matches, nil
+		}
+	}
+}
+
+// categoryCopy is the part of a category embedded in its items.
+func categoryCopy(category models.Category) models.Category {
+	return models.Category{ID: category.ID, Name: category.Name, NameI18n: category.NameI18n, Slug: category.Slug}
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/telemetry"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+var ErrorAuditTimeRange = catalog.BadRequest.New("from must not be after to")
+
+// AuditService reads the audit log, which handlers.AuditHandler writes.
+type AuditService interface {
+	Search(ctx context.Context, query models.AuditQuery) (models.AuditPage, apierrors.ApiError)
+}
+
+type auditService struct {
+	repository repositories.AuditRepository
+}
+
+func NewAuditService(repository repositories.AuditRepository) AuditService {
+	return &auditService{repository: repository}
+}
+
+func (s *auditService) Search(ctx context.Context, query models.AuditQuery) (models.AuditPage, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "AuditService.Search")
+	defer span.End()
+
+	if !query.From.IsZero() && !query.To.IsZero() && query.From.After(query.To) {
+		return models.AuditPage{}, ErrorAuditTimeRange
+	}
+
+	return s.repository.Search(ctx, query)
+}
@@ -3,23 +3,34 @@ package services
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"reflect"
 	"strings"
 
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
 	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
 	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	"github.com/agustinrabini/items-api-project/src/main/domain/utils"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/telemetry"
 
 	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
 )
 
-var ErrorCategoryExists = apierrors.NewApiError("Error creating the item category. ", fmt.Errorf("category alredy exists. ").Error(), 409, apierrors.CauseList{})
+var ErrorCategoryExists = catalog.CategoryExists.New("the category already exists")
 
 type CategoriesService interface {
 	Get(ctx context.Context, categoryID string) (models.Category, apierrors.ApiError)
 	GetAllCategories(ctx context.Context) ([]models.Category, apierrors.ApiError)
 	Create(ctx context.Context, input models.Category) apierrors.ApiError
-	Update(ctx context.Context, input models.Category) apierrors.ApiError
-	Delete(ctx context.Context, items []models.Item, categoryID string) apierrors.ApiError
+	Update(ctx context.Context, input models.Category) (models.Category, apierrors.ApiError)
+	Delete(ctx context.Context, itemsCount int64, categoryID string) apierrors.ApiError
+	GetBySlug(ctx context.Context, slug string) (models.Category, apierrors.ApiError)
+
+	SetTranslation(ctx context.Context, categoryID string, locale string, name string) (models.Category, apierrors.ApiError)
+	DeleteTranslation(ctx context.Context, categoryID string, locale string) (models.Category, apierrors.ApiError)
+
+	Import(ctx context.Context, input []models.Category, dryRun bool) (dto.CategoriesImportDTO, apierrors.ApiError)
+	Export(ctx context.Context) ([]models.Category, apierrors.ApiError)
 }
 
 type categoriesService struct {
@@ -31,6 +42,9 @@ func NewCategoriesService(repository repositories.CategoriesRepository) Categori
 }
 
 func (s *categoriesService) Get(ctx context.Context, categoryID string) (models.Category, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "CategoriesService.Get")
+	defer span.End()
+
 	category, err := s.repository.Get(ctx, categoryID)
 	if err != nil {
 		return models.Category{}, err
@@ -40,19 +54,25 @@ func (s *categoriesService) Get(ctx context.Context, categoryID string) (models.
 }
 
 func (s *categoriesService) GetAllCategories(ctx context.Context) ([]models.Category, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "CategoriesService.GetAllCategories")
+	defer span.End()
+
 	categories, err := s.repository.GetAllCategories(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	if len(categories) <= 0 {
-		return []models.Category{}, apierrors.NewApiError("no categories found", "categories should never be nil, please contact and administrator", http.StatusInternalServerError, apierrors.CauseList{})
+		return []models.Category{}, catalog.Internal.WithLegacy("categories should never be nil, please contact and administrator").New("no categories found")
 	}
 
 	return categories, nil
 }
 
 func (s *categoriesService) Create(ctx context.Context, input models.Category) apierrors.ApiError {
+	ctx, span := telemetry.Start(ctx, "CategoriesService.Create")
+	defer span.End()
+
 	categories, err := s.repository.GetAllCategories(ctx)
 	if err != nil {
 		return err
@@ -63,6 +83,12 @@ func (s *categoriesService) Create(ctx context.Context, input models.Category) a
 		return err
 	}
 
+	input.Slug, err = s.uniqueSlug(ctx, input.Name, "")
+	if err != nil {
+		return err
+	}
+	input.SlugHistory = nil
+
 	_, err = s.repository.Create(ctx, input)
 	if err != nil {
 		return err
@@ -71,18 +97,53 @@ func (s *categoriesService) Create(ctx context.Context, input models.Category) a
 	return nil
 }
 
-func (s *categoriesService) Update(ctx context.Context, input models.Category) apierrors.ApiError {
+// Update renames the category and returns it as stored, with the new slug, so
+// the caller can cascade it to the items embedding it.
+func (s *categoriesService) Update(ctx context.Context, input models.Category) (models.Category, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "CategoriesService.Update")
+	defer span.End()
+
 	categories, err := s.repository.GetAllCategories(ctx)
 	if err != nil {
-		return err
+		return models.Category{}, err
 	}
 
 	err = validateCategoryExistence(input.Name, categories)
 	if err != nil {
-		return err
+		return models.Category{}, err
+	}
+
+	current, err := s.repository.Get(ctx, input.ID)
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	slug, err := s.uniqueSlug(ctx, input.Name, input.ID)
+	if err != nil {
+		return models.Category{}, err
 	}
+	current.SetSlug(slug)
+
+	input.Slug = current.Slug
+	input.SlugHistory = current.SlugHistory
 
 	_, err = s.repository.Update(ctx, input)
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	return input, nil
+}
+
+func (s *categoriesService) Delete(ctx context.Context, itemsCount int64, categoryID string) apierrors.ApiError {
+	ctx, span := telemetry.Start(ctx, "CategoriesService.Delete")
+	defer span.End()
+
+	if itemsCount != 0 {
+		return catalog.CategoryInUse.WithLegacy(fmt.Sprintf("%d items are using the category", itemsCount)).New(fmt.Sprintf("the category is used by %d items, move them to another category before deleting it", itemsCount))
+	}
+
+	_, err := s.repository.Delete(ctx, categoryID)
 	if err != nil {
 		return err
 	}
@@ -90,25 +151,168 @@ func (s *categoriesService) Update(ctx context.Context, input models.Category) a
 	return nil
 }
 
-func (s *categoriesService) Delete(ctx context.Context, items []models.Item, categoryID string) apierrors.ApiError {
+// SetTranslation stores the localized name and returns the updated category so
+// the caller can cascade it to the items embedding it.
+func (s *categoriesService) SetTranslation(ctx context.Context, categoryID string, locale string, name string) (models.Category, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "CategoriesService.SetTranslation")
+	defer span.End()
 
-	if len(items) != 0 {
+	err := s.repository.SetTranslation(ctx, categoryID, locale, name)
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	return s.repository.Get(ctx, categoryID)
+}
+
+func (s *categoriesService) DeleteTranslation(ctx context.Context, categoryID string, locale string) (models.Category, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "CategoriesService.DeleteTranslation")
+	defer span.End()
 
-		ids := []string{}
+	err := s.repository.DeleteTranslation(ctx, categoryID, locale)
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	return s.repository.Get(ctx, categoryID)
+}
 
-		for _, itm := range items {
-			ids = append(ids, itm.ID)
+// GetBySlug returns the category owning the slug, which may be one of its
+// previous slugs: callers compare it with the category's current slug.
+func (s *categoriesService) GetBySlug(ctx context.Context, slug string) (models.Category, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "CategoriesService.GetBySlug")
+	defer span.End()
+
+	return s.repository.GetBySlug(ctx, slug)
+}
+
+// Import upserts the categories by name, ignoring case, so the same file can be
+// applied any number of times. Existing categories get the translations and the
+// slug of the file, the previous slug kept in the history, and categories
+// missing from the input are left untouched.
+func (s *categoriesService) Import(ctx context.Context, input []models.Category, dryRun bool) (dto.CategoriesImportDTO, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "CategoriesService.Import")
+	defer span.End()
+
+	report := dto.CategoriesImportDTO{DryRun: dryRun, Created: []models.Category{}, Updated: []models.Category{}, Unchanged: []models.Category{}}
+
+	seen := make(map[string]bool, len(input))
+	for _, category := range input {
+		name := strings.ToLower(strings.TrimSpace(category.Name))
+		if name == "" {
+			return report, apierrors.NewBadRequestApiError("every imported category must have a name")
+		}
+		if seen[name] {
+			return report, apierrors.NewBadRequestApiError(fmt.Sprintf("category %q is repeated in the input", category.Name))
 		}
+		seen[name] = true
 
-		return apierrors.NewApiError("error attempting to delete the category, update this implementations before deleting the category", "the following items are using the category "+fmt.Sprint(ids), 409, apierrors.CauseList{})
+		if err := utils.ValidateTranslations("name_i18n", category.NameI18n); err != nil {
+			return report, err
+		}
 	}
 
-	_, err := s.repository.Delete(ctx, categoryID)
+	categories, err := s.repository.GetAllCategories(ctx)
 	if err != nil {
-		return err
+		return report, err
 	}
 
-	return nil
+	existing := make(map[string]models.Category, len(categories))
+	for _, category := range categories {
+		existing[strings.ToLower(category.Name)] = category
+	}
+
+	for _, category := range input {
+		category.Name = strings.TrimSpace(category.Name)
+
+		current, ok := existing[strings.ToLower(category.Name)]
+		if !ok {
+			category.ID = ""
+			category.SlugHistory = nil
+			if !dryRun {
+				category.Slug, err = s.importSlug(ctx, category)
+				if err != nil {
+					return report, err
+				}
+
+				_, err = s.repository.Create(ctx, category)
+				if err != nil {
+					return report, err
+				}
+			}
+			report.Created = append(report.Created, category)
+			continue
+		}
+
+		translated := category.NameI18n != nil && !reflect.DeepEqual(category.NameI18n, current.NameI18n)
+		moved := category.Slug != "" && category.Slug != current.Slug
+		if !translated && !moved {
+			report.Unchanged = append(report.Unchanged, current)
+			continue
+		}
+
+		if translated {
+			current.NameI18n = category.NameI18n
+		}
+		if moved {
+			if err = utils.ValidateSlug(category.Slug); err != nil {
+				return report, err
+			}
+
+			slug := category.Slug
+			if !dryRun {
+				slug, err = s.uniqueSlug(ctx, category.Slug, current.ID)
+				if err != nil {
+					return report, err
+				}
+			}
+			current.SetSlug(slug)
+		}
+
+		if !dryRun {
+			_, err = s.repository.Update(ctx, models.Category{ID: current.ID, Name: current.Name, NameI18n: current.NameI18n, Slug: current.Slug, SlugHistory: current.SlugHistory})
+			if err != nil {
+				return report, err
+			}
+		}
+		report.Updated = append(report.Updated, current)
+	}
+
+	return report, nil
+}
+
+// Export returns every category, an empty list included, for seeding other
+// environments with Import.
+func (s *categoriesService) Export(ctx context.Context) ([]models.Category, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "CategoriesService.Export")
+	defer span.End()
+
+	categories, err := s.repository.GetAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if categories == nil {
+		return []models.Category{}, nil
+	}
+
+	return categories, nil
+}
+
+// importSlug keeps the slug coming in the file when it is still free, so the
+// storefront URLs match between environments.
+func (s *categoriesService) importSlug(ctx context.Context, category models.Category) (string, apierrors.ApiError) {
+	if category.Slug != "" && utils.ValidateSlug(category.Slug) == nil {
+		return s.uniqueSlug(ctx, category.Slug, "")
+	}
+
+	return s.uniqueSlug(ctx, category.Name, "")
+}
+
+func (s *categoriesService) uniqueSlug(ctx context.Context, name string, categoryID string) (string, apierrors.ApiError) {
+	return utils.UniqueSlug(name, func(slug string) (bool, apierrors.ApiError) {
+		return s.repository.SlugExists(ctx, slug, categoryID)
+	})
 }
 
 func validateCategoryExistence(inputCategoryName string, categories []models.Category) apierrors.ApiError {
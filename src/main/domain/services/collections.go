@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/agustinrabini/items-api-project/src/main/domain/clients"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/telemetry"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+var ErrorCollectionNotOwned = catalog.Forbidden.New("the collection does not belong to the user")
+var ErrorCollectionItemsNotOwned = catalog.Forbidden.New("only items of the shop owning the collection can be added")
+var ErrorCollectionDuplicatedItems = apierrors.NewBadRequestApiError("the collection items must not be repeated")
+
+type CollectionsService interface {
+	Get(ctx context.Context, collectionID string) (models.CollectionWithItems, apierrors.ApiError)
+	GetByShopID(ctx context.Context, shopID string) ([]models.Collection, apierrors.ApiError)
+	Create(ctx context.Context, userID string, input dto.CollectionDTO) (interface{}, apierrors.ApiError)
+	Update(ctx context.Context, userID string, collectionID string, input dto.CollectionDTO) apierrors.ApiError
+	SetItems(ctx context.Context, userID string, collectionID string, itemIDs []string) apierrors.ApiError
+	Delete(ctx context.Context, userID string, collectionID string) apierrors.ApiError
+}
+
+type collectionsService struct {
+	repository      repositories.CollectionsRepository
+	itemsRepository repositories.ItemsRepository
+	pricesClient    clients.PriceClient
+	shopsClient     clients.ShopClient
+}
+
+func NewCollectionsService(repository repositories.CollectionsRepository, itemsRepository repositories.ItemsRepository, pricesClient clients.PriceClient, shopsClient clients.ShopClient) CollectionsService {
+	return &collectionsService{repository: repository, itemsRepository: itemsRepository, pricesClient: pricesClient, shopsClient: shopsClient}
+}
+
+// Get returns the collection with its items, priced and in the collection order.
+func (s *collectionsService) Get(ctx context.Context, collectionID string) (models.CollectionWithItems, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "CollectionsService.Get")
+	defer span.End()
+
+	collection, err := s.repository.Get(ctx, collectionID)
+	if err != nil {
+		return models.CollectionWithItems{}, err
+	}
+
+	response := models.CollectionWithItems{Collection: collection, Items: []models.Item{}}
+	if len(collection.ItemIDs) == 0 {
+		return response, nil
+	}
+
+	items, err := s.itemsRepository.GetByIDs(ctx, collection.ItemIDs)
+	if err != nil {
+		if err.Status() == http.StatusNotFound {
+			return response, nil
+		}
+		return models.CollectionWithItems{}, err
+	}
+
+	prices, err := s.pricesClient.GetItemsPrices(ctx, items.GetItemsIds())
+	if err != nil {
+		return models.CollectionWithItems{}, err
+	}
+
+	response.Items = collection.SortItems(items.SetPriceToItems(prices))
+
+	return response, nil
+}
+
+func (s *collectionsService) GetByShopID(ctx context.Context, shopID string) ([]models.Collection, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "CollectionsService.GetByShopID")
+	defer span.End()
+
+	collections, err := s.repository.GetByShopID(ctx, shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	if collections == nil {
+		return []models.Collection{}, nil
+	}
+
+	return collections, nil
+}
+
+func (s *collectionsService) Create(ctx context.Context, userID string, input dto.CollectionDTO) (interface{}, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "CollectionsService.Create")
+	defer span.End()
+
+	shop, err := s.shopsClient.GetShopByUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := models.Collection{
+		ShopID:   shop.ID,
+		UserID:   userID,
+		Name:     input.Name,
+		NameI18n: input.NameI18n,
+		Position: input.Position,
+		ItemIDs:  []string{},
+	}
+
+	return s.repository.Create(ctx, collection)
+}
+
+func (s *collectionsService) Update(ctx context.Context, userID string, collectionID string, input dto.CollectionDTO) apierrors.ApiError {
+	ctx, span := telemetry.Start(ctx, "CollectionsService.Update")
+	defer span.End()
+
+	collection, err := s.getOwned(ctx, userID, collectionID)
+	if err != nil {
+		return err
+	}
+
+	collection.Name = input.Name
+	collection.NameI18n = input.NameI18n
+	collection.Position = input.Position
+
+	return s.repository.Update(ctx, collection)
+}
+
+// SetItems replaces the ordered list of items of the collection. Every item
+// must exist and belong to the shop owning the collection.
+func (s *collectionsService) SetItems(ctx context.Context, userID string, collectionID string, itemIDs []string) apierrors.ApiError {
+	ctx, span := telemetry.Start(ctx, "CollectionsService.SetItems")
+	defer span.End()
+
+	collection, err := s.getOwned(ctx, userID, collectionID)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(itemIDs))
+	for _, id := range itemIDs {
+		if seen[id] {
+			return ErrorCollectionDuplicatedItems
+		}
+		seen[id] = true
+	}
+
+	if len(itemIDs) > 0 {
+		items, err := s.itemsRepository.GetByIDs(ctx, itemIDs)
+		if err != nil {
+			return err
+		}
+
+		if len(items.Items) != len(itemIDs) {
+			return apierrors.NewNotFoundApiError("one or more of the provided items do not exist")
+		}
+
+		for _, item := range items.Items {
+			if item.ShopID != collection.ShopID {
+				return ErrorCollectionItemsNotOwned
+			}
+		}
+	}
+
+	return s.repository.SetItems(ctx, collectionID, itemIDs)
+}
+
+func (s *collectionsService) Delete(ctx context.Context, userID string, collectionID string) apierrors.ApiError {
+	ctx, span := telemetry.Start(ctx, "CollectionsService.Delete")
+	defer span.End()
+
+	_, err := s.getOwned(ctx, userID, collectionID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.repository.Delete(ctx, collectionID)
+
+	return err
+}
+
+func (s *collectionsService) getOwned(ctx context.Context, userID string, collectionID string) (models.Collection, apierrors.ApiError) {
+	collection, err := s.repository.Get(ctx, collectionID)
+	if err != nil {
+		return models.Collection{}, err
+	}
+
+	if collection.UserID != userID {
+		return models.Collection{}, ErrorCollectionNotOwned
+	}
+
+	return collection, nil
+}
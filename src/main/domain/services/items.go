@@ -4,16 +4,26 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
 	"github.com/agustinrabini/items-api-project/src/main/domain/clients"
 	"github.com/agustinrabini/items-api-project/src/main/domain/models"
 	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
 	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	"github.com/agustinrabini/items-api-project/src/main/domain/utils"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/metrics"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/telemetry"
 
 	"github.com/creasty/defaults"
 	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// slugWriteAttempts bounds the retries of a write whose slug was taken by a
+// concurrent one.
+const slugWriteAttempts = 3
+
+var ErrorItemNotOwned = catalog.Forbidden.New("the item does not belong to the user")
+
 type ItemsService interface {
 	Get(ctx context.Context, itemID string) (models.Item, apierrors.ApiError)
 	GetItemsByUserID(ctx context.Context, userID string) (models.Items, apierrors.ApiError)
@@ -26,6 +36,15 @@ type ItemsService interface {
 
 	UpdateItemsCategories(ctx context.Context, category models.Category) apierrors.ApiError
 	GetByCategoryID(ctx context.Context, categoryID string) ([]models.Item, apierrors.ApiError)
+
+	SetTranslation(ctx context.Context, userID string, itemID string, locale string, translation models.ItemTranslation) apierrors.ApiError
+	DeleteTranslation(ctx context.Context, userID string, itemID string, locale string) apierrors.ApiError
+
+	CountByCategory(ctx context.Context, shopID string) ([]models.CategoryCount, apierrors.ApiError)
+	CountByCategoryID(ctx context.Context, categoryID string) (int64, apierrors.ApiError)
+
+	GetBySlug(ctx context.Context, slug string) (models.Item, apierrors.ApiError)
+	Search(ctx context.Context, query models.ItemsQuery) (models.ItemsPage, apierrors.ApiError)
 }
 
 type itemsService struct {
@@ -39,6 +58,9 @@ func NewItemsService(repository repositories.ItemsRepository, pricesClient clien
 }
 
 func (s *itemsService) Get(ctx context.Context, itemID string) (models.Item, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "ItemsService.Get")
+	defer span.End()
+
 	item, err := s.repository.Get(ctx, itemID)
 	if err != nil {
 		return models.Item{}, err
@@ -55,6 +77,9 @@ func (s *itemsService) Get(ctx context.Context, itemID string) (models.Item, api
 }
 
 func (s *itemsService) GetItemsByUserID(ctx context.Context, userID string) (models.Items, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "ItemsService.GetItemsByUserID")
+	defer span.End()
+
 	items, err := s.repository.GetByUserID(ctx, userID)
 	if err != nil {
 		return models.Items{}, err
@@ -71,6 +96,9 @@ func (s *itemsService) GetItemsByUserID(ctx context.Context, userID string) (mod
 }
 
 func (s *itemsService) GetItemsByShopID(ctx context.Context, shopID string) (models.Items, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "ItemsService.GetItemsByShopID")
+	defer span.End()
+
 	items, err := s.repository.GetByShopID(ctx, shopID)
 	if err != nil {
 		return models.Items{}, err
@@ -87,6 +115,9 @@ func (s *itemsService) GetItemsByShopID(ctx context.Context, shopID string) (mod
 }
 
 func (s *itemsService) GetItemsByShopCategoryID(ctx context.Context, shopID string, categoryID string) (models.Items, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "ItemsService.GetItemsByShopCategoryID")
+	defer span.End()
+
 	items, err := s.repository.GetByShopCategoryID(ctx, shopID, categoryID)
 	if err != nil {
 		return models.Items{}, err
@@ -103,6 +134,9 @@ func (s *itemsService) GetItemsByShopCategoryID(ctx context.Context, shopID stri
 }
 
 func (s *itemsService) GetItemsByIDs(ctx context.Context, itemsIds models.ItemsIds) (models.Items, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "ItemsService.GetItemsByIDs")
+	defer span.End()
+
 	items, err := s.repository.GetByIDs(ctx, itemsIds.Items)
 	if err != nil {
 		return models.Items{}, err
@@ -119,6 +153,9 @@ func (s *itemsService) GetItemsByIDs(ctx context.Context, itemsIds models.ItemsI
 }
 
 func (s *itemsService) CreateItem(ctx context.Context, request dto.ItemDTO) (interface{}, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "ItemsService.CreateItem")
+	defer span.End()
+
 	shopID, apiErr := s.shopsClient.GetShopByUserID(ctx)
 	if apiErr != nil {
 		return nil, apiErr
@@ -138,15 +175,24 @@ func (s *itemsService) CreateItem(ctx context.Context, request dto.ItemDTO) (int
 
 	item.SetEligibleIDs()
 
-	insertedID, apiErr := s.repository.Save(ctx, item)
+	var insertedID interface{}
+	apiErr = writeWithSlug(ctx, s.repository, item.Name, "", func(slug string) apierrors.ApiError {
+		item.Slug = slug
+
+		var err apierrors.ApiError
+		insertedID, err = s.repository.Save(ctx, item)
+		return err
+	})
 	if apiErr != nil {
 		return nil, apiErr
 	}
+	metrics.ItemsCreated.Inc()
 
 	item.Price.ItemID = insertedID.(primitive.ObjectID).Hex()
 
 	apiErr = s.pricesClient.CreatePrice(ctx, &item.Price)
 	if apiErr != nil {
+		metrics.PriceSyncFailures.WithLabelValues("create").Inc()
 		return nil, apiErr
 	}
 
@@ -154,6 +200,9 @@ func (s *itemsService) CreateItem(ctx context.Context, request dto.ItemDTO) (int
 }
 
 func (s *itemsService) Update(ctx context.Context, itemID string, request dto.ItemDTO) apierrors.ApiError {
+	ctx, span := telemetry.Start(ctx, "ItemsService.Update")
+	defer span.End()
+
 	item, err := request.ToItem()
 	if err != nil {
 		return apierrors.NewBadRequestApiError("Error convert body to domain: " + err.Error())
@@ -167,15 +216,42 @@ func (s *itemsService) Update(ctx context.Context, itemID string, request dto.It
 	item.Price.ID = oldPrice.ID
 	item.Price.ItemID = itemID
 
-	_, apiErr = s.repository.Update(ctx, itemID, &item)
+	current, apiErr := s.repository.Get(ctx, itemID)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	// legacy items get their first slug on the next update
+	if current.Slug == "" || current.Name != item.Name {
+		apiErr = writeWithSlug(ctx, s.repository, item.Name, itemID, func(slug string) apierrors.ApiError {
+			renamed := current
+			renamed.SetSlug(slug)
+
+			item.Slug = renamed.Slug
+			item.SlugHistory = renamed.SlugHistory
+
+			_, err := s.repository.Update(ctx, itemID, &item)
+			return err
+		})
+	} else {
+		_, apiErr = s.repository.Update(ctx, itemID, &item)
+	}
+	if apiErr != nil {
+		return apiErr
+	}
+
+	apiErr = s.pricesClient.UpdatePrice(ctx, &item.Price)
 	if apiErr != nil {
+		metrics.PriceSyncFailures.WithLabelValues("update").Inc()
 		return apiErr
 	}
 
-	return s.pricesClient.UpdatePrice(ctx, &item.Price)
+	return nil
 }
 
 func (s *itemsService) Delete(ctx context.Context, itemID string) apierrors.ApiError {
+	ctx, span := telemetry.Start(ctx, "ItemsService.Delete")
+	defer span.End()
 
 	item, err := s.repository.Get(ctx, itemID)
 	if err != nil {
@@ -186,15 +262,27 @@ func (s *itemsService) Delete(ctx context.Context, itemID string) apierrors.ApiE
 	if err != nil {
 		return err
 	}
+	metrics.ItemsDeleted.Inc()
+
+	err = s.pricesClient.DeletePrice(ctx, item.ID)
+	if err != nil {
+		metrics.PriceSyncFailures.WithLabelValues("delete").Inc()
+		return err
+	}
 
-	return s.pricesClient.DeletePrice(ctx, item.ID)
+	return nil
 }
 
 func (s *itemsService) UpdateItemsCategories(ctx context.Context, category models.Category) apierrors.ApiError {
+	ctx, span := telemetry.Start(ctx, "ItemsService.UpdateItemsCategories")
+	defer span.End()
+
 	return s.repository.UpdateItemsCategories(ctx, &category)
 }
 
 func (s *itemsService) GetByCategoryID(ctx context.Context, categoryID string) ([]models.Item, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "ItemsService.GetByCategoryID")
+	defer span.End()
 
 	items, err := s.repository.GetByCategoryID(ctx, categoryID)
 	if err != nil {
@@ -203,3 +291,131 @@ func (s *itemsService) GetByCategoryID(ctx context.Context, categoryID string) (
 
 	return items, nil
 }
+
+func (s *itemsService) SetTranslation(ctx context.Context, userID string, itemID string, locale string, translation models.ItemTranslation) apierrors.ApiError {
+	ctx, span := telemetry.Start(ctx, "ItemsService.SetTranslation")
+	defer span.End()
+
+	apiErr := s.validateOwnership(ctx, userID, itemID)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return s.repository.SetTranslation(ctx, itemID, locale, translation)
+}
+
+func (s *itemsService) DeleteTranslation(ctx context.Context, userID string, itemID string, locale string) apierrors.ApiError {
+	ctx, span := telemetry.Start(ctx, "ItemsService.DeleteTranslation")
+	defer span.End()
+
+	apiErr := s.validateOwnership(ctx, userID, itemID)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return s.repository.DeleteTranslation(ctx, itemID, locale)
+}
+
+func (s *itemsService) validateOwnership(ctx context.Context, userID string, itemID string) apierrors.ApiError {
+	item, err := s.repository.Get(ctx, itemID)
+	if err != nil {
+		return err
+	}
+
+	if item.UserID != userID {
+		return ErrorItemNotOwned
+	}
+
+	return nil
+}
+
+func (s *itemsService) CountByCategory(ctx context.Context, shopID string) ([]models.CategoryCount, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "ItemsService.CountByCategory")
+	defer span.End()
+
+	counts, err := s.repository.CountByCategory(ctx, shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	if counts == nil {
+		return []models.CategoryCount{}, nil
+	}
+
+	return counts, nil
+}
+
+func (s *itemsService) CountByCategoryID(ctx context.Context, categoryID string) (int64, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "ItemsService.CountByCategoryID")
+	defer span.End()
+
+	return s.repository.CountByCategoryID(ctx, categoryID)
+}
+
+// GetBySlug returns the priced item owning the slug, which may be one of its
+// previous slugs: callers compare it with the item's current slug.
+func (s *itemsService) GetBySlug(ctx context.Context, slug string) (models.Item, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "ItemsService.GetBySlug")
+	defer span.End()
+
+	item, err := s.repository.GetBySlug(ctx, slug)
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	item.Price, err = s.pricesClient.GetPriceByItemID(ctx, item.ID)
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	item.Validate()
+
+	return item, nil
+}
+
+// writeWithSlug runs write with a free slug for the name of the item. The
+// slug index is unique, so when a concurrent write takes the slug between the
+// check and the write it retries with the next free one.
+func writeWithSlug(ctx context.Context, repository repositories.ItemsRepository, name string, itemID string, write func(slug string) apierrors.ApiError) apierrors.ApiError {
+	var err apierrors.ApiError
+	for attempt := 0; attempt < slugWriteAttempts; attempt++ {
+		var slug string
+		slug, err = utils.UniqueSlug(name, func(slug string) (bool, apierrors.ApiError) {
+			return repository.SlugExists(ctx, slug, itemID)
+		})
+		if err != nil {
+			return err
+		}
+
+		if err = write(slug); err != repositories.SlugTakenError {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (s *itemsService) Search(ctx context.Context, query models.ItemsQuery) (models.ItemsPage, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "ItemsService.Search")
+	defer span.End()
+
+	page, err := s.repository.Search(ctx, query)
+	if err != nil {
+		return models.ItemsPage{}, err
+	}
+
+	if len(page.Items) == 0 {
+		return page, nil
+	}
+
+	items := models.Items{Items: page.Items}
+
+	response, err := s.pricesClient.GetItemsPrices(ctx, items.GetItemsIds())
+	if err != nil {
+		return models.ItemsPage{}, err
+	}
+
+	page.Items = items.SetPriceToItems(response).Items
+
+	return page, nil
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/agustinrabini/items-api-project/src/main/domain/clients"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/actor"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/telemetry"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// reconciliationLeaseTTL is how long a run holds the lease without renewing
+// it. Runs renew it on every chunk, so it only expires when the replica dies.
+const reconciliationLeaseTTL = 5 * time.Minute
+
+var ErrorReconciliationRunning = catalog.Conflict.New("the price reconciliation is already running")
+
+// ReconciliationService compares the items with the prices API, because
+// their writes are not atomic. It finds:
+//   - active items without a price, which the listings leave out silently;
+//   - prices of items in the trash, which nothing sells anymore;
+//   - unpriced items whose price exists again;
+//   - prices of items removed from the collection.
+//
+// With repair it marks the items without a price as unpriced, which can't be
+// bought until their price exists again, deletes the prices of the trash and
+// of the removed items and activates the unpriced items again. A lease in
+// the database keeps the replicas from running it at the same time.
+type ReconciliationService interface {
+	Run(ctx context.Context, repair bool) (models.ReconciliationReport, apierrors.ApiError)
+	LastReport(ctx context.Context) (models.ReconciliationReport, apierrors.ApiError)
+}
+
+type reconciliationService struct {
+	items        repositories.ItemsRepository
+	pricesClient clients.PriceClient
+	reports      repositories.ReconciliationRepository
+	chunkSize    int
+
+	// running keeps a scheduled run and one asked for by hand apart, and
+	// the lease of owner the runs of other replicas
+	running sync.Mutex
+	owner   string
+}
+
+// NewReconciliationService asks the prices API for chunkSize items at once.
+func NewReconciliationService(items repositories.ItemsRepository, pricesClient clients.PriceClient, reports repositories.ReconciliationRepository, chunkSize int) ReconciliationService {
+	return &reconciliationService{items: items, pricesClient: pricesClient, reports: reports, chunkSize: chunkSize, owner: primitive.NewObjectID().Hex()}
+}
+
+// Run checks every item and stores the report. A repair that fails is
+// recorded in its finding and doesn't stop the run, and neither does a failed
+// scan of the prices without item, recorded in PriceScanError.
+func (s *reconciliationService) Run(ctx context.Context, repair bool) (models.ReconciliationReport, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "ReconciliationService.Run")
+	defer span.End()
+
+	ctx = actor.With(ctx, actor.Reconciliation)
+
+	if !s.running.TryLock() {
+		return models.ReconciliationReport{}, ErrorReconciliationRunning
+	}
+	defer s.running.Unlock()
+
+	if err := s.lease(ctx); err != nil {
+		return models.ReconciliationReport{}, err
+	}
+	defer s.releaseLease(ctx)
+
+	report := models.ReconciliationReport{Repair: repair, StartedAt: time.Now().UTC(), Findings: []models.ReconciliationFinding{}}
+
+	// the pages are sorted by _id, so the repairs don't move items between them
+	for offset := 0; ; offset += s.chunkSize {
+		query := models.ItemsQuery{Limit: s.chunkSize, Offset: offset}
+		page, err := s.items.Search(ctx, query)
+		if err != nil {
+			return report, err
+		}
+
+		findings, err := s.check(ctx, page.Items, repair)
+		if err != nil {
+			return report, err
+		}
+		report.ItemsChecked += len(page.Items)
+		report.Findings = append(report.Findings, findings...)
+
+		if !page.HasMore(query) {
+			break
+		}
+
+		if err = s.lease(ctx); err != nil {
+			return report, err
+		}
+	}
+
+	// not every prices API lists its items, so a failed scan is recorded in
+	// the report instead of losing the findings and repairs of the items
+	findings, err := s.checkPrices(ctx, repair)
+	if err != nil {
+		logger.Error("Error scanning the prices without item", err)
+		report.PriceScanError = err.Message()
+	}
+	report.Findings = append(report.Findings, findings...)
+
+	report.FinishedAt = time.Now().UTC()
+
+	if err = s.reports.Save(ctx, report); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+func (s *reconciliationService) LastReport(ctx context.Context) (models.ReconciliationReport, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "ReconciliationService.LastReport")
+	defer span.End()
+
+	return s.reports.Last(ctx)
+}
+
+// check compares one chunk of items with their prices.
+func (s *reconciliationService) check(ctx context.Context, items []models.Item, repair bool) ([]models.ReconciliationFinding, apierrors.ApiError) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	chunk := models.Items{Items: items}
+	response, err := s.pricesClient.GetItemsPrices(ctx, chunk.GetItemsIds())
+	if err != nil && err.Status() != http.StatusNotFound {
+		return nil, err
+	}
+
+	prices := make(map[string]models.Price, len(response.Prices))
+	for _, price := range response.Prices {
+		prices[price.ItemID] = price
+	}
+
+	var findings []models.ReconciliationFinding
+	for _, item := range items {
+		price, priced := prices[item.ID]
+		finding := models.ReconciliationFinding{ItemID: item.ID, ShopID: item.ShopID, Status: item.Status, PriceID: price.ID}
+
+		var repairErr apierrors.ApiError
+		switch {
+		case !priced && (item.Status == "" || item.Status == models.ItemStatusActive):
+			finding.Reason = models.ReconciliationMissingPrice
+			if repair {
+				repairErr = s.setStatus(ctx, item, models.ItemStatusUnpriced)
+			}
+		case priced && item.Status == models.ItemStatusDeleted:
+			finding.Reason = models.ReconciliationOrphanPrice
+			if repair {
+				repairErr = s.pricesClient.DeletePrice(ctx, item.ID)
+				if repairErr != nil && repairErr.Status() == http.StatusNotFound {
+					repairErr = nil
+				}
+			}
+		case priced && item.Status == models.ItemStatusUnpriced:
+			finding.Reason = models.ReconciliationPriceBack
+			if repair {
+				repairErr = s.setStatus(ctx, item, models.ItemStatusActive)
+			}
+		default:
+			continue
+		}
+
+		if repairErr != nil {
+			finding.Error = repairErr.Message()
+		} else {
+			finding.Repaired = repair
+		}
+		findings = append(findings, finding)
+	}
+
+	return findings, nil
+}
+
+// checkPrices finds the prices whose item is not in the collection, e.g. when
+// deleting the price failed after the item was deleted. They are all listed
+// before deleting any, as the deletes would move the pages.
+func (s *reconciliationService) checkPrices(ctx context.Context, repair bool) ([]models.ReconciliationFinding, apierrors.ApiError) {
+	var orphans []string
+	for offset := 0; ; offset += s.chunkSize {
+		page, err := s.pricesClient.ListPricedItems(ctx, offset, s.chunkSize)
+		if err != nil {
+			return nil, err
+		}
+
+		missing, err := s.missingItems(ctx, page.ItemIDs)
+		if err != nil {
+			return nil, err
+		}
+		orphans = append(orphans, missing...)
+
+		if len(page.ItemIDs) == 0 || int64(offset+len(page.ItemIDs)) >= page.Total {
+			break
+		}
+
+		if err = s.lease(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	findings := make([]models.ReconciliationFinding, 0, len(orphans))
+	for _, itemID := range orphans {
+		finding := models.ReconciliationFinding{Reason: models.ReconciliationPriceWithoutItem, ItemID: itemID}
+		if repair {
+			if err := s.pricesClient.DeletePrice(ctx, itemID); err != nil && err.Status() != http.StatusNotFound {
+				finding.Error = err.Message()
+			} else {
+				finding.Repaired = true
+			}
+		}
+		findings = append(findings, finding)
+	}
+
+	return findings, nil
+}
+
+// missingItems returns the IDs that are not in the items collection.
+func (s *reconciliationService) missingItems(ctx context.Context, itemIDs []string) ([]string, apierrors.ApiError) {
+	var valid []string
+	for _, itemID := range itemIDs {
+		if primitive.IsValidObjectID(itemID) {
+			valid = append(valid, itemID)
+		}
+	}
+
+	found := map[string]bool{}
+	if len(valid) > 0 {
+		items, err := s.items.GetByIDs(ctx, valid)
+		if err != nil && err.Status() != http.StatusNotFound {
+			return nil, err
+		}
+		for _, item := range items.Items {
+			found[item.ID] = true
+		}
+	}
+
+	var missing []string
+	for _, itemID := range itemIDs {
+		if !found[itemID] {
+			missing = append(missing, itemID)
+		}
+	}
+
+	return missing, nil
+}
+
+// lease takes or renews the lease of the runs, failing when another replica
+// holds it.
+func (s *reconciliationService) lease(ctx context.Context) apierrors.ApiError {
+	acquired, err := s.reports.AcquireLease(ctx, s.owner, reconciliationLeaseTTL)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return ErrorReconciliationRunning
+	}
+
+	return nil
+}
+
+// releaseLease lets the next run start right away, even when ctx was
+// cancelled; otherwise the lease expires.
+func (s *reconciliationService) releaseLease(ctx context.Context) {
+	if err := s.reports.ReleaseLease(context.WithoutCancel(ctx), s.owner); err != nil {
+		logger.Error("Error releasing the price reconciliation lease", err)
+	}
+}
+
+// setStatus keeps the category of the item, as the update sets it even when
+// empty.
+func (s *reconciliationService) setStatus(ctx context.Context, item models.Item, status string) apierrors.ApiError {
+	_, err := s.items.Update(ctx, item.ID, &models.Item{Status: status, Category: item.Category})
+	return err
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/agustinrabini/items-api-project/src/main/domain/clients"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/telemetry"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+var ErrorRevisionCategoryGone = catalog.BadRequest.New("the category of the revision doesn't exist anymore, update the item instead")
+var ErrorRevisionWithoutItem = catalog.Conflict.New("the revision doesn't keep the item, it can't be reverted to")
+
+// RevisionsService reads the revisions the items repository records, see
+// repositories.NewRevisionedItemsRepository, to the owner of the item.
+type RevisionsService interface {
+	List(ctx context.Context, userID string, itemID string) ([]models.Revision, apierrors.ApiError)
+	Get(ctx context.Context, userID string, itemID string, number int) (models.Revision, apierrors.ApiError)
+	Revert(ctx context.Context, userID string, itemID string, number int) apierrors.ApiError
+}
+
+type revisionsService struct {
+	items        repositories.ItemsRepository
+	revisions    repositories.RevisionsRepository
+	categories   repositories.CategoriesRepository
+	itemsService ItemsService
+	pricesClient clients.PriceClient
+}
+
+func NewRevisionsService(items repositories.ItemsRepository, revisions repositories.RevisionsRepository, categories repositories.CategoriesRepository, itemsService ItemsService, pricesClient clients.PriceClient) RevisionsService {
+	return &revisionsService{items: items, revisions: revisions, categories: categories, itemsService: itemsService, pricesClient: pricesClient}
+}
+
+func (s *revisionsService) List(ctx context.Context, userID string, itemID string) ([]models.Revision, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "RevisionsService.List")
+	defer span.End()
+
+	if _, err := s.owned(ctx, userID, itemID); err != nil {
+		return nil, err
+	}
+
+	return s.revisions.List(ctx, itemID)
+}
+
+func (s *revisionsService) Get(ctx context.Context, userID string, itemID string, number int) (models.Revision, apierrors.ApiError) {
+	ctx, span := telemetry.Start(ctx, "RevisionsService.Get")
+	defer span.End()
+
+	if _, err := s.owned(ctx, userID, itemID); err != nil {
+		return models.Revision{}, err
+	}
+
+	return s.revisions.Get(ctx, itemID, number)
+}
+
+// Revert updates the item to its state and price at the revision through
+// ItemsService.Update, which records it as a new revision. Like any update,
+// it doesn't clear the fields the revision has empty. The category is the
+// current one with the ID of the revision; the price is the current one when
+// the revision doesn't know it.
+func (s *revisionsService) Revert(ctx context.Context, userID string, itemID string, number int) apierrors.ApiError {
+	ctx, span := telemetry.Start(ctx, "RevisionsService.Revert")
+	defer span.End()
+
+	current, err := s.owned(ctx, userID, itemID)
+	if err != nil {
+		return err
+	}
+
+	revision, err := s.revisions.Get(ctx, itemID, number)
+	if err != nil {
+		return err
+	}
+
+	if revision.Item == nil {
+		return ErrorRevisionWithoutItem
+	}
+
+	var price models.Price
+	if revision.Price != nil {
+		price = *revision.Price
+	} else {
+		price, err = s.pricesClient.GetPriceByItemID(ctx, itemID)
+		if err != nil {
+			return err
+		}
+	}
+
+	item := *revision.Item
+	category, err := s.categories.Get(ctx, item.Category.ID)
+	if err != nil && err.Status() == http.StatusNotFound {
+		return ErrorRevisionCategoryGone
+	}
+	if err != nil {
+		return err
+	}
+	item.Category = models.Category{ID: category.ID, Name: category.Name, NameI18n: category.NameI18n, Slug: category.Slug}
+	item.UserID = current.UserID
+
+	request, mapErr := dto.NewItemDTO(item, price)
+	if mapErr != nil {
+		return apierrors.NewInternalServerApiError("Error building the update of the revision", mapErr)
+	}
+
+	return s.itemsService.Update(ctx, itemID, request)
+}
+
+func (s *revisionsService) owned(ctx context.Context, userID string, itemID string) (models.Item, apierrors.ApiError) {
+	item, err := s.items.Get(ctx, itemID)
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	if item.UserID != userID {
+		return models.Item{}, ErrorItemNotOwned
+	}
+
+	return item, nil
+}
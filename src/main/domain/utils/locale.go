@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+const (
+	LangQueryParam       = "lang"
+	AcceptLanguageHeader = "Accept-Language"
+	ContentLanguageKey   = "Content-Language"
+)
+
+// ResolveLocale picks the locale of a read request. The lang query parameter
+// wins over Accept-Language; regional variants (pt-BR) fall back to their base
+// language and anything unsupported falls back to the default locale.
+func ResolveLocale(acceptLanguage string, lang string) string {
+	if locale, ok := normalizeLocale(lang); ok {
+		return locale
+	}
+
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if locale, ok := normalizeLocale(tag); ok {
+			return locale
+		}
+	}
+
+	return models.DefaultLocale
+}
+
+func ValidateLocale(locale string) apierrors.ApiError {
+	if !models.IsSupportedLocale(locale) {
+		return catalog.InvalidLocale.New("unsupported locale " + locale)
+	}
+
+	if locale == models.DefaultLocale {
+		return catalog.InvalidLocale.New("the default locale is managed through the base fields")
+	}
+
+	return nil
+}
+
+// ValidateTranslations runs the locales of the translations of field, like
+// name_i18n, through ValidateLocale, in order so the error is stable.
+func ValidateTranslations(field string, translations map[string]string) apierrors.ApiError {
+	locales := make([]string, 0, len(translations))
+	for locale := range translations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	for _, locale := range locales {
+		if err := ValidateLocale(locale); err != nil {
+			return catalog.InvalidLocale.New(field + ": " + err.Message())
+		}
+	}
+
+	return nil
+}
+
+func normalizeLocale(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" || tag == "*" {
+		return "", false
+	}
+
+	base := strings.SplitN(strings.ReplaceAll(tag, "_", "-"), "-", 2)[0]
+	if !models.IsSupportedLocale(base) {
+		return "", false
+	}
+
+	return base, true
+}
+
+// parseAcceptLanguage returns the language tags of the header ordered by
+// their quality value, keeping the header order for equal weights.
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		if fields[0] == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if value, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					q = value
+				}
+			}
+		}
+
+		if q > 0 {
+			tags = append(tags, weighted{tag: fields[0], q: q})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		result = append(result, t.tag)
+	}
+
+	return result
+}
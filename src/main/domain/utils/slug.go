@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	slugMaxLength = 80
+	slugAttempts  = 20
+)
+
+var slugRegex = regexp.MustCompile("^[a-z0-9]+(-[a-z0-9]+)*$")
+
+var slugReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "ä", "a", "â", "a", "ã", "a",
+	"é", "e", "è", "e", "ë", "e", "ê", "e",
+	"í", "i", "ì", "i", "ï", "i", "î", "i",
+	"ó", "o", "ò", "o", "ö", "o", "ô", "o", "õ", "o",
+	"ú", "u", "ù", "u", "ü", "u", "û", "u",
+	"ñ", "n", "ç", "c",
+)
+
+// Slugify turns a name into a lowercase, URL-safe slug: accents are dropped
+// and every other run of non alphanumeric characters becomes a single dash.
+func Slugify(name string) string {
+	name = slugReplacer.Replace(strings.ToLower(name))
+
+	var b strings.Builder
+	dash := false
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimSuffix(b.String(), "-")
+	if len(slug) > slugMaxLength {
+		slug = strings.TrimSuffix(slug[:slugMaxLength], "-")
+	}
+
+	return slug
+}
+
+// UniqueSlug returns the slug of the name, adding a numeric suffix until the
+// exists callback reports it free. After a few collisions a short unique
+// suffix is used instead so very common names don't scan the collection.
+func UniqueSlug(name string, exists func(slug string) (bool, apierrors.ApiError)) (string, apierrors.ApiError) {
+	base := Slugify(name)
+	if base == "" {
+		return primitive.NewObjectID().Hex(), nil
+	}
+
+	for i := 1; i <= slugAttempts; i++ {
+		slug := base
+		if i > 1 {
+			slug = fmt.Sprintf("%s-%d", base, i)
+		}
+
+		taken, err := exists(slug)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return slug, nil
+		}
+	}
+
+	return fmt.Sprintf("%s-%s", base, primitive.NewObjectID().Hex()[18:]), nil
+}
+
+func ValidateSlug(slug string) apierrors.ApiError {
+	if !slugRegex.MatchString(slug) {
+		return catalog.InvalidSlug.New("the provided slug is not valid")
+	}
+
+	return nil
+}
@@ -3,6 +3,8 @@ package utils
 import (
 	"regexp"
 
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+
 	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
 )
 
@@ -15,7 +17,7 @@ func ValidateHexID(ids []string) apierrors.ApiError {
 	for _, id := range ids {
 		val := regex.MatchString(id)
 		if !val {
-			return apierrors.NewApiError("one or more of the provided ids are not a valid hex string", "bad_request", 400, apierrors.CauseList{})
+			return catalog.InvalidID.New("one or more of the provided ids are not a valid hex string")
 		}
 	}
 
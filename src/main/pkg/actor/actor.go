@@ -0,0 +1,23 @@
+// Package actor carries who makes a change through the context, for the
+// records that keep it, like the item revisions.
+package actor
+
+import "context"
+
+// Names of the actors that are not users.
+const (
+	Admin          = "items-admin"
+	Reconciliation = "price-reconciliation"
+)
+
+type key struct{}
+
+func With(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, key{}, actor)
+}
+
+// From returns the actor of ctx, empty when none was set.
+func From(ctx context.Context) string {
+	actor, _ := ctx.Value(key{}).(string)
+	return actor
+}
@@ -0,0 +1,91 @@
+package itemsclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+const (
+	CategoryBaseEndpoint   = ItemsBaseEndpoint + "/category"
+	CategoriesBaseEndpoint = ItemsBaseEndpoint + "/categories"
+)
+
+func (c *Client) GetCategories(ctx context.Context) ([]models.Category, apierrors.ApiError) {
+	var categories dto.CategoriesDTO
+
+	apiErr := c.get(ctx, CategoriesBaseEndpoint, &categories)
+
+	return categories.CategoryDTO, apiErr
+}
+
+// GetCategoriesCounts returns every category with the amount of items using
+// it.
+func (c *Client) GetCategoriesCounts(ctx context.Context) ([]models.CategoryCount, apierrors.ApiError) {
+	var counts dto.CategoriesCountDTO
+
+	apiErr := c.get(ctx, CategoriesBaseEndpoint+"?with_counts=true", &counts)
+
+	return counts.CategoryCountDTO, apiErr
+}
+
+// GetShopCategories returns the categories used by the items of a shop with
+// the amount of items in each one.
+func (c *Client) GetShopCategories(ctx context.Context, shopID string) ([]models.CategoryCount, apierrors.ApiError) {
+	var counts dto.CategoriesCountDTO
+
+	endpoint := fmt.Sprintf("%s/shop/%s/categories", ItemsBaseEndpoint, url.PathEscape(shopID))
+	apiErr := c.get(ctx, endpoint, &counts)
+
+	return counts.CategoryCountDTO, apiErr
+}
+
+func (c *Client) GetCategory(ctx context.Context, categoryID string) (models.Category, apierrors.ApiError) {
+	var category models.Category
+
+	endpoint := fmt.Sprintf("%s/%s", CategoryBaseEndpoint, url.PathEscape(categoryID))
+	apiErr := c.get(ctx, endpoint, &category)
+
+	return category, apiErr
+}
+
+// GetCategoryBySlug follows the redirect of the slugs a category had before.
+func (c *Client) GetCategoryBySlug(ctx context.Context, slug string) (models.Category, apierrors.ApiError) {
+	var category models.Category
+
+	endpoint := fmt.Sprintf("%s/by-slug/%s", CategoryBaseEndpoint, url.PathEscape(slug))
+	apiErr := c.get(ctx, endpoint, &category)
+
+	return category, apiErr
+}
+
+// CreateCategory, UpdateCategory, DeleteCategory and the category
+// translations are password protected, see WithHeader.
+func (c *Client) CreateCategory(ctx context.Context, category models.Category) apierrors.ApiError {
+	_, apiErr := c.create(ctx, CategoryBaseEndpoint, category)
+	return apiErr
+}
+
+func (c *Client) UpdateCategory(ctx context.Context, category models.Category) apierrors.ApiError {
+	return c.write(ctx, http.MethodPut, CategoryBaseEndpoint, category)
+}
+
+func (c *Client) DeleteCategory(ctx context.Context, categoryID string) apierrors.ApiError {
+	endpoint := fmt.Sprintf("%s/%s", CategoryBaseEndpoint, url.PathEscape(categoryID))
+	return c.write(ctx, http.MethodDelete, endpoint, nil)
+}
+
+func (c *Client) SetCategoryTranslation(ctx context.Context, categoryID string, locale string, translation dto.CategoryTranslationDTO) apierrors.ApiError {
+	endpoint := fmt.Sprintf("%s/%s/translations/%s", CategoryBaseEndpoint, url.PathEscape(categoryID), url.PathEscape(locale))
+	return c.write(ctx, http.MethodPut, endpoint, translation)
+}
+
+func (c *Client) DeleteCategoryTranslation(ctx context.Context, categoryID string, locale string) apierrors.ApiError {
+	endpoint := fmt.Sprintf("%s/%s/translations/%s", CategoryBaseEndpoint, url.PathEscape(categoryID), url.PathEscape(locale))
+	return c.write(ctx, http.MethodDelete, endpoint, nil)
+}
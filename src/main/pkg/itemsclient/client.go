@@ -0,0 +1,284 @@
+// Package itemsclient is the Go client of the items API. It adds the
+// Authorization and X-Trace-ID headers, propagates the OpenTelemetry trace,
+// retries the calls that are safe to repeat and decodes the API errors into
+// apierrors.ApiError values.
+package itemsclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	mathrand "math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jopitnow/go-jopit-toolkit/goauth"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/jopitnow/go-jopit-toolkit/rest"
+	"github.com/jopitnow/go-jopit-toolkit/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	DefaultVersion = "v1"
+	DefaultTimeout = 5 * time.Second
+
+	IdempotencyKeyHeader = "Idempotency-Key"
+	retryAfterHeader     = "Retry-After"
+)
+
+// TokenSource returns the Authorization header of the calls that don't carry
+// one in their context, e.g. a service account token.
+type TokenSource func(ctx context.Context) (string, error)
+
+// RetryPolicy is how many times a failed call is repeated and how long the
+// client waits between attempts. The wait doubles on every attempt, with
+// jitter, unless the API answers with a Retry-After.
+type RetryPolicy struct {
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{MaxRetries: 2, MinBackoff: 100 * time.Millisecond, MaxBackoff: 2 * time.Second}
+
+// Client calls the items API. It is safe for concurrent use.
+type Client struct {
+	Builder *rest.RequestBuilder
+
+	version     string
+	tokenSource TokenSource
+	headers     http.Header
+	retry       RetryPolicy
+}
+
+type Option func(*Client)
+
+// WithVersion picks the API version the calls go to, v1 by default.
+func WithVersion(version string) Option {
+	return func(c *Client) { c.version = version }
+}
+
+// WithTimeout bounds every attempt of a call.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) { c.Builder.Timeout = timeout }
+}
+
+// WithTokenSource sets where the Authorization header comes from when the
+// context of the call has none.
+func WithTokenSource(source TokenSource) Option {
+	return func(c *Client) { c.tokenSource = source }
+}
+
+// WithHeader adds a header to every call, e.g. the password of the category
+// routes.
+func WithHeader(name string, value string) Option {
+	return func(c *Client) { c.headers.Add(name, value) }
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy. A zero MaxRetries disables
+// the retries.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) { c.retry = policy }
+}
+
+// New returns a client of the items API served at baseURL.
+func New(baseURL string, opts ...Option) *Client {
+	client := &Client{
+		Builder: &rest.RequestBuilder{
+			BaseURL:        baseURL,
+			Timeout:        DefaultTimeout,
+			ContentType:    rest.JSON,
+			EnableCache:    false,
+			DisableTimeout: false,
+			FollowRedirect: true,
+			MetricsConfig:  rest.MetricsReportConfig{TargetId: "items-api"},
+		},
+		version: DefaultVersion,
+		headers: http.Header{},
+		retry:   DefaultRetryPolicy,
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	return client
+}
+
+type contextKey string
+
+const (
+	authorizationKey  contextKey = "itemsclient.authorization"
+	idempotencyKeyKey contextKey = "itemsclient.idempotency_key"
+)
+
+// WithAuthorization sets the Authorization header of the calls made with ctx.
+// Contexts built by this API's handlers already carry it under
+// goauth.FirebaseAuthHeader, which is used when this is missing.
+func WithAuthorization(ctx context.Context, authorization string) context.Context {
+	return context.WithValue(ctx, authorizationKey, authorization)
+}
+
+// WithIdempotencyKey sets the Idempotency-Key of the create calls made with
+// ctx. Without it every create gets a random key, kept across its retries.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey, key)
+}
+
+// call is one request to the API. retryable tells whether repeating it can't
+// apply it twice.
+type call struct {
+	method    string
+	endpoint  string
+	body      interface{}
+	retryable bool
+	expected  []int
+}
+
+func (c *Client) do(ctx context.Context, req call) (*rest.Response, apierrors.ApiError) {
+	headers, apiErr := c.requestHeaders(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	if req.method == http.MethodPost && req.retryable {
+		headers.Set(IdempotencyKeyHeader, idempotencyKey(ctx))
+	}
+
+	endpoint := "/" + c.version + req.endpoint
+
+	var response *rest.Response
+	for attempt := 0; ; attempt++ {
+		response = c.send(ctx, req.method, endpoint, req.body, headers)
+
+		if !req.retryable || attempt >= c.retry.MaxRetries || !shouldRetry(response) {
+			break
+		}
+
+		timer := time.NewTimer(c.retry.backoff(attempt, response))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, apierrors.NewInternalServerApiError(fmt.Sprintf("call to items api canceled, url: %s", endpoint), ctx.Err())
+		case <-timer.C:
+		}
+	}
+
+	if response.Response == nil {
+		return nil, apierrors.NewInternalServerApiError(fmt.Sprintf("unexpected error calling items api, url: %s", endpoint), response.Err)
+	}
+
+	for _, status := range req.expected {
+		if response.StatusCode == status {
+			return response, nil
+		}
+	}
+
+	return nil, decodeError(response)
+}
+
+func (c *Client) send(ctx context.Context, method string, endpoint string, body interface{}, headers http.Header) *rest.Response {
+	options := []rest.Option{rest.Context(ctx), rest.Headers(headers)}
+
+	switch method {
+	case http.MethodPost:
+		return c.Builder.Post(endpoint, body, options...)
+	case http.MethodPut:
+		return c.Builder.Put(endpoint, body, options...)
+	case http.MethodDelete:
+		return c.Builder.Delete(endpoint, options...)
+	default:
+		return c.Builder.Get(endpoint, options...)
+	}
+}
+
+// requestHeaders adds to the static headers the Authorization, the
+// X-Trace-ID and the traceparent of the call.
+func (c *Client) requestHeaders(ctx context.Context) (http.Header, apierrors.ApiError) {
+	headers := c.headers.Clone()
+
+	authorization, err := c.authorization(ctx)
+	if err != nil {
+		return nil, apierrors.NewUnauthorizedApiError(fmt.Sprintf("error getting the items api token: %s", err.Error()))
+	}
+	if authorization != "" {
+		headers.Set(goauth.FirebaseAuthHeader, authorization)
+	}
+
+	if traceID := ctx.Value(tracing.XtraceHeaderKey); traceID != nil && fmt.Sprint(traceID) != "" {
+		headers.Set(tracing.XtraceHeaderKey, fmt.Sprint(traceID))
+	}
+
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(headers))
+
+	return headers, nil
+}
+
+func (c *Client) authorization(ctx context.Context) (string, error) {
+	if authorization, ok := ctx.Value(authorizationKey).(string); ok && authorization != "" {
+		return authorization, nil
+	}
+
+	if authorization, ok := ctx.Value(goauth.FirebaseAuthHeader).(string); ok && authorization != "" {
+		return authorization, nil
+	}
+
+	if c.tokenSource != nil {
+		return c.tokenSource(ctx)
+	}
+
+	return "", nil
+}
+
+func idempotencyKey(ctx context.Context) string {
+	if key, ok := ctx.Value(idempotencyKeyKey).(string); ok && key != "" {
+		return key
+	}
+
+	key := make([]byte, 16)
+	_, _ = rand.Read(key)
+
+	return hex.EncodeToString(key)
+}
+
+// shouldRetry tells whether the attempt failed in a way another one may not:
+// no response at all, rate limited or the API unavailable.
+func shouldRetry(response *rest.Response) bool {
+	if response.Response == nil {
+		return true
+	}
+
+	switch response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+func (p RetryPolicy) backoff(attempt int, response *rest.Response) time.Duration {
+	if response.Response != nil {
+		if seconds, err := strconv.Atoi(response.Header.Get(retryAfterHeader)); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	backoff := float64(p.MinBackoff) * math.Pow(2, float64(attempt))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	// waits between half and the whole backoff, so callers don't retry in step
+	half := time.Duration(backoff / 2)
+	if half <= 0 {
+		return 0
+	}
+
+	return half + time.Duration(mathrand.Int63n(int64(half)+1))
+}
@@ -0,0 +1,55 @@
+package itemsclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+const CollectionsBaseEndpoint = ItemsBaseEndpoint + "/collections"
+
+// GetCollection returns the collection with its items in order.
+func (c *Client) GetCollection(ctx context.Context, collectionID string) (models.CollectionWithItems, apierrors.ApiError) {
+	var collection models.CollectionWithItems
+
+	endpoint := fmt.Sprintf("%s/%s", CollectionsBaseEndpoint, url.PathEscape(collectionID))
+	apiErr := c.get(ctx, endpoint, &collection)
+
+	return collection, apiErr
+}
+
+func (c *Client) GetShopCollections(ctx context.Context, shopID string) ([]models.Collection, apierrors.ApiError) {
+	var collections []models.Collection
+
+	endpoint := fmt.Sprintf("%s/shop/%s/collections", ItemsBaseEndpoint, url.PathEscape(shopID))
+	apiErr := c.get(ctx, endpoint, &collections)
+
+	return collections, apiErr
+}
+
+// CreateCollection creates the collection in the shop of the authenticated
+// seller and returns its ID.
+func (c *Client) CreateCollection(ctx context.Context, collection dto.CollectionDTO) (string, apierrors.ApiError) {
+	return c.create(ctx, CollectionsBaseEndpoint, collection)
+}
+
+func (c *Client) UpdateCollection(ctx context.Context, collectionID string, collection dto.CollectionDTO) apierrors.ApiError {
+	endpoint := fmt.Sprintf("%s/%s", CollectionsBaseEndpoint, url.PathEscape(collectionID))
+	return c.write(ctx, http.MethodPut, endpoint, collection)
+}
+
+// SetCollectionItems replaces the items of the collection, in order.
+func (c *Client) SetCollectionItems(ctx context.Context, collectionID string, itemsIDs []string) apierrors.ApiError {
+	endpoint := fmt.Sprintf("%s/%s/items", CollectionsBaseEndpoint, url.PathEscape(collectionID))
+	return c.write(ctx, http.MethodPut, endpoint, models.ItemsIds{Items: itemsIDs})
+}
+
+func (c *Client) DeleteCollection(ctx context.Context, collectionID string) apierrors.ApiError {
+	endpoint := fmt.Sprintf("%s/%s", CollectionsBaseEndpoint, url.PathEscape(collectionID))
+	return c.write(ctx, http.MethodDelete, endpoint, nil)
+}
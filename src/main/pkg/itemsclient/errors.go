@@ -0,0 +1,73 @@
+package itemsclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/jopitnow/go-jopit-toolkit/rest"
+)
+
+// errorBody holds both error formats of the API: the apierrors JSON of v1
+// and the problem details of v2.
+type errorBody struct {
+	Message string              `json:"message"`
+	Error   string              `json:"error"`
+	Cause   apierrors.CauseList `json:"cause"`
+
+	Title  string       `json:"title"`
+	Detail string       `json:"detail"`
+	Code   string       `json:"code"`
+	Errors []fieldError `json:"errors"`
+}
+
+type fieldError struct {
+	Path   string `json:"path"`
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+}
+
+// decodeError turns an error response into an ApiError with the status of
+// the response and the message, code and causes of its body.
+func decodeError(response *rest.Response) apierrors.ApiError {
+	var body errorBody
+	_ = json.Unmarshal(response.Bytes(), &body)
+
+	message := firstNonEmpty(body.Detail, body.Message, body.Title)
+	if message == "" {
+		message = fmt.Sprintf("items api answered with state %d", response.StatusCode)
+	}
+
+	code := firstNonEmpty(body.Code, body.Error, statusCode(response.StatusCode))
+
+	cause := apierrors.CauseList{}
+	cause = append(cause, body.Cause...)
+	for _, field := range body.Errors {
+		cause = append(cause, fmt.Sprintf("%s: %s", field.Path, field.Detail))
+	}
+
+	return apierrors.NewApiError(message, code, response.StatusCode, cause)
+}
+
+// statusCode is the code of an error without body, e.g. not_found.
+func statusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// IsNotFound tells whether err is the API answering 404.
+func IsNotFound(err error) bool {
+	var apiErr apierrors.ApiError
+	return errors.As(err, &apiErr) && apiErr.Status() == http.StatusNotFound
+}
@@ -0,0 +1,699 @@
+package itemsclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const problemContentType = "application/problem+json"
+
+// FakeServer is an in-memory items API for the tests of the services using
+// Client. It answers the same routes, on v1 with the apierrors JSON and on v2
+// with problem details, and requires an Authorization on the seller routes
+// without checking it. Prices, locales and category passwords are ignored.
+//
+//	fake := itemsclient.NewFakeServer()
+//	defer fake.Close()
+//	item := fake.AddItem(models.Item{Name: "Mate"})
+//	client := fake.Client()
+type FakeServer struct {
+	*httptest.Server
+
+	// UserID and ShopID are the seller behind every Authorization.
+	UserID string
+	ShopID string
+
+	mu          sync.Mutex
+	items       map[string]models.Item
+	categories  map[string]models.Category
+	collections map[string]models.Collection
+	failures    []int
+	requests    []FakeRequest
+}
+
+// FakeRequest is a call the fake server got.
+type FakeRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+}
+
+func NewFakeServer() *FakeServer {
+	fake := &FakeServer{
+		UserID:      "fake-user",
+		ShopID:      primitive.NewObjectID().Hex(),
+		items:       map[string]models.Item{},
+		categories:  map[string]models.Category{},
+		collections: map[string]models.Collection{},
+	}
+	fake.Server = httptest.NewServer(http.HandlerFunc(fake.serve))
+
+	return fake
+}
+
+// Client returns a client of the fake server.
+func (f *FakeServer) Client(opts ...Option) *Client {
+	return New(f.URL, opts...)
+}
+
+// AddItem stores item, filling its ID, shop, user and slug when empty.
+func (f *FakeServer) AddItem(item models.Item) models.Item {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if item.ID == "" {
+		item.ID = primitive.NewObjectID().Hex()
+	}
+	if item.ShopID == "" {
+		item.ShopID = f.ShopID
+	}
+	if item.UserID == "" {
+		item.UserID = f.UserID
+	}
+	if item.Slug == "" {
+		item.Slug = fakeSlug(item.Name)
+	}
+	if item.Status == "" {
+		item.Status = models.ItemStatusActive
+	}
+	f.items[item.ID] = item
+
+	return item
+}
+
+// AddCategory stores category, filling its ID and slug when empty.
+func (f *FakeServer) AddCategory(category models.Category) models.Category {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if category.ID == "" {
+		category.ID = primitive.NewObjectID().Hex()
+	}
+	if category.Slug == "" {
+		category.Slug = fakeSlug(category.Name)
+	}
+	f.categories[category.ID] = category
+
+	return category
+}
+
+// AddCollection stores collection, filling its ID, shop and user when empty.
+func (f *FakeServer) AddCollection(collection models.Collection) models.Collection {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if collection.ID == "" {
+		collection.ID = primitive.NewObjectID().Hex()
+	}
+	if collection.ShopID == "" {
+		collection.ShopID = f.ShopID
+	}
+	if collection.UserID == "" {
+		collection.UserID = f.UserID
+	}
+	if collection.ItemIDs == nil {
+		collection.ItemIDs = []string{}
+	}
+	f.collections[collection.ID] = collection
+
+	return collection
+}
+
+// Item returns the stored item, e.g. to check what a call changed.
+func (f *FakeServer) Item(itemID string) (models.Item, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, ok := f.items[itemID]
+	return item, ok
+}
+
+// FailNext answers the next calls with statuses, one per call, before going
+// back to normal.
+func (f *FakeServer) FailNext(statuses ...int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.failures = append(f.failures, statuses...)
+}
+
+// Requests returns the calls got so far.
+func (f *FakeServer) Requests() []FakeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]FakeRequest{}, f.requests...)
+}
+
+// fakeResponse is what a route answers: a status with a body, or an error.
+type fakeResponse struct {
+	status  int
+	body    interface{}
+	message string
+	header  http.Header
+}
+
+func answer(status int, body interface{}) fakeResponse {
+	return fakeResponse{status: status, body: body}
+}
+
+func reject(status int, message string) fakeResponse {
+	return fakeResponse{status: status, message: message}
+}
+
+func (f *FakeServer) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, FakeRequest{Method: r.Method, Path: r.URL.Path, Header: r.Header.Clone()})
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	version := segments[0]
+	if version != "v1" && version != "v2" {
+		version, segments = "v1", append([]string{"v1"}, segments...)
+	}
+
+	var response fakeResponse
+	if len(f.failures) > 0 {
+		response = reject(f.failures[0], "fake failure")
+		f.failures = f.failures[1:]
+	} else {
+		response = f.route(r, version, segments[1:])
+	}
+
+	for name, values := range response.header {
+		w.Header()[name] = values
+	}
+
+	if response.message != "" {
+		writeFakeError(w, version, response.status, response.message)
+		return
+	}
+
+	if response.body == nil {
+		w.WriteHeader(response.status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.status)
+	_ = json.NewEncoder(w).Encode(response.body)
+}
+
+func writeFakeError(w http.ResponseWriter, version string, status int, message string) {
+	code := statusCode(status)
+
+	if version == "v2" {
+		w.Header().Set("Content-Type", problemContentType)
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"type":   "about:blank",
+			"title":  http.StatusText(status),
+			"status": status,
+			"detail": message,
+			"code":   code,
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": message,
+		"error":   code,
+		"status":  status,
+		"cause":   []interface{}{},
+	})
+}
+
+// route answers the path after the version, e.g. items/shop/:id.
+func (f *FakeServer) route(r *http.Request, version string, path []string) fakeResponse {
+	if len(path) == 0 || path[0] != "items" {
+		return reject(http.StatusNotFound, "route not found")
+	}
+	path = path[1:]
+
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		return f.requireSeller(r, f.userItems)
+	case len(path) == 0 && r.Method == http.MethodPost:
+		return f.requireSeller(r, func() fakeResponse { return f.createItem(r) })
+	case len(path) == 1 && path[0] == "list" && r.Method == http.MethodPost:
+		return f.itemsByIDs(r)
+	case len(path) == 1 && path[0] == "search" && r.Method == http.MethodGet:
+		return f.search(r)
+	case len(path) >= 1 && (path[0] == "categories" || path[0] == "category"):
+		return f.routeCategories(r, path)
+	case len(path) >= 1 && path[0] == "collections":
+		return f.routeCollections(r, path[1:])
+	case len(path) >= 2 && path[0] == "shop":
+		return f.routeShop(r, version, path[1], path[2:])
+	case len(path) == 2 && path[0] == "by-slug" && r.Method == http.MethodGet:
+		return f.itemBySlug(path[1])
+	case len(path) == 1 && r.Method == http.MethodGet:
+		return f.item(path[0])
+	case len(path) == 1 && r.Method == http.MethodPut:
+		return f.requireSeller(r, func() fakeResponse { return f.updateItem(r, path[0]) })
+	case len(path) == 1 && r.Method == http.MethodDelete:
+		return f.requireSeller(r, func() fakeResponse { return f.deleteItem(path[0]) })
+	case len(path) == 3 && path[1] == "translations":
+		return f.requireSeller(r, func() fakeResponse { return f.itemTranslation(r, path[0], path[2]) })
+	}
+
+	return reject(http.StatusNotFound, "route not found")
+}
+
+func (f *FakeServer) requireSeller(r *http.Request, next func() fakeResponse) fakeResponse {
+	if r.Header.Get("Authorization") == "" {
+		return reject(http.StatusUnauthorized, "missing Authorization header")
+	}
+	return next()
+}
+
+func (f *FakeServer) item(itemID string) fakeResponse {
+	item, found := f.items[itemID]
+	if !found {
+		return reject(http.StatusNotFound, fmt.Sprintf("item %s not found", itemID))
+	}
+	return answer(http.StatusOK, item)
+}
+
+func (f *FakeServer) itemBySlug(slug string) fakeResponse {
+	for _, item := range f.sortedItems() {
+		if item.Slug == slug {
+			return answer(http.StatusOK, item)
+		}
+	}
+	return reject(http.StatusNotFound, fmt.Sprintf("item with slug %s not found", slug))
+}
+
+func (f *FakeServer) userItems() fakeResponse {
+	return f.itemsWhere(func(item models.Item) bool { return item.UserID == f.UserID })
+}
+
+func (f *FakeServer) itemsWhere(match func(item models.Item) bool) fakeResponse {
+	items := models.Items{Items: []models.Item{}}
+	for _, item := range f.sortedItems() {
+		if match(item) {
+			items.Items = append(items.Items, item)
+		}
+	}
+
+	if len(items.Items) == 0 {
+		return reject(http.StatusNotFound, "items not found")
+	}
+	return answer(http.StatusOK, items)
+}
+
+func (f *FakeServer) itemsByIDs(r *http.Request) fakeResponse {
+	var input models.ItemsIds
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return reject(http.StatusBadRequest, err.Error())
+	}
+
+	items := models.Items{Items: []models.Item{}}
+	for _, id := range input.Items {
+		if item, found := f.items[id]; found {
+			items.Items = append(items.Items, item)
+		}
+	}
+
+	response := answer(http.StatusOK, items)
+	if len(items.Items) != len(input.Items) {
+		response.header = http.Header{"Integrity": []string{"false"}}
+	}
+	return response
+}
+
+func (f *FakeServer) search(r *http.Request) fakeResponse {
+	query := r.URL.Query()
+	text, shopID := strings.ToLower(query.Get("q")), query.Get("shop_id")
+
+	return f.page(r, func(item models.Item) bool {
+		if shopID != "" && item.ShopID != shopID {
+			return false
+		}
+		return text == "" || strings.Contains(strings.ToLower(item.Name), text) || strings.Contains(strings.ToLower(item.Description), text)
+	})
+}
+
+// page answers the page of the items that match, with the limit and offset of
+// the query, like the search and the v2 items of a shop.
+func (f *FakeServer) page(r *http.Request, match func(item models.Item) bool) fakeResponse {
+	query := r.URL.Query()
+
+	limit, offset := models.DefaultItemsPageSize, 0
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > models.MaxItemsPageSize {
+			return reject(http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", models.MaxItemsPageSize))
+		}
+		limit = parsed
+	}
+	if value := query.Get("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return reject(http.StatusBadRequest, "offset must not be negative")
+		}
+		offset = parsed
+	}
+
+	matching := []models.Item{}
+	for _, item := range f.sortedItems() {
+		if match(item) {
+			matching = append(matching, item)
+		}
+	}
+
+	page := dto.ItemsPageDTO{Items: []models.Item{}, Total: int64(len(matching)), Limit: limit, Offset: offset}
+	if offset < len(matching) {
+		end := offset + limit
+		if end > len(matching) {
+			end = len(matching)
+		}
+		page.Items = matching[offset:end]
+	}
+
+	return answer(http.StatusOK, page)
+}
+
+func (f *FakeServer) createItem(r *http.Request) fakeResponse {
+	var input dto.ItemDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return reject(http.StatusBadRequest, err.Error())
+	}
+
+	category, found := f.categories[input.Category.ID]
+	if !found {
+		return reject(http.StatusNotFound, fmt.Sprintf("category %s not found", input.Category.ID))
+	}
+
+	item, err := input.ToItem()
+	if err != nil {
+		return reject(http.StatusBadRequest, err.Error())
+	}
+	item.ID = primitive.NewObjectID().Hex()
+	item.ShopID, item.UserID = f.ShopID, f.UserID
+	item.Category = category
+	item.Slug = fakeSlug(item.Name)
+	if item.Status == "" {
+		item.Status = models.ItemStatusActive
+	}
+	item.Price.ItemID = item.ID
+	f.items[item.ID] = item
+
+	return answer(http.StatusCreated, item.ID)
+}
+
+func (f *FakeServer) updateItem(r *http.Request, itemID string) fakeResponse {
+	current, found := f.items[itemID]
+	if !found || current.UserID != f.UserID {
+		return reject(http.StatusNotFound, fmt.Sprintf("item %s not found", itemID))
+	}
+
+	var input dto.ItemDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return reject(http.StatusBadRequest, err.Error())
+	}
+
+	item, err := input.ToItem()
+	if err != nil {
+		return reject(http.StatusBadRequest, err.Error())
+	}
+	item.ID, item.ShopID, item.UserID, item.Slug = current.ID, current.ShopID, current.UserID, current.Slug
+	if item.Status == "" {
+		item.Status = current.Status
+	}
+	f.items[itemID] = item
+
+	return answer(http.StatusNoContent, nil)
+}
+
+func (f *FakeServer) deleteItem(itemID string) fakeResponse {
+	item, found := f.items[itemID]
+	if !found || item.UserID != f.UserID {
+		return reject(http.StatusNotFound, fmt.Sprintf("item %s not found", itemID))
+	}
+	delete(f.items, itemID)
+
+	return answer(http.StatusNoContent, nil)
+}
+
+func (f *FakeServer) itemTranslation(r *http.Request, itemID string, locale string) fakeResponse {
+	item, found := f.items[itemID]
+	if !found || item.UserID != f.UserID {
+		return reject(http.StatusNotFound, fmt.Sprintf("item %s not found", itemID))
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		var input dto.ItemTranslationDTO
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Name == "" {
+			return reject(http.StatusBadRequest, "translation name must not be empty")
+		}
+		item.NameI18n = setTranslation(item.NameI18n, locale, input.Name)
+		item.DescriptionI18n = setTranslation(item.DescriptionI18n, locale, input.Description)
+	case http.MethodDelete:
+		delete(item.NameI18n, locale)
+		delete(item.DescriptionI18n, locale)
+	default:
+		return reject(http.StatusNotFound, "route not found")
+	}
+	f.items[itemID] = item
+
+	return answer(http.StatusNoContent, nil)
+}
+
+func (f *FakeServer) routeShop(r *http.Request, version string, shopID string, path []string) fakeResponse {
+	if r.Method != http.MethodGet {
+		return reject(http.StatusNotFound, "route not found")
+	}
+
+	switch {
+	case len(path) == 0 && version == "v2":
+		return f.page(r, func(item models.Item) bool { return item.ShopID == shopID })
+	case len(path) == 0:
+		return f.itemsWhere(func(item models.Item) bool { return item.ShopID == shopID })
+	case len(path) == 2 && path[0] == "category":
+		return f.itemsWhere(func(item models.Item) bool { return item.ShopID == shopID && item.Category.ID == path[1] })
+	case len(path) == 1 && path[0] == "categories":
+		return answer(http.StatusOK, dto.CategoriesCountDTO{CategoryCountDTO: f.categoriesCounts(shopID, false)})
+	case len(path) == 1 && path[0] == "collections":
+		collections := []models.Collection{}
+		for _, collection := range f.collections {
+			if collection.ShopID == shopID {
+				collections = append(collections, collection)
+			}
+		}
+		sort.Slice(collections, func(i, j int) bool { return collections[i].Position < collections[j].Position })
+		return answer(http.StatusOK, collections)
+	}
+
+	return reject(http.StatusNotFound, "route not found")
+}
+
+func (f *FakeServer) routeCategories(r *http.Request, path []string) fakeResponse {
+	if path[0] == "categories" {
+		if len(path) != 1 || r.Method != http.MethodGet {
+			return reject(http.StatusNotFound, "route not found")
+		}
+		if withCounts, _ := strconv.ParseBool(r.URL.Query().Get("with_counts")); withCounts {
+			return answer(http.StatusOK, dto.CategoriesCountDTO{CategoryCountDTO: f.categoriesCounts("", true)})
+		}
+		return answer(http.StatusOK, dto.CategoriesDTO{CategoryDTO: f.sortedCategories()})
+	}
+	path = path[1:]
+
+	switch {
+	case len(path) == 0 && r.Method == http.MethodPost:
+		var input models.Category
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Name == "" {
+			return reject(http.StatusBadRequest, "category name must not be nil")
+		}
+		input.ID, input.Slug = primitive.NewObjectID().Hex(), fakeSlug(input.Name)
+		f.categories[input.ID] = input
+		return answer(http.StatusCreated, nil)
+	case len(path) == 0 && r.Method == http.MethodPut:
+		var input models.Category
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Name == "" {
+			return reject(http.StatusBadRequest, "category name must not be nil")
+		}
+		current, found := f.categories[input.ID]
+		if !found {
+			return reject(http.StatusNotFound, fmt.Sprintf("category %s not found", input.ID))
+		}
+		current.Name, current.NameI18n = input.Name, input.NameI18n
+		f.categories[current.ID] = current
+		return answer(http.StatusNoContent, nil)
+	case len(path) == 2 && path[0] == "by-slug" && r.Method == http.MethodGet:
+		for _, category := range f.sortedCategories() {
+			if category.Slug == path[1] {
+				return answer(http.StatusOK, category)
+			}
+		}
+		return reject(http.StatusNotFound, fmt.Sprintf("category with slug %s not found", path[1]))
+	case len(path) == 1 && r.Method == http.MethodGet:
+		category, found := f.categories[path[0]]
+		if !found {
+			return reject(http.StatusNotFound, fmt.Sprintf("category %s not found", path[0]))
+		}
+		return answer(http.StatusOK, category)
+	case len(path) == 1 && r.Method == http.MethodDelete:
+		if _, found := f.categories[path[0]]; !found {
+			return reject(http.StatusNotFound, fmt.Sprintf("category %s not found", path[0]))
+		}
+		delete(f.categories, path[0])
+		return answer(http.StatusNoContent, nil)
+	case len(path) == 3 && path[1] == "translations":
+		category, found := f.categories[path[0]]
+		if !found {
+			return reject(http.StatusNotFound, fmt.Sprintf("category %s not found", path[0]))
+		}
+		if r.Method == http.MethodDelete {
+			delete(category.NameI18n, path[2])
+		} else {
+			var input dto.CategoryTranslationDTO
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Name == "" {
+				return reject(http.StatusBadRequest, "translation name must not be empty")
+			}
+			category.NameI18n = setTranslation(category.NameI18n, path[2], input.Name)
+		}
+		f.categories[category.ID] = category
+		return answer(http.StatusNoContent, nil)
+	}
+
+	return reject(http.StatusNotFound, "route not found")
+}
+
+func (f *FakeServer) routeCollections(r *http.Request, path []string) fakeResponse {
+	if len(path) == 0 {
+		if r.Method != http.MethodPost {
+			return reject(http.StatusNotFound, "route not found")
+		}
+		return f.requireSeller(r, func() fakeResponse {
+			var input dto.CollectionDTO
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Name == "" {
+				return reject(http.StatusBadRequest, "collection name must not be empty")
+			}
+			collection := models.Collection{
+				ID:       primitive.NewObjectID().Hex(),
+				ShopID:   f.ShopID,
+				UserID:   f.UserID,
+				Name:     input.Name,
+				NameI18n: input.NameI18n,
+				Position: input.Position,
+				ItemIDs:  []string{},
+			}
+			f.collections[collection.ID] = collection
+			return answer(http.StatusCreated, collection.ID)
+		})
+	}
+
+	collection, found := f.collections[path[0]]
+	if !found {
+		return reject(http.StatusNotFound, fmt.Sprintf("collection %s not found", path[0]))
+	}
+
+	if len(path) == 1 && r.Method == http.MethodGet {
+		items := []models.Item{}
+		for _, id := range collection.ItemIDs {
+			if item, found := f.items[id]; found {
+				items = append(items, item)
+			}
+		}
+		return answer(http.StatusOK, models.CollectionWithItems{Collection: collection, Items: items})
+	}
+
+	return f.requireSeller(r, func() fakeResponse {
+		if collection.UserID != f.UserID {
+			return reject(http.StatusForbidden, "the collection belongs to another seller")
+		}
+
+		switch {
+		case len(path) == 1 && r.Method == http.MethodPut:
+			var input dto.CollectionDTO
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Name == "" {
+				return reject(http.StatusBadRequest, "collection name must not be empty")
+			}
+			collection.Name, collection.NameI18n, collection.Position = input.Name, input.NameI18n, input.Position
+		case len(path) == 2 && path[1] == "items" && r.Method == http.MethodPut:
+			var input models.ItemsIds
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				return reject(http.StatusBadRequest, err.Error())
+			}
+			collection.ItemIDs = append([]string{}, input.Items...)
+		case len(path) == 1 && r.Method == http.MethodDelete:
+			delete(f.collections, collection.ID)
+			return answer(http.StatusNoContent, nil)
+		default:
+			return reject(http.StatusNotFound, "route not found")
+		}
+
+		f.collections[collection.ID] = collection
+		return answer(http.StatusNoContent, nil)
+	})
+}
+
+// categoriesCounts counts the items of the shop in each category, or of every
+// shop when shopID is empty. all keeps the categories without items.
+func (f *FakeServer) categoriesCounts(shopID string, all bool) []models.CategoryCount {
+	counts := map[string]int64{}
+	for _, item := range f.items {
+		if shopID == "" || item.ShopID == shopID {
+			counts[item.Category.ID]++
+		}
+	}
+
+	result := []models.CategoryCount{}
+	for _, category := range f.sortedCategories() {
+		if all || counts[category.ID] > 0 {
+			result = append(result, models.CategoryCount{Category: category, ItemsCount: counts[category.ID]})
+		}
+	}
+	return result
+}
+
+// sortedItems returns the items in ID order, the order of the API searches.
+func (f *FakeServer) sortedItems() []models.Item {
+	items := make([]models.Item, 0, len(f.items))
+	for _, item := range f.items {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items
+}
+
+func (f *FakeServer) sortedCategories() []models.Category {
+	categories := make([]models.Category, 0, len(f.categories))
+	for _, category := range f.categories {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Name < categories[j].Name })
+	return categories
+}
+
+func setTranslation(translations models.Translations, locale string, value string) models.Translations {
+	if translations == nil {
+		translations = models.Translations{}
+	}
+	if value == "" {
+		delete(translations, locale)
+	} else {
+		translations[locale] = value
+	}
+	return translations
+}
+
+func fakeSlug(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), "-")
+}
@@ -0,0 +1,225 @@
+package itemsclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/jopitnow/go-jopit-toolkit/rest"
+)
+
+const ItemsBaseEndpoint = "/items"
+
+// SearchQuery filters and pages SearchItems. Zero values don't filter and
+// leave the paging to the API defaults.
+type SearchQuery struct {
+	Text   string
+	ShopID string
+	Limit  int
+	Offset int
+}
+
+func (q SearchQuery) values() url.Values {
+	values := url.Values{}
+	if q.Text != "" {
+		values.Set("q", q.Text)
+	}
+	if q.ShopID != "" {
+		values.Set("shop_id", q.ShopID)
+	}
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.Offset > 0 {
+		values.Set("offset", strconv.Itoa(q.Offset))
+	}
+	return values
+}
+
+func (c *Client) GetItem(ctx context.Context, itemID string) (models.Item, apierrors.ApiError) {
+	var item models.Item
+
+	endpoint := fmt.Sprintf("%s/%s", ItemsBaseEndpoint, url.PathEscape(itemID))
+	apiErr := c.get(ctx, endpoint, &item)
+
+	return item, apiErr
+}
+
+// GetItemBySlug follows the redirect of the slugs an item had before.
+func (c *Client) GetItemBySlug(ctx context.Context, slug string) (models.Item, apierrors.ApiError) {
+	var item models.Item
+
+	endpoint := fmt.Sprintf("%s/by-slug/%s", ItemsBaseEndpoint, url.PathEscape(slug))
+	apiErr := c.get(ctx, endpoint, &item)
+
+	return item, apiErr
+}
+
+// GetUserItems returns the items of the user the Authorization belongs to.
+func (c *Client) GetUserItems(ctx context.Context) (models.Items, apierrors.ApiError) {
+	var items models.Items
+
+	apiErr := c.get(ctx, ItemsBaseEndpoint, &items)
+
+	return items, apiErr
+}
+
+// GetShopItems returns every item of the shop. v2 answers a page at a time,
+// so there it fetches the pages until the items run out.
+func (c *Client) GetShopItems(ctx context.Context, shopID string) (models.Items, apierrors.ApiError) {
+	var items models.Items
+
+	endpoint := fmt.Sprintf("%s/shop/%s", ItemsBaseEndpoint, url.PathEscape(shopID))
+	if c.version == DefaultVersion {
+		apiErr := c.get(ctx, endpoint, &items)
+		return items, apiErr
+	}
+
+	items.Items = []models.Item{}
+	for {
+		var page dto.ItemsPageDTO
+
+		values := url.Values{"limit": {strconv.Itoa(models.MaxItemsPageSize)}, "offset": {strconv.Itoa(len(items.Items))}}
+		if apiErr := c.get(ctx, endpoint+"?"+values.Encode(), &page); apiErr != nil {
+			return models.Items{}, apiErr
+		}
+
+		items.Items = append(items.Items, page.Items...)
+		if len(page.Items) == 0 || int64(len(items.Items)) >= page.Total {
+			return items, nil
+		}
+	}
+}
+
+func (c *Client) GetShopCategoryItems(ctx context.Context, shopID string, categoryID string) (models.Items, apierrors.ApiError) {
+	var items models.Items
+
+	endpoint := fmt.Sprintf("%s/shop/%s/category/%s", ItemsBaseEndpoint, url.PathEscape(shopID), url.PathEscape(categoryID))
+	apiErr := c.get(ctx, endpoint, &items)
+
+	return items, apiErr
+}
+
+// GetItemsByIDs returns the items that exist among itemsIDs, the missing
+// ones are left out.
+func (c *Client) GetItemsByIDs(ctx context.Context, itemsIDs []string) (models.Items, apierrors.ApiError) {
+	var items models.Items
+
+	response, apiErr := c.do(ctx, call{
+		method:    http.MethodPost,
+		endpoint:  ItemsBaseEndpoint + "/list",
+		body:      models.ItemsIds{Items: itemsIDs},
+		retryable: true,
+		expected:  []int{http.StatusOK},
+	})
+	if apiErr != nil {
+		return models.Items{}, apiErr
+	}
+
+	return items, unmarshal(response, &items)
+}
+
+// SearchItems returns one page of the items matching query. Use
+// SearchAllItems to go through every page.
+func (c *Client) SearchItems(ctx context.Context, query SearchQuery) (dto.ItemsPageDTO, apierrors.ApiError) {
+	var page dto.ItemsPageDTO
+
+	endpoint := ItemsBaseEndpoint + "/search"
+	if values := query.values(); len(values) > 0 {
+		endpoint += "?" + values.Encode()
+	}
+
+	apiErr := c.get(ctx, endpoint, &page)
+
+	return page, apiErr
+}
+
+// CreateItem creates the item in the shop of the authenticated seller and
+// returns its ID.
+func (c *Client) CreateItem(ctx context.Context, item dto.ItemDTO) (string, apierrors.ApiError) {
+	return c.create(ctx, ItemsBaseEndpoint, item)
+}
+
+func (c *Client) UpdateItem(ctx context.Context, itemID string, item dto.ItemDTO) apierrors.ApiError {
+	endpoint := fmt.Sprintf("%s/%s", ItemsBaseEndpoint, url.PathEscape(itemID))
+	return c.write(ctx, http.MethodPut, endpoint, item)
+}
+
+func (c *Client) DeleteItem(ctx context.Context, itemID string) apierrors.ApiError {
+	endpoint := fmt.Sprintf("%s/%s", ItemsBaseEndpoint, url.PathEscape(itemID))
+	return c.write(ctx, http.MethodDelete, endpoint, nil)
+}
+
+func (c *Client) SetItemTranslation(ctx context.Context, itemID string, locale string, translation dto.ItemTranslationDTO) apierrors.ApiError {
+	endpoint := fmt.Sprintf("%s/%s/translations/%s", ItemsBaseEndpoint, url.PathEscape(itemID), url.PathEscape(locale))
+	return c.write(ctx, http.MethodPut, endpoint, translation)
+}
+
+func (c *Client) DeleteItemTranslation(ctx context.Context, itemID string, locale string) apierrors.ApiError {
+	endpoint := fmt.Sprintf("%s/%s/translations/%s", ItemsBaseEndpoint, url.PathEscape(itemID), url.PathEscape(locale))
+	return c.write(ctx, http.MethodDelete, endpoint, nil)
+}
+
+// get reads endpoint into out.
+func (c *Client) get(ctx context.Context, endpoint string, out interface{}) apierrors.ApiError {
+	response, apiErr := c.do(ctx, call{
+		method:    http.MethodGet,
+		endpoint:  endpoint,
+		retryable: true,
+		expected:  []int{http.StatusOK},
+	})
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return unmarshal(response, out)
+}
+
+// create posts body with an Idempotency-Key, so it is retried like the
+// other calls, and returns the ID the API answers with.
+func (c *Client) create(ctx context.Context, endpoint string, body interface{}) (string, apierrors.ApiError) {
+	var id string
+
+	response, apiErr := c.do(ctx, call{
+		method:    http.MethodPost,
+		endpoint:  endpoint,
+		body:      body,
+		retryable: true,
+		expected:  []int{http.StatusCreated},
+	})
+	if apiErr != nil {
+		return "", apiErr
+	}
+
+	if len(response.Bytes()) == 0 {
+		return "", nil
+	}
+
+	return id, unmarshal(response, &id)
+}
+
+// write sends an update or a delete, answered with no content.
+func (c *Client) write(ctx context.Context, method string, endpoint string, body interface{}) apierrors.ApiError {
+	_, apiErr := c.do(ctx, call{
+		method:    method,
+		endpoint:  endpoint,
+		body:      body,
+		retryable: true,
+		expected:  []int{http.StatusOK, http.StatusNoContent},
+	})
+
+	return apiErr
+}
+
+func unmarshal(response *rest.Response, out interface{}) apierrors.ApiError {
+	if err := json.Unmarshal(response.Bytes(), out); err != nil {
+		return apierrors.NewInternalServerApiError("unexpected error unmarshalling items api json response. value: "+string(response.Bytes()), err)
+	}
+	return nil
+}
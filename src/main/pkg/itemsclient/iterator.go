@@ -0,0 +1,74 @@
+package itemsclient
+
+import (
+	"context"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+// ItemsIterator goes through every item matching a search, fetching the
+// pages as they are needed:
+//
+//	it := client.SearchAllItems(query)
+//	for it.Next(ctx) {
+//		items = append(items, it.Item())
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type ItemsIterator struct {
+	client *Client
+	query  SearchQuery
+
+	page []models.Item
+	next int
+	item models.Item
+	done bool
+	err  apierrors.ApiError
+}
+
+// SearchAllItems iterates the items matching query from query.Offset on,
+// query.Limit items per page.
+func (c *Client) SearchAllItems(query SearchQuery) *ItemsIterator {
+	return &ItemsIterator{client: c, query: query}
+}
+
+// Next moves to the next item. It returns false once the items run out or a
+// page fails, see Err.
+func (it *ItemsIterator) Next(ctx context.Context) bool {
+	if it.next >= len(it.page) {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		page, apiErr := it.client.SearchItems(ctx, it.query)
+		if apiErr != nil {
+			it.err = apiErr
+			return false
+		}
+
+		it.page, it.next = page.Items, 0
+		it.query.Offset += len(page.Items)
+		it.done = len(page.Items) == 0 || int64(it.query.Offset) >= page.Total
+
+		if len(it.page) == 0 {
+			return false
+		}
+	}
+
+	it.item = it.page[it.next]
+	it.next++
+
+	return true
+}
+
+// Item is the item Next moved to.
+func (it *ItemsIterator) Item() models.Item {
+	return it.item
+}
+
+// Err is the error that stopped the iteration, if any.
+func (it *ItemsIterator) Err() apierrors.ApiError {
+	return it.err
+}
@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "items_api"
+
+// Registry holds every metric of the API. It is not the global prometheus one
+// so the toolkit or the tests can't register clashing collectors in it.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	HTTPRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route name, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	HTTPDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route name and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	MongoDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mongo_operation_duration_seconds",
+		Help:      "Mongo latency by repository and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"repository", "operation"})
+
+	ClientRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "client_requests_total",
+		Help:      "Calls to the downstream APIs by client, operation and status code. Transport errors use status \"error\".",
+	}, []string{"client", "operation", "status"})
+
+	ClientDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "client_request_duration_seconds",
+		Help:      "Latency of the downstream APIs by client and operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"client", "operation"})
+
+	ItemsCreated = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "items_created_total",
+		Help:      "Items stored by CreateItem.",
+	})
+
+	ItemsDeleted = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "items_deleted_total",
+		Help:      "Items removed by Delete.",
+	})
+
+	PriceSyncFailures = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "price_sync_failures_total",
+		Help:      "Item writes whose price could not be created, updated or deleted in the prices API.",
+	}, []string{"operation"})
+
+	DeprecatedRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "deprecated_requests_total",
+		Help:      "Requests to deprecated routes by route path, to know when they can be removed.",
+	}, []string{"route"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves the registry in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest records a served request under the name given to LoggerHandler.
+func ObserveRequest(route string, method string, status int, start time.Time) {
+	HTTPRequests.WithLabelValues(route, method, strconv.Itoa(status)).Inc()
+	HTTPDuration.WithLabelValues(route, method).Observe(time.Since(start).Seconds())
+}
+
+// ObserveMongo records the latency of a repository method. Meant to be
+// deferred as the first statement of the method.
+func ObserveMongo(repository string, operation string, start time.Time) {
+	MongoDuration.WithLabelValues(repository, operation).Observe(time.Since(start).Seconds())
+}
+
+// ObserveClient records a call to a downstream API. A zero status means the
+// request got no response.
+func ObserveClient(client string, operation string, status int, start time.Time) {
+	label := "error"
+	if status != 0 {
+		label = strconv.Itoa(status)
+	}
+
+	ClientRequests.WithLabelValues(client, operation, label).Inc()
+	ClientDuration.WithLabelValues(client, operation).Observe(time.Since(start).Seconds())
+}
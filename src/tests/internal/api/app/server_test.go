@@ -0,0 +1,102 @@
+package app
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type workerMock struct {
+	stopped chan struct{}
+}
+
+func (w workerMock) Run(ctx context.Context) {
+	<-ctx.Done()
+	close(w.stopped)
+}
+
+// slowHandler answers once release is closed, signalling when a request is in.
+func slowHandler(started chan struct{}, release chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func listen(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	return listener
+}
+
+func TestServe_Drains_InFlight_Requests(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	listener := listen(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	worker := workerMock{stopped: make(chan struct{})}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- app.Serve(ctx, listener, app.NewServer(slowHandler(started, release)), time.Second, worker)
+	}()
+
+	responses := make(chan int, 1)
+	go func() {
+		res, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			responses <- 0
+			return
+		}
+		res.Body.Close()
+		responses <- res.StatusCode
+	}()
+
+	<-started
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	assert.Equal(t, http.StatusOK, <-responses)
+	assert.Nil(t, <-served)
+	<-worker.stopped
+}
+
+func TestServe_Shutdown_Deadline_Exceeded(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	listener := listen(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	served := make(chan error, 1)
+	go func() {
+		served <- app.Serve(ctx, listener, app.NewServer(slowHandler(started, release)), 50*time.Millisecond)
+	}()
+
+	go http.Get("http://" + listener.Addr().String())
+
+	<-started
+	cancel()
+
+	err := <-served
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error draining requests")
+}
+
+func TestServe_Returns_Serve_Error_And_Stops_Workers(t *testing.T) {
+	listener := listen(t)
+	listener.Close()
+	worker := workerMock{stopped: make(chan struct{})}
+
+	err := app.Serve(context.Background(), listener, app.NewServer(http.NotFoundHandler()), time.Second, worker)
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error serving")
+	<-worker.stopped
+}
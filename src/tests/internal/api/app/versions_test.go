@@ -0,0 +1,167 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/app"
+	"github.com/agustinrabini/items-api-project/src/main/api/handlers"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/problems"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/ratelimit"
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/repositories/idempotency"
+	"github.com/gin-gonic/gin"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func answer(body string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.String(http.StatusOK, body)
+	}
+}
+
+func notFound(c *gin.Context) {
+	problems.Respond(c, catalog.NotFound.New("item not found"))
+}
+
+func versionedRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	v1 := app.Routes{
+		app.NewRoute(http.MethodGet, "/things/:id", "GetThing", answer("v1")),
+		app.NewRoute(http.MethodGet, "/missing", "GetMissing", notFound),
+	}
+	v2 := v1.Override(app.NewRoute(http.MethodGet, "/things/:id", "GetThing", answer("v2")))
+
+	app.MountVersions(router, v1, v2)
+
+	return router
+}
+
+func request(router *gin.Engine, url string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, url, nil))
+	return response
+}
+
+func TestRoutes_Override_And_Without(t *testing.T) {
+	v1 := app.Routes{
+		app.NewRoute(http.MethodGet, "/things", "GetThings"),
+		app.NewRoute(http.MethodPost, "/things", "CreateThing"),
+	}
+
+	v2 := v1.Override(
+		app.NewRoute(http.MethodGet, "/things", "ListThings"),
+		app.NewRoute(http.MethodGet, "/things/:id", "GetThing"),
+	).Without(http.MethodPost, "/things")
+
+	assert.Equal(t, []string{"GetThings", "CreateThing"}, []string{v1[0].Name, v1[1].Name})
+	assert.Len(t, v2, 2)
+	assert.Equal(t, "ListThings", v2[0].Name)
+	assert.Equal(t, "GetThing", v2[1].Name)
+}
+
+func TestMountVersions_Shares_And_Overrides_Handlers(t *testing.T) {
+	router := versionedRouter()
+
+	assert.Equal(t, "v1", request(router, "/v1/things/1").Body.String())
+	assert.Equal(t, "v2", request(router, "/v2/things/1").Body.String())
+	assert.Equal(t, "v1", request(router, "/things/1").Body.String())
+}
+
+func TestMountVersions_Bare_Routes_Are_Deprecated(t *testing.T) {
+	router := versionedRouter()
+
+	legacy := request(router, "/things/1")
+	current := request(router, "/v1/things/1")
+
+	assert.Equal(t, "@1793491200", legacy.Header().Get("Deprecation"))
+	assert.Equal(t, "Sat, 01 May 2027 00:00:00 GMT", legacy.Header().Get("Sunset"))
+	assert.Equal(t, `</v1/things/1>; rel="successor-version"`, legacy.Header().Get("Link"))
+	assert.Empty(t, current.Header().Get("Deprecation"))
+	assert.Empty(t, current.Header().Get("Sunset"))
+}
+
+func TestMountVersions_V2_Errors_Are_Problem_Details(t *testing.T) {
+	router := versionedRouter()
+
+	v1 := request(router, "/v1/missing")
+	v2 := request(router, "/v2/missing")
+
+	var problem problems.Problem
+	assert.Nil(t, json.Unmarshal(v2.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusNotFound, v2.Code)
+	assert.Equal(t, problems.MIMEProblemJSON, v2.Header().Get("Content-Type"))
+	assert.Equal(t, "not_found", problem.Code)
+	assert.Equal(t, "/v2/missing", problem.Instance)
+
+	assert.Equal(t, http.StatusNotFound, v1.Code)
+	assert.Contains(t, v1.Header().Get("Content-Type"), "application/json")
+	assert.Contains(t, v1.Body.String(), `"error":"not_found"`)
+}
+
+func TestMountVersions_Versions_Share_Rate_Limit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	limit := handlers.RateLimitHandler(ratelimit.NewMemoryStore(), ratelimit.Limit{Rate: 0.001, Burst: 1})
+	v1 := app.Routes{app.NewRoute(http.MethodGet, "/things/:id", "GetThing", limit, answer("v1"))}
+	app.MountVersions(router, v1, v1)
+
+	first := request(router, "/v1/things/1")
+	bare := request(router, "/things/1")
+	v2 := request(router, "/v2/things/1")
+
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, http.StatusTooManyRequests, bare.Code)
+	assert.Equal(t, http.StatusTooManyRequests, v2.Code)
+}
+
+func TestMountVersions_Versions_Share_Idempotency_Keys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	records := map[string]models.IdempotencyRecord{}
+	repository := idempotency.NewRepositoryMock()
+	repository.HandleReserve = func(ctx context.Context, record models.IdempotencyRecord) (models.IdempotencyRecord, bool, apierrors.ApiError) {
+		if stored, ok := records[record.Key]; ok {
+			return stored, false, nil
+		}
+		records[record.Key] = record
+		return record, true, nil
+	}
+	repository.HandleComplete = func(ctx context.Context, key string, status int, contentType string, body []byte) apierrors.ApiError {
+		record := records[key]
+		record.Completed, record.Status, record.ContentType, record.Body = true, status, contentType, body
+		records[key] = record
+		return nil
+	}
+
+	created := 0
+	create := func(c *gin.Context) {
+		created++
+		c.String(http.StatusCreated, "created")
+	}
+	v1 := app.Routes{app.NewRoute(http.MethodPost, "/things", "CreateThing", handlers.IdempotencyHandler(repository), create)}
+	app.MountVersions(router, v1, v1)
+
+	for _, url := range []string{"/v1/things", "/things", "/v2/things"} {
+		request := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"name":"a"}`))
+		request.Header.Set(handlers.IdempotencyKeyHeader, "key-1")
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+	}
+
+	assert.Equal(t, 1, created)
+	assert.Len(t, records, 1)
+}
@@ -8,6 +8,11 @@ import (
 type DependencyMock interface {
 	ItemsRepository() repositories.ItemsRepository
 	CategoriesRepository() repositories.CategoriesRepository
+	CollectionsRepository() repositories.CollectionsRepository
+	IdempotencyRepository() repositories.IdempotencyRepository
+	ReconciliationRepository() repositories.ReconciliationRepository
+	RevisionsRepository() repositories.RevisionsRepository
+	AuditRepository() repositories.AuditRepository
 }
 
 func GetDependencyManagerMock(server *memongo.Server) DependencyMock {
@@ -19,14 +24,29 @@ func BuildMockDependencies(server *memongo.Server) (Dependencies, error) {
 
 	itemsRepository := manager.ItemsRepository()
 	categoriesRepository := manager.CategoriesRepository()
+	collectionsRepository := manager.CollectionsRepository()
+	idempotencyRepository := manager.IdempotencyRepository()
+	reconciliationRepository := manager.ReconciliationRepository()
+	revisionsRepository := manager.RevisionsRepository()
+	auditRepository := manager.AuditRepository()
 
 	return Dependencies{
-		ItemsRepository:      itemsRepository,
-		CategoriesRepository: categoriesRepository,
+		ItemsRepository:          itemsRepository,
+		CategoriesRepository:     categoriesRepository,
+		CollectionsRepository:    collectionsRepository,
+		IdempotencyRepository:    idempotencyRepository,
+		ReconciliationRepository: reconciliationRepository,
+		RevisionsRepository:      revisionsRepository,
+		AuditRepository:          auditRepository,
 	}, nil
 }
 
 type Dependencies struct {
-	ItemsRepository      repositories.ItemsRepository
-	CategoriesRepository repositories.CategoriesRepository
+	ItemsRepository          repositories.ItemsRepository
+	CategoriesRepository     repositories.CategoriesRepository
+	CollectionsRepository    repositories.CollectionsRepository
+	IdempotencyRepository    repositories.IdempotencyRepository
+	ReconciliationRepository repositories.ReconciliationRepository
+	RevisionsRepository      repositories.RevisionsRepository
+	AuditRepository          repositories.AuditRepository
 }
@@ -30,3 +30,23 @@ func (m DependencyManagerMock) ItemsRepository() repositories.ItemsRepository {
 func (m DependencyManagerMock) CategoriesRepository() repositories.CategoriesRepository {
 	return repositories.NewCategoriesRepository(m.NewCollection(dependencies.KvsCategoriesCollection))
 }
+
+func (m DependencyManagerMock) CollectionsRepository() repositories.CollectionsRepository {
+	return repositories.NewCollectionsRepository(m.NewCollection(dependencies.KvsCollectionsCollection))
+}
+
+func (m DependencyManagerMock) IdempotencyRepository() repositories.IdempotencyRepository {
+	return repositories.NewIdempotencyRepository(m.NewCollection(dependencies.KvsIdempotencyCollection))
+}
+
+func (m DependencyManagerMock) ReconciliationRepository() repositories.ReconciliationRepository {
+	return repositories.NewReconciliationRepository(m.NewCollection(dependencies.KvsReconciliationReports), m.NewCollection(dependencies.KvsLocksCollection))
+}
+
+func (m DependencyManagerMock) RevisionsRepository() repositories.RevisionsRepository {
+	return repositories.NewRevisionsRepository(m.NewCollection(dependencies.KvsRevisionsCollection))
+}
+
+func (m DependencyManagerMock) AuditRepository() repositories.AuditRepository {
+	return repositories.NewAuditRepository(m.NewCollection(dependencies.KvsAuditCollection))
+}
@@ -0,0 +1,423 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/graph"
+	"github.com/agustinrabini/items-api-project/src/main/api/handlers"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/ratelimit"
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/clients"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/repositories/audit"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/repositories/idempotency"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/repositories/items"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/services/categories"
+	itemsServices "github.com/agustinrabini/items-api-project/src/tests/internal/domain/services/items"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/setup"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	itemOne   = "65a1f0c2e4b0a1b2c3d4e5f1"
+	itemTwo   = "65a1f0c2e4b0a1b2c3d4e5f2"
+	itemThree = "65a1f0c2e4b0a1b2c3d4e5f3"
+)
+
+type fixture struct {
+	repository items.RepositoryMock
+	prices     clients.PriceClientMock
+	service    itemsServices.ServiceMock
+	categories categories.ServiceMock
+	audit      repositories.AuditRepository
+	limits     graph.Limits
+}
+
+func newFixture() *fixture {
+	return &fixture{
+		repository: items.NewItemsRepositoryMock(),
+		prices:     clients.NewPriceClientMock(),
+		service:    itemsServices.NewItemsServiceMock(),
+		categories: categories.NewServiceMock(),
+		limits:     graph.Limits{MaxDepth: 5, MaxComplexity: 1000, BatchWait: 20 * time.Millisecond},
+	}
+}
+
+// router authenticates the requests with an Authorization header as the user
+// it carries.
+func (f *fixture) router() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	auth := func(c *gin.Context) {
+		c.Set("user_id", c.GetHeader("Authorization"))
+	}
+	handler := graph.NewHandler(f.repository, f.prices, f.service, f.categories, f.audit, f.limits)
+	router.POST("/graphql", handlers.OptionalAuthHandler(auth), handler.Serve)
+
+	return router
+}
+
+type response struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func (f *fixture) execute(t *testing.T, query string, headers map[string]string) response {
+	body, _ := json.Marshal(map[string]string{"query": query})
+
+	all := map[string]string{"Content-Type": "application/json"}
+	for key, value := range headers {
+		all[key] = value
+	}
+
+	recorder := setup.ExecuteRequest(f.router(), http.MethodPost, "/graphql", all, string(body))
+
+	var result response
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+	return result
+}
+
+// storeItems serves the given items from GetByIDs and a price for each of
+// them, counting the calls.
+func (f *fixture) storeItems(stored ...models.Item) (itemsCalls *int, pricesCalls *int) {
+	var mu sync.Mutex
+	itemsCalls, pricesCalls = new(int), new(int)
+
+	f.repository.HandleGetByIDs = func(ctx context.Context, ids []string) (models.Items, apierrors.ApiError) {
+		mu.Lock()
+		defer mu.Unlock()
+		*itemsCalls++
+
+		var found []models.Item
+		for _, id := range ids {
+			for _, item := range stored {
+				if item.ID == id {
+					found = append(found, item)
+				}
+			}
+		}
+		if len(found) == 0 {
+			return models.Items{}, catalog.NotFound.New("items not found")
+		}
+		return models.Items{Items: found}, nil
+	}
+
+	f.prices.HandleGetItemsPrices = func(ctx context.Context, ids []string) (models.Prices, apierrors.ApiError) {
+		mu.Lock()
+		defer mu.Unlock()
+		*pricesCalls++
+
+		var prices []models.Price
+		for _, id := range ids {
+			prices = append(prices, models.Price{ItemID: id, Amount: 10})
+		}
+		return models.Prices{Prices: prices}, nil
+	}
+
+	return itemsCalls, pricesCalls
+}
+
+func TestGraphQL_Batches_Items_And_Prices(t *testing.T) {
+	f := newFixture()
+	itemsCalls, pricesCalls := f.storeItems(
+		models.Item{ID: itemOne, Name: "One"},
+		models.Item{ID: itemTwo, Name: "Two"},
+	)
+
+	result := f.execute(t, `{
+		first: item(id: "`+itemOne+`") { name price { amount } }
+		second: item(id: "`+itemTwo+`") { name price { amount } }
+		items(ids: ["`+itemOne+`", "`+itemThree+`"]) { id price { amount } }
+	}`, nil)
+
+	assert.Empty(t, result.Errors)
+	assert.JSONEq(t, `{"name": "One", "price": {"amount": 10}}`, string(result.Data["first"]))
+	assert.JSONEq(t, `{"name": "Two", "price": {"amount": 10}}`, string(result.Data["second"]))
+	assert.JSONEq(t, `[{"id": "`+itemOne+`", "price": {"amount": 10}}, null]`, string(result.Data["items"]))
+	assert.Equal(t, 1, *itemsCalls)
+	assert.Equal(t, 1, *pricesCalls)
+}
+
+func TestGraphQL_Item_Not_Found_Is_Null(t *testing.T) {
+	f := newFixture()
+	f.storeItems()
+
+	result := f.execute(t, `{ item(id: "`+itemOne+`") { name } }`, nil)
+
+	assert.Empty(t, result.Errors)
+	assert.JSONEq(t, `null`, string(result.Data["item"]))
+}
+
+func TestGraphQL_Item_Invalid_ID_Error(t *testing.T) {
+	result := newFixture().execute(t, `{ item(id: "nope") { name } }`, nil)
+
+	assert.Len(t, result.Errors, 1)
+	assert.Equal(t, catalog.InvalidID.Code, result.Errors[0].Extensions["code"])
+	assert.EqualValues(t, http.StatusBadRequest, result.Errors[0].Extensions["status"])
+}
+
+func TestGraphQL_ShopItems_Pages_And_Primes_Items(t *testing.T) {
+	f := newFixture()
+	itemsCalls, pricesCalls := f.storeItems()
+
+	var query models.ItemsQuery
+	f.repository.HandleSearch = func(ctx context.Context, q models.ItemsQuery) (models.ItemsPage, apierrors.ApiError) {
+		query = q
+		return models.ItemsPage{
+			Items: []models.Item{{ID: itemOne, Name: "One"}, {ID: itemTwo, Name: "Two"}},
+			Total: 5,
+		}, nil
+	}
+
+	result := f.execute(t, `{
+		shopItems(shopId: "shop", limit: 2, offset: 2) { total hasMore items { name price { amount } } }
+		item(id: "`+itemOne+`") { name }
+	}`, nil)
+
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, models.ItemsQuery{ShopID: "shop", Limit: 2, Offset: 2}, query)
+	assert.JSONEq(t, `{"total": 5, "hasMore": true, "items": [
+		{"name": "One", "price": {"amount": 10}},
+		{"name": "Two", "price": {"amount": 10}}
+	]}`, string(result.Data["shopItems"]))
+	assert.Equal(t, 1, *pricesCalls)
+	assert.LessOrEqual(t, *itemsCalls, 1)
+}
+
+func TestGraphQL_Search_Limit_Error(t *testing.T) {
+	result := newFixture().execute(t, `{ search(query: "remera", limit: 500) { total } }`, nil)
+
+	assert.Len(t, result.Errors, 1)
+	assert.Equal(t, catalog.BadRequest.Code, result.Errors[0].Extensions["code"])
+}
+
+func TestGraphQL_Categories(t *testing.T) {
+	f := newFixture()
+	f.categories.HandleGetAllCategories = func(ctx context.Context) ([]models.Category, apierrors.ApiError) {
+		return []models.Category{{ID: "category", Name: "Clothes", Slug: "clothes"}}, nil
+	}
+
+	result := f.execute(t, `{ categories { id name slug } }`, nil)
+
+	assert.Empty(t, result.Errors)
+	assert.JSONEq(t, `[{"id": "category", "name": "Clothes", "slug": "clothes"}]`, string(result.Data["categories"]))
+}
+
+func TestGraphQL_Depth_Limit_Error(t *testing.T) {
+	f := newFixture()
+	f.limits.MaxDepth = 2
+
+	result := f.execute(t, `{ shopItems(shopId: "shop") { items { price { currency { symbol } } } } }`, nil)
+
+	assert.Len(t, result.Errors, 1)
+	assert.Equal(t, "DEPTH_LIMIT_EXCEEDED", result.Errors[0].Extensions["code"])
+	assert.Nil(t, result.Data)
+}
+
+func TestGraphQL_Complexity_Limit_Error(t *testing.T) {
+	f := newFixture()
+	f.limits.MaxComplexity = 100
+
+	result := f.execute(t, `{ shopItems(shopId: "shop", limit: 100) { items { id name } } }`, nil)
+
+	assert.Len(t, result.Errors, 1)
+	assert.Equal(t, "COMPLEXITY_LIMIT_EXCEEDED", result.Errors[0].Extensions["code"])
+}
+
+func TestGraphQL_Mutation_Without_Token_Error(t *testing.T) {
+	f := newFixture()
+	f.service.HandleDelete = func(ctx context.Context, itemID string) apierrors.ApiError {
+		t.Fatal("the item must not be deleted")
+		return nil
+	}
+
+	result := f.execute(t, `mutation { deleteItem(id: "`+itemOne+`") }`, nil)
+
+	assert.Len(t, result.Errors, 1)
+	assert.Equal(t, catalog.Unauthorized.Code, result.Errors[0].Extensions["code"])
+}
+
+func TestGraphQL_Mutation_With_Token(t *testing.T) {
+	f := newFixture()
+
+	var deleted string
+	f.service.HandleDelete = func(ctx context.Context, itemID string) apierrors.ApiError {
+		deleted = itemID
+		return nil
+	}
+
+	result := f.execute(t, `mutation { deleteItem(id: "`+itemOne+`") }`, map[string]string{"Authorization": "01-USER-TEST"})
+
+	assert.Empty(t, result.Errors)
+	assert.JSONEq(t, `true`, string(result.Data["deleteItem"]))
+	assert.Equal(t, itemOne, deleted)
+}
+
+// auditEntries records the audit entries the mutations save.
+func (f *fixture) auditEntries() *[]models.AuditEntry {
+	entries := &[]models.AuditEntry{}
+	mock := audit.NewRepositoryMock()
+	mock.HandleSave = func(ctx context.Context, entry models.AuditEntry) apierrors.ApiError {
+		*entries = append(*entries, entry)
+		return nil
+	}
+	f.audit = mock
+
+	return entries
+}
+
+func TestGraphQL_Mutation_Audit(t *testing.T) {
+	f := newFixture()
+	entries := f.auditEntries()
+
+	stored := map[string]models.Item{itemOne: {ID: itemOne, Name: "Boots"}}
+	f.repository.HandleGet = func(ctx context.Context, itemID string) (models.Item, apierrors.ApiError) {
+		item, ok := stored[itemID]
+		if !ok {
+			return models.Item{}, apierrors.NewNotFoundApiError("item not found")
+		}
+		return item, nil
+	}
+	f.service.HandleDelete = func(ctx context.Context, itemID string) apierrors.ApiError {
+		delete(stored, itemID)
+		return nil
+	}
+
+	result := f.execute(t, `mutation { deleteItem(id: "`+itemOne+`") }`, map[string]string{"Authorization": "01-USER-TEST"})
+
+	assert.Empty(t, result.Errors)
+	assert.Len(t, *entries, 1)
+	entry := (*entries)[0]
+	assert.Equal(t, "01-USER-TEST", entry.Actor)
+	assert.Equal(t, "item.delete", entry.Action)
+	assert.Equal(t, "item", entry.TargetType)
+	assert.Equal(t, itemOne, entry.TargetID)
+	assert.Equal(t, http.MethodPost, entry.Method)
+	assert.Equal(t, "/graphql", entry.Path)
+	assert.Equal(t, http.StatusOK, entry.Status)
+	assert.Equal(t, "Boots", entry.Before["name"])
+	assert.Nil(t, entry.After)
+}
+
+func TestGraphQL_Mutation_Audit_Failed(t *testing.T) {
+	f := newFixture()
+	entries := f.auditEntries()
+	f.service.HandleDelete = func(ctx context.Context, itemID string) apierrors.ApiError {
+		return catalog.Forbidden.New("not your item")
+	}
+
+	f.execute(t, `mutation { deleteItem(id: "`+itemOne+`") }`, map[string]string{"Authorization": "01-USER-TEST"})
+	f.execute(t, `mutation { deleteItem(id: "`+itemOne+`") }`, nil)
+
+	assert.Len(t, *entries, 1)
+	assert.Equal(t, http.StatusForbidden, (*entries)[0].Status)
+	assert.Nil(t, (*entries)[0].After)
+}
+
+func TestGraphQL_CreateItem_Category_Mismatch_Error(t *testing.T) {
+	f := newFixture()
+	f.categories.HandleGet = func(ctx context.Context, categoryID string) (models.Category, apierrors.ApiError) {
+		return models.Category{ID: categoryID, Name: "Clothes"}, nil
+	}
+
+	result := f.execute(t, `mutation { createItem(input: {
+		name: "Shirt", description: "Cotton",
+		category: {id: "`+itemThree+`", name: "Shoes"},
+		price: {amount: 10, currency: {symbol: "$", decimalDivider: ",", thousandsDivider: "."}}
+	}) }`, map[string]string{"Authorization": "01-USER-TEST"})
+
+	assert.Len(t, result.Errors, 1)
+	assert.Equal(t, catalog.ValidationFailed.Code, result.Errors[0].Extensions["code"])
+}
+
+func TestGraphQL_IsMutation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := map[string]bool{
+		`{"query":"mutation { deleteItem(id: \"1\") }"}`:                                                     true,
+		`{"query":"{ categories { id } }"}`:                                                                  false,
+		`{"query":"query Q { categories { id } } mutation M { deleteItem(id: \"1\") }","operationName":"M"}`: true,
+		`{"query":"query Q { categories { id } } mutation M { deleteItem(id: \"1\") }","operationName":"Q"}`: false,
+		`{"query":"mutation {"}`: false,
+	}
+
+	for body, expected := range cases {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+
+		assert.Equal(t, expected, graph.IsMutation(c), body)
+
+		// the body is left for the server
+		rest, _ := io.ReadAll(c.Request.Body)
+		assert.Equal(t, body, string(rest))
+	}
+}
+
+func TestGraphQL_Mutation_Write_Limit_And_Idempotency(t *testing.T) {
+	f := newFixture()
+
+	deleted := 0
+	f.service.HandleDelete = func(ctx context.Context, itemID string) apierrors.ApiError {
+		deleted++
+		return nil
+	}
+
+	records := map[string]models.IdempotencyRecord{}
+	repository := idempotency.NewRepositoryMock()
+	repository.HandleReserve = func(ctx context.Context, record models.IdempotencyRecord) (models.IdempotencyRecord, bool, apierrors.ApiError) {
+		if stored, ok := records[record.Key]; ok {
+			return stored, false, nil
+		}
+		records[record.Key] = record
+		return record, true, nil
+	}
+	repository.HandleComplete = func(ctx context.Context, key string, status int, contentType string, body []byte) apierrors.ApiError {
+		record := records[key]
+		record.Completed, record.Status, record.ContentType, record.Body = true, status, contentType, body
+		records[key] = record
+		return nil
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	auth := func(c *gin.Context) {
+		c.Set("user_id", c.GetHeader("Authorization"))
+	}
+	store := ratelimit.NewMemoryStore()
+	readLimit := handlers.RateLimitHandler(store, ratelimit.Limit{Rate: 100, Burst: 100})
+	writeLimit := handlers.RateLimitHandler(store, ratelimit.Limit{Rate: 0.001, Burst: 3})
+	limits := handlers.OperationHandler(graph.IsMutation, []gin.HandlerFunc{readLimit}, []gin.HandlerFunc{writeLimit, handlers.IdempotencyHandler(repository)})
+	handler := graph.NewHandler(f.repository, f.prices, f.service, f.categories, f.audit, f.limits)
+	router.POST("/graphql", handlers.OptionalAuthHandler(auth), limits, handler.Serve)
+
+	body := `{"query":"mutation { deleteItem(id: \"` + itemOne + `\") }"}`
+	headers := map[string]string{"Content-Type": "application/json", "Authorization": "01-USER-TEST", handlers.IdempotencyKeyHeader: "key-1"}
+
+	first := setup.ExecuteRequest(router, http.MethodPost, "/graphql", headers, body)
+	replayed := setup.ExecuteRequest(router, http.MethodPost, "/graphql", headers, body)
+	delete(headers, handlers.IdempotencyKeyHeader)
+	setup.ExecuteRequest(router, http.MethodPost, "/graphql", headers, body)
+	limited := setup.ExecuteRequest(router, http.MethodPost, "/graphql", headers, body)
+
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "true", replayed.Header().Get(handlers.IdempotentReplayedHeader))
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Equal(t, 2, deleted)
+}
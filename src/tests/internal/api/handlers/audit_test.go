@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/handlers"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/actor"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/repositories/audit"
+	"github.com/gin-gonic/gin"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/jopitnow/go-jopit-toolkit/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+// things is a target stored in a map, changed by the routes of auditRouter.
+type things map[string]map[string]interface{}
+
+func (s things) target() handlers.AuditTarget {
+	return handlers.AuditTarget{Type: "thing", ID: handlers.AuditPath("id"), State: func(ctx context.Context, id string) (interface{}, apierrors.ApiError) {
+		thing, ok := s[id]
+		if !ok {
+			return nil, apierrors.NewNotFoundApiError("thing not found")
+		}
+		return thing, nil
+	}}
+}
+
+func auditRouter(t *testing.T, store things, entries *[]models.AuditEntry, userID string) *gin.Engine {
+	mock := audit.NewRepositoryMock()
+	mock.HandleSave = func(ctx context.Context, entry models.AuditEntry) apierrors.ApiError {
+		*entries = append(*entries, entry)
+		return nil
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(tracing.XtraceHeaderKey, "trace-1")
+		if userID != "" {
+			c.Set("user_id", userID)
+		}
+	})
+
+	router.POST("/things", handlers.AuditHandler(mock, "thing.create", store.target()), func(c *gin.Context) {
+		store["new"] = map[string]interface{}{"name": "created"}
+		c.JSON(http.StatusCreated, "new")
+	})
+	router.POST("/other-things", handlers.AuditHandler(mock, "thing.create", store.target()), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	router.PUT("/things/:id", handlers.AuditHandler(mock, "thing.update", store.target()), func(c *gin.Context) {
+		assert.Equal(t, handlers.AdminActor, actor.From(c.Request.Context()))
+		if _, ok := store[c.Param("id")]; !ok {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		store[c.Param("id")] = map[string]interface{}{"name": "renamed"}
+		c.Status(http.StatusNoContent)
+	})
+	router.DELETE("/things/:id", handlers.AuditHandler(mock, "thing.delete", store.target()), func(c *gin.Context) {
+		delete(store, c.Param("id"))
+		c.Status(http.StatusNoContent)
+	})
+
+	return router
+}
+
+func send(router *gin.Engine, method string, path string, body string, headers map[string]string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.RemoteAddr = "10.0.0.1:1234"
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	return response
+}
+
+func TestAuditHandler_Update_Records_Before_And_After(t *testing.T) {
+	var entries []models.AuditEntry
+	router := auditRouter(t, things{"1": {"name": "original"}}, &entries, "")
+
+	response := send(router, http.MethodPut, "/things/1", `{"name":"renamed"}`, map[string]string{handlers.AdminActorHeader: " alice "})
+
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	assert.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, handlers.AdminActor, entry.Actor)
+	assert.Equal(t, "alice", entry.ClaimedActor)
+	assert.Equal(t, "thing.update", entry.Action)
+	assert.Equal(t, "thing", entry.TargetType)
+	assert.Equal(t, "1", entry.TargetID)
+	assert.Equal(t, "trace-1", entry.RequestID)
+	assert.Equal(t, "10.0.0.1", entry.IP)
+	assert.Equal(t, http.MethodPut, entry.Method)
+	assert.Equal(t, "/things/1", entry.Path)
+	assert.Equal(t, http.StatusNoContent, entry.Status)
+	assert.Equal(t, map[string]interface{}{"name": "original"}, entry.Before)
+	assert.Equal(t, map[string]interface{}{"name": "renamed"}, entry.After)
+	assert.False(t, entry.CreatedAt.IsZero())
+}
+
+func TestAuditHandler_Failed_Request_Has_No_After(t *testing.T) {
+	var entries []models.AuditEntry
+	router := auditRouter(t, things{}, &entries, "")
+
+	send(router, http.MethodPut, "/things/2", `{}`, map[string]string{handlers.AdminActorHeader: "alice"})
+
+	assert.Len(t, entries, 1)
+	assert.Equal(t, http.StatusNotFound, entries[0].Status)
+	assert.Nil(t, entries[0].Before)
+	assert.Nil(t, entries[0].After)
+}
+
+func TestAuditHandler_Create_Takes_The_ID_Of_The_Response(t *testing.T) {
+	var entries []models.AuditEntry
+	router := auditRouter(t, things{}, &entries, "01-USER-TEST")
+
+	send(router, http.MethodPost, "/things", `{"name":"created"}`, nil)
+
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "01-USER-TEST", entries[0].Actor)
+	assert.Equal(t, "new", entries[0].TargetID)
+	assert.Nil(t, entries[0].Before)
+	assert.Equal(t, map[string]interface{}{"name": "created"}, entries[0].After)
+}
+
+func TestAuditHandler_Create_Without_ID_Keeps_The_Request(t *testing.T) {
+	var entries []models.AuditEntry
+	router := auditRouter(t, things{}, &entries, "")
+
+	send(router, http.MethodPost, "/other-things", `{"name":"posted"}`, nil)
+
+	assert.Len(t, entries, 1)
+	assert.Equal(t, handlers.AdminActor, entries[0].Actor)
+	assert.Empty(t, entries[0].TargetID)
+	assert.Equal(t, map[string]interface{}{"name": "posted"}, entries[0].After)
+}
+
+func TestAuditHandler_Delete(t *testing.T) {
+	var entries []models.AuditEntry
+	router := auditRouter(t, things{"1": {"name": "original"}}, &entries, "01-USER-TEST")
+
+	send(router, http.MethodDelete, "/things/1", "", nil)
+
+	assert.Len(t, entries, 1)
+	assert.Equal(t, map[string]interface{}{"name": "original"}, entries[0].Before)
+	assert.Nil(t, entries[0].After)
+}
+
+func TestAuditHandler_Body_ID(t *testing.T) {
+	var entries []models.AuditEntry
+	store := things{"7": {"name": "original"}}
+	mock := audit.NewRepositoryMock()
+	mock.HandleSave = func(ctx context.Context, entry models.AuditEntry) apierrors.ApiError {
+		entries = append(entries, entry)
+		return nil
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/things", handlers.AuditHandler(mock, "thing.update", store.target().With(handlers.AuditBody)), func(c *gin.Context) {
+		var body struct {
+			ID string `json:"id"`
+		}
+		assert.Nil(t, c.BindJSON(&body))
+		assert.Equal(t, "7", body.ID)
+		c.Status(http.StatusNoContent)
+	})
+
+	response := send(router, http.MethodPut, "/things", `{"id":"7","name":"renamed"}`, nil)
+
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	assert.Equal(t, "7", entries[0].TargetID)
+	assert.Equal(t, map[string]interface{}{"name": "original"}, entries[0].Before)
+}
+
+func TestAuditHandler_Body_Too_Large(t *testing.T) {
+	var entries []models.AuditEntry
+	router := auditRouter(t, things{"1": {"name": "original"}}, &entries, "01-USER-TEST")
+
+	response := send(router, http.MethodPut, "/things/1", strings.Repeat("a", 2<<20), nil)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, response.Code)
+	assert.Empty(t, entries)
+}
+
+func TestAuditHandler_Without_Repository_Passes_Through(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/things/:id", handlers.AuditHandler(nil, "thing.update", handlers.AuditTarget{Type: "thing"}), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	response := send(router, http.MethodPut, "/things/1", "", nil)
+
+	assert.Equal(t, http.StatusNoContent, response.Code)
+}
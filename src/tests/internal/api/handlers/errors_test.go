@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/handlers"
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/gin-gonic/gin"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func catalogRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/errors", handlers.ErrorCatalog)
+	router.GET("/errors/:code", handlers.ErrorType)
+	return router
+}
+
+func getCatalog(router *gin.Engine, url string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, url, nil))
+	return response
+}
+
+func TestErrorCatalog(t *testing.T) {
+	response := getCatalog(catalogRouter(), "/errors")
+
+	var definitions []catalog.Definition
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &definitions))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, catalog.All(), definitions)
+}
+
+func TestErrorType(t *testing.T) {
+	response := getCatalog(catalogRouter(), catalog.TooManyRequests.Type)
+
+	var definition catalog.Definition
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &definition))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, catalog.TooManyRequests, definition)
+}
+
+func TestErrorType_Unknown_Code(t *testing.T) {
+	response := getCatalog(catalogRouter(), "/errors/no_such_code")
+
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
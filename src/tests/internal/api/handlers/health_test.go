@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/handlers"
+	"github.com/gin-gonic/gin"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func healthRouter(health *handlers.HealthCheckerHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/health/live", health.Live)
+	router.GET("/health/ready", health.Ready)
+	return router
+}
+
+func get(router *gin.Engine, url string) (*httptest.ResponseRecorder, handlers.HealthReport) {
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, url, nil))
+
+	var report handlers.HealthReport
+	_ = json.Unmarshal(response.Body.Bytes(), &report)
+
+	return response, report
+}
+
+func probeOK(ctx context.Context) error {
+	return nil
+}
+
+func probeFail(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func TestHealth_Live(t *testing.T) {
+	health := handlers.NewHealthCheckerHandler(time.Second, 0, handlers.HealthCheck{Name: "mongo", Required: true, Probe: probeFail})
+
+	response, report := get(healthRouter(health), "/health/live")
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, handlers.HealthStatusUp, report.Status)
+}
+
+func TestHealth_Ready_Up(t *testing.T) {
+	health := handlers.NewHealthCheckerHandler(time.Second, 0,
+		handlers.HealthCheck{Name: "mongo", Required: true, Probe: probeOK},
+		handlers.HealthCheck{Name: "prices", Probe: probeOK},
+	)
+
+	response, report := get(healthRouter(health), "/health/ready")
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, handlers.HealthStatusUp, report.Status)
+	assert.Equal(t, 2, len(report.Checks))
+	assert.Equal(t, "mongo", report.Checks[0].Name)
+	assert.Equal(t, handlers.HealthStatusUp, report.Checks[0].Status)
+}
+
+func TestHealth_Ready_Required_Down(t *testing.T) {
+	health := handlers.NewHealthCheckerHandler(time.Second, 0, handlers.HealthCheck{Name: "mongo", Required: true, Probe: probeFail})
+
+	response, report := get(healthRouter(health), "/health/ready")
+
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	assert.Equal(t, handlers.HealthStatusDown, report.Status)
+	assert.Equal(t, "connection refused", report.Checks[0].Error)
+}
+
+func TestHealth_Ready_Optional_Down_Is_Degraded(t *testing.T) {
+	health := handlers.NewHealthCheckerHandler(time.Second, 0,
+		handlers.HealthCheck{Name: "mongo", Required: true, Probe: probeOK},
+		handlers.HealthCheck{Name: "shops", Probe: probeFail},
+	)
+
+	response, report := get(healthRouter(health), "/health/ready")
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, handlers.HealthStatusDegraded, report.Status)
+}
+
+func TestHealth_Ready_Probe_Timeout(t *testing.T) {
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	health := handlers.NewHealthCheckerHandler(20*time.Millisecond, 0, handlers.HealthCheck{Name: "mongo", Required: true, Probe: slow})
+
+	response, report := get(healthRouter(health), "/health/ready")
+
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[0].Error)
+}
+
+func TestHealth_Ready_Cached(t *testing.T) {
+	calls := 0
+	counted := func(ctx context.Context) error {
+		calls++
+		return nil
+	}
+	health := handlers.NewHealthCheckerHandler(time.Second, time.Minute, handlers.HealthCheck{Name: "mongo", Required: true, Probe: counted})
+	router := healthRouter(health)
+
+	get(router, "/health/ready")
+	get(router, "/health/ready")
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestHealth_Check_Cancelled_Not_Cached(t *testing.T) {
+	calls := 0
+	counted := func(ctx context.Context) error {
+		calls++
+		return ctx.Err()
+	}
+	health := handlers.NewHealthCheckerHandler(time.Second, time.Minute, handlers.HealthCheck{Name: "mongo", Required: true, Probe: counted})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cancelled := health.Check(ctx)
+	report := health.Check(context.Background())
+
+	assert.Equal(t, handlers.HealthStatusDown, cancelled.Status)
+	assert.Equal(t, handlers.HealthStatusUp, report.Status)
+	assert.Equal(t, 2, calls)
+}
+
+func TestHealth_Check_Concurrent_Callers_Share_Probe(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	blocking := func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return nil
+	}
+	health := handlers.NewHealthCheckerHandler(time.Second, time.Minute, handlers.HealthCheck{Name: "mongo", Required: true, Probe: blocking})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, handlers.HealthStatusUp, health.Check(context.Background()).Status)
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestHealth_URLProbe(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ping", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer up.Close()
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+
+	assert.Nil(t, handlers.URLProbe(up.URL)(context.Background()))
+	assert.NotNil(t, handlers.URLProbe(down.URL)(context.Background()))
+}
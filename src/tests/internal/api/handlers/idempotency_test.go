@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/handlers"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/repositories/idempotency"
+	"github.com/gin-gonic/gin"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryIdempotency keeps the records in a map, mimicking the Mongo repository.
+func memoryIdempotency() (idempotency.RepositoryMock, map[string]models.IdempotencyRecord) {
+	var mu sync.Mutex
+	records := map[string]models.IdempotencyRecord{}
+
+	mock := idempotency.NewRepositoryMock()
+	mock.HandleReserve = func(ctx context.Context, record models.IdempotencyRecord) (models.IdempotencyRecord, bool, apierrors.ApiError) {
+		mu.Lock()
+		defer mu.Unlock()
+		if stored, ok := records[record.Key]; ok {
+			return stored, false, nil
+		}
+		records[record.Key] = record
+		return record, true, nil
+	}
+	mock.HandleComplete = func(ctx context.Context, key string, status int, contentType string, body []byte) apierrors.ApiError {
+		mu.Lock()
+		defer mu.Unlock()
+		record := records[key]
+		record.Completed, record.Status, record.ContentType, record.Body = true, status, contentType, body
+		records[key] = record
+		return nil
+	}
+	mock.HandleRelease = func(ctx context.Context, key string) apierrors.ApiError {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(records, key)
+		return nil
+	}
+
+	return mock, records
+}
+
+func idempotentRouter(mock idempotency.RepositoryMock, status int, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/things", func(c *gin.Context) {
+		c.Set("user_id", "01-USER-TEST")
+	}, handlers.IdempotencyHandler(mock), func(c *gin.Context) {
+		*calls++
+		c.JSON(status, gin.H{"call": *calls})
+	})
+	return router
+}
+
+func post(router *gin.Engine, key string, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(body))
+	if key != "" {
+		request.Header.Set(handlers.IdempotencyKeyHeader, key)
+	}
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	return response
+}
+
+func TestIdempotencyHandler_Without_Key_Passes_Through(t *testing.T) {
+	mock, records := memoryIdempotency()
+	calls := 0
+	router := idempotentRouter(mock, http.StatusCreated, &calls)
+
+	post(router, "", `{"name":"a"}`)
+	response := post(router, "", `{"name":"a"}`)
+
+	assert.Equal(t, http.StatusCreated, response.Code)
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, records)
+}
+
+func TestIdempotencyHandler_Replays_Stored_Response(t *testing.T) {
+	mock, _ := memoryIdempotency()
+	calls := 0
+	router := idempotentRouter(mock, http.StatusCreated, &calls)
+
+	first := post(router, "key-1", `{"name":"a"}`)
+	second := post(router, "key-1", `{"name":"a"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "true", second.Header().Get(handlers.IdempotentReplayedHeader))
+	assert.Empty(t, first.Header().Get(handlers.IdempotentReplayedHeader))
+}
+
+func TestIdempotencyHandler_Key_Reused_With_Other_Body(t *testing.T) {
+	mock, _ := memoryIdempotency()
+	calls := 0
+	router := idempotentRouter(mock, http.StatusCreated, &calls)
+
+	post(router, "key-1", `{"name":"a"}`)
+	response := post(router, "key-1", `{"name":"b"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	assert.Contains(t, response.Body.String(), "idempotency_key_reused")
+}
+
+func TestIdempotencyHandler_Key_In_Progress(t *testing.T) {
+	mock, records := memoryIdempotency()
+	calls := 0
+	router := idempotentRouter(mock, http.StatusCreated, &calls)
+
+	post(router, "key-1", `{"name":"a"}`)
+	for key, record := range records {
+		record.Completed = false
+		records[key] = record
+	}
+	response := post(router, "key-1", `{"name":"a"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusConflict, response.Code)
+	assert.Contains(t, response.Body.String(), "idempotency_key_in_progress")
+}
+
+func TestIdempotencyHandler_Server_Error_Releases_Key(t *testing.T) {
+	mock, records := memoryIdempotency()
+	calls := 0
+	router := idempotentRouter(mock, http.StatusInternalServerError, &calls)
+
+	post(router, "key-1", `{"name":"a"}`)
+	response := post(router, "key-1", `{"name":"a"}`)
+
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.Empty(t, records)
+}
+
+func TestIdempotencyHandler_Panic_Releases_Key(t *testing.T) {
+	mock, records := memoryIdempotency()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.POST("/things", handlers.IdempotencyHandler(mock), func(c *gin.Context) {
+		panic("boom")
+	})
+
+	response := post(router, "key-1", `{"name":"a"}`)
+
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.Empty(t, records)
+}
+
+func TestIdempotencyHandler_Keys_Scoped_By_User(t *testing.T) {
+	mock, records := memoryIdempotency()
+	calls := 0
+	router := idempotentRouter(mock, http.StatusCreated, &calls)
+
+	post(router, "key-1", `{"name":"a"}`)
+
+	for key := range records {
+		assert.Equal(t, "/things|01-USER-TEST|key-1", key)
+	}
+}
+
+func TestIdempotencyHandler_Key_Too_Long(t *testing.T) {
+	mock, _ := memoryIdempotency()
+	calls := 0
+	router := idempotentRouter(mock, http.StatusCreated, &calls)
+
+	response := post(router, strings.Repeat("k", 256), `{}`)
+
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
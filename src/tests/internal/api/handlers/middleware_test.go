@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/handlers"
+	"github.com/gin-gonic/gin"
+	"github.com/jopitnow/go-jopit-toolkit/goauth"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func devAuthRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/me", handlers.DevAuthHandler(), func(c *gin.Context) {
+		userID, err := goauth.GetUserId(c)
+		if err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.String(http.StatusOK, userID)
+	})
+	return router
+}
+
+func TestDevAuthHandler_Sets_User(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/me", nil)
+	request.Header.Set("Authorization", "Dev alice")
+	response := httptest.NewRecorder()
+
+	devAuthRouter().ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "alice", response.Body.String())
+}
+
+func TestDevAuthHandler_Rejects_Other_Headers(t *testing.T) {
+	for _, header := range []string{"", "Bearer firebase-token"} {
+		request := httptest.NewRequest(http.MethodGet, "/me", nil)
+		request.Header.Set("Authorization", header)
+		response := httptest.NewRecorder()
+
+		devAuthRouter().ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnauthorized, response.Code, header)
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/handlers"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/ratelimit"
+	"github.com/gin-gonic/gin"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("backend down")
+}
+
+func limitedRouter(store ratelimit.Store, userID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/things", func(c *gin.Context) {
+		if userID != "" {
+			c.Set("user_id", userID)
+		}
+	}, handlers.RateLimitHandler(store, ratelimit.Limit{Rate: 1, Burst: 1}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func call(router *gin.Engine, remoteAddr string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, "/things", nil)
+	request.RemoteAddr = remoteAddr
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	return response
+}
+
+func TestRateLimit_Too_Many_Requests(t *testing.T) {
+	router := limitedRouter(ratelimit.NewMemoryStore(), "")
+
+	first := call(router, "10.0.0.1:1234")
+	second := call(router, "10.0.0.1:1234")
+
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "1", first.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", first.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "1", first.Header().Get("X-RateLimit-Reset"))
+
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+	assert.Equal(t, "1", second.Header().Get("Retry-After"))
+	assert.Contains(t, second.Body.String(), "too_many_requests")
+}
+
+func TestRateLimit_Keyed_By_IP(t *testing.T) {
+	router := limitedRouter(ratelimit.NewMemoryStore(), "")
+
+	call(router, "10.0.0.1:1234")
+	other := call(router, "10.0.0.2:1234")
+
+	assert.Equal(t, http.StatusOK, other.Code)
+}
+
+func TestRateLimit_Keyed_By_User(t *testing.T) {
+	router := limitedRouter(ratelimit.NewMemoryStore(), "01-USER-TEST")
+
+	call(router, "10.0.0.1:1234")
+	sameUser := call(router, "10.0.0.2:1234")
+
+	assert.Equal(t, http.StatusTooManyRequests, sameUser.Code)
+}
+
+func TestRateLimit_Disabled_Or_Failing_Backend(t *testing.T) {
+	for _, store := range []ratelimit.Store{nil, failingStore{}} {
+		router := limitedRouter(store, "")
+
+		call(router, "10.0.0.1:1234")
+		response := call(router, "10.0.0.1:1234")
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	}
+}
@@ -0,0 +1,280 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/backup"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// storeMock keeps the documents by collection and _id.
+type storeMock struct {
+	collections map[string]map[string]bson.Raw
+	replaceErr  error
+}
+
+func newStoreMock() *storeMock {
+	return &storeMock{collections: map[string]map[string]bson.Raw{}}
+}
+
+func (s *storeMock) add(t *testing.T, collection string, doc bson.M) {
+	raw, err := bson.Marshal(doc)
+	assert.Nil(t, err)
+	assert.Nil(t, s.Replace(context.Background(), collection, []bson.Raw{raw}))
+}
+
+func (s *storeMock) ids(collection string) []string {
+	var ids []string
+	for id := range s.collections[collection] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (s *storeMock) Each(ctx context.Context, collection string, fn func(doc bson.Raw) error) error {
+	for _, id := range s.ids(collection) {
+		if err := fn(s.collections[collection][id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *storeMock) Replace(ctx context.Context, collection string, docs []bson.Raw) error {
+	if s.replaceErr != nil {
+		return s.replaceErr
+	}
+	if s.collections[collection] == nil {
+		s.collections[collection] = map[string]bson.Raw{}
+	}
+	for _, doc := range docs {
+		s.collections[collection][doc.Lookup("_id").String()] = append(bson.Raw(nil), doc...)
+	}
+	return nil
+}
+
+func (s *storeMock) Remove(ctx context.Context, collection string, filter bson.D) (int64, error) {
+	matches := s.matching(collection, filter)
+	for _, id := range matches {
+		delete(s.collections[collection], id)
+	}
+	return int64(len(matches)), nil
+}
+
+// matching supports the filter of Restore, an _id $in.
+func (s *storeMock) matching(collection string, filter bson.D) []string {
+	var ids []string
+	for _, value := range filter[0].Value.(bson.D)[0].Value.(bson.A) {
+		id := value.(bson.RawValue).String()
+		if _, ok := s.collections[collection][id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func catalog(t *testing.T) *storeMock {
+	store := newStoreMock()
+	store.add(t, "items", bson.M{"_id": primitive.NewObjectID(), "name": "Boots", "shop_id": "shop-a", "stock": int32(3), "price": 10.5})
+	store.add(t, "items", bson.M{"_id": primitive.NewObjectID(), "name": "Hat", "shop_id": "shop-b", "added": primitive.NewDateTimeFromTime(time.Now())})
+	store.add(t, "categories", bson.M{"_id": primitive.NewObjectID(), "name": "Shoes"})
+	return store
+}
+
+func backupOf(t *testing.T, store *storeMock, format string) []byte {
+	var archive bytes.Buffer
+	manifest, err := backup.Backup(context.Background(), store, &archive, backup.Options{
+		Database:    "items",
+		Format:      format,
+		Collections: []string{"items", "categories"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "items", manifest.Database)
+	assert.Len(t, manifest.Collections, 2)
+
+	return archive.Bytes()
+}
+
+func TestBackup_Restore_Round_Trip(t *testing.T) {
+	for _, format := range []string{backup.FormatBSON, backup.FormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			source := catalog(t)
+			archive := backupOf(t, source, format)
+
+			target := newStoreMock()
+			result, err := backup.Restore(context.Background(), target, bytes.NewReader(archive), backup.RestoreOptions{})
+
+			assert.Nil(t, err)
+			assert.Equal(t, format, result.Manifest.Format)
+			assert.Equal(t, int64(2), result.Collections[0].Restored)
+			assert.Equal(t, int64(1), result.Collections[1].Restored)
+			assert.Equal(t, source.collections, target.collections)
+		})
+	}
+}
+
+func TestBackup_Manifest_Counts(t *testing.T) {
+	var archive bytes.Buffer
+	manifest, err := backup.Backup(context.Background(), catalog(t), &archive, backup.Options{Format: backup.FormatBSON, Collections: []string{"items", "categories", "collections"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "items.bson", manifest.Collections[0].File)
+	assert.Equal(t, int64(2), manifest.Collections[0].Count)
+	assert.Equal(t, int64(1), manifest.Collections[1].Count)
+	assert.Equal(t, int64(0), manifest.Collections[2].Count)
+	assert.Len(t, manifest.Collections[0].SHA256, 64)
+}
+
+func TestBackup_Invalid_Format(t *testing.T) {
+	_, err := backup.Backup(context.Background(), newStoreMock(), io.Discard, backup.Options{Format: "csv"})
+
+	assert.NotNil(t, err)
+}
+
+func TestRestore_Shop(t *testing.T) {
+	source := catalog(t)
+	archive := backupOf(t, source, backup.FormatBSON)
+
+	target := newStoreMock()
+	target.add(t, "items", bson.M{"_id": "created-after", "name": "Sandals", "shop_id": "shop-a"})
+	target.add(t, "items", bson.M{"_id": "other-shop", "name": "Scarf", "shop_id": "shop-b"})
+	target.add(t, "categories", bson.M{"_id": "category", "name": "Hats"})
+
+	result, err := backup.Restore(context.Background(), target, bytes.NewReader(archive), backup.RestoreOptions{ShopID: "shop-a", Drop: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, backup.RestoredCollection{Name: "items", Restored: 1, Skipped: 1, Removed: 1}, result.Collections[0])
+	assert.Equal(t, backup.RestoredCollection{Name: "categories", Restored: 0, Skipped: 1, Removed: 0}, result.Collections[1])
+
+	var names []string
+	for _, doc := range target.collections["items"] {
+		names = append(names, doc.Lookup("name").StringValue())
+	}
+	assert.ElementsMatch(t, []string{"Boots", "Scarf"}, names)
+	assert.Len(t, target.collections["categories"], 1)
+}
+
+func TestRestore_Drop_Keeps_The_Restored(t *testing.T) {
+	source := catalog(t)
+	archive := backupOf(t, source, backup.FormatBSON)
+
+	target := newStoreMock()
+	target.collections["items"] = map[string]bson.Raw{}
+	for id, doc := range source.collections["items"] {
+		target.collections["items"][id] = doc
+	}
+	target.add(t, "items", bson.M{"_id": "created-after", "name": "Sandals", "shop_id": "shop-a"})
+
+	result, err := backup.Restore(context.Background(), target, bytes.NewReader(archive), backup.RestoreOptions{Drop: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, backup.RestoredCollection{Name: "items", Restored: 2, Skipped: 0, Removed: 1}, result.Collections[0])
+	assert.Equal(t, source.collections, target.collections)
+}
+
+func TestRestore_Drop_Failed_Restore_Removes_Nothing(t *testing.T) {
+	archive := backupOf(t, catalog(t), backup.FormatBSON)
+
+	target := newStoreMock()
+	target.add(t, "items", bson.M{"_id": "created-after", "name": "Sandals", "shop_id": "shop-a"})
+	target.replaceErr = errors.New("connection lost")
+
+	result, err := backup.Restore(context.Background(), target, bytes.NewReader(archive), backup.RestoreOptions{Drop: true})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, int64(0), result.Collections[0].Removed)
+	assert.Equal(t, []string{`"created-after"`}, target.ids("items"))
+}
+
+func TestRestore_Dry_Run(t *testing.T) {
+	archive := backupOf(t, catalog(t), backup.FormatJSON)
+
+	target := newStoreMock()
+	target.add(t, "items", bson.M{"_id": "existing", "shop_id": "shop-a"})
+
+	result, err := backup.Restore(context.Background(), target, bytes.NewReader(archive), backup.RestoreOptions{Drop: true, DryRun: true})
+
+	assert.Nil(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, int64(1), result.Collections[0].Removed)
+	assert.Equal(t, int64(2), result.Collections[0].Restored)
+	assert.Equal(t, []string{`"existing"`}, target.ids("items"))
+	assert.Empty(t, target.collections["categories"])
+}
+
+// rewrite copies the archive changing the content of the file name.
+func rewrite(t *testing.T, archive []byte, name string, change func([]byte) []byte) []byte {
+	compressed, err := gzip.NewReader(bytes.NewReader(archive))
+	assert.Nil(t, err)
+	reader := tar.NewReader(compressed)
+
+	var out bytes.Buffer
+	recompressed := gzip.NewWriter(&out)
+	writer := tar.NewWriter(recompressed)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+
+		content, err := io.ReadAll(reader)
+		assert.Nil(t, err)
+		if header.Name == name {
+			content = change(content)
+			header.Size = int64(len(content))
+		}
+
+		assert.Nil(t, writer.WriteHeader(header))
+		_, err = writer.Write(content)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, writer.Close())
+	assert.Nil(t, recompressed.Close())
+
+	return out.Bytes()
+}
+
+func TestRestore_Checksum_Mismatch_Writes_Nothing(t *testing.T) {
+	archive := backupOf(t, catalog(t), backup.FormatJSON)
+	tampered := rewrite(t, archive, "categories.jsonl", func(content []byte) []byte {
+		return bytes.Replace(content, []byte("Shoes"), []byte("Boots"), 1)
+	})
+
+	target := newStoreMock()
+	_, err := backup.Restore(context.Background(), target, bytes.NewReader(tampered), backup.RestoreOptions{})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "checksum of categories.jsonl")
+	assert.Empty(t, target.collections)
+}
+
+func TestRestore_Missing_File(t *testing.T) {
+	archive := backupOf(t, catalog(t), backup.FormatBSON)
+	truncated := rewrite(t, archive, "manifest.json", func(content []byte) []byte {
+		return bytes.Replace(content, []byte(`"categories.bson"`), []byte(`"other.bson"`), 1)
+	})
+
+	_, err := backup.Restore(context.Background(), newStoreMock(), bytes.NewReader(truncated), backup.RestoreOptions{})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "categories.bson is not in the manifest")
+}
+
+func TestRestore_Not_A_Backup(t *testing.T) {
+	_, err := backup.Restore(context.Background(), newStoreMock(), bytes.NewReader([]byte("not a backup")), backup.RestoreOptions{})
+
+	assert.NotNil(t, err)
+}
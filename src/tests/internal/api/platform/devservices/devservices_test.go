@@ -0,0 +1,128 @@
+package devservices
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/config"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/devservices"
+	"github.com/agustinrabini/items-api-project/src/main/domain/clients"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/mocks"
+
+	"github.com/jopitnow/go-jopit-toolkit/goauth"
+	"github.com/stretchr/testify/assert"
+)
+
+// serve points the clients built after it to the fake prices and shops.
+func serve(t *testing.T) {
+	prices := httptest.NewServer(devservices.NewPrices().Handler())
+	shops := httptest.NewServer(devservices.NewShops().Handler())
+	t.Cleanup(prices.Close)
+	t.Cleanup(shops.Close)
+
+	previous := config.ConfMap
+	t.Cleanup(func() { config.ConfMap = previous })
+
+	config.ConfMap.PricesBaseURL = prices.URL
+	config.ConfMap.ShopsBaseURL = shops.URL
+}
+
+func TestDevServices_UserID(t *testing.T) {
+	userID, err := devservices.UserID("Dev alice")
+	assert.Nil(t, err)
+	assert.Equal(t, "alice", userID)
+
+	for _, header := range []string{"", "Dev", "Dev  ", "Bearer alice"} {
+		_, err := devservices.UserID(header)
+		assert.Equal(t, devservices.ErrInvalidAuthorization, err, header)
+	}
+}
+
+func TestDevServices_Prices_Lifecycle(t *testing.T) {
+	serve(t)
+	client := clients.NewPriceClient()
+	ctx := context.Background()
+
+	price := mocks.Price
+	price.ItemID = mocks.ItemIdOne
+	assert.Nil(t, client.CreatePrice(ctx, &price))
+
+	stored, apiErr := client.GetPriceByItemID(ctx, mocks.ItemIdOne)
+	assert.Nil(t, apiErr)
+	assert.NotEmpty(t, stored.ID)
+	assert.Equal(t, price.Amount, stored.Amount)
+
+	stored.Amount = 42
+	assert.Nil(t, client.UpdatePrice(ctx, &stored))
+
+	prices, apiErr := client.GetItemsPrices(ctx, []string{mocks.ItemIdOne, mocks.ItemIdTwo})
+	assert.Nil(t, apiErr)
+	assert.Equal(t, []models.Price{stored}, prices.Prices)
+
+	assert.Nil(t, client.DeletePrice(ctx, mocks.ItemIdOne))
+
+	_, apiErr = client.GetPriceByItemID(ctx, mocks.ItemIdOne)
+	assert.Equal(t, http.StatusNotFound, apiErr.Status())
+}
+
+func TestDevServices_Prices_ListPricedItems(t *testing.T) {
+	serve(t)
+	client := clients.NewPriceClient()
+	ctx := context.Background()
+
+	for _, itemID := range []string{mocks.ItemIdTwo, mocks.ItemIdOne} {
+		price := mocks.Price
+		price.ItemID = itemID
+		assert.Nil(t, client.CreatePrice(ctx, &price))
+	}
+
+	first, apiErr := client.ListPricedItems(ctx, 0, 1)
+	assert.Nil(t, apiErr)
+	second, apiErr := client.ListPricedItems(ctx, 1, 1)
+	assert.Nil(t, apiErr)
+	past, apiErr := client.ListPricedItems(ctx, 2, 1)
+	assert.Nil(t, apiErr)
+
+	assert.Equal(t, int64(2), first.Total)
+	assert.Equal(t, []string{min(mocks.ItemIdOne, mocks.ItemIdTwo)}, first.ItemIDs)
+	assert.Equal(t, []string{max(mocks.ItemIdOne, mocks.ItemIdTwo)}, second.ItemIDs)
+	assert.Empty(t, past.ItemIDs)
+}
+
+func TestDevServices_Shop_Of_Dev_User(t *testing.T) {
+	serve(t)
+	client := clients.NewShopClient()
+
+	ctx := context.WithValue(context.Background(), goauth.FirebaseAuthHeader, "Dev alice")
+	shop, apiErr := client.GetShopByUserID(ctx)
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, devservices.ShopID("alice"), shop.ID)
+	assert.Len(t, shop.ID, 24)
+	assert.NotEqual(t, devservices.ShopID("bob"), shop.ID)
+}
+
+func TestDevServices_Shop_Without_Dev_User(t *testing.T) {
+	serve(t)
+	client := clients.NewShopClient()
+
+	_, apiErr := client.GetShopByUserID(context.Background())
+
+	assert.NotNil(t, apiErr)
+}
+
+func TestDevServices_Worker_Close_Frees_Port(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	address := listener.Addr().String()
+
+	devservices.NewWorker("prices", http.NotFoundHandler(), listener).Close()
+
+	reopened, err := net.Listen("tcp", address)
+	assert.Nil(t, err)
+	reopened.Close()
+}
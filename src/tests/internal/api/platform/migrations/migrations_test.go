@@ -0,0 +1,182 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/migrations"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// historyMock keeps the records in memory.
+type historyMock struct {
+	records map[int]migrations.Record
+}
+
+func (h *historyMock) Applied(ctx context.Context) ([]migrations.Record, error) {
+	records := []migrations.Record{}
+	for _, record := range h.records {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Version < records[j].Version })
+	return records, nil
+}
+
+func (h *historyMock) Add(ctx context.Context, record migrations.Record) error {
+	h.records[record.Version] = record
+	return nil
+}
+
+func (h *historyMock) Remove(ctx context.Context, version int) error {
+	delete(h.records, version)
+	return nil
+}
+
+// journal records the Up and Down calls of the migrations it builds.
+type journal []string
+
+func (j *journal) migration(version int, name string) migrations.Migration {
+	return migrations.Migration{
+		Version: version,
+		Name:    name,
+		Up: func(ctx context.Context) error {
+			*j = append(*j, "up "+name)
+			return nil
+		},
+		Down: func(ctx context.Context) error {
+			*j = append(*j, "down "+name)
+			return nil
+		},
+	}
+}
+
+func newMigrator(t *testing.T, calls *journal, history *historyMock) *migrations.Migrator {
+	migrator, err := migrations.NewMigrator(history,
+		calls.migration(3, "third"),
+		calls.migration(1, "first"),
+		calls.migration(2, "second"),
+	)
+	assert.Nil(t, err)
+	return migrator
+}
+
+func TestMigrator_Up_Applies_The_Pending_In_Order(t *testing.T) {
+	calls := &journal{}
+	history := &historyMock{records: map[int]migrations.Record{2: {Version: 2, Name: "second"}}}
+
+	done, err := newMigrator(t, calls, history).Up(context.Background(), 0)
+
+	assert.Nil(t, err)
+	assert.Len(t, done, 2)
+	assert.Equal(t, journal{"up first", "up third"}, *calls)
+	assert.Len(t, history.records, 3)
+	assert.False(t, history.records[3].AppliedAt.IsZero())
+}
+
+func TestMigrator_Up_To_Target(t *testing.T) {
+	calls := &journal{}
+	history := &historyMock{records: map[int]migrations.Record{}}
+
+	_, err := newMigrator(t, calls, history).Up(context.Background(), 2)
+
+	assert.Nil(t, err)
+	assert.Equal(t, journal{"up first", "up second"}, *calls)
+	assert.NotContains(t, history.records, 3)
+}
+
+func TestMigrator_Up_Stops_At_The_First_Failure(t *testing.T) {
+	calls := &journal{}
+	history := &historyMock{records: map[int]migrations.Record{}}
+	failing := calls.migration(2, "failing")
+	failing.Up = func(ctx context.Context) error { return errors.New("boom") }
+
+	migrator, _ := migrations.NewMigrator(history, calls.migration(1, "first"), failing, calls.migration(3, "third"))
+	done, err := migrator.Up(context.Background(), 0)
+
+	assert.EqualError(t, err, "error applying migration 2 failing: boom")
+	assert.Len(t, done, 1)
+	assert.Equal(t, journal{"up first"}, *calls)
+	assert.NotContains(t, history.records, 2, "a failed migration is not recorded")
+}
+
+func TestMigrator_PlanUp_Does_Not_Apply(t *testing.T) {
+	calls := &journal{}
+	history := &historyMock{records: map[int]migrations.Record{}}
+
+	plan, err := newMigrator(t, calls, history).PlanUp(context.Background(), 0)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3}, []int{plan[0].Version, plan[1].Version, plan[2].Version})
+	assert.Empty(t, *calls)
+	assert.Empty(t, history.records)
+}
+
+func TestMigrator_Down_Reverts_The_Last_Ones(t *testing.T) {
+	calls := &journal{}
+	history := &historyMock{records: map[int]migrations.Record{}}
+	migrator := newMigrator(t, calls, history)
+	_, _ = migrator.Up(context.Background(), 0)
+	*calls = journal{}
+
+	done, err := migrator.Down(context.Background(), 2)
+
+	assert.Nil(t, err)
+	assert.Len(t, done, 2)
+	assert.Equal(t, journal{"down third", "down second"}, *calls)
+	assert.Equal(t, []int{1}, versions(history))
+}
+
+func TestMigrator_Down_Unknown_Migration(t *testing.T) {
+	calls := &journal{}
+	history := &historyMock{records: map[int]migrations.Record{4: {Version: 4, Name: "newer"}}}
+
+	_, err := newMigrator(t, calls, history).Down(context.Background(), 1)
+
+	assert.EqualError(t, err, "migration 4 newer is not known by this build, revert it with the build that applied it")
+	assert.Empty(t, *calls)
+}
+
+func TestMigrator_Status(t *testing.T) {
+	calls := &journal{}
+	history := &historyMock{records: map[int]migrations.Record{1: {Version: 1, Name: "first"}, 4: {Version: 4, Name: "newer"}}}
+
+	statuses, err := newMigrator(t, calls, history).Status(context.Background())
+
+	assert.Nil(t, err)
+	assert.Len(t, statuses, 4)
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[1].Applied)
+	assert.Nil(t, statuses[1].AppliedAt)
+	assert.Equal(t, migrations.Status{Version: 4, Name: "newer", Applied: true, AppliedAt: statuses[3].AppliedAt, Unknown: true}, statuses[3])
+}
+
+func TestNewMigrator_Rejects_Repeated_Versions(t *testing.T) {
+	calls := &journal{}
+
+	_, err := migrations.NewMigrator(&historyMock{}, calls.migration(1, "first"), calls.migration(1, "again"))
+
+	assert.EqualError(t, err, `migrations "first" and "again" share the version 1`)
+}
+
+func TestCatalog_Versions(t *testing.T) {
+	catalog := migrations.Catalog(migrations.Collections{})
+
+	_, err := migrations.NewMigrator(&historyMock{}, catalog...)
+
+	assert.Nil(t, err)
+	for idx, migration := range catalog {
+		assert.Equal(t, idx+1, migration.Version, "versions follow each other")
+	}
+}
+
+func versions(history *historyMock) []int {
+	records, _ := history.Applied(context.Background())
+	result := []int{}
+	for _, record := range records {
+		result = append(result, record.Version)
+	}
+	return result
+}
@@ -0,0 +1,210 @@
+package problems
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/problems"
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/stretchr/testify/assert"
+)
+
+type attributeInput struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type itemInput struct {
+	Name       string           `json:"name" binding:"required"`
+	Stock      int              `json:"stock"`
+	Attributes []attributeInput `json:"attributes" binding:"dive"`
+}
+
+func bind(body string) apierrors.ApiError {
+	var input itemInput
+	request := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+	return problems.BindError(binding.JSON.Bind(request, &input))
+}
+
+func fields(err apierrors.ApiError) []problems.FieldError {
+	var fieldErrs []problems.FieldError
+	for _, cause := range err.Cause() {
+		fieldErrs = append(fieldErrs, cause.(problems.FieldError))
+	}
+	return fieldErrs
+}
+
+func respond(accept string, err apierrors.ApiError) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/items/:id", func(c *gin.Context) {
+		c.Set("X-Trace-ID", "trace-1")
+		problems.Respond(c, err)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/items/1?lang=es", nil)
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	return response
+}
+
+func TestBindError_Validation_Points_At_JSON_Paths(t *testing.T) {
+	err := bind(`{"attributes":[{"name":"size"},{}]}`)
+
+	assert.Equal(t, "validation_failed", err.Code())
+	assert.Equal(t, http.StatusBadRequest, err.Status())
+	assert.Equal(t, []problems.FieldError{
+		{Path: "$.name", Code: "required", Detail: "is required"},
+		{Path: "$.attributes[1].name", Code: "required", Detail: "is required"},
+	}, fields(err))
+	assert.Contains(t, err.Message(), "$.attributes[1].name is required")
+}
+
+func TestBindError_Wrong_Type(t *testing.T) {
+	err := bind(`{"name":"shirt","stock":"many"}`)
+
+	assert.Equal(t, "validation_failed", err.Code())
+	assert.Equal(t, []problems.FieldError{{Path: "$.stock", Code: "invalid_type", Detail: "must be a int, got string"}}, fields(err))
+}
+
+func TestBindError_Malformed_And_Empty_Body(t *testing.T) {
+	malformed := bind(`{"name":`)
+	empty := bind(``)
+
+	assert.Equal(t, "validation_failed", malformed.Code())
+	assert.Empty(t, malformed.Cause())
+	assert.Equal(t, "validation_failed", empty.Code())
+	assert.Equal(t, "the request body is empty", empty.Message())
+}
+
+func TestRespond_Keeps_ApiError_Shape_By_Default(t *testing.T) {
+	for _, accept := range []string{"", "*/*", "application/json"} {
+		response := respond(accept, catalog.NotFound.New("item not found"))
+
+		var body map[string]interface{}
+		assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &body))
+		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.Contains(t, response.Header().Get("Content-Type"), "application/json")
+		assert.Equal(t, "not_found", body["error"])
+		assert.Equal(t, "item not found", body["message"])
+		assert.NotContains(t, body, "type")
+	}
+}
+
+func TestRespond_Problem_Details(t *testing.T) {
+	field := problems.FieldError{Path: "$.name", Code: "required", Detail: "is required"}
+	response := respond("application/problem+json, application/json;q=0.5", catalog.ValidationFailed.New("invalid request body", field, "internal detail"))
+
+	var problem problems.Problem
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Equal(t, problems.MIMEProblemJSON, response.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", response.Header().Get("Vary"))
+	assert.Equal(t, problems.Problem{
+		Type:     "/errors/validation_failed",
+		Title:    "The request is not valid",
+		Status:   http.StatusBadRequest,
+		Detail:   "invalid request body",
+		Instance: "/items/1?lang=es",
+		Code:     "validation_failed",
+		TraceID:  "trace-1",
+		Errors:   []problems.FieldError{field},
+	}, problem)
+}
+
+func TestRespond_Problem_Details_Code_Outside_Catalog(t *testing.T) {
+	response := respond(problems.MIMEProblemJSON, apierrors.NewApiError("token expired", "token_expired", http.StatusUnauthorized, apierrors.CauseList{}))
+
+	var problem problems.Problem
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &problem))
+	assert.Equal(t, "about:blank", problem.Type)
+	assert.Equal(t, "Unauthorized", problem.Title)
+	assert.Equal(t, "token_expired", problem.Code)
+	assert.Equal(t, http.StatusUnauthorized, problem.Status)
+}
+
+func TestRespond_Keeps_Legacy_Error_Value(t *testing.T) {
+	response := respond("", catalog.InvalidID.New("the id is not a valid hex string"))
+
+	var body map[string]interface{}
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, "bad_request", body["error"])
+
+	response = respond(problems.MIMEProblemJSON, catalog.InvalidID.New("the id is not a valid hex string"))
+
+	var problem problems.Problem
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &problem))
+	assert.Equal(t, "invalid_id", problem.Code)
+	assert.Equal(t, "/errors/invalid_id", problem.Type)
+}
+
+func wrapped(accept string, middleware gin.HandlerFunc) (*httptest.ResponseRecorder, bool) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	reached := false
+	router.GET("/items", problems.Wrap(middleware), func(c *gin.Context) {
+		reached = true
+		c.String(http.StatusOK, c.GetString("user_id"))
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/items", nil)
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	return response, reached
+}
+
+func TestWrap_Middleware_Error_As_Problem(t *testing.T) {
+	rejecting := func(c *gin.Context) {
+		apiErr := apierrors.NewUnauthorizedApiError("invalid token")
+		c.AbortWithStatusJSON(apiErr.Status(), apiErr)
+	}
+
+	response, reached := wrapped(problems.MIMEProblemJSON, rejecting)
+
+	var problem problems.Problem
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &problem))
+	assert.False(t, reached)
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Equal(t, problems.MIMEProblemJSON, response.Header().Get("Content-Type"))
+	assert.Equal(t, "unauthorized", problem.Code)
+	assert.Equal(t, "invalid token", problem.Detail)
+}
+
+func TestWrap_Middleware_Error_Keeps_ApiError_Shape(t *testing.T) {
+	rejecting := func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusForbidden)
+	}
+
+	response, reached := wrapped("", rejecting)
+
+	var body map[string]interface{}
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.False(t, reached)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	assert.Equal(t, "forbidden", body["error"])
+}
+
+func TestWrap_Middleware_Passing_Keeps_Keys(t *testing.T) {
+	passing := func(c *gin.Context) {
+		c.Set("user_id", "01-USER-TEST")
+		c.Next()
+	}
+
+	response, reached := wrapped("", passing)
+
+	assert.True(t, reached)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "01-USER-TEST", response.Body.String())
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+var limit = ratelimit.Limit{Rate: 1, Burst: 2}
+
+func TestMemoryStore_Burst_Then_Denied(t *testing.T) {
+	fake := &clock{now: time.Unix(0, 0)}
+	store := ratelimit.NewMemoryStoreWithClock(fake.Now)
+
+	first, _ := store.Take(context.Background(), "key", limit)
+	second, _ := store.Take(context.Background(), "key", limit)
+	third, err := store.Take(context.Background(), "key", limit)
+
+	assert.Nil(t, err)
+	assert.True(t, first.Allowed)
+	assert.Equal(t, 1, first.Remaining)
+	assert.True(t, second.Allowed)
+	assert.Equal(t, 0, second.Remaining)
+	assert.False(t, third.Allowed)
+	assert.Equal(t, 2, third.Limit)
+	assert.Equal(t, time.Second, third.RetryAfter)
+	assert.Equal(t, 2*time.Second, third.Reset)
+}
+
+func TestMemoryStore_Refills_Over_Time(t *testing.T) {
+	fake := &clock{now: time.Unix(0, 0)}
+	store := ratelimit.NewMemoryStoreWithClock(fake.Now)
+
+	store.Take(context.Background(), "key", limit)
+	store.Take(context.Background(), "key", limit)
+
+	fake.Advance(500 * time.Millisecond)
+	denied, _ := store.Take(context.Background(), "key", limit)
+
+	fake.Advance(500 * time.Millisecond)
+	allowed, _ := store.Take(context.Background(), "key", limit)
+
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, 500*time.Millisecond, denied.RetryAfter)
+	assert.True(t, allowed.Allowed)
+}
+
+func TestMemoryStore_Keys_Are_Independent(t *testing.T) {
+	fake := &clock{now: time.Unix(0, 0)}
+	store := ratelimit.NewMemoryStoreWithClock(fake.Now)
+
+	store.Take(context.Background(), "user:1", limit)
+	store.Take(context.Background(), "user:1", limit)
+	other, _ := store.Take(context.Background(), "user:2", limit)
+
+	assert.True(t, other.Allowed)
+}
+
+func TestMemoryStore_Idle_Bucket_Starts_Full(t *testing.T) {
+	fake := &clock{now: time.Unix(0, 0)}
+	store := ratelimit.NewMemoryStoreWithClock(fake.Now)
+
+	store.Take(context.Background(), "key", limit)
+	store.Take(context.Background(), "key", limit)
+
+	fake.Advance(2 * time.Minute)
+	result, _ := store.Take(context.Background(), "key", limit)
+
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+}
@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/scheduler"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorker_Runs_Every_Interval_Until_Cancelled(t *testing.T) {
+	var runs atomic.Int32
+	worker := scheduler.Every("job", 5*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return errors.New("failed runs don't stop the worker")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		worker.Run(ctx)
+		close(stopped)
+	}()
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, time.Millisecond)
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("the worker didn't stop")
+	}
+}
+
+func TestWorker_Waits_An_Interval_Before_The_First_Run(t *testing.T) {
+	var runs atomic.Int32
+	worker := scheduler.Every("job", time.Hour, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	worker.Run(ctx)
+
+	assert.Equal(t, int32(0), runs.Load())
+}
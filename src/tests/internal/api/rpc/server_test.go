@@ -0,0 +1,325 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/rpc"
+	"github.com/agustinrabini/items-api-project/src/main/api/rpc/itemspb"
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/services/items"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/jopitnow/go-jopit-toolkit/tracing"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	itemID      = "65a1f0c2e4b0a1b2c3d4e5f6"
+	otherItemID = "65a1f0c2e4b0a1b2c3d4e5f7"
+	shopID      = "65a1f0c2e4b0a1b2c3d4e5f8"
+)
+
+// dial serves the mocked service over an in-memory listener and returns a
+// client connected to it.
+func dial(t *testing.T, service items.ServiceMock) *grpc.ClientConn {
+	listener := bufconn.Listen(1024 * 1024)
+	server := rpc.NewServer(service)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func client(t *testing.T, service items.ServiceMock) itemspb.ItemsServiceClient {
+	return itemspb.NewItemsServiceClient(dial(t, service))
+}
+
+func sampleItem(id string) models.Item {
+	return models.Item{
+		ID:       id,
+		Name:     "Shirt",
+		ShopID:   "shop",
+		Status:   models.ItemStatusActive,
+		Category: models.Category{ID: "category", Name: "Clothes"},
+		Price:    models.Price{ItemID: id, Amount: 10.5, Currency: models.Currency{ID: "ARS", Symbol: "$"}},
+		Eligible: []models.Eligible{
+			{ID: "size", Title: "Size", IsRequired: true, Options: []models.Option{"S", "M"}},
+			{ID: "color", Title: "Color", Options: []models.Option{"red"}},
+		},
+	}
+}
+
+func TestRPC_GetItem_Success(t *testing.T) {
+	service := items.NewItemsServiceMock()
+	service.HandleGet = func(ctx context.Context, id string) (models.Item, apierrors.ApiError) {
+		return sampleItem(id), nil
+	}
+
+	response, err := client(t, service).GetItem(context.Background(), &itemspb.GetItemRequest{Id: itemID})
+
+	assert.Nil(t, err)
+	assert.Equal(t, itemID, response.GetItem().GetId())
+	assert.Equal(t, 10.5, response.GetItem().GetPrice().GetAmount())
+	assert.Equal(t, "$", response.GetItem().GetPrice().GetCurrency().GetSymbol())
+	assert.Equal(t, []string{"S", "M"}, response.GetItem().GetEligible()[0].GetOptions())
+}
+
+func TestRPC_GetItem_Propagates_Trace_ID(t *testing.T) {
+	var traceID interface{}
+	service := items.NewItemsServiceMock()
+	service.HandleGet = func(ctx context.Context, id string) (models.Item, apierrors.ApiError) {
+		traceID = ctx.Value(tracing.XtraceHeaderKey)
+		return sampleItem(id), nil
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-trace-id", "trace-1")
+	_, err := client(t, service).GetItem(ctx, &itemspb.GetItemRequest{Id: itemID})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "trace-1", traceID)
+}
+
+func TestRPC_GetItem_Not_Found_Error(t *testing.T) {
+	service := items.NewItemsServiceMock()
+	service.HandleGet = func(ctx context.Context, id string) (models.Item, apierrors.ApiError) {
+		return models.Item{}, catalog.NotFound.New("item not found")
+	}
+
+	_, err := client(t, service).GetItem(context.Background(), &itemspb.GetItemRequest{Id: itemID})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "item not found", st.Message())
+	assert.Len(t, st.Details(), 1)
+	info := st.Details()[0].(*errdetails.ErrorInfo)
+	assert.Equal(t, catalog.NotFound.Code, info.GetReason())
+	assert.Equal(t, rpc.ErrorDomain, info.GetDomain())
+}
+
+func TestRPC_GetItem_Invalid_ID_Error(t *testing.T) {
+	_, err := client(t, items.NewItemsServiceMock()).GetItem(context.Background(), &itemspb.GetItemRequest{Id: "nope"})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRPC_GetItem_Downstream_Error(t *testing.T) {
+	service := items.NewItemsServiceMock()
+	service.HandleGet = func(ctx context.Context, id string) (models.Item, apierrors.ApiError) {
+		return models.Item{}, catalog.PricesService.New("prices is down")
+	}
+
+	_, err := client(t, service).GetItem(context.Background(), &itemspb.GetItemRequest{Id: itemID})
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestRPC_BatchGetItems_Reports_Missing_IDs(t *testing.T) {
+	var requested []string
+	service := items.NewItemsServiceMock()
+	service.HandleGetItemsByIDs = func(ctx context.Context, ids models.ItemsIds) (models.Items, apierrors.ApiError) {
+		requested = ids.Items
+		return models.Items{Items: []models.Item{sampleItem(otherItemID)}}, nil
+	}
+
+	response, err := client(t, service).BatchGetItems(context.Background(), &itemspb.BatchGetItemsRequest{
+		Ids: []string{itemID, otherItemID, itemID},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{itemID, otherItemID}, requested)
+	assert.Len(t, response.GetItems(), 1)
+	assert.Equal(t, otherItemID, response.GetItems()[0].GetId())
+	assert.Equal(t, []string{itemID}, response.GetMissingIds())
+}
+
+func TestRPC_BatchGetItems_All_Missing(t *testing.T) {
+	for _, notFound := range []apierrors.ApiError{
+		apierrors.NewNotFoundApiError("items not found"),
+		apierrors.NewNotFoundApiError("price not found"),
+	} {
+		service := items.NewItemsServiceMock()
+		service.HandleGetItemsByIDs = func(ctx context.Context, ids models.ItemsIds) (models.Items, apierrors.ApiError) {
+			return models.Items{}, notFound
+		}
+
+		response, err := client(t, service).BatchGetItems(context.Background(), &itemspb.BatchGetItemsRequest{
+			Ids: []string{itemID, otherItemID},
+		})
+
+		assert.Nil(t, err)
+		assert.Empty(t, response.GetItems())
+		assert.Equal(t, []string{itemID, otherItemID}, response.GetMissingIds())
+	}
+}
+
+func TestRPC_BatchGetItems_Empty_Request(t *testing.T) {
+	service := items.NewItemsServiceMock()
+	service.HandleGetItemsByIDs = func(ctx context.Context, ids models.ItemsIds) (models.Items, apierrors.ApiError) {
+		t.Fatal("the service must not be called without ids")
+		return models.Items{}, nil
+	}
+
+	response, err := client(t, service).BatchGetItems(context.Background(), &itemspb.BatchGetItemsRequest{})
+
+	assert.Nil(t, err)
+	assert.Empty(t, response.GetItems())
+	assert.Empty(t, response.GetMissingIds())
+}
+
+func TestRPC_BatchGetItems_Invalid_ID_Error(t *testing.T) {
+	_, err := client(t, items.NewItemsServiceMock()).BatchGetItems(context.Background(), &itemspb.BatchGetItemsRequest{
+		Ids: []string{itemID, "nope"},
+	})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func receiveAll(stream itemspb.ItemsService_ListItemsByShopClient) ([]string, error) {
+	var ids []string
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			return ids, nil
+		}
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, response.GetItem().GetId())
+	}
+}
+
+func TestRPC_ListItemsByShop_Streams_Every_Item(t *testing.T) {
+	var queries []models.ItemsQuery
+	service := items.NewItemsServiceMock()
+	service.HandleSearch = func(ctx context.Context, query models.ItemsQuery) (models.ItemsPage, apierrors.ApiError) {
+		queries = append(queries, query)
+		// two pages of one item
+		ids := []string{itemID, otherItemID}
+		return models.ItemsPage{Items: []models.Item{sampleItem(ids[len(queries)-1])}, Total: 2}, nil
+	}
+
+	stream, err := client(t, service).ListItemsByShop(context.Background(), &itemspb.ListItemsByShopRequest{ShopId: shopID})
+	assert.Nil(t, err)
+
+	ids, err := receiveAll(stream)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{itemID, otherItemID}, ids)
+	assert.Len(t, queries, 2)
+	assert.Equal(t, shopID, queries[0].ShopID)
+	assert.Equal(t, 0, queries[0].Offset)
+	assert.Equal(t, 1, queries[1].Offset)
+}
+
+func TestRPC_ListItemsByShop_Empty_Shop(t *testing.T) {
+	service := items.NewItemsServiceMock()
+	service.HandleSearch = func(ctx context.Context, query models.ItemsQuery) (models.ItemsPage, apierrors.ApiError) {
+		return models.ItemsPage{Items: []models.Item{}}, nil
+	}
+
+	stream, err := client(t, service).ListItemsByShop(context.Background(), &itemspb.ListItemsByShopRequest{ShopId: shopID})
+	assert.Nil(t, err)
+
+	ids, err := receiveAll(stream)
+
+	assert.Nil(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestRPC_ListItemsByShop_Invalid_Shop_Error(t *testing.T) {
+	stream, err := client(t, items.NewItemsServiceMock()).ListItemsByShop(context.Background(), &itemspb.ListItemsByShopRequest{ShopId: "shop"})
+	assert.Nil(t, err)
+
+	_, err = stream.Recv()
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRPC_ListItemsByShop_Missing_Shop_Error(t *testing.T) {
+	stream, err := client(t, items.NewItemsServiceMock()).ListItemsByShop(context.Background(), &itemspb.ListItemsByShopRequest{})
+	assert.Nil(t, err)
+
+	_, err = stream.Recv()
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRPC_ValidateSelection_Valid(t *testing.T) {
+	service := items.NewItemsServiceMock()
+	service.HandleGet = func(ctx context.Context, id string) (models.Item, apierrors.ApiError) {
+		return sampleItem(id), nil
+	}
+
+	response, err := client(t, service).ValidateSelection(context.Background(), &itemspb.ValidateSelectionRequest{
+		ItemId:     itemID,
+		Selections: []*itemspb.Selection{{EligibleId: "size", Option: "M"}},
+	})
+
+	assert.Nil(t, err)
+	assert.True(t, response.GetValid())
+	assert.Empty(t, response.GetViolations())
+	assert.Equal(t, 10.5, response.GetPrice().GetAmount())
+}
+
+func TestRPC_ValidateSelection_Reports_Every_Violation(t *testing.T) {
+	service := items.NewItemsServiceMock()
+	service.HandleGet = func(ctx context.Context, id string) (models.Item, apierrors.ApiError) {
+		item := sampleItem(id)
+		item.Status = "paused"
+		return item, nil
+	}
+
+	response, err := client(t, service).ValidateSelection(context.Background(), &itemspb.ValidateSelectionRequest{
+		ItemId: itemID,
+		Selections: []*itemspb.Selection{
+			{EligibleId: "color", Option: "blue"},
+			{EligibleId: "color", Option: "red"},
+			{EligibleId: "material", Option: "wool"},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.False(t, response.GetValid())
+
+	var got []string
+	for _, violation := range response.GetViolations() {
+		got = append(got, violation.GetEligibleId()+":"+violation.GetCode())
+	}
+	assert.Equal(t, []string{
+		":" + models.ViolationItemUnavailable,
+		"color:" + models.ViolationInvalidOption,
+		"color:" + models.ViolationRepeatedEligible,
+		"material:" + models.ViolationUnknownEligible,
+		"size:" + models.ViolationMissingRequired,
+	}, got)
+}
+
+func TestRPC_Health_Serving(t *testing.T) {
+	response, err := grpc_health_v1.NewHealthClient(dial(t, items.NewItemsServiceMock())).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{
+		Service: itemspb.ItemsService_ServiceDesc.ServiceName,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.GetStatus())
+}
@@ -0,0 +1,54 @@
+package catalog
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/catalog"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalog(t *testing.T) {
+	definitions := catalog.All()
+
+	for i := 1; i < len(definitions); i++ {
+		assert.Less(t, definitions[i-1].Code, definitions[i].Code)
+	}
+
+	definition, ok := catalog.Lookup("category_in_use")
+	assert.True(t, ok)
+	assert.Equal(t, catalog.CategoryInUse, definition)
+	assert.Equal(t, http.StatusConflict, definition.Status)
+
+	_, ok = catalog.Lookup("no_such_code")
+	assert.False(t, ok)
+}
+
+func TestLegacyCode(t *testing.T) {
+	assert.Equal(t, "bad_request", catalog.LegacyCode(catalog.InvalidSlug.New("the provided slug is not valid")))
+	assert.Equal(t, "3 items are using the category", catalog.LegacyCode(catalog.CategoryInUse.WithLegacy("3 items are using the category").New("in use")))
+	assert.Equal(t, "error hitting price api", catalog.LegacyCode(apierrors.NewApiError("error updating price", catalog.PricesService.Code, http.StatusServiceUnavailable, apierrors.CauseList{})))
+	assert.Equal(t, "token_expired", catalog.LegacyCode(apierrors.NewApiError("token expired", "token_expired", http.StatusUnauthorized, apierrors.CauseList{})))
+}
+
+func TestError_Marshals_Legacy_Shape(t *testing.T) {
+	body, err := json.Marshal(catalog.InvalidID.New("not a hex id"))
+	assert.Nil(t, err)
+
+	var legacy map[string]interface{}
+	assert.Nil(t, json.Unmarshal(body, &legacy))
+	assert.Equal(t, "bad_request", legacy["error"])
+	assert.Equal(t, "not a hex id", legacy["message"])
+	assert.Equal(t, float64(http.StatusBadRequest), legacy["status"])
+}
+
+func TestError_Sentinels_Compare(t *testing.T) {
+	sentinel := catalog.Conflict.New("taken")
+	var err apierrors.ApiError = sentinel
+
+	assert.True(t, err == sentinel)
+	assert.False(t, err == catalog.Conflict.New("taken"), "each error is its own value")
+}
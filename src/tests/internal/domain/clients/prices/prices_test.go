@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/agustinrabini/items-api-project/src/main/domain/clients"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
 	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/mocks"
 	"github.com/jarcoal/httpmock"
 
@@ -245,6 +246,47 @@ func TestClient_GetItemsPrices_UnMarshall_Error(t *testing.T) {
 	assert.EqualValues(t, http.StatusInternalServerError, err.Status())
 }
 
+func TestClient_ListPricedItems_Success(t *testing.T) {
+	var model = models.PricedItems{ItemIDs: []string{mocks.ItemIdOne}, Total: 3}
+	var endpoint = fmt.Sprintf("%s%s?offset=2&limit=1", clients.PricesBaseEndpoint, clients.PricesItemsPrices)
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", endpoint,
+		func(req *http.Request) (*http.Response, error) {
+			if !httpmock.HeaderExists("X-Trace-Id").Check(req) {
+				return nil, errors.New("missing X-Trace-ID header")
+			}
+
+			return httpmock.NewJsonResponse(200, model)
+		},
+	)
+
+	api := clients.NewPriceClient()
+
+	page, err := api.ListPricedItems(context.Background(), 2, 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, model, page)
+}
+
+func TestClient_ListPricedItems_Internal_Server_Error(t *testing.T) {
+	var endpoint = fmt.Sprintf("%s%s?offset=0&limit=100", clients.PricesBaseEndpoint, clients.PricesItemsPrices)
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", endpoint, httpmock.NewStringResponder(http.StatusNotFound, ""))
+
+	api := clients.NewPriceClient()
+
+	_, err := api.ListPricedItems(context.Background(), 0, 100)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, err.Status())
+}
+
 func TestClient_CreatePrice_Success(t *testing.T) {
 	var model = mocks.Price
 
@@ -14,6 +14,7 @@ type PriceClientMock struct {
 	HandleModifyPrice      func(ctx context.Context, price *models.Price) apierrors.ApiError
 	HandleGetItemsPrices   func(ctx context.Context, itemsIDs []string) (models.Prices, apierrors.ApiError)
 	HandleDeletePrice      func(ctx context.Context, priceID string) apierrors.ApiError
+	HandleListPricedItems  func(ctx context.Context, offset int, limit int) (models.PricedItems, apierrors.ApiError)
 }
 
 func NewPriceClientMock() PriceClientMock {
@@ -54,3 +55,10 @@ func (mock PriceClientMock) DeletePrice(ctx context.Context, priceID string) api
 	}
 	return nil
 }
+
+func (mock PriceClientMock) ListPricedItems(ctx context.Context, offset int, limit int) (models.PricedItems, apierrors.ApiError) {
+	if mock.HandleListPricedItems != nil {
+		return mock.HandleListPricedItems(ctx, offset, limit)
+	}
+	return models.PricedItems{ItemIDs: []string{}}, nil
+}
@@ -10,8 +10,11 @@ import (
 	"github.com/agustinrabini/items-api-project/src/main/domain/clients"
 	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/mocks"
 	"github.com/jarcoal/httpmock"
+	"github.com/jopitnow/go-jopit-toolkit/tracing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 // ================================== Get Shop By UserID Tests ==================================
@@ -23,7 +26,7 @@ func TestClient_GetShopByUserID_Success(t *testing.T) {
 
 	defer httpmock.DeactivateAndReset()
 
-	httpmock.RegisterResponder("GET", config.InternalBaseShopsClient+clients.ShopsBaseEndpoint,
+	httpmock.RegisterResponder("GET", config.ConfMap.ShopsBaseURL+clients.ShopsBaseEndpoint,
 		func(req *http.Request) (*http.Response, error) {
 			if !httpmock.HeaderExists("Authorization").Check(req) {
 				return nil, errors.New("missing Authorization header")
@@ -47,12 +50,42 @@ func TestClient_GetShopByUserID_Success(t *testing.T) {
 	assert.Equal(t, model, body)
 }
 
+func TestClient_GetShopByUserID_Propagates_Trace(t *testing.T) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	ctx, span := otel.Tracer("test").Start(context.WithValue(context.Background(), tracing.XtraceHeaderKey, "trace-1"), "test")
+	defer span.End()
+
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", config.ConfMap.ShopsBaseURL+clients.ShopsBaseEndpoint,
+		func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("X-Trace-ID") != "trace-1" {
+				return nil, errors.New("wrong X-Trace-ID header")
+			}
+
+			if req.Header.Get("traceparent") == "" {
+				return nil, errors.New("missing traceparent header")
+			}
+
+			return httpmock.NewJsonResponse(200, mocks.Shop)
+		},
+	)
+
+	api := clients.NewShopClient()
+
+	_, err := api.GetShopByUserID(ctx)
+
+	assert.Nil(t, err)
+}
+
 func TestClient_GetShopByUserID_Response_Error(t *testing.T) {
 	httpmock.Activate()
 
 	defer httpmock.DeactivateAndReset()
 
-	httpmock.RegisterResponder("GET", config.InternalBaseShopsClient+clients.ShopsBaseEndpoint,
+	httpmock.RegisterResponder("GET", config.ConfMap.ShopsBaseURL+clients.ShopsBaseEndpoint,
 		func(req *http.Request) (*http.Response, error) {
 			return nil, errors.New("response error")
 		},
@@ -72,7 +105,7 @@ func TestClient_GetShopByUserID_Not_Found_Error(t *testing.T) {
 
 	defer httpmock.DeactivateAndReset()
 
-	httpmock.RegisterResponder("GET", config.InternalBaseShopsClient+clients.ShopsBaseEndpoint,
+	httpmock.RegisterResponder("GET", config.ConfMap.ShopsBaseURL+clients.ShopsBaseEndpoint,
 		func(req *http.Request) (*http.Response, error) {
 			return httpmock.NewJsonResponse(404, nil)
 		},
@@ -92,7 +125,7 @@ func TestClient_GetShopByUserID_Internal_Server_Error(t *testing.T) {
 
 	defer httpmock.DeactivateAndReset()
 
-	httpmock.RegisterResponder("GET", config.InternalBaseShopsClient+clients.ShopsBaseEndpoint,
+	httpmock.RegisterResponder("GET", config.ConfMap.ShopsBaseURL+clients.ShopsBaseEndpoint,
 		func(req *http.Request) (*http.Response, error) {
 			return httpmock.NewJsonResponse(505, nil)
 		},
@@ -112,7 +145,7 @@ func TestClient_GetShopByUserID_UnMarshall_Error(t *testing.T) {
 
 	defer httpmock.DeactivateAndReset()
 
-	httpmock.RegisterResponder("GET", config.InternalBaseShopsClient+clients.ShopsBaseEndpoint,
+	httpmock.RegisterResponder("GET", config.ConfMap.ShopsBaseURL+clients.ShopsBaseEndpoint,
 		func(req *http.Request) (*http.Response, error) {
 			return httpmock.NewJsonResponse(200, "fake")
 		},
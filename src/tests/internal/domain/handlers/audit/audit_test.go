@@ -0,0 +1,81 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/handlers"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+	"github.com/agustinrabini/items-api-project/src/main/domain/services"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/repositories/audit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func search(repository audit.RepositoryMock, query string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/audit", handlers.NewAuditHandler(services.NewAuditService(repository)).SearchAudit)
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/admin/audit"+query, nil))
+	return response
+}
+
+func TestHandler_SearchAudit_Filters(t *testing.T) {
+	repository := audit.NewRepositoryMock()
+	repository.HandleSearch = func(ctx context.Context, query models.AuditQuery) (models.AuditPage, apierrors.ApiError) {
+		assert.Equal(t, models.AuditQuery{
+			Actor:        "admin",
+			ClaimedActor: "alice",
+			Action:       "category.update",
+			TargetType:   "category",
+			TargetID:     "65f1c2",
+			From:         time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+			To:           time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+			Limit:        10,
+			Offset:       20,
+		}, query)
+		return models.AuditPage{Entries: []models.AuditEntry{{Action: "category.update"}}, Total: 21}, nil
+	}
+
+	response := search(repository, "?actor=admin&claimed_actor=alice&action=category.update&target=category/65f1c2&from=2026-10-01T00:00:00Z&to=2026-10-19T14:00:00%2B02:00&limit=10&offset=20")
+
+	var page dto.AuditPageDTO
+	err := json.Unmarshal(response.Body.Bytes(), &page)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(21), page.Total)
+	assert.Equal(t, 10, page.Limit)
+	assert.Len(t, page.Entries, 1)
+}
+
+func TestHandler_SearchAudit_Target_Type(t *testing.T) {
+	repository := audit.NewRepositoryMock()
+	repository.HandleSearch = func(ctx context.Context, query models.AuditQuery) (models.AuditPage, apierrors.ApiError) {
+		assert.Equal(t, "item", query.TargetType)
+		assert.Empty(t, query.TargetID)
+		assert.Equal(t, models.DefaultAuditPageSize, query.Limit)
+		return models.AuditPage{Entries: []models.AuditEntry{}}, nil
+	}
+
+	response := search(repository, "?target=item")
+
+	assert.Equal(t, http.StatusOK, response.Code)
+}
+
+func TestHandler_SearchAudit_Invalid_Query(t *testing.T) {
+	for _, query := range []string{"?from=yesterday", "?limit=1000", "?from=2026-10-19T00:00:00Z&to=2026-10-01T00:00:00Z"} {
+		response := search(audit.NewRepositoryMock(), query)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code, query)
+	}
+}
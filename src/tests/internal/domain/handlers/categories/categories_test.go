@@ -15,6 +15,7 @@ import (
 	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/services/categories"
 	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/services/items"
 	"github.com/agustinrabini/items-api-project/src/tests/internal/setup"
+	"github.com/gin-gonic/gin"
 	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -39,6 +40,22 @@ func TestHandler_Get_Success(t *testing.T) {
 	assert.Equal(t, mocks.CategoryOne, result)
 }
 
+func TestHandler_Get_Passes_Request_Context(t *testing.T) {
+	service := categories.NewServiceMock()
+	service.HandleGet = func(ctx context.Context, categoryID string) (models.Category, apierrors.ApiError) {
+		_, isGin := ctx.(*gin.Context)
+		assert.False(t, isGin)
+		return mocks.CategoryOne, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	depend.Categories = handlers.NewCategoriesHandler(service, nil)
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/category/"+primitive.NewObjectID().Hex(), nil, "")
+
+	assert.Equal(t, http.StatusOK, response.Code)
+}
+
 func TestHandler_Get_Not_Found_Error(t *testing.T) {
 	service := categories.NewServiceMock()
 	service.HandleGet = func(ctx context.Context, categoryID string) (models.Category, apierrors.ApiError) {
@@ -224,6 +241,34 @@ func TestHandler_CreateCategory_Bad_Request_Empty_Name_Error(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, apiError.ErrorStatus)
 }
 
+func TestHandler_CreateCategory_Bad_Request_Locales(t *testing.T) {
+	for _, translations := range []models.Translations{{"fr": "Chaussures"}, {models.DefaultLocale: "Calzado"}} {
+		var model = mocks.CategoryOne
+		model.NameI18n = translations
+
+		service := categories.NewServiceMock()
+		service.HandleCreate = func(ctx context.Context, input models.Category) apierrors.ApiError {
+			t.Fatal("the category must not be created")
+			return nil
+		}
+
+		var depend dependencies.HandlersStruct
+		handler := handlers.NewCategoriesHandler(service, nil)
+		depend.Categories = handler
+
+		response := setup.ExecuteRequest(setup.BuildRouter(depend), "POST", "/items/category", nil, mocks.CategoryToJson(model))
+
+		var apiError mocks.ApiError
+		err := json.Unmarshal(response.Body.Bytes(), &apiError)
+		if err != nil {
+			panic("Cannot decode error response body.")
+		}
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Contains(t, apiError.ErrorMessage, "name_i18n: ")
+	}
+}
+
 func TestHandler_CreateCategory_Internal_Server_Error(t *testing.T) {
 	service := categories.NewServiceMock()
 	service.HandleCreate = func(ctx context.Context, input models.Category) apierrors.ApiError {
@@ -254,8 +299,8 @@ func TestHandler_UpdateCategory_Success(t *testing.T) {
 	}
 
 	service := categories.NewServiceMock()
-	service.HandleUpdate = func(ctx context.Context, input models.Category) apierrors.ApiError {
-		return nil
+	service.HandleUpdate = func(ctx context.Context, input models.Category) (models.Category, apierrors.ApiError) {
+		return input, nil
 	}
 
 	var depend dependencies.HandlersStruct
@@ -275,8 +320,8 @@ func TestHandler_UpdateCategory_Bad_Request_Parse_Error(t *testing.T) {
 	}
 
 	service := categories.NewServiceMock()
-	service.HandleUpdate = func(ctx context.Context, input models.Category) apierrors.ApiError {
-		return nil
+	service.HandleUpdate = func(ctx context.Context, input models.Category) (models.Category, apierrors.ApiError) {
+		return input, nil
 	}
 
 	var depend dependencies.HandlersStruct
@@ -350,6 +395,27 @@ func TestHandler_UpdateCategory_Bad_Request_Empty_Name_Error(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, apiError.ErrorStatus)
 }
 
+func TestHandler_UpdateCategory_Bad_Request_Locales(t *testing.T) {
+	for _, translations := range []models.Translations{{"fr": "Chaussures"}, {models.DefaultLocale: "Calzado"}} {
+		var model = mocks.CategoryOne
+		model.NameI18n = translations
+
+		service := categories.NewServiceMock()
+		service.HandleUpdate = func(ctx context.Context, input models.Category) (models.Category, apierrors.ApiError) {
+			t.Fatal("the category must not be updated")
+			return input, nil
+		}
+
+		var depend dependencies.HandlersStruct
+		handler := handlers.NewCategoriesHandler(service, items.NewItemsServiceMock())
+		depend.Categories = handler
+
+		response := setup.ExecuteRequest(setup.BuildRouter(depend), "PUT", "/items/category", nil, mocks.CategoryToJson(model))
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	}
+}
+
 func TestHandler_UpdateCategory_Internal_Server_Error(t *testing.T) {
 
 	itemsServiceMock := items.NewItemsServiceMock()
@@ -358,8 +424,8 @@ func TestHandler_UpdateCategory_Internal_Server_Error(t *testing.T) {
 	}
 
 	service := categories.NewServiceMock()
-	service.HandleUpdate = func(ctx context.Context, input models.Category) apierrors.ApiError {
-		return apierrors.NewInternalServerApiError("mock error", fmt.Errorf("mock error"))
+	service.HandleUpdate = func(ctx context.Context, input models.Category) (models.Category, apierrors.ApiError) {
+		return models.Category{}, apierrors.NewInternalServerApiError("mock error", fmt.Errorf("mock error"))
 	}
 
 	var depend dependencies.HandlersStruct
@@ -386,8 +452,8 @@ func TestHandler_UpdateCategory_Item_Internal_Service_Error(t *testing.T) {
 	}
 
 	service := categories.NewServiceMock()
-	service.HandleUpdate = func(ctx context.Context, input models.Category) apierrors.ApiError {
-		return apierrors.NewInternalServerApiError("mock error", fmt.Errorf("mock error"))
+	service.HandleUpdate = func(ctx context.Context, input models.Category) (models.Category, apierrors.ApiError) {
+		return models.Category{}, apierrors.NewInternalServerApiError("mock error", fmt.Errorf("mock error"))
 	}
 
 	var depend dependencies.HandlersStruct
@@ -409,12 +475,12 @@ func TestHandler_UpdateCategory_Item_Internal_Service_Error(t *testing.T) {
 func TestHandler_DeleteCategory_Success(t *testing.T) {
 
 	itemsServiceMock := items.NewItemsServiceMock()
-	itemsServiceMock.HandleGetItemsByShopCategoryID = func(ctx context.Context, shopID, categoryID string) (models.Items, apierrors.ApiError) {
-		return mocks.ItemsMock, nil
+	itemsServiceMock.HandleCountByCategoryID = func(ctx context.Context, categoryID string) (int64, apierrors.ApiError) {
+		return 0, nil
 	}
 
 	service := categories.NewServiceMock()
-	service.HandleDelete = func(ctx context.Context, items []models.Item, categoryID string) apierrors.ApiError {
+	service.HandleDelete = func(ctx context.Context, itemsCount int64, categoryID string) apierrors.ApiError {
 		return nil
 	}
 
@@ -430,12 +496,12 @@ func TestHandler_DeleteCategory_Success(t *testing.T) {
 func TestHandler_DeleteCategory_Success_Conflict_Error(t *testing.T) {
 
 	itemsServiceMock := items.NewItemsServiceMock()
-	itemsServiceMock.HandleGetItemsByShopCategoryID = func(ctx context.Context, shopID, categoryID string) (models.Items, apierrors.ApiError) {
-		return mocks.ItemsMock, nil
+	itemsServiceMock.HandleCountByCategoryID = func(ctx context.Context, categoryID string) (int64, apierrors.ApiError) {
+		return 0, nil
 	}
 
 	service := categories.NewServiceMock()
-	service.HandleDelete = func(ctx context.Context, items []models.Item, categoryID string) apierrors.ApiError {
+	service.HandleDelete = func(ctx context.Context, itemsCount int64, categoryID string) apierrors.ApiError {
 		return apierrors.NewApiError("mock err", "mock err", http.StatusConflict, apierrors.CauseList{})
 	}
 
@@ -451,12 +517,12 @@ func TestHandler_DeleteCategory_Success_Conflict_Error(t *testing.T) {
 func TestHandler_DeleteCategory_InternalError(t *testing.T) {
 
 	itemsServiceMock := items.NewItemsServiceMock()
-	itemsServiceMock.HandleGetItemsByShopCategoryID = func(ctx context.Context, shopID, categoryID string) (models.Items, apierrors.ApiError) {
-		return mocks.ItemsMock, nil
+	itemsServiceMock.HandleCountByCategoryID = func(ctx context.Context, categoryID string) (int64, apierrors.ApiError) {
+		return 0, nil
 	}
 
 	service := categories.NewServiceMock()
-	service.HandleDelete = func(ctx context.Context, items []models.Item, categoryID string) apierrors.ApiError {
+	service.HandleDelete = func(ctx context.Context, itemsCount int64, categoryID string) apierrors.ApiError {
 		return apierrors.NewInternalServerApiError("mock error", fmt.Errorf("mock error"))
 	}
 
@@ -502,3 +568,285 @@ func TestHandler_DeleteCategory_Bad_Request_Invalid_Hex_ID(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, response.Code)
 	assert.Equal(t, http.StatusBadRequest, apiError.ErrorStatus)
 }
+
+func TestHandler_Get_Localized_Success(t *testing.T) {
+	var category = mocks.CategoryOne
+	category.NameI18n = models.Translations{"pt": "Nome Um"}
+
+	service := categories.NewServiceMock()
+	service.HandleGet = func(ctx context.Context, categoryID string) (models.Category, apierrors.ApiError) {
+		return category, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewCategoriesHandler(service, nil)
+	depend.Categories = handler
+
+	var result models.Category
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/category/"+primitive.NewObjectID().Hex(), map[string]string{"Accept-Language": "pt-BR,pt;q=0.9,es;q=0.8"}, "")
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "pt", response.Header().Get("Content-Language"))
+	assert.Equal(t, "Nome Um", result.Name)
+}
+
+func TestHandler_GetAllCategories_Localized_Fallback_Success(t *testing.T) {
+	var category = mocks.CategoryOne
+	category.NameI18n = models.Translations{"pt": "Nome Um"}
+
+	service := categories.NewServiceMock()
+	service.HandleGetAllCategories = func(ctx context.Context) ([]models.Category, apierrors.ApiError) {
+		return []models.Category{category}, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewCategoriesHandler(service, nil)
+	depend.Categories = handler
+
+	var result dto.CategoriesDTO
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/categories?lang=fr", nil, "")
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, models.DefaultLocale, response.Header().Get("Content-Language"))
+	assert.Equal(t, mocks.CategoryNameOne, result.CategoryDTO[0].Name)
+}
+
+func TestHandler_SetCategoryTranslation_Success(t *testing.T) {
+	itemsServiceMock := items.NewItemsServiceMock()
+	itemsServiceMock.HandleUpdateItemsCategories = func(ctx context.Context, category models.Category) apierrors.ApiError {
+		return apierrors.NewNotFoundApiError("no items using the category")
+	}
+
+	service := categories.NewServiceMock()
+	service.HandleSetTranslation = func(ctx context.Context, categoryID string, locale string, name string) (models.Category, apierrors.ApiError) {
+		return mocks.CategoryOne, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewCategoriesHandler(service, itemsServiceMock)
+	depend.Categories = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "PUT", "/items/category/"+mocks.CategoryOne.ID+"/translations/pt", nil, `{"name":"Nome Um"}`)
+
+	assert.Equal(t, http.StatusNoContent, response.Code)
+}
+
+func TestHandler_SetCategoryTranslation_Bad_Request_Default_Locale(t *testing.T) {
+	service := categories.NewServiceMock()
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewCategoriesHandler(service, nil)
+	depend.Categories = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "PUT", "/items/category/"+mocks.CategoryOne.ID+"/translations/"+models.DefaultLocale, nil, `{"name":"Nombre"}`)
+
+	var apiError mocks.ApiError
+	err := json.Unmarshal(response.Body.Bytes(), &apiError)
+	if err != nil {
+		panic("Cannot decode error response body.")
+	}
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Equal(t, http.StatusBadRequest, apiError.ErrorStatus)
+}
+
+func TestHandler_SetCategoryTranslation_Items_Internal_Server_Error(t *testing.T) {
+	itemsServiceMock := items.NewItemsServiceMock()
+	itemsServiceMock.HandleUpdateItemsCategories = func(ctx context.Context, category models.Category) apierrors.ApiError {
+		return apierrors.NewInternalServerApiError("mock error", fmt.Errorf("mock error"))
+	}
+
+	service := categories.NewServiceMock()
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewCategoriesHandler(service, itemsServiceMock)
+	depend.Categories = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "PUT", "/items/category/"+mocks.CategoryOne.ID+"/translations/pt", nil, `{"name":"Nome Um"}`)
+
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+}
+
+func TestHandler_DeleteCategoryTranslation_Success(t *testing.T) {
+	itemsServiceMock := items.NewItemsServiceMock()
+	service := categories.NewServiceMock()
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewCategoriesHandler(service, itemsServiceMock)
+	depend.Categories = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "DELETE", "/items/category/"+mocks.CategoryOne.ID+"/translations/pt", nil, "")
+
+	assert.Equal(t, http.StatusNoContent, response.Code)
+}
+
+func TestHandler_DeleteCategoryTranslation_Not_Found_Error(t *testing.T) {
+	service := categories.NewServiceMock()
+	service.HandleDeleteTranslation = func(ctx context.Context, categoryID string, locale string) (models.Category, apierrors.ApiError) {
+		return models.Category{}, apierrors.NewNotFoundApiError("mock error")
+	}
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewCategoriesHandler(service, nil)
+	depend.Categories = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "DELETE", "/items/category/"+mocks.CategoryOne.ID+"/translations/pt", nil, "")
+
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestHandler_DeleteCategory_Count_Internal_Server_Error(t *testing.T) {
+	itemsServiceMock := items.NewItemsServiceMock()
+	itemsServiceMock.HandleCountByCategoryID = func(ctx context.Context, categoryID string) (int64, apierrors.ApiError) {
+		return -1, apierrors.NewInternalServerApiError("mock error", fmt.Errorf("mock error"))
+	}
+
+	service := categories.NewServiceMock()
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewCategoriesHandler(service, itemsServiceMock)
+	depend.Categories = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "DELETE", "/items/category/"+primitive.NewObjectID().Hex(), nil, "")
+
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+}
+
+func TestHandler_GetAllCategories_With_Counts_Success(t *testing.T) {
+	itemsServiceMock := items.NewItemsServiceMock()
+	itemsServiceMock.HandleCountByCategory = func(ctx context.Context, shopID string) ([]models.CategoryCount, apierrors.ApiError) {
+		assert.Equal(t, "", shopID)
+		return []models.CategoryCount{{Category: mocks.CategoryOne, ItemsCount: 132}}, nil
+	}
+
+	service := categories.NewServiceMock()
+	service.HandleGetAllCategories = func(ctx context.Context) ([]models.Category, apierrors.ApiError) {
+		return []models.Category{mocks.CategoryOne, mocks.CategoryTwo}, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewCategoriesHandler(service, itemsServiceMock)
+	depend.Categories = handler
+
+	var result dto.CategoriesCountDTO
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/categories?with_counts=true", nil, "")
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, []models.CategoryCount{
+		{Category: mocks.CategoryOne, ItemsCount: 132},
+		{Category: mocks.CategoryTwo, ItemsCount: 0},
+	}, result.CategoryCountDTO)
+}
+
+func TestHandler_GetAllCategories_With_Counts_Internal_Server_Error(t *testing.T) {
+	itemsServiceMock := items.NewItemsServiceMock()
+	itemsServiceMock.HandleCountByCategory = func(ctx context.Context, shopID string) ([]models.CategoryCount, apierrors.ApiError) {
+		return nil, apierrors.NewInternalServerApiError("mock error", fmt.Errorf("mock error"))
+	}
+
+	service := categories.NewServiceMock()
+	service.HandleGetAllCategories = func(ctx context.Context) ([]models.Category, apierrors.ApiError) {
+		return []models.Category{mocks.CategoryOne}, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewCategoriesHandler(service, itemsServiceMock)
+	depend.Categories = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/categories?with_counts=true", nil, "")
+
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+}
+
+func TestHandler_GetShopCategories_Success(t *testing.T) {
+	itemsServiceMock := items.NewItemsServiceMock()
+	itemsServiceMock.HandleCountByCategory = func(ctx context.Context, shopID string) ([]models.CategoryCount, apierrors.ApiError) {
+		assert.Equal(t, mocks.ShopIDOne, shopID)
+		return []models.CategoryCount{{Category: mocks.CategoryOne, ItemsCount: 3}}, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewCategoriesHandler(nil, itemsServiceMock)
+	depend.Categories = handler
+
+	var result dto.CategoriesCountDTO
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/shop/"+mocks.ShopIDOne+"/categories", nil, "")
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, []models.CategoryCount{{Category: mocks.CategoryOne, ItemsCount: 3}}, result.CategoryCountDTO)
+}
+
+func TestHandler_GetShopCategories_Bad_Request_Invalid_Hex(t *testing.T) {
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewCategoriesHandler(nil, items.NewItemsServiceMock())
+	depend.Categories = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/shop/a/categories", nil, "")
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestHandler_GetBySlug_Success(t *testing.T) {
+	service := categories.NewServiceMock()
+	service.HandleGetBySlug = func(ctx context.Context, slug string) (models.Category, apierrors.ApiError) {
+		return models.Category{ID: mocks.CategoryIDOne, Name: "Zapatos", Slug: "zapatos"}, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	depend.Categories = handlers.NewCategoriesHandler(service, nil)
+
+	var result models.Category
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/category/by-slug/zapatos", nil, "")
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, mocks.CategoryIDOne, result.ID)
+}
+
+func TestHandler_GetBySlug_Old_Slug_Redirect(t *testing.T) {
+	service := categories.NewServiceMock()
+	service.HandleGetBySlug = func(ctx context.Context, slug string) (models.Category, apierrors.ApiError) {
+		return models.Category{ID: mocks.CategoryIDOne, Name: "Calzado", Slug: "calzado"}, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	depend.Categories = handlers.NewCategoriesHandler(service, nil)
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/category/by-slug/zapatos", nil, "")
+
+	assert.Equal(t, http.StatusMovedPermanently, response.Code)
+	assert.Equal(t, "/items/category/by-slug/calzado", response.Header().Get("Location"))
+}
+
+func TestHandler_Update_Cascades_Stored_Category(t *testing.T) {
+	var cascaded models.Category
+
+	service := categories.NewServiceMock()
+	service.HandleUpdate = func(ctx context.Context, input models.Category) (models.Category, apierrors.ApiError) {
+		input.Slug = "calzado"
+		return input, nil
+	}
+
+	itemsService := items.NewItemsServiceMock()
+	itemsService.HandleUpdateItemsCategories = func(ctx context.Context, category models.Category) apierrors.ApiError {
+		cascaded = category
+		return nil
+	}
+
+	var depend dependencies.HandlersStruct
+	depend.Categories = handlers.NewCategoriesHandler(service, itemsService)
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "PUT", "/items/category", nil, `{"id":"`+mocks.CategoryIDOne+`","name":"Calzado"}`)
+
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	assert.Equal(t, "calzado", cascaded.Slug)
+}
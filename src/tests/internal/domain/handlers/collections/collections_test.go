@@ -0,0 +1,177 @@
+package collections
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/dependencies"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/problems"
+	"github.com/agustinrabini/items-api-project/src/main/domain/handlers"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/mocks"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/services/collections"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/setup"
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_GetCollection_Success(t *testing.T) {
+	service := collections.NewServiceMock()
+	service.HandleGet = func(ctx context.Context, collectionID string) (models.CollectionWithItems, apierrors.ApiError) {
+		return models.CollectionWithItems{Collection: mocks.CollectionOne, Items: []models.Item{mocks.ItemMockTwo, mocks.ItemMockOne}}, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	depend.Collections = handlers.NewCollectionsHandler(service)
+
+	var result models.CollectionWithItems
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/collections/"+mocks.CollectionIdOne+"?lang=pt", nil, "")
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "Novidades", result.Name)
+	assert.Equal(t, mocks.ItemIdTwo, result.Items[0].ID)
+}
+
+func TestHandler_GetCollection_Bad_Request_Invalid_Hex(t *testing.T) {
+	var depend dependencies.HandlersStruct
+	depend.Collections = handlers.NewCollectionsHandler(collections.NewServiceMock())
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/collections/a", nil, "")
+
+	var apiError mocks.ApiError
+	err := json.Unmarshal(response.Body.Bytes(), &apiError)
+	if err != nil {
+		panic("Cannot decode error response body.")
+	}
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Equal(t, http.StatusBadRequest, apiError.ErrorStatus)
+}
+
+func TestHandler_GetCollection_Not_Found_Error(t *testing.T) {
+	service := collections.NewServiceMock()
+	service.HandleGet = func(ctx context.Context, collectionID string) (models.CollectionWithItems, apierrors.ApiError) {
+		return models.CollectionWithItems{}, apierrors.NewNotFoundApiError("mock error")
+	}
+
+	var depend dependencies.HandlersStruct
+	depend.Collections = handlers.NewCollectionsHandler(service)
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/collections/"+mocks.CollectionIdOne, nil, "")
+
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestHandler_GetShopCollections_Success(t *testing.T) {
+	service := collections.NewServiceMock()
+	service.HandleGetByShopID = func(ctx context.Context, shopID string) ([]models.Collection, apierrors.ApiError) {
+		return mocks.Collections, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	depend.Collections = handlers.NewCollectionsHandler(service)
+
+	var result []models.Collection
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/shop/"+mocks.ShopIDOne+"/collections", nil, "")
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Len(t, result, 2)
+}
+
+func TestHandler_CreateCollection_Success(t *testing.T) {
+	service := collections.NewServiceMock()
+	service.HandleCreate = func(ctx context.Context, userID string, input dto.CollectionDTO) (interface{}, apierrors.ApiError) {
+		return mocks.CollectionIdOne, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	depend.Collections = handlers.NewCollectionsHandler(service)
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "POST", "/items/collections", nil, mocks.CollectionDTOToJson())
+
+	assert.Equal(t, http.StatusCreated, response.Code)
+}
+
+func TestHandler_CreateCollection_Bad_Request_Missing_Name(t *testing.T) {
+	var depend dependencies.HandlersStruct
+	depend.Collections = handlers.NewCollectionsHandler(collections.NewServiceMock())
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "POST", "/items/collections", nil, `{"position":1}`)
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestHandler_CreateCollection_Bad_Request_Problem_Details(t *testing.T) {
+	var depend dependencies.HandlersStruct
+	depend.Collections = handlers.NewCollectionsHandler(collections.NewServiceMock())
+
+	headers := map[string]string{"Accept": problems.MIMEProblemJSON}
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "POST", "/items/collections", headers, `{"position":1}`)
+
+	var problem problems.Problem
+	err := json.Unmarshal(response.Body.Bytes(), &problem)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Equal(t, problems.MIMEProblemJSON, response.Header().Get("Content-Type"))
+	assert.Equal(t, "validation_failed", problem.Code)
+	assert.Equal(t, "/items/collections", problem.Instance)
+	assert.Equal(t, []problems.FieldError{{Path: "$.name", Code: "required", Detail: "is required"}}, problem.Errors)
+}
+
+func TestHandler_UpdateCollection_Forbidden_Error(t *testing.T) {
+	service := collections.NewServiceMock()
+	service.HandleUpdate = func(ctx context.Context, userID string, collectionID string, input dto.CollectionDTO) apierrors.ApiError {
+		return apierrors.NewApiError("mock error", "forbidden", http.StatusForbidden, apierrors.CauseList{})
+	}
+
+	var depend dependencies.HandlersStruct
+	depend.Collections = handlers.NewCollectionsHandler(service)
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "PUT", "/items/collections/"+mocks.CollectionIdOne, nil, mocks.CollectionDTOToJson())
+
+	assert.Equal(t, http.StatusForbidden, response.Code)
+}
+
+func TestHandler_SetCollectionItems_Success(t *testing.T) {
+	var received []string
+
+	service := collections.NewServiceMock()
+	service.HandleSetItems = func(ctx context.Context, userID string, collectionID string, itemIDs []string) apierrors.ApiError {
+		received = itemIDs
+		return nil
+	}
+
+	var depend dependencies.HandlersStruct
+	depend.Collections = handlers.NewCollectionsHandler(service)
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "PUT", "/items/collections/"+mocks.CollectionIdOne+"/items", nil, mocks.ItemsIdsToJson())
+
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	assert.Equal(t, mocks.ItemIds.Items, received)
+}
+
+func TestHandler_SetCollectionItems_Bad_Request_Invalid_Item_Hex(t *testing.T) {
+	var depend dependencies.HandlersStruct
+	depend.Collections = handlers.NewCollectionsHandler(collections.NewServiceMock())
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "PUT", "/items/collections/"+mocks.CollectionIdOne+"/items", nil, `{"items":["a"]}`)
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestHandler_DeleteCollection_Success(t *testing.T) {
+	var depend dependencies.HandlersStruct
+	depend.Collections = handlers.NewCollectionsHandler(collections.NewServiceMock())
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "DELETE", "/items/collections/"+mocks.CollectionIdOne, nil, "")
+
+	assert.Equal(t, http.StatusNoContent, response.Code)
+}
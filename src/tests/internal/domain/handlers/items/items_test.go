@@ -243,6 +243,44 @@ func TestHandler_GetItemsByShopID_Bad_Request_Invalid_Hex(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, apiError.ErrorStatus)
 }
 
+func TestHandler_GetItemsPageByShopID_Success(t *testing.T) {
+	shopID := primitive.NewObjectID().Hex()
+
+	var query models.ItemsQuery
+	service := items.NewItemsServiceMock()
+	service.HandleSearch = func(ctx context.Context, q models.ItemsQuery) (models.ItemsPage, apierrors.ApiError) {
+		query = q
+		return models.ItemsPage{Items: mocks.ItemsMock.Items, Total: 42}, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	depend.Items = handlers.NewItemsHandler(service, nil)
+
+	var result dto.ItemsPageDTO
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/v2/items/shop/"+shopID+"?limit=10&offset=30", nil, "")
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Nil(t, err)
+	assert.Equal(t, models.ItemsQuery{ShopID: shopID, Limit: 10, Offset: 30}, query)
+	assert.EqualValues(t, 42, result.Total)
+	assert.Equal(t, 10, result.Limit)
+	assert.Equal(t, 30, result.Offset)
+	assert.Len(t, result.Items, len(mocks.ItemsMock.Items))
+}
+
+func TestHandler_GetItemsPageByShopID_Bad_Request(t *testing.T) {
+	var depend dependencies.HandlersStruct
+	depend.Items = handlers.NewItemsHandler(items.NewItemsServiceMock(), nil)
+
+	shopID := primitive.NewObjectID().Hex()
+	for _, path := range []string{"/v2/items/shop/a", "/v2/items/shop/" + shopID + "?limit=101", "/v2/items/shop/" + shopID + "?offset=-1"} {
+		response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", path, nil, "")
+
+		assert.Equal(t, http.StatusBadRequest, response.Code, path)
+	}
+}
+
 func TestHandler_GetItemsByShopCategoryID_Success(t *testing.T) {
 
 	service := items.NewItemsServiceMock()
@@ -550,6 +588,94 @@ func TestHandler_CreateItem_Internal_Server_Error(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, apiError.ErrorStatus)
 }
 
+// itemWithTranslations is the body of ItemMockOne with translations.
+func itemWithTranslations(name models.Translations, description models.Translations) string {
+	item := mocks.ItemMockOne
+	item.NameI18n = name
+	item.DescriptionI18n = description
+
+	bytes, _ := json.Marshal(item)
+	return string(bytes)
+}
+
+func TestHandler_CreateItem_Bad_Request_Unsupported_Locale(t *testing.T) {
+	service := items.NewItemsServiceMock()
+	service.HandleCreateItem = func(ctx context.Context, itemRequest dto.ItemDTO) (interface{}, apierrors.ApiError) {
+		t.Fatal("the item must not be created")
+		return nil, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewItemsHandler(service, categories.NewServiceMock())
+	depend.Items = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "POST", "/items", nil, itemWithTranslations(nil, models.Translations{"fr": "Bottes"}))
+
+	var apiError mocks.ApiError
+	err := json.Unmarshal(response.Body.Bytes(), &apiError)
+	if err != nil {
+		panic("Cannot decode error response body.")
+	}
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Equal(t, "description_i18n: unsupported locale fr", apiError.ErrorMessage)
+}
+
+func TestHandler_CreateItem_Bad_Request_Default_Locale(t *testing.T) {
+	service := items.NewItemsServiceMock()
+	service.HandleCreateItem = func(ctx context.Context, itemRequest dto.ItemDTO) (interface{}, apierrors.ApiError) {
+		t.Fatal("the item must not be created")
+		return nil, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewItemsHandler(service, categories.NewServiceMock())
+	depend.Items = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "POST", "/items", nil, itemWithTranslations(models.Translations{"pt": "Botas", models.DefaultLocale: "Botas"}, nil))
+
+	var apiError mocks.ApiError
+	err := json.Unmarshal(response.Body.Bytes(), &apiError)
+	if err != nil {
+		panic("Cannot decode error response body.")
+	}
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, apiError.ErrorMessage, "name_i18n: the default locale")
+}
+
+func TestHandler_UpdateItem_Bad_Request_Unsupported_Locale(t *testing.T) {
+	service := items.NewItemsServiceMock()
+	service.HandleUpdate = func(ctx context.Context, itemID string, itemRequest dto.ItemDTO) apierrors.ApiError {
+		t.Fatal("the item must not be updated")
+		return nil
+	}
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewItemsHandler(service, categories.NewServiceMock())
+	depend.Items = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "PUT", "/items/"+primitive.NewObjectID().Hex(), nil, itemWithTranslations(models.Translations{"fr": "Bottes"}, nil))
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestHandler_UpdateItem_Bad_Request_Default_Locale(t *testing.T) {
+	service := items.NewItemsServiceMock()
+	service.HandleUpdate = func(ctx context.Context, itemID string, itemRequest dto.ItemDTO) apierrors.ApiError {
+		t.Fatal("the item must not be updated")
+		return nil
+	}
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewItemsHandler(service, categories.NewServiceMock())
+	depend.Items = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "PUT", "/items/"+primitive.NewObjectID().Hex(), nil, itemWithTranslations(nil, models.Translations{models.DefaultLocale: "Botas de cuero"}))
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
 func TestHandler_UpdateItem_Success(t *testing.T) {
 
 	service := items.NewItemsServiceMock()
@@ -743,3 +869,211 @@ func TestHandler_DeleteItem_Bad_Request_Invalid_Hex(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, response.Code)
 	assert.Equal(t, http.StatusBadRequest, apiError.ErrorStatus)
 }
+
+func TestHandler_Get_Localized_Success(t *testing.T) {
+	var item = mocks.ItemMockOne
+	item.NameI18n = models.Translations{"pt": "Item de exemplo"}
+	item.DescriptionI18n = models.Translations{"pt": "Descrição"}
+
+	service := items.NewItemsServiceMock()
+	service.HandleGet = func(ctx context.Context, itemID string) (models.Item, apierrors.ApiError) {
+		return item, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewItemsHandler(service, nil)
+	depend.Items = handler
+
+	var result models.Item
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/"+primitive.NewObjectID().Hex()+"?lang=pt", nil, "")
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Nil(t, err)
+	assert.Equal(t, "pt", response.Header().Get("Content-Language"))
+	assert.Equal(t, "Item de exemplo", result.Name)
+	assert.Equal(t, "Descrição", result.Description)
+	assert.Equal(t, item.Category.Name, result.Category.Name)
+}
+
+func TestHandler_SetItemTranslation_Success(t *testing.T) {
+	service := items.NewItemsServiceMock()
+	service.HandleSetTranslation = func(ctx context.Context, userID string, itemID string, locale string, translation models.ItemTranslation) apierrors.ApiError {
+		assert.Equal(t, "01-USER-TEST", userID)
+		assert.Equal(t, "Nome", translation.Name)
+		return nil
+	}
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewItemsHandler(service, nil)
+	depend.Items = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "PUT", "/items/"+mocks.ItemIdOne+"/translations/pt", nil, `{"name":"Nome","description":"Descrição"}`)
+
+	assert.Equal(t, http.StatusNoContent, response.Code)
+}
+
+func TestHandler_SetItemTranslation_Bad_Request_Unsupported_Locale(t *testing.T) {
+	service := items.NewItemsServiceMock()
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewItemsHandler(service, nil)
+	depend.Items = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "PUT", "/items/"+mocks.ItemIdOne+"/translations/fr", nil, `{"name":"Nom"}`)
+
+	var apiError mocks.ApiError
+	err := json.Unmarshal(response.Body.Bytes(), &apiError)
+	if err != nil {
+		panic("Cannot decode error response body.")
+	}
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Equal(t, http.StatusBadRequest, apiError.ErrorStatus)
+}
+
+func TestHandler_SetItemTranslation_Forbidden_Error(t *testing.T) {
+	service := items.NewItemsServiceMock()
+	service.HandleSetTranslation = func(ctx context.Context, userID string, itemID string, locale string, translation models.ItemTranslation) apierrors.ApiError {
+		return apierrors.NewApiError("mock error", "forbidden", http.StatusForbidden, apierrors.CauseList{})
+	}
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewItemsHandler(service, nil)
+	depend.Items = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "PUT", "/items/"+mocks.ItemIdOne+"/translations/pt", nil, `{"name":"Nome"}`)
+
+	assert.Equal(t, http.StatusForbidden, response.Code)
+}
+
+func TestHandler_DeleteItemTranslation_Success(t *testing.T) {
+	service := items.NewItemsServiceMock()
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewItemsHandler(service, nil)
+	depend.Items = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "DELETE", "/items/"+mocks.ItemIdOne+"/translations/pt", nil, "")
+
+	assert.Equal(t, http.StatusNoContent, response.Code)
+}
+
+func TestHandler_DeleteItemTranslation_Bad_Request_Invalid_Hex(t *testing.T) {
+	service := items.NewItemsServiceMock()
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewItemsHandler(service, nil)
+	depend.Items = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "DELETE", "/items/a/translations/pt", nil, "")
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestHandler_GetItemBySlug_Success(t *testing.T) {
+	service := items.NewItemsServiceMock()
+	service.HandleGetBySlug = func(ctx context.Context, slug string) (models.Item, apierrors.ApiError) {
+		var item = mocks.ItemMockOne
+		item.Slug = "example-item"
+		return item, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewItemsHandler(service, nil)
+	depend.Items = handler
+
+	var result models.Item
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/by-slug/example-item", nil, "")
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, mocks.ItemIdOne, result.ID)
+}
+
+func TestHandler_GetItemBySlug_Old_Slug_Redirect(t *testing.T) {
+	service := items.NewItemsServiceMock()
+	service.HandleGetBySlug = func(ctx context.Context, slug string) (models.Item, apierrors.ApiError) {
+		var item = mocks.ItemMockOne
+		item.Slug = "example-item"
+		return item, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewItemsHandler(service, nil)
+	depend.Items = handler
+
+	var result models.SlugRedirect
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/by-slug/old-item?lang=pt", nil, "")
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusMovedPermanently, response.Code)
+	assert.Equal(t, "/items/by-slug/example-item?lang=pt", response.Header().Get("Location"))
+	assert.Equal(t, "example-item", result.Slug)
+}
+
+func TestHandler_GetItemBySlug_Bad_Request_Invalid_Slug(t *testing.T) {
+	service := items.NewItemsServiceMock()
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewItemsHandler(service, nil)
+	depend.Items = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/by-slug/Not_A_Slug", nil, "")
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestHandler_GetItemBySlug_Not_Found_Error(t *testing.T) {
+	service := items.NewItemsServiceMock()
+	service.HandleGetBySlug = func(ctx context.Context, slug string) (models.Item, apierrors.ApiError) {
+		return models.Item{}, apierrors.NewNotFoundApiError("mock error")
+	}
+
+	var depend dependencies.HandlersStruct
+	handler := handlers.NewItemsHandler(service, nil)
+	depend.Items = handler
+
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/by-slug/example-item", nil, "")
+
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestHandler_SearchItems_Success(t *testing.T) {
+	shopID := primitive.NewObjectID().Hex()
+
+	var query models.ItemsQuery
+	service := items.NewItemsServiceMock()
+	service.HandleSearch = func(ctx context.Context, q models.ItemsQuery) (models.ItemsPage, apierrors.ApiError) {
+		query = q
+		return models.ItemsPage{Items: mocks.ItemsMock.Items, Total: 42}, nil
+	}
+
+	var depend dependencies.HandlersStruct
+	depend.Items = handlers.NewItemsHandler(service, nil)
+
+	var result dto.ItemsPageDTO
+	response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/search?q=remera&shop_id="+shopID+"&offset=20", nil, "")
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Nil(t, err)
+	assert.Equal(t, models.ItemsQuery{ShopID: shopID, Text: "remera", Limit: models.DefaultItemsPageSize, Offset: 20}, query)
+	assert.EqualValues(t, 42, result.Total)
+	assert.Equal(t, models.DefaultItemsPageSize, result.Limit)
+	assert.Equal(t, 20, result.Offset)
+	assert.Len(t, result.Items, len(mocks.ItemsMock.Items))
+}
+
+func TestHandler_SearchItems_Bad_Request_Limit(t *testing.T) {
+	var depend dependencies.HandlersStruct
+	depend.Items = handlers.NewItemsHandler(items.NewItemsServiceMock(), nil)
+
+	for _, params := range []string{"limit=0", "limit=101", "limit=ten", "offset=-1", "shop_id=nope"} {
+		response := setup.ExecuteRequest(setup.BuildRouter(depend), "GET", "/items/search?"+params, nil, "")
+
+		assert.Equal(t, http.StatusBadRequest, response.Code, params)
+	}
+}
@@ -0,0 +1,84 @@
+package revisions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/dependencies"
+	"github.com/agustinrabini/items-api-project/src/main/domain/handlers"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/services"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/actor"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/mocks"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/services/revisions"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/setup"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func router(service revisions.RevisionsServiceMock) dependencies.HandlersStruct {
+	var depend dependencies.HandlersStruct
+	depend.Revisions = handlers.NewRevisionsHandler(service)
+	return depend
+}
+
+func TestHandler_ListRevisions(t *testing.T) {
+	itemID := primitive.NewObjectID().Hex()
+	service := revisions.NewRevisionsServiceMock()
+	service.HandleList = func(ctx context.Context, userID string, id string) ([]models.Revision, apierrors.ApiError) {
+		assert.Equal(t, "01-USER-TEST", userID)
+		assert.Equal(t, itemID, id)
+		return []models.Revision{{ItemID: id, Number: 1, Action: models.RevisionInitial, Changes: []models.FieldChange{}}}, nil
+	}
+
+	response := setup.ExecuteRequest(setup.BuildRouter(router(service)), "GET", "/items/"+itemID+"/revisions", nil, "")
+
+	var result []models.Revision
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, models.RevisionInitial, result[0].Action)
+}
+
+func TestHandler_GetRevision_Invalid_Number(t *testing.T) {
+	for _, rev := range []string{"0", "-1", "first"} {
+		response := setup.ExecuteRequest(setup.BuildRouter(router(revisions.NewRevisionsServiceMock())), "GET", "/items/"+primitive.NewObjectID().Hex()+"/revisions/"+rev, nil, "")
+
+		assert.Equal(t, http.StatusBadRequest, response.Code, rev)
+	}
+}
+
+func TestHandler_GetRevision_Not_Owned(t *testing.T) {
+	service := revisions.NewRevisionsServiceMock()
+	service.HandleGet = func(ctx context.Context, userID string, itemID string, number int) (models.Revision, apierrors.ApiError) {
+		assert.Equal(t, 3, number)
+		return models.Revision{}, services.ErrorItemNotOwned
+	}
+
+	response := setup.ExecuteRequest(setup.BuildRouter(router(service)), "GET", "/items/"+primitive.NewObjectID().Hex()+"/revisions/3", nil, "")
+
+	var apiError mocks.ApiError
+	err := json.Unmarshal(response.Body.Bytes(), &apiError)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+}
+
+func TestHandler_RevertRevision(t *testing.T) {
+	service := revisions.NewRevisionsServiceMock()
+	service.HandleRevert = func(ctx context.Context, userID string, itemID string, number int) apierrors.ApiError {
+		assert.Equal(t, "01-USER-TEST", actor.From(ctx))
+		assert.Equal(t, 2, number)
+		return nil
+	}
+
+	response := setup.ExecuteRequest(setup.BuildRouter(router(service)), "POST", "/items/"+primitive.NewObjectID().Hex()+"/revisions/2/revert", nil, "")
+
+	assert.Equal(t, http.StatusNoContent, response.Code)
+}
@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+)
+
+const (
+	CollectionIdOne = "676695ae1d8a4720e8c60df0"
+	CollectionIdTwo = "676695ae1d8a4720e8c60df1"
+)
+
+var CollectionOne = models.Collection{
+	ID:       CollectionIdOne,
+	ShopID:   ShopIDOne,
+	UserID:   UserIdOne,
+	Name:     "New arrivals",
+	NameI18n: models.Translations{"pt": "Novidades"},
+	Position: 0,
+	ItemIDs:  []string{ItemIdTwo, ItemIdOne},
+}
+
+var CollectionTwo = models.Collection{
+	ID:       CollectionIdTwo,
+	ShopID:   ShopIDOne,
+	UserID:   UserIdOne,
+	Name:     "Summer sale",
+	Position: 1,
+	ItemIDs:  []string{},
+}
+
+var Collections = []models.Collection{CollectionOne, CollectionTwo}
+
+var CollectionDTO = dto.CollectionDTO{
+	Name:     "New arrivals",
+	Position: 2,
+}
+
+func CollectionDTOToJson() string {
+	bytes, err := json.Marshal(CollectionDTO)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return string(bytes)
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffItems(t *testing.T) {
+	before := models.Item{
+		ID:          "1",
+		Name:        "Boots",
+		Description: "Leather boots",
+		Images:      []models.Image{"front.png", "back.png"},
+		Attributes:  models.Attributes{"size": "42"},
+	}
+	after := before
+	after.ID = "2"
+	after.Description = ""
+	after.Images = []models.Image{"front.png", "side.png"}
+	after.Attributes = models.Attributes{"size": "42", "color": "brown"}
+
+	changes := models.DiffItems(before, &models.Price{ID: "a", Amount: 10}, after, &models.Price{ID: "b", Amount: 12})
+
+	assert.Equal(t, []models.FieldChange{
+		{Field: "attributes.color", After: "brown"},
+		{Field: "description", Before: "Leather boots"},
+		{Field: "images.1", Before: "back.png", After: "side.png"},
+		{Field: "price.amount", Before: 10.0, After: 12.0},
+	}, changes)
+}
+
+func TestDiffItems_Unknown_Price(t *testing.T) {
+	item := models.Item{Name: "Boots", Price: models.Price{Amount: 10}}
+
+	changes := models.DiffItems(item, nil, item, &models.Price{Amount: 12})
+
+	assert.Empty(t, changes)
+}
@@ -0,0 +1,32 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+type RepositoryMock struct {
+	HandleSave   func(ctx context.Context, entry models.AuditEntry) apierrors.ApiError
+	HandleSearch func(ctx context.Context, query models.AuditQuery) (models.AuditPage, apierrors.ApiError)
+}
+
+func NewRepositoryMock() RepositoryMock {
+	return RepositoryMock{}
+}
+
+func (mock RepositoryMock) Save(ctx context.Context, entry models.AuditEntry) apierrors.ApiError {
+	if mock.HandleSave != nil {
+		return mock.HandleSave(ctx, entry)
+	}
+	return nil
+}
+
+func (mock RepositoryMock) Search(ctx context.Context, query models.AuditQuery) (models.AuditPage, apierrors.ApiError) {
+	if mock.HandleSearch != nil {
+		return mock.HandleSearch(ctx, query)
+	}
+	return models.AuditPage{Entries: []models.AuditEntry{}}, nil
+}
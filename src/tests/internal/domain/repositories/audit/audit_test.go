@@ -0,0 +1,56 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	mockdeppkg "github.com/agustinrabini/items-api-project/src/tests/internal/api/dependencies"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/setup"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var depMock mockdeppkg.Dependencies
+
+func TestMain(m *testing.M) {
+	depMock = setup.BeforeMemongoTestCase()
+	m.Run()
+	setup.AfterMemongoTestCase()
+	depMock.AuditRepository = nil
+}
+
+func TestRepository_Search(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	entries := []models.AuditEntry{
+		{Actor: "admin", ClaimedActor: "alice", Action: "category.update", TargetType: "category", TargetID: "1", CreatedAt: now.Add(-2 * time.Hour), Before: map[string]interface{}{"name": "Shoes"}, After: map[string]interface{}{"name": "Footwear"}},
+		{Actor: "user", Action: "item.update", TargetType: "item", TargetID: "2", CreatedAt: now.Add(-time.Hour)},
+		{Actor: "admin", ClaimedActor: "alice", Action: "category.delete", TargetType: "category", TargetID: "1", CreatedAt: now},
+	}
+	for _, entry := range entries {
+		assert.Nil(t, depMock.AuditRepository.Save(context.TODO(), entry))
+	}
+
+	page, err := depMock.AuditRepository.Search(context.TODO(), models.AuditQuery{Actor: "admin", ClaimedActor: "alice", Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), page.Total)
+	assert.Equal(t, "category.delete", page.Entries[0].Action)
+	assert.Equal(t, "Footwear", page.Entries[1].After["name"])
+	assert.NotEmpty(t, page.Entries[0].ID)
+
+	page, err = depMock.AuditRepository.Search(context.TODO(), models.AuditQuery{TargetType: "category", TargetID: "1", To: now.Add(-time.Minute), Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), page.Total)
+	assert.Equal(t, "category.update", page.Entries[0].Action)
+
+	page, err = depMock.AuditRepository.Search(context.TODO(), models.AuditQuery{From: now.Add(-90 * time.Minute), Limit: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), page.Total)
+	assert.Len(t, page.Entries, 1)
+	assert.Equal(t, "category.delete", page.Entries[0].Action)
+
+	page, err = depMock.AuditRepository.Search(context.TODO(), models.AuditQuery{Action: "item.delete", Limit: 10})
+	assert.Nil(t, err)
+	assert.Empty(t, page.Entries)
+}
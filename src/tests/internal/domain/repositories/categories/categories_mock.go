@@ -14,6 +14,12 @@ type RepositoryMock struct {
 	HandleCreate           func(ctx context.Context, input models.Category) (interface{}, apierrors.ApiError)
 	HandleUpdate           func(ctx context.Context, input models.Category) (int64, apierrors.ApiError)
 	HandleDelete           func(ctx context.Context, categoryID string) (int64, apierrors.ApiError)
+
+	HandleSetTranslation    func(ctx context.Context, categoryID string, locale string, name string) apierrors.ApiError
+	HandleDeleteTranslation func(ctx context.Context, categoryID string, locale string) apierrors.ApiError
+
+	HandleGetBySlug  func(ctx context.Context, slug string) (models.Category, apierrors.ApiError)
+	HandleSlugExists func(ctx context.Context, slug string, excludeID string) (bool, apierrors.ApiError)
 }
 
 func NewRepositoryMock() RepositoryMock {
@@ -62,3 +68,31 @@ func (mock RepositoryMock) GetAllCategories(ctx context.Context) ([]models.Categ
 	}
 	return []models.Category{}, nil
 }
+
+func (mock RepositoryMock) SetTranslation(ctx context.Context, categoryID string, locale string, name string) apierrors.ApiError {
+	if mock.HandleSetTranslation != nil {
+		return mock.HandleSetTranslation(ctx, categoryID, locale, name)
+	}
+	return nil
+}
+
+func (mock RepositoryMock) DeleteTranslation(ctx context.Context, categoryID string, locale string) apierrors.ApiError {
+	if mock.HandleDeleteTranslation != nil {
+		return mock.HandleDeleteTranslation(ctx, categoryID, locale)
+	}
+	return nil
+}
+
+func (mock RepositoryMock) GetBySlug(ctx context.Context, slug string) (models.Category, apierrors.ApiError) {
+	if mock.HandleGetBySlug != nil {
+		return mock.HandleGetBySlug(ctx, slug)
+	}
+	return models.Category{}, nil
+}
+
+func (mock RepositoryMock) SlugExists(ctx context.Context, slug string, excludeID string) (bool, apierrors.ApiError) {
+	if mock.HandleSlugExists != nil {
+		return mock.HandleSlugExists(ctx, slug, excludeID)
+	}
+	return false, nil
+}
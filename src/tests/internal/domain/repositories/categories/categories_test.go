@@ -333,3 +333,68 @@ func TestRepository_Delete_Internal_Server_Error(t *testing.T) {
 		assert.EqualValues(mock, http.StatusInternalServerError, err.Status())
 	})
 }
+
+func TestRepository_SetTranslation_Success(t *testing.T) {
+	arrangeCat := models.Category{Name: "zapatos"}
+	idinterface, err := depMock.CategoriesRepository.Create(context.TODO(), arrangeCat)
+	if err != nil {
+		log.Fatal(err)
+	}
+	hexID := fmt.Sprint(idinterface)[10 : len(fmt.Sprint(idinterface))-2]
+
+	err = depMock.CategoriesRepository.SetTranslation(context.TODO(), hexID, "pt", "sapatos")
+	assert.EqualValues(t, nil, err)
+
+	assertCat, err := depMock.CategoriesRepository.Get(context.TODO(), hexID)
+
+	assert.EqualValues(t, nil, err)
+	assert.Equal(t, models.Translations{"pt": "sapatos"}, assertCat.NameI18n)
+
+	err = depMock.CategoriesRepository.DeleteTranslation(context.TODO(), hexID, "pt")
+	assert.EqualValues(t, nil, err)
+
+	assertCat, err = depMock.CategoriesRepository.Get(context.TODO(), hexID)
+
+	assert.EqualValues(t, nil, err)
+	assert.Empty(t, assertCat.NameI18n)
+}
+
+func TestRepository_SetTranslation_Not_Found_Error(t *testing.T) {
+	err := depMock.CategoriesRepository.SetTranslation(context.TODO(), primitive.NewObjectID().Hex(), "pt", "sapatos")
+
+	assert.EqualValues(t, "not_found", err.Code())
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestRepository_DeleteTranslation_Invalid_Id_Error(t *testing.T) {
+	err := depMock.CategoriesRepository.DeleteTranslation(context.TODO(), "fake_id", "pt")
+
+	assert.EqualValues(t, "internal_server_error", err.Code())
+	assert.EqualValues(t, fmt.Sprintf(repositories.CategoriesDatabaseError, "DeleteTranslation"), err.Message())
+	assert.EqualValues(t, http.StatusInternalServerError, err.Status())
+}
+
+func TestRepository_GetBySlug_Current_And_Previous(t *testing.T) {
+	arrangeCat := models.Category{Name: "calzado", Slug: "calzado", SlugHistory: []string{"zapatos-viejos"}}
+	idinterface, err := depMock.CategoriesRepository.Create(context.TODO(), arrangeCat)
+	if err != nil {
+		log.Fatal(err)
+	}
+	hexID := fmt.Sprint(idinterface)[10 : len(fmt.Sprint(idinterface))-2]
+
+	found, err := depMock.CategoriesRepository.GetBySlug(context.TODO(), "zapatos-viejos")
+	assert.EqualValues(t, nil, err)
+	assert.Equal(t, hexID, found.ID)
+	assert.Equal(t, "calzado", found.Slug)
+
+	exists, err := depMock.CategoriesRepository.SlugExists(context.TODO(), "calzado", hexID)
+	assert.EqualValues(t, nil, err)
+	assert.False(t, exists)
+}
+
+func TestRepository_GetBySlug_Not_Found_Error(t *testing.T) {
+	_, err := depMock.CategoriesRepository.GetBySlug(context.TODO(), "missing-slug")
+
+	assert.EqualValues(t, "not_found", err.Code())
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
@@ -0,0 +1,23 @@
+package categories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/api/platform/storage"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/repositories/conformance"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TestRepository_Conformance runs the shared suite, each case on a new
+// collection so the data of the other tests doesn't get in the way.
+func TestRepository_Conformance(t *testing.T) {
+	conformance.RunCategoriesRepository(t, func(t *testing.T) repositories.CategoriesRepository {
+		collection := storage.OpenNoSQLMock(nil).NewCollection("categories_" + primitive.NewObjectID().Hex())
+		t.Cleanup(func() { _ = collection.Drop(context.Background()) })
+
+		return repositories.NewCategoriesRepository(collection)
+	})
+}
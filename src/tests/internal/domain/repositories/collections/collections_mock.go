@@ -0,0 +1,64 @@
+package collections
+
+import (
+	"context"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+type RepositoryMock struct {
+	HandleGet         func(ctx context.Context, collectionID string) (models.Collection, apierrors.ApiError)
+	HandleGetByShopID func(ctx context.Context, shopID string) ([]models.Collection, apierrors.ApiError)
+	HandleCreate      func(ctx context.Context, collection models.Collection) (interface{}, apierrors.ApiError)
+	HandleUpdate      func(ctx context.Context, collection models.Collection) apierrors.ApiError
+	HandleSetItems    func(ctx context.Context, collectionID string, itemIDs []string) apierrors.ApiError
+	HandleDelete      func(ctx context.Context, collectionID string) (int64, apierrors.ApiError)
+}
+
+func NewRepositoryMock() RepositoryMock {
+	return RepositoryMock{}
+}
+
+func (mock RepositoryMock) Get(ctx context.Context, collectionID string) (models.Collection, apierrors.ApiError) {
+	if mock.HandleGet != nil {
+		return mock.HandleGet(ctx, collectionID)
+	}
+	return models.Collection{}, nil
+}
+
+func (mock RepositoryMock) GetByShopID(ctx context.Context, shopID string) ([]models.Collection, apierrors.ApiError) {
+	if mock.HandleGetByShopID != nil {
+		return mock.HandleGetByShopID(ctx, shopID)
+	}
+	return nil, nil
+}
+
+func (mock RepositoryMock) Create(ctx context.Context, collection models.Collection) (interface{}, apierrors.ApiError) {
+	if mock.HandleCreate != nil {
+		return mock.HandleCreate(ctx, collection)
+	}
+	return "", nil
+}
+
+func (mock RepositoryMock) Update(ctx context.Context, collection models.Collection) apierrors.ApiError {
+	if mock.HandleUpdate != nil {
+		return mock.HandleUpdate(ctx, collection)
+	}
+	return nil
+}
+
+func (mock RepositoryMock) SetItems(ctx context.Context, collectionID string, itemIDs []string) apierrors.ApiError {
+	if mock.HandleSetItems != nil {
+		return mock.HandleSetItems(ctx, collectionID, itemIDs)
+	}
+	return nil
+}
+
+func (mock RepositoryMock) Delete(ctx context.Context, collectionID string) (int64, apierrors.ApiError) {
+	if mock.HandleDelete != nil {
+		return mock.HandleDelete(ctx, collectionID)
+	}
+	return -1, nil
+}
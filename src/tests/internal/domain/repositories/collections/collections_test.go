@@ -0,0 +1,140 @@
+package collections
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/dependencies"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	mockdeppkg "github.com/agustinrabini/items-api-project/src/tests/internal/api/dependencies"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/mocks"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/setup"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+var depMock mockdeppkg.Dependencies
+
+func TestMain(m *testing.M) {
+	depMock = setup.BeforeMemongoTestCase()
+	m.Run()
+	setup.AfterMemongoTestCase()
+	depMock.CollectionsRepository = nil
+}
+
+func createCollection(collection models.Collection) string {
+	idinterface, err := depMock.CollectionsRepository.Create(context.TODO(), collection)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return fmt.Sprint(idinterface)[10 : len(fmt.Sprint(idinterface))-2]
+}
+
+func TestRepository_NewRepository(t *testing.T) {
+	assert.NotNil(t, depMock.CollectionsRepository)
+}
+
+func TestRepository_Get_Success(t *testing.T) {
+	arrange := models.Collection{ShopID: mocks.ShopIDOne, UserID: mocks.UserIdOne, Name: "New arrivals", ItemIDs: []string{mocks.ItemIdOne}}
+	arrange.ID = createCollection(arrange)
+
+	collection, err := depMock.CollectionsRepository.Get(context.TODO(), arrange.ID)
+
+	assert.EqualValues(t, nil, err)
+	assert.Equal(t, arrange, collection)
+}
+
+func TestRepository_Get_Not_Found_Error(t *testing.T) {
+	collection, err := depMock.CollectionsRepository.Get(context.TODO(), primitive.NewObjectID().Hex())
+
+	assert.EqualValues(t, models.Collection{}, collection)
+	assert.EqualValues(t, "not_found", err.Code())
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestRepository_GetByShopID_Sorted_By_Position(t *testing.T) {
+	shopID := primitive.NewObjectID().Hex()
+	createCollection(models.Collection{ShopID: shopID, Name: "Second", Position: 2, ItemIDs: []string{}})
+	createCollection(models.Collection{ShopID: shopID, Name: "First", Position: 1, ItemIDs: []string{}})
+
+	collections, err := depMock.CollectionsRepository.GetByShopID(context.TODO(), shopID)
+
+	assert.EqualValues(t, nil, err)
+	assert.Len(t, collections, 2)
+	assert.Equal(t, "First", collections[0].Name)
+	assert.Equal(t, "Second", collections[1].Name)
+}
+
+func TestRepository_Update_And_SetItems_Success(t *testing.T) {
+	arrange := models.Collection{ShopID: mocks.ShopIDOne, UserID: mocks.UserIdOne, Name: "Sale", ItemIDs: []string{}}
+	arrange.ID = createCollection(arrange)
+
+	arrange.Name = "Summer sale"
+	arrange.Position = 3
+	err := depMock.CollectionsRepository.Update(context.TODO(), arrange)
+	assert.EqualValues(t, nil, err)
+
+	err = depMock.CollectionsRepository.SetItems(context.TODO(), arrange.ID, []string{mocks.ItemIdTwo, mocks.ItemIdOne})
+	assert.EqualValues(t, nil, err)
+
+	collection, err := depMock.CollectionsRepository.Get(context.TODO(), arrange.ID)
+
+	assert.EqualValues(t, nil, err)
+	assert.Equal(t, "Summer sale", collection.Name)
+	assert.Equal(t, 3, collection.Position)
+	assert.Equal(t, []string{mocks.ItemIdTwo, mocks.ItemIdOne}, collection.ItemIDs)
+}
+
+func TestRepository_SetItems_Not_Found_Error(t *testing.T) {
+	err := depMock.CollectionsRepository.SetItems(context.TODO(), primitive.NewObjectID().Hex(), []string{})
+
+	assert.EqualValues(t, "not_found", err.Code())
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestRepository_SetItems_Invalid_Id_Error(t *testing.T) {
+	err := depMock.CollectionsRepository.SetItems(context.TODO(), "fake_id", []string{})
+
+	assert.EqualValues(t, "internal_server_error", err.Code())
+	assert.EqualValues(t, fmt.Sprintf(repositories.CollectionsDatabaseError, "SetItems"), err.Message())
+	assert.EqualValues(t, http.StatusInternalServerError, err.Status())
+}
+
+func TestRepository_Delete_Success(t *testing.T) {
+	id := createCollection(models.Collection{ShopID: mocks.ShopIDOne, Name: "Old", ItemIDs: []string{}})
+
+	deleted, err := depMock.CollectionsRepository.Delete(context.TODO(), id)
+
+	assert.EqualValues(t, nil, err)
+	assert.EqualValues(t, 1, deleted)
+}
+
+func TestRepository_Delete_Not_Found_Error(t *testing.T) {
+	_, err := depMock.CollectionsRepository.Delete(context.TODO(), primitive.NewObjectID().Hex())
+
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestRepository_GetByShopID_Internal_Server_Error(t *testing.T) {
+	mock := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mock.Cleanup(func() { mock.Client = nil })
+
+	mock.Run("InternalServerError", func(mock *mtest.T) {
+		repository := repositories.NewCollectionsRepository(mock.DB.Collection(dependencies.KvsCollectionsCollection))
+
+		mock.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "mock error"}))
+
+		collections, err := repository.GetByShopID(context.TODO(), mocks.ShopIDOne)
+
+		assert.Nil(t, collections)
+		assert.EqualValues(t, http.StatusInternalServerError, err.Status())
+	})
+}
@@ -0,0 +1,182 @@
+package conformance
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func RunCategoriesRepository(t *testing.T, newRepository func(t *testing.T) repositories.CategoriesRepository) {
+	ctx := context.Background()
+
+	t.Run("Create_And_Get", func(t *testing.T) {
+		repository := newRepository(t)
+
+		id := createCategory(t, repository, models.Category{Name: "Mates", Slug: "mates"})
+
+		category, err := repository.Get(ctx, id)
+
+		assert.Nil(t, err)
+		assert.Equal(t, models.Category{ID: id, Name: "Mates", Slug: "mates"}, category)
+	})
+
+	t.Run("Get_Not_Found", func(t *testing.T) {
+		_, err := newRepository(t).Get(ctx, primitive.NewObjectID().Hex())
+
+		assert.Equal(t, repositories.CategoriesItemNotFoundError, err)
+	})
+
+	t.Run("Get_Invalid_ID", func(t *testing.T) {
+		_, err := newRepository(t).Get(ctx, "123")
+
+		assertInternalError(t, "[Get] Error in DB", err)
+	})
+
+	t.Run("GetAllCategories", func(t *testing.T) {
+		repository := newRepository(t)
+
+		categories, err := repository.GetAllCategories(ctx)
+		assert.Nil(t, err)
+		assert.Empty(t, categories)
+
+		first := createCategory(t, repository, models.Category{Name: "Mates"})
+		second := createCategory(t, repository, models.Category{Name: "Bombillas"})
+
+		categories, err = repository.GetAllCategories(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []models.Category{{ID: first, Name: "Mates"}, {ID: second, Name: "Bombillas"}}, categories)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repository := newRepository(t)
+		id := createCategory(t, repository, models.Category{Name: "Mates", NameI18n: models.Translations{"pt": "Cuias"}, Slug: "mates"})
+
+		modified, err := repository.Update(ctx, models.Category{ID: id, Name: "Yerbas"})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), modified)
+
+		category, _ := repository.Get(ctx, id)
+		assert.Equal(t, models.Category{ID: id, Name: "Yerbas", NameI18n: models.Translations{"pt": "Cuias"}, Slug: "mates"}, category)
+
+		modified, err = repository.Update(ctx, models.Category{ID: id, Name: "Yerbas", NameI18n: models.Translations{}, Slug: "yerbas", SlugHistory: []string{"mates"}})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), modified)
+
+		category, _ = repository.Get(ctx, id)
+		assert.Empty(t, category.NameI18n)
+		assert.Equal(t, "yerbas", category.Slug)
+		assert.Equal(t, []string{"mates"}, category.SlugHistory)
+	})
+
+	t.Run("Update_Without_Changes", func(t *testing.T) {
+		repository := newRepository(t)
+		id := createCategory(t, repository, models.Category{Name: "Mates"})
+
+		modified, err := repository.Update(ctx, models.Category{ID: id, Name: "Mates"})
+
+		assert.Equal(t, int64(-1), modified)
+		assert.Equal(t, http.StatusNotFound, err.Status())
+		assert.Equal(t, "[Update] Error in DB", err.Message())
+	})
+
+	t.Run("Update_Not_Found", func(t *testing.T) {
+		modified, err := newRepository(t).Update(ctx, models.Category{ID: primitive.NewObjectID().Hex(), Name: "Mates"})
+
+		assert.Equal(t, int64(-1), modified)
+		assert.Equal(t, http.StatusNotFound, err.Status())
+	})
+
+	t.Run("Update_Invalid_ID", func(t *testing.T) {
+		modified, err := newRepository(t).Update(ctx, models.Category{ID: "123", Name: "Mates"})
+
+		assert.Equal(t, int64(-1), modified)
+		assertInternalError(t, "[Update] Error in DB", err)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repository := newRepository(t)
+		id := createCategory(t, repository, models.Category{Name: "Mates"})
+
+		deleted, err := repository.Delete(ctx, id)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		_, err = repository.Get(ctx, id)
+		assert.Equal(t, repositories.CategoriesItemNotFoundError, err)
+
+		deleted, err = repository.Delete(ctx, id)
+		assert.Equal(t, int64(-1), deleted)
+		assert.Equal(t, http.StatusNotFound, err.Status())
+		assert.Equal(t, "[Delete] Error in DB", err.Message())
+
+		_, err = repository.Delete(ctx, "123")
+		assertInternalError(t, "[Delete] Error in DB", err)
+	})
+
+	t.Run("Translations", func(t *testing.T) {
+		repository := newRepository(t)
+		id := createCategory(t, repository, models.Category{Name: "Mates"})
+
+		err := repository.SetTranslation(ctx, id, "pt", "Cuias")
+		assert.Nil(t, err)
+
+		category, _ := repository.Get(ctx, id)
+		assert.Equal(t, models.Translations{"pt": "Cuias"}, category.NameI18n)
+
+		err = repository.DeleteTranslation(ctx, id, "pt")
+		assert.Nil(t, err)
+
+		category, _ = repository.Get(ctx, id)
+		assert.Empty(t, category.NameI18n)
+
+		err = repository.SetTranslation(ctx, primitive.NewObjectID().Hex(), "pt", "Cuias")
+		assert.Equal(t, repositories.CategoriesItemNotFoundError, err)
+
+		err = repository.DeleteTranslation(ctx, primitive.NewObjectID().Hex(), "pt")
+		assert.Equal(t, repositories.CategoriesItemNotFoundError, err)
+
+		err = repository.DeleteTranslation(ctx, "123", "pt")
+		assertInternalError(t, "[DeleteTranslation] Error in DB", err)
+	})
+
+	t.Run("Slugs", func(t *testing.T) {
+		repository := newRepository(t)
+		id := createCategory(t, repository, models.Category{Name: "Mates", Slug: "mates", SlugHistory: []string{"cuias"}})
+
+		for _, slug := range []string{"mates", "cuias"} {
+			category, err := repository.GetBySlug(ctx, slug)
+			assert.Nil(t, err)
+			assert.Equal(t, id, category.ID)
+		}
+
+		_, err := repository.GetBySlug(ctx, "yerbas")
+		assert.Equal(t, repositories.CategoriesItemNotFoundError, err)
+
+		exists, err := repository.SlugExists(ctx, "cuias", "")
+		assert.Nil(t, err)
+		assert.True(t, exists)
+
+		exists, err = repository.SlugExists(ctx, "cuias", id)
+		assert.Nil(t, err)
+		assert.False(t, exists, "a category can take back its own slugs")
+
+		_, err = repository.SlugExists(ctx, "cuias", "123")
+		assertInternalError(t, "[SlugExists] Error in DB", err)
+	})
+}
+
+// createCategory stores category and returns its new ID.
+func createCategory(t *testing.T, repository repositories.CategoriesRepository, category models.Category) string {
+	id, err := repository.Create(context.Background(), category)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	return id.(primitive.ObjectID).Hex()
+}
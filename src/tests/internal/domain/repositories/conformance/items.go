@@ -0,0 +1,388 @@
+// Package conformance holds the behavior every implementation of the
+// repositories must share. Each implementation runs the suites from its own
+// tests, with a constructor that returns an empty repository.
+package conformance
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/mocks"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const invalidHexCause = "the provided hex string is not a valid ObjectID"
+
+func RunItemsRepository(t *testing.T, newRepository func(t *testing.T) repositories.ItemsRepository) {
+	ctx := context.Background()
+
+	t.Run("Save_And_Get", func(t *testing.T) {
+		repository := newRepository(t)
+
+		id := saveItem(t, repository, mocks.ItemMockOne)
+
+		item, err := repository.Get(ctx, id)
+
+		assert.Nil(t, err)
+		assert.Equal(t, id, item.ID)
+		assert.Equal(t, mocks.ItemMockOne.Name, item.Name)
+		assert.Equal(t, mocks.ItemMockOne.Category, item.Category)
+		assert.Equal(t, mocks.ItemMockOne.Attributes, item.Attributes)
+		assert.Equal(t, mocks.ItemMockOne.Eligible, item.Eligible)
+		assert.Empty(t, item.Price, "the price is not stored with the item")
+	})
+
+	t.Run("Get_Not_Found", func(t *testing.T) {
+		_, err := newRepository(t).Get(ctx, primitive.NewObjectID().Hex())
+
+		assert.Equal(t, repositories.ItemNotFoundError, err)
+	})
+
+	t.Run("Get_Invalid_ID", func(t *testing.T) {
+		_, err := newRepository(t).Get(ctx, "123")
+
+		assertInternalError(t, "[Get] Error in DB", err)
+	})
+
+	t.Run("Get_Returns_A_Copy", func(t *testing.T) {
+		repository := newRepository(t)
+		id := saveItem(t, repository, mocks.ItemMockOne)
+
+		item, _ := repository.Get(ctx, id)
+		item.Attributes["Color"] = "Red"
+
+		item, _ = repository.Get(ctx, id)
+		assert.Equal(t, "Blue", item.Attributes["Color"])
+	})
+
+	t.Run("GetByUserID_ShopID_And_ShopCategoryID", func(t *testing.T) {
+		repository := newRepository(t)
+		saveItem(t, repository, mocks.ItemMockOne)
+		saveItem(t, repository, mocks.ItemMockTwo)
+
+		byUser, err := repository.GetByUserID(ctx, mocks.UserIdTwo)
+		assert.Nil(t, err)
+		assert.Len(t, byUser.Items, 1)
+		assert.Equal(t, mocks.UserIdTwo, byUser.Items[0].UserID)
+
+		byShop, err := repository.GetByShopID(ctx, mocks.ShopIDOne)
+		assert.Nil(t, err)
+		assert.Len(t, byShop.Items, 1)
+
+		byCategory, err := repository.GetByShopCategoryID(ctx, mocks.ShopIDOne, mocks.CategoryIDOne)
+		assert.Nil(t, err)
+		assert.Len(t, byCategory.Items, 1)
+
+		_, err = repository.GetByUserID(ctx, primitive.NewObjectID().Hex())
+		assert.Equal(t, repositories.ItemsNotFoundError, err)
+
+		_, err = repository.GetByShopID(ctx, mocks.ShopIDTwo)
+		assert.Equal(t, repositories.ItemsNotFoundError, err)
+
+		_, err = repository.GetByShopCategoryID(ctx, mocks.ShopIDOne, primitive.NewObjectID().Hex())
+		assert.Equal(t, repositories.ItemsNotFoundError, err)
+	})
+
+	t.Run("GetByIDs_Leaves_Missing_Out", func(t *testing.T) {
+		repository := newRepository(t)
+		first := saveItem(t, repository, mocks.ItemMockOne)
+		second := saveItem(t, repository, mocks.ItemMockTwo)
+
+		items, err := repository.GetByIDs(ctx, []string{second, primitive.NewObjectID().Hex(), first})
+
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{first, second}, items.GetItemsIds())
+
+		_, err = repository.GetByIDs(ctx, []string{primitive.NewObjectID().Hex()})
+		assert.Equal(t, repositories.ItemsNotFoundError, err)
+	})
+
+	t.Run("Update_Sets_The_Given_Fields", func(t *testing.T) {
+		repository := newRepository(t)
+		id := saveItem(t, repository, mocks.ItemMockOne)
+
+		modified, err := repository.Update(ctx, id, &models.Item{Name: "Renamed", Attributes: models.Attributes{"Size": "L"}})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), modified)
+
+		item, _ := repository.Get(ctx, id)
+		assert.Equal(t, "Renamed", item.Name)
+		assert.Equal(t, models.Attributes{"Size": "L"}, item.Attributes)
+		assert.Equal(t, mocks.ItemMockOne.Description, item.Description)
+		assert.Equal(t, mocks.ItemMockOne.Images, item.Images)
+
+		modified, err = repository.Update(ctx, id, &models.Item{Name: "Renamed"})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), modified)
+	})
+
+	t.Run("Update_Not_Found", func(t *testing.T) {
+		modified, err := newRepository(t).Update(ctx, primitive.NewObjectID().Hex(), &models.Item{Name: "Renamed"})
+
+		assert.Equal(t, int64(-1), modified)
+		assert.Equal(t, http.StatusNotFound, err.Status())
+		assert.Equal(t, "[Update] Error in DB", err.Message())
+	})
+
+	t.Run("Update_Invalid_ID", func(t *testing.T) {
+		modified, err := newRepository(t).Update(ctx, "123", &models.Item{Name: "Renamed"})
+
+		assert.Equal(t, int64(-1), modified)
+		assertInternalError(t, "[Update] Error in DB", err)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repository := newRepository(t)
+		id := saveItem(t, repository, mocks.ItemMockOne)
+
+		deleted, err := repository.Delete(ctx, id)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		_, err = repository.Get(ctx, id)
+		assert.Equal(t, repositories.ItemNotFoundError, err)
+
+		deleted, err = repository.Delete(ctx, id)
+		assert.Equal(t, int64(-1), deleted)
+		assert.Equal(t, http.StatusNotFound, err.Status())
+		assert.Equal(t, "[Delete] Error in DB", err.Message())
+
+		_, err = repository.Delete(ctx, "123")
+		assertInternalError(t, "[Delete] Error in DB", err)
+	})
+
+	t.Run("UpdateItemsCategories_Cascades_To_The_Items", func(t *testing.T) {
+		repository := newRepository(t)
+		first := saveItem(t, repository, mocks.ItemMockOne)
+		second := saveItem(t, repository, mocks.ItemMockTwo)
+		other := mocks.ItemMockOne
+		other.Category = models.Category{ID: primitive.NewObjectID().Hex(), Name: "Other"}
+		otherID := saveItem(t, repository, other)
+
+		category := models.Category{ID: mocks.CategoryIDOne, Name: "Mates", NameI18n: models.Translations{"pt": "Cuias"}, Slug: "mates"}
+		err := repository.UpdateItemsCategories(ctx, &category)
+		assert.Nil(t, err)
+
+		for _, id := range []string{first, second} {
+			item, _ := repository.Get(ctx, id)
+			assert.Equal(t, models.Category{ID: mocks.CategoryIDOne, Name: "Mates", NameI18n: models.Translations{"pt": "Cuias"}, Slug: "mates"}, item.Category)
+		}
+
+		item, _ := repository.Get(ctx, otherID)
+		assert.Equal(t, "Other", item.Category.Name)
+
+		err = repository.UpdateItemsCategories(ctx, &models.Category{ID: mocks.CategoryIDOne, Name: "Yerbas"})
+		assert.Nil(t, err)
+
+		item, _ = repository.Get(ctx, first)
+		assert.Equal(t, "Yerbas", item.Category.Name)
+		assert.Equal(t, "mates", item.Category.Slug, "an empty slug is not set")
+		assert.Equal(t, models.Translations{"pt": "Cuias"}, item.Category.NameI18n, "nil translations are not set")
+	})
+
+	t.Run("UpdateItemsCategories_No_Items", func(t *testing.T) {
+		err := newRepository(t).UpdateItemsCategories(ctx, &models.Category{ID: mocks.CategoryIDOne, Name: "Mates"})
+
+		assert.Equal(t, http.StatusNotFound, err.Status())
+		assert.Equal(t, "no update", err.Code())
+	})
+
+	t.Run("GetByCategoryID_And_CountByCategoryID", func(t *testing.T) {
+		repository := newRepository(t)
+		saveItem(t, repository, mocks.ItemMockOne)
+		saveItem(t, repository, mocks.ItemMockTwo)
+
+		items, err := repository.GetByCategoryID(ctx, mocks.CategoryIDOne)
+		assert.Nil(t, err)
+		assert.Len(t, items, 2)
+
+		count, err := repository.CountByCategoryID(ctx, mocks.CategoryIDOne)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), count)
+
+		deleted := mocks.ItemMockOne
+		deleted.Status = models.ItemStatusDeleted
+		saveItem(t, repository, deleted)
+
+		count, err = repository.CountByCategoryID(ctx, mocks.CategoryIDOne)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), count, "deleted items still hold the category")
+
+		items, err = repository.GetByCategoryID(ctx, primitive.NewObjectID().Hex())
+		assert.Nil(t, err)
+		assert.Empty(t, items)
+
+		count, err = repository.CountByCategoryID(ctx, primitive.NewObjectID().Hex())
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("Translations", func(t *testing.T) {
+		repository := newRepository(t)
+		id := saveItem(t, repository, mocks.ItemMockOne)
+
+		err := repository.SetTranslation(ctx, id, "pt", models.ItemTranslation{Name: "Item", Description: "Descrição"})
+		assert.Nil(t, err)
+
+		item, _ := repository.Get(ctx, id)
+		assert.Equal(t, "Item", item.NameI18n["pt"])
+		assert.Equal(t, "Descrição", item.DescriptionI18n["pt"])
+
+		err = repository.SetTranslation(ctx, id, "pt", models.ItemTranslation{Name: "Item novo"})
+		assert.Nil(t, err)
+
+		item, _ = repository.Get(ctx, id)
+		assert.Equal(t, "Item novo", item.NameI18n["pt"])
+		assert.NotContains(t, item.DescriptionI18n, "pt", "an empty description removes the translation")
+
+		err = repository.DeleteTranslation(ctx, id, "pt")
+		assert.Nil(t, err)
+
+		item, _ = repository.Get(ctx, id)
+		assert.NotContains(t, item.NameI18n, "pt")
+
+		err = repository.SetTranslation(ctx, primitive.NewObjectID().Hex(), "pt", models.ItemTranslation{Name: "Item"})
+		assert.Equal(t, repositories.ItemNotFoundError, err)
+
+		err = repository.DeleteTranslation(ctx, primitive.NewObjectID().Hex(), "pt")
+		assert.Equal(t, repositories.ItemNotFoundError, err)
+
+		err = repository.SetTranslation(ctx, "123", "pt", models.ItemTranslation{Name: "Item"})
+		assertInternalError(t, "[SetTranslation] Error in DB", err)
+	})
+
+	t.Run("CountByCategory", func(t *testing.T) {
+		repository := newRepository(t)
+		saveItem(t, repository, mocks.ItemMockOne)
+		saveItem(t, repository, mocks.ItemMockOne)
+		saveItem(t, repository, mocks.ItemMockTwo)
+		bombillas := mocks.ItemMockOne
+		bombillas.Category = models.Category{ID: primitive.NewObjectID().Hex(), Name: "Bombillas", Slug: "bombillas"}
+		saveItem(t, repository, bombillas)
+		for _, status := range []string{models.ItemStatusDeleted, models.ItemStatusUnpriced} {
+			hidden := mocks.ItemMockOne
+			hidden.Status = status
+			saveItem(t, repository, hidden)
+		}
+
+		counts, err := repository.CountByCategory(ctx, "")
+		assert.Nil(t, err)
+		assert.Len(t, counts, 2)
+		assert.Equal(t, "Bombillas", counts[0].Name)
+		assert.Equal(t, "bombillas", counts[0].Slug)
+		assert.Equal(t, int64(1), counts[0].ItemsCount)
+		assert.Equal(t, mocks.CategoryIDOne, counts[1].ID)
+		assert.Equal(t, int64(3), counts[1].ItemsCount)
+
+		counts, err = repository.CountByCategory(ctx, mocks.ShopIDOne)
+		assert.Nil(t, err)
+		assert.Len(t, counts, 2)
+		assert.Equal(t, int64(2), counts[1].ItemsCount)
+
+		counts, err = repository.CountByCategory(ctx, mocks.ShopIDTwo)
+		assert.Nil(t, err)
+		assert.Empty(t, counts)
+	})
+
+	t.Run("Slugs", func(t *testing.T) {
+		repository := newRepository(t)
+		item := mocks.ItemMockOne
+		item.Slug = "mate-imperial"
+		item.SlugHistory = []string{"mate"}
+		id := saveItem(t, repository, item)
+
+		for _, slug := range []string{"mate-imperial", "mate"} {
+			found, err := repository.GetBySlug(ctx, slug)
+			assert.Nil(t, err)
+			assert.Equal(t, id, found.ID)
+		}
+
+		_, err := repository.GetBySlug(ctx, "bombilla")
+		assert.Equal(t, repositories.ItemNotFoundError, err)
+
+		exists, err := repository.SlugExists(ctx, "mate", "")
+		assert.Nil(t, err)
+		assert.True(t, exists)
+
+		exists, err = repository.SlugExists(ctx, "mate", id)
+		assert.Nil(t, err)
+		assert.False(t, exists, "an item can take back its own slugs")
+
+		_, err = repository.SlugExists(ctx, "mate", "123")
+		assertInternalError(t, "[SlugExists] Error in DB", err)
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		repository := newRepository(t)
+		var ids []string
+		for _, name := range []string{"Mate Imperial", "Bombilla", "mate camionero", "Yerba"} {
+			item := mocks.ItemMockOne
+			item.Name = name
+			item.Description = ""
+			ids = append(ids, saveItem(t, repository, item))
+		}
+		other := mocks.ItemMockTwo
+		other.ShopID = mocks.ShopIDTwo
+		other.Name = "Termo"
+		other.Description = "Para el MATE"
+		otherID := saveItem(t, repository, other)
+
+		page, err := repository.Search(ctx, models.ItemsQuery{Text: "MATE", Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), page.Total)
+		assert.Equal(t, []string{ids[0], ids[2]}, idsOf(page.Items))
+
+		page, err = repository.Search(ctx, models.ItemsQuery{Text: "mate", Limit: 2, Offset: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{otherID}, idsOf(page.Items))
+
+		page, err = repository.Search(ctx, models.ItemsQuery{ShopID: mocks.ShopIDOne})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(4), page.Total)
+		assert.Equal(t, ids, idsOf(page.Items))
+
+		page, err = repository.Search(ctx, models.ItemsQuery{Text: "termo", ShopID: mocks.ShopIDOne, Limit: 10})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), page.Total)
+		assert.Equal(t, []models.Item{}, page.Items)
+	})
+}
+
+// saveItem stores a copy of item without its ID and returns the new one.
+func saveItem(t *testing.T, repository repositories.ItemsRepository, item models.Item) string {
+	item.ID = ""
+
+	id, err := repository.Save(context.Background(), item)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	return id.(primitive.ObjectID).Hex()
+}
+
+func idsOf(items []models.Item) []string {
+	ids := []string{}
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+// assertInternalError checks the error of an operation given an ID that is
+// not an ObjectID.
+func assertInternalError(t *testing.T, message string, err apierrors.ApiError) {
+	t.Helper()
+
+	if !assert.NotNil(t, err) {
+		return
+	}
+	assert.Equal(t, http.StatusInternalServerError, err.Status())
+	assert.Equal(t, message, err.Message())
+	assert.EqualValues(t, invalidHexCause, err.Cause()[0])
+}
@@ -0,0 +1,49 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+type RepositoryMock struct {
+	HandleReserve       func(ctx context.Context, record models.IdempotencyRecord) (models.IdempotencyRecord, bool, apierrors.ApiError)
+	HandleComplete      func(ctx context.Context, key string, status int, contentType string, body []byte) apierrors.ApiError
+	HandleRelease       func(ctx context.Context, key string) apierrors.ApiError
+	HandleEnsureIndexes func(ctx context.Context, ttl time.Duration) apierrors.ApiError
+}
+
+func NewRepositoryMock() RepositoryMock {
+	return RepositoryMock{}
+}
+
+func (mock RepositoryMock) Reserve(ctx context.Context, record models.IdempotencyRecord) (models.IdempotencyRecord, bool, apierrors.ApiError) {
+	if mock.HandleReserve != nil {
+		return mock.HandleReserve(ctx, record)
+	}
+	return record, true, nil
+}
+
+func (mock RepositoryMock) Complete(ctx context.Context, key string, status int, contentType string, body []byte) apierrors.ApiError {
+	if mock.HandleComplete != nil {
+		return mock.HandleComplete(ctx, key, status, contentType, body)
+	}
+	return nil
+}
+
+func (mock RepositoryMock) Release(ctx context.Context, key string) apierrors.ApiError {
+	if mock.HandleRelease != nil {
+		return mock.HandleRelease(ctx, key)
+	}
+	return nil
+}
+
+func (mock RepositoryMock) EnsureIndexes(ctx context.Context, ttl time.Duration) apierrors.ApiError {
+	if mock.HandleEnsureIndexes != nil {
+		return mock.HandleEnsureIndexes(ctx, ttl)
+	}
+	return nil
+}
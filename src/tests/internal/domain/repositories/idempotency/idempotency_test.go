@@ -0,0 +1,89 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	mockdeppkg "github.com/agustinrabini/items-api-project/src/tests/internal/api/dependencies"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/setup"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var depMock mockdeppkg.Dependencies
+
+func TestMain(m *testing.M) {
+	depMock = setup.BeforeMemongoTestCase()
+	m.Run()
+	setup.AfterMemongoTestCase()
+	depMock.IdempotencyRepository = nil
+}
+
+func newRecord(key string) models.IdempotencyRecord {
+	return models.IdempotencyRecord{Key: key, Fingerprint: "fingerprint", CreatedAt: time.Now().UTC().Truncate(time.Millisecond)}
+}
+
+func TestRepository_Reserve_New_Key(t *testing.T) {
+	record := newRecord("/items|user|new")
+
+	stored, reserved, err := depMock.IdempotencyRepository.Reserve(context.TODO(), record)
+
+	assert.Nil(t, err)
+	assert.True(t, reserved)
+	assert.Equal(t, record, stored)
+}
+
+func TestRepository_Reserve_Taken_Key_Returns_Stored(t *testing.T) {
+	record := newRecord("/items|user|taken")
+	_, _, err := depMock.IdempotencyRepository.Reserve(context.TODO(), record)
+	assert.Nil(t, err)
+
+	err = depMock.IdempotencyRepository.Complete(context.TODO(), record.Key, http.StatusCreated, "application/json", []byte(`{"id":"1"}`))
+	assert.Nil(t, err)
+
+	retry := newRecord(record.Key)
+	retry.Fingerprint = "other"
+	stored, reserved, err := depMock.IdempotencyRepository.Reserve(context.TODO(), retry)
+
+	assert.Nil(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, "fingerprint", stored.Fingerprint)
+	assert.True(t, stored.Completed)
+	assert.Equal(t, http.StatusCreated, stored.Status)
+	assert.Equal(t, "application/json", stored.ContentType)
+	assert.Equal(t, []byte(`{"id":"1"}`), stored.Body)
+}
+
+func TestRepository_Release_Frees_Key(t *testing.T) {
+	record := newRecord("/items|user|released")
+	_, _, err := depMock.IdempotencyRepository.Reserve(context.TODO(), record)
+	assert.Nil(t, err)
+
+	err = depMock.IdempotencyRepository.Release(context.TODO(), record.Key)
+	assert.Nil(t, err)
+
+	_, reserved, err := depMock.IdempotencyRepository.Reserve(context.TODO(), record)
+
+	assert.Nil(t, err)
+	assert.True(t, reserved)
+}
+
+func TestRepository_EnsureIndexes(t *testing.T) {
+	err := depMock.IdempotencyRepository.EnsureIndexes(context.TODO(), time.Hour)
+	assert.Nil(t, err)
+
+	// creating the same index again is a no-op
+	err = depMock.IdempotencyRepository.EnsureIndexes(context.TODO(), time.Hour)
+	assert.Nil(t, err)
+}
+
+func TestRepository_EnsureIndexes_Changed_TTL(t *testing.T) {
+	err := depMock.IdempotencyRepository.EnsureIndexes(context.TODO(), time.Hour)
+	assert.Nil(t, err)
+
+	err = depMock.IdempotencyRepository.EnsureIndexes(context.TODO(), 2*time.Hour)
+	assert.Nil(t, err)
+}
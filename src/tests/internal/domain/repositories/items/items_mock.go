@@ -20,6 +20,17 @@ type RepositoryMock struct {
 
 	HandleUpdateItemsCategories func(ctx context.Context, category *models.Category) apierrors.ApiError
 	HandleGetByCategoryID       func(ctx context.Context, categoryID string) ([]models.Item, apierrors.ApiError)
+
+	HandleSetTranslation    func(ctx context.Context, itemID string, locale string, translation models.ItemTranslation) apierrors.ApiError
+	HandleDeleteTranslation func(ctx context.Context, itemID string, locale string) apierrors.ApiError
+
+	HandleCountByCategory   func(ctx context.Context, shopID string) ([]models.CategoryCount, apierrors.ApiError)
+	HandleCountByCategoryID func(ctx context.Context, categoryID string) (int64, apierrors.ApiError)
+
+	HandleGetBySlug  func(ctx context.Context, slug string) (models.Item, apierrors.ApiError)
+	HandleSlugExists func(ctx context.Context, slug string, excludeID string) (bool, apierrors.ApiError)
+
+	HandleSearch func(ctx context.Context, query models.ItemsQuery) (models.ItemsPage, apierrors.ApiError)
 }
 
 func NewItemsRepositoryMock() RepositoryMock {
@@ -95,3 +106,52 @@ func (mock RepositoryMock) GetByCategoryID(ctx context.Context, categoryID strin
 	}
 	return []models.Item{}, nil
 }
+
+func (mock RepositoryMock) SetTranslation(ctx context.Context, itemID string, locale string, translation models.ItemTranslation) apierrors.ApiError {
+	if mock.HandleSetTranslation != nil {
+		return mock.HandleSetTranslation(ctx, itemID, locale, translation)
+	}
+	return nil
+}
+
+func (mock RepositoryMock) DeleteTranslation(ctx context.Context, itemID string, locale string) apierrors.ApiError {
+	if mock.HandleDeleteTranslation != nil {
+		return mock.HandleDeleteTranslation(ctx, itemID, locale)
+	}
+	return nil
+}
+
+func (mock RepositoryMock) CountByCategory(ctx context.Context, shopID string) ([]models.CategoryCount, apierrors.ApiError) {
+	if mock.HandleCountByCategory != nil {
+		return mock.HandleCountByCategory(ctx, shopID)
+	}
+	return nil, nil
+}
+
+func (mock RepositoryMock) CountByCategoryID(ctx context.Context, categoryID string) (int64, apierrors.ApiError) {
+	if mock.HandleCountByCategoryID != nil {
+		return mock.HandleCountByCategoryID(ctx, categoryID)
+	}
+	return 0, nil
+}
+
+func (mock RepositoryMock) GetBySlug(ctx context.Context, slug string) (models.Item, apierrors.ApiError) {
+	if mock.HandleGetBySlug != nil {
+		return mock.HandleGetBySlug(ctx, slug)
+	}
+	return models.Item{}, nil
+}
+
+func (mock RepositoryMock) SlugExists(ctx context.Context, slug string, excludeID string) (bool, apierrors.ApiError) {
+	if mock.HandleSlugExists != nil {
+		return mock.HandleSlugExists(ctx, slug, excludeID)
+	}
+	return false, nil
+}
+
+func (mock RepositoryMock) Search(ctx context.Context, query models.ItemsQuery) (models.ItemsPage, apierrors.ApiError) {
+	if mock.HandleSearch != nil {
+		return mock.HandleSearch(ctx, query)
+	}
+	return models.ItemsPage{}, nil
+}
@@ -65,6 +65,23 @@ func TestRepository_Save_Internal_Server_Error(t *testing.T) {
 	})
 }
 
+func TestRepository_Save_Slug_Taken_Error(t *testing.T) {
+	mock := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mock.Cleanup(func() { mock.Client = nil })
+
+	mock.Run("SlugTaken", func(mock *mtest.T) {
+		repository := repositories.NewItemsRepository(mock.DB.Collection(dependencies.KvsItemsCollection))
+
+		mock.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Code: 11000, Message: "E11000 duplicate key error"}))
+
+		id, err := repository.Save(context.TODO(), mocks.ItemsMock.Items[0])
+
+		assert.Nil(mock, id)
+		assert.Equal(mock, repositories.SlugTakenError, err)
+	})
+}
+
 func TestRepository_Get_Success_By_Id(t *testing.T) {
 
 	arrangetItem := mocks.ItemMockOne
@@ -655,3 +672,154 @@ func TestRepository_GetByCategoryID_Bad_Parse_Error(t *testing.T) {
 		assert.EqualValues(mock, http.StatusInternalServerError, err.Status())
 	})
 }
+
+func TestRepository_SetTranslation_Success(t *testing.T) {
+	arrangetItem := mocks.ItemMockOne
+	arrangetItem.ID = ""
+	idinterface, err := depMock.ItemsRepository.Save(context.Background(), arrangetItem)
+	if err != nil {
+		log.Fatal(err)
+	}
+	hexID := fmt.Sprint(idinterface)[10 : len(fmt.Sprint(idinterface))-2]
+
+	err = depMock.ItemsRepository.SetTranslation(context.TODO(), hexID, "pt", models.ItemTranslation{Name: "Nome", Description: "Descrição"})
+	assert.Nil(t, err)
+
+	updated, _ := depMock.ItemsRepository.Get(context.TODO(), hexID)
+
+	assert.Equal(t, models.Translations{"pt": "Nome"}, updated.NameI18n)
+	assert.Equal(t, models.Translations{"pt": "Descrição"}, updated.DescriptionI18n)
+
+	err = depMock.ItemsRepository.DeleteTranslation(context.TODO(), hexID, "pt")
+	assert.Nil(t, err)
+
+	updated, _ = depMock.ItemsRepository.Get(context.TODO(), hexID)
+
+	assert.Empty(t, updated.NameI18n)
+	assert.Empty(t, updated.DescriptionI18n)
+}
+
+func TestRepository_SetTranslation_Not_Found_Error(t *testing.T) {
+	err := depMock.ItemsRepository.SetTranslation(context.TODO(), primitive.NewObjectID().Hex(), "pt", models.ItemTranslation{Name: "Nome"})
+
+	assert.EqualValues(t, "not_found", err.Code())
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestRepository_DeleteTranslation_Invalid_Id_Error(t *testing.T) {
+	err := depMock.ItemsRepository.DeleteTranslation(context.TODO(), "fake_id", "pt")
+
+	assert.EqualValues(t, "internal_server_error", err.Code())
+	assert.EqualValues(t, fmt.Sprintf(repositories.ItemsDatabaseError, "DeleteTranslation"), err.Message())
+	assert.EqualValues(t, http.StatusInternalServerError, err.Status())
+}
+
+func TestRepository_CountByCategory_Success(t *testing.T) {
+	arrangetItem := mocks.ItemMockOne
+	arrangetItem.ID = ""
+	arrangetItem.ShopID = primitive.NewObjectID().Hex()
+	arrangetItem.Category = models.Category{ID: primitive.NewObjectID().Hex(), Name: "count"}
+
+	for i := 0; i < 2; i++ {
+		_, err := depMock.ItemsRepository.Save(context.Background(), arrangetItem)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	counts, err := depMock.ItemsRepository.CountByCategory(context.TODO(), arrangetItem.ShopID)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []models.CategoryCount{{Category: arrangetItem.Category, ItemsCount: 2}}, counts)
+
+	count, err := depMock.ItemsRepository.CountByCategoryID(context.TODO(), arrangetItem.Category.ID)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, count)
+}
+
+func TestRepository_CountByCategory_Internal_Server_Error(t *testing.T) {
+	mock := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mock.Cleanup(func() { mock.Client = nil })
+
+	mock.Run("InternalServerError", func(mock *mtest.T) {
+		repository := repositories.NewItemsRepository(mock.DB.Collection(dependencies.KvsItemsCollection))
+
+		mock.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{}))
+
+		counts, err := repository.CountByCategory(context.TODO(), "")
+
+		assert.Nil(mock, counts)
+		assert.EqualValues(mock, "internal_server_error", err.Code())
+		assert.EqualValues(mock, fmt.Sprintf(repositories.ItemsDatabaseError, "CountByCategory"), err.Message())
+	})
+}
+
+func TestRepository_GetBySlug_Current_And_Previous(t *testing.T) {
+	item := models.Item{Name: "Remera", ShopID: mocks.ShopIDOne, Slug: "remera-lisa", SlugHistory: []string{"remera"}}
+	idinterface, err := depMock.ItemsRepository.Save(context.TODO(), item)
+	if err != nil {
+		log.Fatal(err)
+	}
+	hexID := idinterface.(primitive.ObjectID).Hex()
+
+	found, err := depMock.ItemsRepository.GetBySlug(context.TODO(), "remera-lisa")
+	assert.EqualValues(t, nil, err)
+	assert.Equal(t, hexID, found.ID)
+
+	found, err = depMock.ItemsRepository.GetBySlug(context.TODO(), "remera")
+	assert.EqualValues(t, nil, err)
+	assert.Equal(t, "remera-lisa", found.Slug)
+
+	exists, err := depMock.ItemsRepository.SlugExists(context.TODO(), "remera", "")
+	assert.EqualValues(t, nil, err)
+	assert.True(t, exists)
+
+	exists, err = depMock.ItemsRepository.SlugExists(context.TODO(), "remera", hexID)
+	assert.EqualValues(t, nil, err)
+	assert.False(t, exists)
+}
+
+func TestRepository_GetBySlug_Not_Found_Error(t *testing.T) {
+	_, err := depMock.ItemsRepository.GetBySlug(context.TODO(), "missing-slug")
+
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestRepository_SlugExists_Invalid_Id_Error(t *testing.T) {
+	_, err := depMock.ItemsRepository.SlugExists(context.TODO(), "remera", "fake_id")
+
+	assert.EqualValues(t, fmt.Sprintf(repositories.ItemsDatabaseError, "SlugExists"), err.Message())
+	assert.EqualValues(t, http.StatusInternalServerError, err.Status())
+}
+
+func TestRepository_Search_Text_And_Pages(t *testing.T) {
+	shopID := primitive.NewObjectID().Hex()
+	for _, name := range []string{"Remera Azul", "Remera (roja)", "Pantalon"} {
+		item := models.Item{Name: name, ShopID: shopID}
+		if _, err := depMock.ItemsRepository.Save(context.TODO(), item); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	page, err := depMock.ItemsRepository.Search(context.TODO(), models.ItemsQuery{ShopID: shopID, Text: "remera", Limit: 1})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, page.Total)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "Remera Azul", page.Items[0].Name)
+	assert.True(t, page.HasMore(models.ItemsQuery{Limit: 1}))
+
+	page, err = depMock.ItemsRepository.Search(context.TODO(), models.ItemsQuery{ShopID: shopID, Text: "(roja)", Limit: 10})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, page.Total)
+	assert.Equal(t, "Remera (roja)", page.Items[0].Name)
+
+	page, err = depMock.ItemsRepository.Search(context.TODO(), models.ItemsQuery{ShopID: shopID, Limit: 10, Offset: 3})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, page.Total)
+	assert.Empty(t, page.Items)
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories/memory"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/mocks"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/repositories/conformance"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestItemsRepository_Conformance(t *testing.T) {
+	conformance.RunItemsRepository(t, func(t *testing.T) repositories.ItemsRepository {
+		return memory.NewItemsRepository()
+	})
+}
+
+func TestCategoriesRepository_Conformance(t *testing.T) {
+	conformance.RunCategoriesRepository(t, func(t *testing.T) repositories.CategoriesRepository {
+		return memory.NewCategoriesRepository()
+	})
+}
+
+// Mirrors the unique slug index of the migrations, which the Mongo
+// conformance run doesn't create.
+func TestItemsRepository_Slug_Taken_Error(t *testing.T) {
+	repository := memory.NewItemsRepository()
+	ctx := context.Background()
+
+	item := mocks.ItemMockOne
+	item.ID, item.Slug = "", "mate"
+	_, err := repository.Save(ctx, item)
+	assert.Nil(t, err)
+
+	_, err = repository.Save(ctx, item)
+	assert.Equal(t, repositories.SlugTakenError, err)
+
+	item.Slug = "bombilla"
+	id, err := repository.Save(ctx, item)
+	assert.Nil(t, err)
+
+	_, err = repository.Update(ctx, id.(primitive.ObjectID).Hex(), &models.Item{Slug: "mate"})
+	assert.Equal(t, repositories.SlugTakenError, err)
+
+	_, err = repository.Update(ctx, id.(primitive.ObjectID).Hex(), &models.Item{Slug: "bombilla", Description: "updated"})
+	assert.Nil(t, err, "an item keeps its own slug")
+
+	item.Slug = ""
+	_, err = repository.Save(ctx, item)
+	assert.Nil(t, err)
+	_, err = repository.Save(ctx, item)
+	assert.Nil(t, err, "items without a slug are left out")
+}
+
+func TestItemsRepository_Concurrent_Use(t *testing.T) {
+	repository := memory.NewItemsRepository()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			item := mocks.ItemMockOne
+			item.ID = ""
+			item.Name = fmt.Sprintf("Item %d", i)
+			id, err := repository.Save(ctx, item)
+			assert.Nil(t, err)
+
+			_, err = repository.Update(ctx, id.(primitive.ObjectID).Hex(), &models.Item{Description: "updated"})
+			assert.Nil(t, err)
+
+			_, err = repository.GetByShopID(ctx, mocks.ShopIDOne)
+			assert.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	items, err := repository.GetByShopID(ctx, mocks.ShopIDOne)
+
+	assert.Nil(t, err)
+	assert.Len(t, items.Items, 20)
+	for _, item := range items.Items {
+		assert.Equal(t, "updated", item.Description)
+	}
+}
+
+func TestCategoriesRepository_Create_Keeps_The_Given_ID(t *testing.T) {
+	repository := memory.NewCategoriesRepository()
+
+	id, err := repository.Create(context.Background(), models.Category{ID: "mates", Name: "Mates"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "mates", id)
+}
@@ -0,0 +1,51 @@
+package reconciliation
+
+import (
+	"context"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+type RepositoryMock struct {
+	HandleSave func(ctx context.Context, report models.ReconciliationReport) apierrors.ApiError
+	HandleLast func(ctx context.Context) (models.ReconciliationReport, apierrors.ApiError)
+
+	HandleAcquireLease func(ctx context.Context, owner string, ttl time.Duration) (bool, apierrors.ApiError)
+	HandleReleaseLease func(ctx context.Context, owner string) apierrors.ApiError
+}
+
+func NewRepositoryMock() RepositoryMock {
+	return RepositoryMock{}
+}
+
+func (mock RepositoryMock) Save(ctx context.Context, report models.ReconciliationReport) apierrors.ApiError {
+	if mock.HandleSave != nil {
+		return mock.HandleSave(ctx, report)
+	}
+	return nil
+}
+
+func (mock RepositoryMock) Last(ctx context.Context) (models.ReconciliationReport, apierrors.ApiError) {
+	if mock.HandleLast != nil {
+		return mock.HandleLast(ctx)
+	}
+	return models.ReconciliationReport{}, repositories.ReconciliationReportNotFoundError
+}
+
+func (mock RepositoryMock) AcquireLease(ctx context.Context, owner string, ttl time.Duration) (bool, apierrors.ApiError) {
+	if mock.HandleAcquireLease != nil {
+		return mock.HandleAcquireLease(ctx, owner, ttl)
+	}
+	return true, nil
+}
+
+func (mock RepositoryMock) ReleaseLease(ctx context.Context, owner string) apierrors.ApiError {
+	if mock.HandleReleaseLease != nil {
+		return mock.HandleReleaseLease(ctx, owner)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package reconciliation
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	mockdeppkg "github.com/agustinrabini/items-api-project/src/tests/internal/api/dependencies"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/setup"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var depMock mockdeppkg.Dependencies
+
+func TestMain(m *testing.M) {
+	depMock = setup.BeforeMemongoTestCase()
+	m.Run()
+	setup.AfterMemongoTestCase()
+	depMock.ReconciliationRepository = nil
+}
+
+func TestRepository_Last_Not_Found(t *testing.T) {
+	_, err := depMock.ReconciliationRepository.Last(context.TODO())
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.Status())
+}
+
+func TestRepository_Last_Returns_Latest_Run(t *testing.T) {
+	finished := time.Now().UTC().Truncate(time.Millisecond)
+	older := models.ReconciliationReport{FinishedAt: finished.Add(-time.Hour), ItemsChecked: 1, Findings: []models.ReconciliationFinding{}}
+	latest := models.ReconciliationReport{FinishedAt: finished, ItemsChecked: 2, Findings: []models.ReconciliationFinding{
+		{Reason: models.ReconciliationMissingPrice, ItemID: "1", ShopID: "shop", Status: models.ItemStatusActive},
+	}}
+
+	assert.Nil(t, depMock.ReconciliationRepository.Save(context.TODO(), latest))
+	assert.Nil(t, depMock.ReconciliationRepository.Save(context.TODO(), older))
+
+	report, err := depMock.ReconciliationRepository.Last(context.TODO())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, report.ItemsChecked)
+	assert.Equal(t, latest.Findings, report.Findings)
+	assert.True(t, latest.FinishedAt.Equal(report.FinishedAt))
+}
+
+func TestRepository_Lease(t *testing.T) {
+	ctx := context.TODO()
+	repository := depMock.ReconciliationRepository
+
+	acquired, err := repository.AcquireLease(ctx, "replica-1", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = repository.AcquireLease(ctx, "replica-2", time.Minute)
+	assert.Nil(t, err)
+	assert.False(t, acquired, "held by replica-1")
+
+	acquired, err = repository.AcquireLease(ctx, "replica-1", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, acquired, "renewed by its owner")
+
+	assert.Nil(t, repository.ReleaseLease(ctx, "replica-2"))
+	acquired, _ = repository.AcquireLease(ctx, "replica-2", time.Minute)
+	assert.False(t, acquired, "only the owner releases it")
+
+	assert.Nil(t, repository.ReleaseLease(ctx, "replica-1"))
+	acquired, err = repository.AcquireLease(ctx, "replica-2", -time.Second)
+	assert.Nil(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = repository.AcquireLease(ctx, "replica-1", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, acquired, "the lease of replica-2 expired")
+}
@@ -0,0 +1,49 @@
+package revisions
+
+import (
+	"context"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+type RepositoryMock struct {
+	HandleSave func(ctx context.Context, revision models.Revision) apierrors.ApiError
+	HandleGet  func(ctx context.Context, itemID string, number int) (models.Revision, apierrors.ApiError)
+	HandleLast func(ctx context.Context, itemID string) (models.Revision, apierrors.ApiError)
+	HandleList func(ctx context.Context, itemID string) ([]models.Revision, apierrors.ApiError)
+}
+
+func NewRepositoryMock() RepositoryMock {
+	return RepositoryMock{}
+}
+
+func (mock RepositoryMock) Save(ctx context.Context, revision models.Revision) apierrors.ApiError {
+	if mock.HandleSave != nil {
+		return mock.HandleSave(ctx, revision)
+	}
+	return nil
+}
+
+func (mock RepositoryMock) Get(ctx context.Context, itemID string, number int) (models.Revision, apierrors.ApiError) {
+	if mock.HandleGet != nil {
+		return mock.HandleGet(ctx, itemID, number)
+	}
+	return models.Revision{}, repositories.RevisionNotFoundError
+}
+
+func (mock RepositoryMock) Last(ctx context.Context, itemID string) (models.Revision, apierrors.ApiError) {
+	if mock.HandleLast != nil {
+		return mock.HandleLast(ctx, itemID)
+	}
+	return models.Revision{}, repositories.RevisionNotFoundError
+}
+
+func (mock RepositoryMock) List(ctx context.Context, itemID string) ([]models.Revision, apierrors.ApiError) {
+	if mock.HandleList != nil {
+		return mock.HandleList(ctx, itemID)
+	}
+	return []models.Revision{}, nil
+}
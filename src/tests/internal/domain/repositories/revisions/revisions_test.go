@@ -0,0 +1,72 @@
+package revisions
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	mockdeppkg "github.com/agustinrabini/items-api-project/src/tests/internal/api/dependencies"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/setup"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var depMock mockdeppkg.Dependencies
+
+func TestMain(m *testing.M) {
+	depMock = setup.BeforeMemongoTestCase()
+	m.Run()
+	setup.AfterMemongoTestCase()
+	depMock.RevisionsRepository = nil
+}
+
+func revision(itemID string, number int, description string) models.Revision {
+	return models.Revision{
+		ItemID:    itemID,
+		Number:    number,
+		Action:    models.RevisionUpdate,
+		Actor:     "user",
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+		Changes:   []models.FieldChange{{Field: "description", After: description}},
+		Item:      &models.Item{Name: "Boots", Description: description},
+		Price:     &models.Price{Amount: float64(number)},
+	}
+}
+
+func TestRepository_Last_Not_Found(t *testing.T) {
+	_, err := depMock.RevisionsRepository.Last(context.TODO(), "missing")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.Status())
+}
+
+func TestRepository_Save_Get_Last_List(t *testing.T) {
+	assert.Nil(t, depMock.RevisionsRepository.Save(context.TODO(), revision("boots", 2, "Suede")))
+	assert.Nil(t, depMock.RevisionsRepository.Save(context.TODO(), revision("boots", 1, "Leather")))
+	assert.Nil(t, depMock.RevisionsRepository.Save(context.TODO(), revision("hat", 1, "Wool")))
+
+	last, err := depMock.RevisionsRepository.Last(context.TODO(), "boots")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, last.Number)
+	assert.Equal(t, "Suede", last.Item.Description)
+	assert.Equal(t, 2.0, last.Price.Amount)
+
+	first, err := depMock.RevisionsRepository.Get(context.TODO(), "boots", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "Leather", first.Item.Description)
+
+	list, err := depMock.RevisionsRepository.List(context.TODO(), "boots")
+	assert.Nil(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, 1, list[0].Number)
+	assert.Equal(t, 2, list[1].Number)
+	assert.Nil(t, list[0].Item)
+	assert.Nil(t, list[0].Price)
+	assert.Equal(t, "Leather", list[0].Changes[0].After)
+
+	_, err = depMock.RevisionsRepository.Get(context.TODO(), "boots", 3)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.Status())
+}
@@ -0,0 +1,240 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories/memory"
+	"github.com/agustinrabini/items-api-project/src/main/domain/services"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/clients"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type fixture struct {
+	items      repositories.ItemsRepository
+	categories repositories.CategoriesRepository
+	prices     clients.PriceClientMock
+}
+
+func newFixture() *fixture {
+	return &fixture{
+		items:      memory.NewItemsRepository(),
+		categories: memory.NewCategoriesRepository(),
+		prices:     clients.NewPriceClientMock(),
+	}
+}
+
+func (f *fixture) service() services.AdminService {
+	return services.NewAdminService(f.items, f.categories, f.prices)
+}
+
+func (f *fixture) category(t *testing.T, name string, slug string) models.Category {
+	id, err := f.categories.Create(context.Background(), models.Category{Name: name, Slug: slug})
+	assert.Nil(t, err)
+
+	category, err := f.categories.Get(context.Background(), id.(primitive.ObjectID).Hex())
+	assert.Nil(t, err)
+
+	return category
+}
+
+func (f *fixture) item(t *testing.T, name string, status string, category models.Category) string {
+	item := models.Item{Name: name, ShopID: "shop", UserID: "user", Status: status, Category: category}
+	id, err := f.items.Save(context.Background(), item)
+	assert.Nil(t, err)
+
+	return id.(primitive.ObjectID).Hex()
+}
+
+func TestAdminService_RecountCategories(t *testing.T) {
+	f := newFixture()
+	shoes := f.category(t, "Shoes", "shoes")
+	hats := f.category(t, "Hats", "hats")
+	f.item(t, "Boots", models.ItemStatusActive, shoes)
+	f.item(t, "Sandals", models.ItemStatusActive, models.Category{ID: shoes.ID, Name: "Old shoes", Slug: "old-shoes"})
+	f.item(t, "Scarf", models.ItemStatusActive, models.Category{ID: primitive.NewObjectID().Hex(), Name: "Gone"})
+
+	report, apiErr := f.service().RecountCategories(context.Background(), true)
+
+	assert.Nil(t, apiErr)
+	assert.True(t, report.DryRun)
+	assert.Len(t, report.Categories, 2)
+	assert.Len(t, report.Orphans, 1)
+	assert.Equal(t, "Gone", report.Orphans[0].Name)
+	for _, count := range report.Categories {
+		switch count.ID {
+		case shoes.ID:
+			assert.Equal(t, int64(2), count.Items)
+			assert.True(t, count.Resynced)
+		case hats.ID:
+			assert.Equal(t, int64(0), count.Items)
+			assert.False(t, count.Resynced)
+		}
+	}
+
+	items, _ := f.items.GetByCategoryID(context.Background(), shoes.ID)
+	assert.ElementsMatch(t, []string{"Shoes", "Old shoes"}, []string{items[0].Category.Name, items[1].Category.Name})
+
+	_, apiErr = f.service().RecountCategories(context.Background(), false)
+	assert.Nil(t, apiErr)
+
+	items, _ = f.items.GetByCategoryID(context.Background(), shoes.ID)
+	for _, item := range items {
+		assert.Equal(t, "Shoes", item.Category.Name)
+		assert.Equal(t, "shoes", item.Category.Slug)
+	}
+}
+
+func TestAdminService_ExportShop_Empty(t *testing.T) {
+	f := newFixture()
+
+	export, apiErr := f.service().ExportShop(context.Background(), "shop")
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, "shop", export.ShopID)
+	assert.NotNil(t, export.Items)
+	assert.Empty(t, export.Items)
+}
+
+func TestAdminService_ImportItems(t *testing.T) {
+	f := newFixture()
+	shoes := f.category(t, "Shoes", "shoes")
+	bootsID := f.item(t, "Boots", models.ItemStatusActive, shoes)
+	sandalsID := f.item(t, "Sandals", models.ItemStatusActive, shoes)
+
+	boots, _ := f.items.Get(context.Background(), bootsID)
+	sandals, _ := f.items.Get(context.Background(), sandalsID)
+	boots.Description = "Leather boots"
+	sandals.Category = models.Category{ID: shoes.ID}
+	input := []models.Item{
+		boots,
+		sandals,
+		{ID: "old-id", Name: "Sneakers", ShopID: "shop", UserID: "user", Category: models.Category{ID: shoes.ID}},
+	}
+
+	dryRun, apiErr := f.service().ImportItems(context.Background(), input, true)
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, bootsID, dryRun.Updated[0].ID)
+	assert.Equal(t, sandalsID, dryRun.Unchanged[0].ID)
+	assert.Len(t, dryRun.Created, 1)
+	assert.Empty(t, dryRun.Created[0].ID)
+
+	report, apiErr := f.service().ImportItems(context.Background(), input, false)
+
+	assert.Nil(t, apiErr)
+	assert.Len(t, report.Updated, 1)
+	assert.Equal(t, bootsID, report.Updated[0].ID)
+	assert.Equal(t, sandalsID, report.Unchanged[0].ID)
+	assert.Equal(t, "old-id", report.Created[0].SourceID)
+
+	created, apiErr := f.items.Get(context.Background(), report.Created[0].ID)
+	assert.Nil(t, apiErr)
+	assert.Equal(t, "sneakers", created.Slug)
+	assert.Equal(t, "Shoes", created.Category.Name)
+	assert.Equal(t, models.ItemStatusActive, created.Status)
+
+	updated, _ := f.items.Get(context.Background(), bootsID)
+	assert.Equal(t, "Leather boots", updated.Description)
+}
+
+func TestAdminService_ImportItems_Dry_Run_Unchanged_Attributes(t *testing.T) {
+	f := newFixture()
+	shoes := f.category(t, "Shoes", "shoes")
+	attributes := models.Attributes{"Color": "Black", "Size": "42", "Material": "Leather", "Sole": "Rubber"}
+	id, apiErr := f.items.Save(context.Background(), models.Item{Name: "Boots", ShopID: "shop", UserID: "user", Status: models.ItemStatusActive, Category: shoes, Attributes: attributes})
+	assert.Nil(t, apiErr)
+
+	stored, _ := f.items.Get(context.Background(), id.(primitive.ObjectID).Hex())
+
+	report, apiErr := f.service().ImportItems(context.Background(), []models.Item{stored}, true)
+
+	assert.Nil(t, apiErr)
+	assert.Empty(t, report.Updated)
+	assert.Len(t, report.Unchanged, 1)
+}
+
+func TestAdminService_ImportItems_Unknown_Category(t *testing.T) {
+	f := newFixture()
+	input := []models.Item{{Name: "Boots", ShopID: "shop", UserID: "user", Category: models.Category{ID: primitive.NewObjectID().Hex()}}}
+
+	_, apiErr := f.service().ImportItems(context.Background(), input, false)
+
+	assert.NotNil(t, apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Status())
+
+	items, _ := f.items.GetByShopID(context.Background(), "shop")
+	assert.Empty(t, items.Items)
+}
+
+func TestAdminService_PurgeTrash(t *testing.T) {
+	f := newFixture()
+	shoes := f.category(t, "Shoes", "shoes")
+	f.item(t, "Boots", models.ItemStatusActive, shoes)
+	trashedID := f.item(t, "Sandals", models.ItemStatusDeleted, shoes)
+	failingID := f.item(t, "Sneakers", models.ItemStatusDeleted, shoes)
+
+	var deletedPrices []string
+	f.prices.HandleDeletePrice = func(ctx context.Context, itemID string) apierrors.ApiError {
+		deletedPrices = append(deletedPrices, itemID)
+		if itemID == failingID {
+			return apierrors.NewUnauthorizedApiError("no token")
+		}
+		return nil
+	}
+
+	dryRun, apiErr := f.service().PurgeTrash(context.Background(), true)
+
+	assert.Nil(t, apiErr)
+	assert.Len(t, dryRun.Purged, 2)
+	assert.Empty(t, deletedPrices)
+
+	report, apiErr := f.service().PurgeTrash(context.Background(), false)
+
+	assert.Nil(t, apiErr)
+	assert.Len(t, report.Purged, 2)
+	assert.ElementsMatch(t, []string{trashedID, failingID}, deletedPrices)
+	for _, purged := range report.Purged {
+		if purged.ID == failingID {
+			assert.Equal(t, "no token", purged.PriceError)
+		} else {
+			assert.Empty(t, purged.PriceError)
+		}
+	}
+
+	items, _ := f.items.GetByShopID(context.Background(), "shop")
+	assert.Len(t, items.Items, 1)
+	assert.Equal(t, "Boots", items.Items[0].Name)
+}
+
+func TestAdminService_ReassignCategory(t *testing.T) {
+	f := newFixture()
+	shoes := f.category(t, "Shoes", "shoes")
+	footwear := f.category(t, "Footwear", "footwear")
+	bootsID := f.item(t, "Boots", models.ItemStatusActive, shoes)
+
+	report, apiErr := f.service().ReassignCategory(context.Background(), shoes.ID, footwear.ID, false)
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, []string{bootsID}, report.Items)
+	assert.Equal(t, "Footwear", report.To.Name)
+
+	boots, _ := f.items.Get(context.Background(), bootsID)
+	assert.Equal(t, footwear.ID, boots.Category.ID)
+	assert.Equal(t, "footwear", boots.Category.Slug)
+}
+
+func TestAdminService_ReassignCategory_Same_Category(t *testing.T) {
+	f := newFixture()
+
+	_, apiErr := f.service().ReassignCategory(context.Background(), "1", "1", false)
+
+	assert.NotNil(t, apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Status())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
 
 	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
 )
@@ -12,24 +13,30 @@ type ServiceMock struct {
 	HandleGet              func(ctx context.Context, categoryID string) (models.Category, apierrors.ApiError)
 	HandleGetAllCategories func(ctx context.Context) ([]models.Category, apierrors.ApiError)
 	HandleCreate           func(ctx context.Context, input models.Category) apierrors.ApiError
-	HandleUpdate           func(ctx context.Context, input models.Category) apierrors.ApiError
-	HandleDelete           func(ctx context.Context, items []models.Item, categoryID string) apierrors.ApiError
+	HandleUpdate           func(ctx context.Context, input models.Category) (models.Category, apierrors.ApiError)
+	HandleDelete           func(ctx context.Context, itemsCount int64, categoryID string) apierrors.ApiError
+	HandleGetBySlug        func(ctx context.Context, slug string) (models.Category, apierrors.ApiError)
+	HandleImport           func(ctx context.Context, input []models.Category, dryRun bool) (dto.CategoriesImportDTO, apierrors.ApiError)
+	HandleExport           func(ctx context.Context) ([]models.Category, apierrors.ApiError)
+
+	HandleSetTranslation    func(ctx context.Context, categoryID string, locale string, name string) (models.Category, apierrors.ApiError)
+	HandleDeleteTranslation func(ctx context.Context, categoryID string, locale string) (models.Category, apierrors.ApiError)
 }
 
 func NewServiceMock() ServiceMock {
 	return ServiceMock{}
 }
 
-func (mock ServiceMock) Update(ctx context.Context, input models.Category) apierrors.ApiError {
+func (mock ServiceMock) Update(ctx context.Context, input models.Category) (models.Category, apierrors.ApiError) {
 	if mock.HandleUpdate != nil {
 		return mock.HandleUpdate(ctx, input)
 	}
-	return nil
+	return input, nil
 }
 
-func (mock ServiceMock) Delete(ctx context.Context, items []models.Item, categoryID string) apierrors.ApiError {
+func (mock ServiceMock) Delete(ctx context.Context, itemsCount int64, categoryID string) apierrors.ApiError {
 	if mock.HandleDelete != nil {
-		return mock.HandleDelete(ctx, items, categoryID)
+		return mock.HandleDelete(ctx, itemsCount, categoryID)
 	}
 	return nil
 }
@@ -54,3 +61,38 @@ func (mock ServiceMock) GetAllCategories(ctx context.Context) ([]models.Category
 	}
 	return []models.Category{}, nil
 }
+
+func (mock ServiceMock) SetTranslation(ctx context.Context, categoryID string, locale string, name string) (models.Category, apierrors.ApiError) {
+	if mock.HandleSetTranslation != nil {
+		return mock.HandleSetTranslation(ctx, categoryID, locale, name)
+	}
+	return models.Category{}, nil
+}
+
+func (mock ServiceMock) DeleteTranslation(ctx context.Context, categoryID string, locale string) (models.Category, apierrors.ApiError) {
+	if mock.HandleDeleteTranslation != nil {
+		return mock.HandleDeleteTranslation(ctx, categoryID, locale)
+	}
+	return models.Category{}, nil
+}
+
+func (mock ServiceMock) GetBySlug(ctx context.Context, slug string) (models.Category, apierrors.ApiError) {
+	if mock.HandleGetBySlug != nil {
+		return mock.HandleGetBySlug(ctx, slug)
+	}
+	return models.Category{}, nil
+}
+
+func (mock ServiceMock) Import(ctx context.Context, input []models.Category, dryRun bool) (dto.CategoriesImportDTO, apierrors.ApiError) {
+	if mock.HandleImport != nil {
+		return mock.HandleImport(ctx, input, dryRun)
+	}
+	return dto.CategoriesImportDTO{}, nil
+}
+
+func (mock ServiceMock) Export(ctx context.Context) ([]models.Category, apierrors.ApiError) {
+	if mock.HandleExport != nil {
+		return mock.HandleExport(ctx)
+	}
+	return []models.Category{}, nil
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestService_Get_Success(t *testing.T) {
@@ -166,7 +167,7 @@ func TestService_Update_Success(t *testing.T) {
 
 	service := services.NewCategoriesService(repository)
 
-	err := service.Update(context.TODO(), mocks.CategoryOne)
+	_, err := service.Update(context.TODO(), mocks.CategoryOne)
 
 	assert.Nil(t, err)
 }
@@ -179,7 +180,7 @@ func TestService_Update_Get_Categories_Error(t *testing.T) {
 
 	service := services.NewCategoriesService(repository)
 
-	err := service.Update(context.TODO(), mocks.CategoryOne)
+	_, err := service.Update(context.TODO(), mocks.CategoryOne)
 
 	assert.NotNil(t, err)
 	assert.Equal(t, http.StatusInternalServerError, err.Status())
@@ -193,7 +194,7 @@ func TestService_Update_Already_Exist_Error(t *testing.T) {
 
 	service := services.NewCategoriesService(repository)
 
-	err := service.Update(context.TODO(), mocks.CategoryOne)
+	_, err := service.Update(context.TODO(), mocks.CategoryOne)
 
 	assert.NotNil(t, err)
 	assert.Equal(t, http.StatusConflict, err.Status())
@@ -207,7 +208,7 @@ func TestService_Update_Repository_Error(t *testing.T) {
 
 	service := services.NewCategoriesService(repository)
 
-	err := service.Update(context.TODO(), mocks.CategoryOne)
+	_, err := service.Update(context.TODO(), mocks.CategoryOne)
 
 	assert.NotNil(t, err)
 	assert.Equal(t, http.StatusInternalServerError, err.Status())
@@ -221,7 +222,7 @@ func TestService_Delete_Success(t *testing.T) {
 
 	service := services.NewCategoriesService(repository)
 
-	err := service.Delete(context.TODO(), []models.Item{}, "1")
+	err := service.Delete(context.TODO(), 0, "1")
 
 	assert.Nil(t, err)
 }
@@ -234,7 +235,7 @@ func TestService_Delete_Conflic_Deletion_Error(t *testing.T) {
 
 	service := services.NewCategoriesService(repository)
 
-	err := service.Delete(context.TODO(), mocks.ItemsMock.Items, "1")
+	err := service.Delete(context.TODO(), int64(len(mocks.ItemsMock.Items)), "1")
 
 	assert.NotNil(t, err)
 	assert.Equal(t, http.StatusConflict, err.Status())
@@ -248,8 +249,259 @@ func TestService_Delete_Repository_Error(t *testing.T) {
 
 	service := services.NewCategoriesService(repository)
 
-	err := service.Delete(context.TODO(), []models.Item{}, "1")
+	err := service.Delete(context.TODO(), 0, "1")
 
 	assert.NotNil(t, err)
 	assert.Equal(t, http.StatusInternalServerError, err.Status())
 }
+
+func TestService_SetTranslation_Success(t *testing.T) {
+	var translated = mocks.CategoryOne
+	translated.NameI18n = models.Translations{"pt": "Nome Um"}
+
+	repository := categories.NewRepositoryMock()
+	repository.HandleSetTranslation = func(ctx context.Context, categoryID string, locale string, name string) apierrors.ApiError {
+		return nil
+	}
+	repository.HandleGet = func(ctx context.Context, categoryID string) (models.Category, apierrors.ApiError) {
+		return translated, nil
+	}
+
+	service := services.NewCategoriesService(repository)
+
+	category, err := service.SetTranslation(context.TODO(), mocks.CategoryOne.ID, "pt", "Nome Um")
+
+	assert.Nil(t, err)
+	assert.Equal(t, translated, category)
+}
+
+func TestService_SetTranslation_Not_Found_Error(t *testing.T) {
+	repository := categories.NewRepositoryMock()
+	repository.HandleSetTranslation = func(ctx context.Context, categoryID string, locale string, name string) apierrors.ApiError {
+		return apierrors.NewNotFoundApiError("mock error")
+	}
+
+	service := services.NewCategoriesService(repository)
+
+	_, err := service.SetTranslation(context.TODO(), mocks.CategoryOne.ID, "pt", "Nome Um")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.Status())
+}
+
+func TestService_DeleteTranslation_Success(t *testing.T) {
+	repository := categories.NewRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, categoryID string) (models.Category, apierrors.ApiError) {
+		return mocks.CategoryOne, nil
+	}
+
+	service := services.NewCategoriesService(repository)
+
+	category, err := service.DeleteTranslation(context.TODO(), mocks.CategoryOne.ID, "pt")
+
+	assert.Nil(t, err)
+	assert.Equal(t, mocks.CategoryOne, category)
+}
+
+func TestService_DeleteTranslation_Repository_Error(t *testing.T) {
+	repository := categories.NewRepositoryMock()
+	repository.HandleDeleteTranslation = func(ctx context.Context, categoryID string, locale string) apierrors.ApiError {
+		return apierrors.NewInternalServerApiError("error mock", fmt.Errorf("error mock"))
+	}
+
+	service := services.NewCategoriesService(repository)
+
+	_, err := service.DeleteTranslation(context.TODO(), mocks.CategoryOne.ID, "pt")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, err.Status())
+}
+
+func TestService_Create_Sets_Slug(t *testing.T) {
+	var created models.Category
+
+	repository := categories.NewRepositoryMock()
+	repository.HandleCreate = func(ctx context.Context, input models.Category) (interface{}, apierrors.ApiError) {
+		created = input
+		return primitive.NewObjectID(), nil
+	}
+
+	service := services.NewCategoriesService(repository)
+
+	err := service.Create(context.TODO(), models.Category{Name: "Zapatos de Niño"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "zapatos-de-nino", created.Slug)
+}
+
+func TestService_Update_Keeps_Slug_History(t *testing.T) {
+	repository := categories.NewRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, categoryID string) (models.Category, apierrors.ApiError) {
+		return models.Category{ID: categoryID, Name: "Zapatos", Slug: "zapatos", SlugHistory: []string{"calzado"}}, nil
+	}
+	repository.HandleUpdate = func(ctx context.Context, input models.Category) (int64, apierrors.ApiError) {
+		return 1, nil
+	}
+
+	service := services.NewCategoriesService(repository)
+
+	category, err := service.Update(context.TODO(), models.Category{ID: mocks.CategoryIDOne, Name: "Calzado"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "calzado", category.Slug)
+	assert.Equal(t, []string{"zapatos"}, category.SlugHistory)
+}
+
+func TestService_Update_Get_Category_Error(t *testing.T) {
+	repository := categories.NewRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, categoryID string) (models.Category, apierrors.ApiError) {
+		return models.Category{}, apierrors.NewNotFoundApiError("mock error")
+	}
+
+	service := services.NewCategoriesService(repository)
+
+	_, err := service.Update(context.TODO(), mocks.CategoryOne)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.Status())
+}
+
+func TestService_GetBySlug_Success(t *testing.T) {
+	repository := categories.NewRepositoryMock()
+	repository.HandleGetBySlug = func(ctx context.Context, slug string) (models.Category, apierrors.ApiError) {
+		return mocks.CategoryOne, nil
+	}
+
+	service := services.NewCategoriesService(repository)
+
+	category, err := service.GetBySlug(context.TODO(), "name-one")
+
+	assert.Nil(t, err)
+	assert.Equal(t, mocks.CategoryOne, category)
+}
+
+func TestService_Import_Upserts_By_Name(t *testing.T) {
+	var created []models.Category
+	var updated []models.Category
+
+	repository := categories.NewRepositoryMock()
+	repository.HandleGetAllCategories = func(ctx context.Context) ([]models.Category, apierrors.ApiError) {
+		return []models.Category{
+			{ID: mocks.CategoryIDOne, Name: "Zapatos", Slug: "zapatos"},
+			{ID: primitive.NewObjectID().Hex(), Name: "Remeras", NameI18n: models.Translations{"pt": "Camisetas"}},
+		}, nil
+	}
+	repository.HandleCreate = func(ctx context.Context, input models.Category) (interface{}, apierrors.ApiError) {
+		created = append(created, input)
+		return primitive.NewObjectID(), nil
+	}
+	repository.HandleUpdate = func(ctx context.Context, input models.Category) (int64, apierrors.ApiError) {
+		updated = append(updated, input)
+		return 1, nil
+	}
+
+	service := services.NewCategoriesService(repository)
+
+	report, err := service.Import(context.TODO(), []models.Category{
+		{Name: "zapatos", NameI18n: models.Translations{"pt": "Sapatos"}},
+		{Name: "Remeras", NameI18n: models.Translations{"pt": "Camisetas"}},
+		{Name: "Gorras", Slug: "gorras-y-sombreros"},
+	}, false)
+
+	assert.Nil(t, err)
+	assert.Len(t, report.Created, 1)
+	assert.Len(t, report.Updated, 1)
+	assert.Len(t, report.Unchanged, 1)
+	assert.Equal(t, "gorras-y-sombreros", created[0].Slug)
+	assert.Equal(t, mocks.CategoryIDOne, updated[0].ID)
+	assert.Equal(t, "Zapatos", updated[0].Name)
+	assert.Equal(t, models.Translations{"pt": "Sapatos"}, updated[0].NameI18n)
+}
+
+func TestService_Import_Moves_Slug(t *testing.T) {
+	var updated []models.Category
+
+	repository := categories.NewRepositoryMock()
+	repository.HandleGetAllCategories = func(ctx context.Context) ([]models.Category, apierrors.ApiError) {
+		return []models.Category{{ID: mocks.CategoryIDOne, Name: "Zapatos", Slug: "zapatos"}}, nil
+	}
+	repository.HandleUpdate = func(ctx context.Context, input models.Category) (int64, apierrors.ApiError) {
+		updated = append(updated, input)
+		return 1, nil
+	}
+
+	service := services.NewCategoriesService(repository)
+
+	report, err := service.Import(context.TODO(), []models.Category{{Name: "Zapatos", Slug: "calzado"}}, false)
+
+	assert.Nil(t, err)
+	assert.Len(t, report.Updated, 1)
+	assert.Equal(t, "calzado", updated[0].Slug)
+	assert.Equal(t, []string{"zapatos"}, updated[0].SlugHistory)
+}
+
+func TestService_Import_Invalid_Slug_Error(t *testing.T) {
+	repository := categories.NewRepositoryMock()
+	repository.HandleGetAllCategories = func(ctx context.Context) ([]models.Category, apierrors.ApiError) {
+		return []models.Category{{ID: mocks.CategoryIDOne, Name: "Zapatos", Slug: "zapatos"}}, nil
+	}
+
+	service := services.NewCategoriesService(repository)
+
+	_, err := service.Import(context.TODO(), []models.Category{{Name: "Zapatos", Slug: "Not A Slug"}}, false)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.Status())
+}
+
+func TestService_Import_Dry_Run_Does_Not_Write(t *testing.T) {
+	repository := categories.NewRepositoryMock()
+	repository.HandleCreate = func(ctx context.Context, input models.Category) (interface{}, apierrors.ApiError) {
+		t.Fatal("dry run must not create categories")
+		return nil, nil
+	}
+
+	service := services.NewCategoriesService(repository)
+
+	report, err := service.Import(context.TODO(), []models.Category{{Name: "Gorras"}}, true)
+
+	assert.Nil(t, err)
+	assert.True(t, report.DryRun)
+	assert.Len(t, report.Created, 1)
+}
+
+func TestService_Import_Repeated_Name_Error(t *testing.T) {
+	service := services.NewCategoriesService(categories.NewRepositoryMock())
+
+	_, err := service.Import(context.TODO(), []models.Category{{Name: "Gorras"}, {Name: "gorras "}}, false)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.Status())
+}
+
+func TestService_Import_Empty_Name_Error(t *testing.T) {
+	service := services.NewCategoriesService(categories.NewRepositoryMock())
+
+	_, err := service.Import(context.TODO(), []models.Category{{Name: " "}}, false)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.Status())
+}
+
+func TestService_Import_Default_Locale_Error(t *testing.T) {
+	service := services.NewCategoriesService(categories.NewRepositoryMock())
+
+	_, err := service.Import(context.TODO(), []models.Category{{Name: "Gorras", NameI18n: models.Translations{models.DefaultLocale: "Gorras"}}}, false)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.Status())
+}
+
+func TestService_Export_Empty(t *testing.T) {
+	service := services.NewCategoriesService(categories.NewRepositoryMock())
+
+	result, err := service.Export(context.TODO())
+
+	assert.Nil(t, err)
+	assert.Equal(t, []models.Category{}, result)
+}
@@ -0,0 +1,65 @@
+package collections
+
+import (
+	"context"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+type ServiceMock struct {
+	HandleGet         func(ctx context.Context, collectionID string) (models.CollectionWithItems, apierrors.ApiError)
+	HandleGetByShopID func(ctx context.Context, shopID string) ([]models.Collection, apierrors.ApiError)
+	HandleCreate      func(ctx context.Context, userID string, input dto.CollectionDTO) (interface{}, apierrors.ApiError)
+	HandleUpdate      func(ctx context.Context, userID string, collectionID string, input dto.CollectionDTO) apierrors.ApiError
+	HandleSetItems    func(ctx context.Context, userID string, collectionID string, itemIDs []string) apierrors.ApiError
+	HandleDelete      func(ctx context.Context, userID string, collectionID string) apierrors.ApiError
+}
+
+func NewServiceMock() ServiceMock {
+	return ServiceMock{}
+}
+
+func (mock ServiceMock) Get(ctx context.Context, collectionID string) (models.CollectionWithItems, apierrors.ApiError) {
+	if mock.HandleGet != nil {
+		return mock.HandleGet(ctx, collectionID)
+	}
+	return models.CollectionWithItems{}, nil
+}
+
+func (mock ServiceMock) GetByShopID(ctx context.Context, shopID string) ([]models.Collection, apierrors.ApiError) {
+	if mock.HandleGetByShopID != nil {
+		return mock.HandleGetByShopID(ctx, shopID)
+	}
+	return []models.Collection{}, nil
+}
+
+func (mock ServiceMock) Create(ctx context.Context, userID string, input dto.CollectionDTO) (interface{}, apierrors.ApiError) {
+	if mock.HandleCreate != nil {
+		return mock.HandleCreate(ctx, userID, input)
+	}
+	return nil, nil
+}
+
+func (mock ServiceMock) Update(ctx context.Context, userID string, collectionID string, input dto.CollectionDTO) apierrors.ApiError {
+	if mock.HandleUpdate != nil {
+		return mock.HandleUpdate(ctx, userID, collectionID, input)
+	}
+	return nil
+}
+
+func (mock ServiceMock) SetItems(ctx context.Context, userID string, collectionID string, itemIDs []string) apierrors.ApiError {
+	if mock.HandleSetItems != nil {
+		return mock.HandleSetItems(ctx, userID, collectionID, itemIDs)
+	}
+	return nil
+}
+
+func (mock ServiceMock) Delete(ctx context.Context, userID string, collectionID string) apierrors.ApiError {
+	if mock.HandleDelete != nil {
+		return mock.HandleDelete(ctx, userID, collectionID)
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package collections
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/services"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/clients"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/mocks"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/repositories/collections"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/repositories/items"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Get_Success_Keeps_Collection_Order(t *testing.T) {
+	repository := collections.NewRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, collectionID string) (models.Collection, apierrors.ApiError) {
+		return mocks.CollectionOne, nil
+	}
+
+	itemsRepository := items.NewItemsRepositoryMock()
+	itemsRepository.HandleGetByIDs = func(ctx context.Context, itemsIDs []string) (models.Items, apierrors.ApiError) {
+		return mocks.ItemsMock, nil
+	}
+
+	priceClient := clients.NewPriceClientMock()
+	priceClient.HandleGetItemsPrices = func(ctx context.Context, itemsIDs []string) (models.Prices, apierrors.ApiError) {
+		return mocks.Prices, nil
+	}
+
+	service := services.NewCollectionsService(repository, itemsRepository, priceClient, clients.NewShopClientMock())
+
+	result, apiErr := service.Get(context.TODO(), mocks.CollectionIdOne)
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, mocks.CollectionIdOne, result.ID)
+	assert.Len(t, result.Items, 2)
+	assert.Equal(t, mocks.ItemIdTwo, result.Items[0].ID)
+	assert.Equal(t, mocks.ItemIdOne, result.Items[1].ID)
+	assert.Equal(t, mocks.Price, result.Items[1].Price)
+}
+
+func TestService_Get_Empty_Collection(t *testing.T) {
+	repository := collections.NewRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, collectionID string) (models.Collection, apierrors.ApiError) {
+		return mocks.CollectionTwo, nil
+	}
+
+	itemsRepository := items.NewItemsRepositoryMock()
+	itemsRepository.HandleGetByIDs = func(ctx context.Context, itemsIDs []string) (models.Items, apierrors.ApiError) {
+		t.Fatal("items should not be fetched for an empty collection")
+		return models.Items{}, nil
+	}
+
+	service := services.NewCollectionsService(repository, itemsRepository, clients.NewPriceClientMock(), clients.NewShopClientMock())
+
+	result, apiErr := service.Get(context.TODO(), mocks.CollectionIdTwo)
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, []models.Item{}, result.Items)
+}
+
+func TestService_Get_Items_Not_Found(t *testing.T) {
+	repository := collections.NewRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, collectionID string) (models.Collection, apierrors.ApiError) {
+		return mocks.CollectionOne, nil
+	}
+
+	itemsRepository := items.NewItemsRepositoryMock()
+	itemsRepository.HandleGetByIDs = func(ctx context.Context, itemsIDs []string) (models.Items, apierrors.ApiError) {
+		return models.Items{}, apierrors.NewNotFoundApiError("mock error")
+	}
+
+	service := services.NewCollectionsService(repository, itemsRepository, clients.NewPriceClientMock(), clients.NewShopClientMock())
+
+	result, apiErr := service.Get(context.TODO(), mocks.CollectionIdOne)
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, []models.Item{}, result.Items)
+}
+
+func TestService_Get_Prices_Error(t *testing.T) {
+	repository := collections.NewRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, collectionID string) (models.Collection, apierrors.ApiError) {
+		return mocks.CollectionOne, nil
+	}
+
+	itemsRepository := items.NewItemsRepositoryMock()
+	itemsRepository.HandleGetByIDs = func(ctx context.Context, itemsIDs []string) (models.Items, apierrors.ApiError) {
+		return mocks.ItemsMock, nil
+	}
+
+	priceClient := clients.NewPriceClientMock()
+	priceClient.HandleGetItemsPrices = func(ctx context.Context, itemsIDs []string) (models.Prices, apierrors.ApiError) {
+		return models.Prices{}, apierrors.NewInternalServerApiError("mock error", fmt.Errorf("mock error"))
+	}
+
+	service := services.NewCollectionsService(repository, itemsRepository, priceClient, clients.NewShopClientMock())
+
+	_, apiErr := service.Get(context.TODO(), mocks.CollectionIdOne)
+
+	assert.NotNil(t, apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.Status())
+}
+
+func TestService_GetByShopID_Empty(t *testing.T) {
+	repository := collections.NewRepositoryMock()
+
+	service := services.NewCollectionsService(repository, items.NewItemsRepositoryMock(), clients.NewPriceClientMock(), clients.NewShopClientMock())
+
+	result, apiErr := service.GetByShopID(context.TODO(), mocks.ShopIDOne)
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, []models.Collection{}, result)
+}
+
+func TestService_Create_Success(t *testing.T) {
+	var created models.Collection
+
+	repository := collections.NewRepositoryMock()
+	repository.HandleCreate = func(ctx context.Context, collection models.Collection) (interface{}, apierrors.ApiError) {
+		created = collection
+		return mocks.CollectionIdOne, nil
+	}
+
+	shopClient := clients.NewShopClientMock()
+	shopClient.HandleGetShopByUserID = func(ctx context.Context) (models.Shop, apierrors.ApiError) {
+		return models.Shop{ID: mocks.ShopIDOne}, nil
+	}
+
+	service := services.NewCollectionsService(repository, items.NewItemsRepositoryMock(), clients.NewPriceClientMock(), shopClient)
+
+	id, apiErr := service.Create(context.TODO(), mocks.UserIdOne, mocks.CollectionDTO)
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, mocks.CollectionIdOne, id)
+	assert.Equal(t, mocks.ShopIDOne, created.ShopID)
+	assert.Equal(t, mocks.UserIdOne, created.UserID)
+	assert.Equal(t, []string{}, created.ItemIDs)
+}
+
+func TestService_Create_Shop_Error(t *testing.T) {
+	shopClient := clients.NewShopClientMock()
+	shopClient.HandleGetShopByUserID = func(ctx context.Context) (models.Shop, apierrors.ApiError) {
+		return models.Shop{}, apierrors.NewNotFoundApiError("mock error")
+	}
+
+	service := services.NewCollectionsService(collections.NewRepositoryMock(), items.NewItemsRepositoryMock(), clients.NewPriceClientMock(), shopClient)
+
+	_, apiErr := service.Create(context.TODO(), mocks.UserIdOne, mocks.CollectionDTO)
+
+	assert.NotNil(t, apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.Status())
+}
+
+func TestService_Update_Not_Owned_Error(t *testing.T) {
+	repository := collections.NewRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, collectionID string) (models.Collection, apierrors.ApiError) {
+		return mocks.CollectionOne, nil
+	}
+
+	service := services.NewCollectionsService(repository, items.NewItemsRepositoryMock(), clients.NewPriceClientMock(), clients.NewShopClientMock())
+
+	apiErr := service.Update(context.TODO(), mocks.UserIdTwo, mocks.CollectionIdOne, mocks.CollectionDTO)
+
+	assert.NotNil(t, apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.Status())
+}
+
+func TestService_Update_Success(t *testing.T) {
+	var updated models.Collection
+
+	repository := collections.NewRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, collectionID string) (models.Collection, apierrors.ApiError) {
+		return mocks.CollectionOne, nil
+	}
+	repository.HandleUpdate = func(ctx context.Context, collection models.Collection) apierrors.ApiError {
+		updated = collection
+		return nil
+	}
+
+	service := services.NewCollectionsService(repository, items.NewItemsRepositoryMock(), clients.NewPriceClientMock(), clients.NewShopClientMock())
+
+	apiErr := service.Update(context.TODO(), mocks.UserIdOne, mocks.CollectionIdOne, mocks.CollectionDTO)
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, mocks.CollectionDTO.Position, updated.Position)
+	assert.Equal(t, mocks.CollectionOne.ItemIDs, updated.ItemIDs)
+}
+
+func TestService_SetItems_Success(t *testing.T) {
+	var saved []string
+
+	repository := collections.NewRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, collectionID string) (models.Collection, apierrors.ApiError) {
+		return mocks.CollectionOne, nil
+	}
+	repository.HandleSetItems = func(ctx context.Context, collectionID string, itemIDs []string) apierrors.ApiError {
+		saved = itemIDs
+		return nil
+	}
+
+	itemsRepository := items.NewItemsRepositoryMock()
+	itemsRepository.HandleGetByIDs = func(ctx context.Context, itemsIDs []string) (models.Items, apierrors.ApiError) {
+		return models.Items{Items: []models.Item{mocks.ItemMockOne}}, nil
+	}
+
+	service := services.NewCollectionsService(repository, itemsRepository, clients.NewPriceClientMock(), clients.NewShopClientMock())
+
+	apiErr := service.SetItems(context.TODO(), mocks.UserIdOne, mocks.CollectionIdOne, []string{mocks.ItemIdOne})
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, []string{mocks.ItemIdOne}, saved)
+}
+
+func TestService_SetItems_Item_From_Other_Shop_Error(t *testing.T) {
+	repository := collections.NewRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, collectionID string) (models.Collection, apierrors.ApiError) {
+		return mocks.CollectionOne, nil
+	}
+
+	itemsRepository := items.NewItemsRepositoryMock()
+	itemsRepository.HandleGetByIDs = func(ctx context.Context, itemsIDs []string) (models.Items, apierrors.ApiError) {
+		var item = mocks.ItemMockTwo
+		item.ShopID = mocks.ShopIDTwo
+		return models.Items{Items: []models.Item{item}}, nil
+	}
+
+	service := services.NewCollectionsService(repository, itemsRepository, clients.NewPriceClientMock(), clients.NewShopClientMock())
+
+	apiErr := service.SetItems(context.TODO(), mocks.UserIdOne, mocks.CollectionIdOne, []string{mocks.ItemIdTwo})
+
+	assert.NotNil(t, apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.Status())
+}
+
+func TestService_SetItems_Missing_Item_Error(t *testing.T) {
+	repository := collections.NewRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, collectionID string) (models.Collection, apierrors.ApiError) {
+		return mocks.CollectionOne, nil
+	}
+
+	itemsRepository := items.NewItemsRepositoryMock()
+	itemsRepository.HandleGetByIDs = func(ctx context.Context, itemsIDs []string) (models.Items, apierrors.ApiError) {
+		return models.Items{Items: []models.Item{mocks.ItemMockOne}}, nil
+	}
+
+	service := services.NewCollectionsService(repository, itemsRepository, clients.NewPriceClientMock(), clients.NewShopClientMock())
+
+	apiErr := service.SetItems(context.TODO(), mocks.UserIdOne, mocks.CollectionIdOne, []string{mocks.ItemIdOne, mocks.ItemIdTwo})
+
+	assert.NotNil(t, apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.Status())
+}
+
+func TestService_SetItems_Duplicated_Items_Error(t *testing.T) {
+	repository := collections.NewRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, collectionID string) (models.Collection, apierrors.ApiError) {
+		return mocks.CollectionOne, nil
+	}
+
+	service := services.NewCollectionsService(repository, items.NewItemsRepositoryMock(), clients.NewPriceClientMock(), clients.NewShopClientMock())
+
+	apiErr := service.SetItems(context.TODO(), mocks.UserIdOne, mocks.CollectionIdOne, []string{mocks.ItemIdOne, mocks.ItemIdOne})
+
+	assert.NotNil(t, apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Status())
+}
+
+func TestService_Delete_Not_Found_Error(t *testing.T) {
+	repository := collections.NewRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, collectionID string) (models.Collection, apierrors.ApiError) {
+		return models.Collection{}, apierrors.NewNotFoundApiError("mock error")
+	}
+
+	service := services.NewCollectionsService(repository, items.NewItemsRepositoryMock(), clients.NewPriceClientMock(), clients.NewShopClientMock())
+
+	apiErr := service.Delete(context.TODO(), mocks.UserIdOne, mocks.CollectionIdOne)
+
+	assert.NotNil(t, apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.Status())
+}
+
+func TestService_Delete_Success(t *testing.T) {
+	repository := collections.NewRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, collectionID string) (models.Collection, apierrors.ApiError) {
+		return mocks.CollectionOne, nil
+	}
+	repository.HandleDelete = func(ctx context.Context, collectionID string) (int64, apierrors.ApiError) {
+		return 1, nil
+	}
+
+	service := services.NewCollectionsService(repository, items.NewItemsRepositoryMock(), clients.NewPriceClientMock(), clients.NewShopClientMock())
+
+	apiErr := service.Delete(context.TODO(), mocks.UserIdOne, mocks.CollectionIdOne)
+
+	assert.Nil(t, apiErr)
+}
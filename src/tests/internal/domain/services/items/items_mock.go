@@ -22,6 +22,15 @@ type ServiceMock struct {
 
 	HandleGetByCategoryID       func(ctx context.Context, categoryID string) ([]models.Item, apierrors.ApiError)
 	HandleUpdateItemsCategories func(ctx context.Context, category models.Category) apierrors.ApiError
+
+	HandleSetTranslation    func(ctx context.Context, userID string, itemID string, locale string, translation models.ItemTranslation) apierrors.ApiError
+	HandleDeleteTranslation func(ctx context.Context, userID string, itemID string, locale string) apierrors.ApiError
+
+	HandleCountByCategory   func(ctx context.Context, shopID string) ([]models.CategoryCount, apierrors.ApiError)
+	HandleCountByCategoryID func(ctx context.Context, categoryID string) (int64, apierrors.ApiError)
+
+	HandleGetBySlug func(ctx context.Context, slug string) (models.Item, apierrors.ApiError)
+	HandleSearch    func(ctx context.Context, query models.ItemsQuery) (models.ItemsPage, apierrors.ApiError)
 }
 
 func NewItemsServiceMock() ServiceMock {
@@ -104,3 +113,45 @@ func (mock ServiceMock) GetByCategoryID(ctx context.Context, categoryID string)
 	}
 	return []models.Item{}, nil
 }
+
+func (mock ServiceMock) SetTranslation(ctx context.Context, userID string, itemID string, locale string, translation models.ItemTranslation) apierrors.ApiError {
+	if mock.HandleSetTranslation != nil {
+		return mock.HandleSetTranslation(ctx, userID, itemID, locale, translation)
+	}
+	return nil
+}
+
+func (mock ServiceMock) DeleteTranslation(ctx context.Context, userID string, itemID string, locale string) apierrors.ApiError {
+	if mock.HandleDeleteTranslation != nil {
+		return mock.HandleDeleteTranslation(ctx, userID, itemID, locale)
+	}
+	return nil
+}
+
+func (mock ServiceMock) CountByCategory(ctx context.Context, shopID string) ([]models.CategoryCount, apierrors.ApiError) {
+	if mock.HandleCountByCategory != nil {
+		return mock.HandleCountByCategory(ctx, shopID)
+	}
+	return []models.CategoryCount{}, nil
+}
+
+func (mock ServiceMock) CountByCategoryID(ctx context.Context, categoryID string) (int64, apierrors.ApiError) {
+	if mock.HandleCountByCategoryID != nil {
+		return mock.HandleCountByCategoryID(ctx, categoryID)
+	}
+	return 0, nil
+}
+
+func (mock ServiceMock) GetBySlug(ctx context.Context, slug string) (models.Item, apierrors.ApiError) {
+	if mock.HandleGetBySlug != nil {
+		return mock.HandleGetBySlug(ctx, slug)
+	}
+	return models.Item{}, nil
+}
+
+func (mock ServiceMock) Search(ctx context.Context, query models.ItemsQuery) (models.ItemsPage, apierrors.ApiError) {
+	if mock.HandleSearch != nil {
+		return mock.HandleSearch(ctx, query)
+	}
+	return models.ItemsPage{}, nil
+}
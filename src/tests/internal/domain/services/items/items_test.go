@@ -8,6 +8,7 @@ import (
 
 	"github.com/agustinrabini/items-api-project/src/main/domain/models"
 	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
 	"github.com/agustinrabini/items-api-project/src/main/domain/services"
 	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/clients"
 	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/mocks"
@@ -952,3 +953,370 @@ func TestService_GetByCategoryID_Repository_Error(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, apiErr.Status())
 	assert.Equal(t, []models.Item([]models.Item{}), item)
 }
+
+func TestService_SetTranslation_Success(t *testing.T) {
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, itemID string) (models.Item, apierrors.ApiError) {
+		return mocks.ItemMockOne, nil
+	}
+	repository.HandleSetTranslation = func(ctx context.Context, itemID string, locale string, translation models.ItemTranslation) apierrors.ApiError {
+		assert.Equal(t, "pt", locale)
+		return nil
+	}
+
+	service := services.NewItemsService(repository, nil, nil)
+
+	err := service.SetTranslation(context.TODO(), mocks.UserIdOne, mocks.ItemIdOne, "pt", models.ItemTranslation{Name: "Nome"})
+
+	assert.Nil(t, err)
+}
+
+func TestService_SetTranslation_Not_Owner_Error(t *testing.T) {
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, itemID string) (models.Item, apierrors.ApiError) {
+		return mocks.ItemMockOne, nil
+	}
+
+	service := services.NewItemsService(repository, nil, nil)
+
+	err := service.SetTranslation(context.TODO(), mocks.UserIdTwo, mocks.ItemIdOne, "pt", models.ItemTranslation{Name: "Nome"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusForbidden, err.Status())
+}
+
+func TestService_SetTranslation_Repository_Error(t *testing.T) {
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, itemID string) (models.Item, apierrors.ApiError) {
+		return models.Item{}, apierrors.NewNotFoundApiError("mock error")
+	}
+
+	service := services.NewItemsService(repository, nil, nil)
+
+	err := service.SetTranslation(context.TODO(), mocks.UserIdOne, mocks.ItemIdOne, "pt", models.ItemTranslation{Name: "Nome"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.Status())
+}
+
+func TestService_DeleteTranslation_Success(t *testing.T) {
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, itemID string) (models.Item, apierrors.ApiError) {
+		return mocks.ItemMockOne, nil
+	}
+
+	service := services.NewItemsService(repository, nil, nil)
+
+	err := service.DeleteTranslation(context.TODO(), mocks.UserIdOne, mocks.ItemIdOne, "pt")
+
+	assert.Nil(t, err)
+}
+
+func TestService_DeleteTranslation_Not_Owner_Error(t *testing.T) {
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, itemID string) (models.Item, apierrors.ApiError) {
+		return mocks.ItemMockOne, nil
+	}
+
+	service := services.NewItemsService(repository, nil, nil)
+
+	err := service.DeleteTranslation(context.TODO(), mocks.UserIdTwo, mocks.ItemIdOne, "pt")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusForbidden, err.Status())
+}
+
+func TestService_CountByCategory_Success(t *testing.T) {
+	counts := []models.CategoryCount{{Category: mocks.CategoryOne, ItemsCount: 2}}
+
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleCountByCategory = func(ctx context.Context, shopID string) ([]models.CategoryCount, apierrors.ApiError) {
+		return counts, nil
+	}
+
+	service := services.NewItemsService(repository, nil, nil)
+
+	result, err := service.CountByCategory(context.TODO(), mocks.ShopIDOne)
+
+	assert.Nil(t, err)
+	assert.Equal(t, counts, result)
+}
+
+func TestService_CountByCategory_Empty_Success(t *testing.T) {
+	repository := items.NewItemsRepositoryMock()
+
+	service := services.NewItemsService(repository, nil, nil)
+
+	result, err := service.CountByCategory(context.TODO(), "")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []models.CategoryCount{}, result)
+}
+
+func TestService_CountByCategory_Repository_Error(t *testing.T) {
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleCountByCategory = func(ctx context.Context, shopID string) ([]models.CategoryCount, apierrors.ApiError) {
+		return nil, apierrors.NewInternalServerApiError("mock error", nil)
+	}
+
+	service := services.NewItemsService(repository, nil, nil)
+
+	_, err := service.CountByCategory(context.TODO(), "")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, err.Status())
+}
+
+func TestService_CountByCategoryID_Success(t *testing.T) {
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleCountByCategoryID = func(ctx context.Context, categoryID string) (int64, apierrors.ApiError) {
+		return 5, nil
+	}
+
+	service := services.NewItemsService(repository, nil, nil)
+
+	count, err := service.CountByCategoryID(context.TODO(), mocks.CategoryIDOne)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 5, count)
+}
+
+func TestService_Create_Sets_Unique_Slug(t *testing.T) {
+	var saved models.Item
+
+	shopClient := clients.NewShopClientMock()
+	shopClient.HandleGetShopByUserID = func(ctx context.Context) (models.Shop, apierrors.ApiError) {
+		return mocks.Shop, nil
+	}
+
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleSlugExists = func(ctx context.Context, slug string, excludeID string) (bool, apierrors.ApiError) {
+		return slug == "example-item", nil
+	}
+	repository.HandleSave = func(ctx context.Context, item models.Item) (interface{}, apierrors.ApiError) {
+		saved = item
+		return primitive.NewObjectID(), nil
+	}
+
+	service := services.NewItemsService(repository, clients.NewPriceClientMock(), shopClient)
+
+	_, err := service.CreateItem(context.TODO(), mocks.ItemDTO)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "example-item-2", saved.Slug)
+}
+
+func TestService_Create_Retries_Taken_Slug(t *testing.T) {
+	var saved []string
+	taken := map[string]bool{}
+
+	shopClient := clients.NewShopClientMock()
+	shopClient.HandleGetShopByUserID = func(ctx context.Context) (models.Shop, apierrors.ApiError) {
+		return mocks.Shop, nil
+	}
+
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleSlugExists = func(ctx context.Context, slug string, excludeID string) (bool, apierrors.ApiError) {
+		return taken[slug], nil
+	}
+	// a concurrent create takes the slug between the check and the insert
+	repository.HandleSave = func(ctx context.Context, item models.Item) (interface{}, apierrors.ApiError) {
+		saved = append(saved, item.Slug)
+		if len(saved) == 1 {
+			taken[item.Slug] = true
+			return nil, repositories.SlugTakenError
+		}
+		return primitive.NewObjectID(), nil
+	}
+
+	service := services.NewItemsService(repository, clients.NewPriceClientMock(), shopClient)
+
+	_, err := service.CreateItem(context.TODO(), mocks.ItemDTO)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"example-item", "example-item-2"}, saved)
+}
+
+func TestService_Create_Slug_Always_Taken_Error(t *testing.T) {
+	shopClient := clients.NewShopClientMock()
+	shopClient.HandleGetShopByUserID = func(ctx context.Context) (models.Shop, apierrors.ApiError) {
+		return mocks.Shop, nil
+	}
+
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleSave = func(ctx context.Context, item models.Item) (interface{}, apierrors.ApiError) {
+		return nil, repositories.SlugTakenError
+	}
+
+	service := services.NewItemsService(repository, clients.NewPriceClientMock(), shopClient)
+
+	_, err := service.CreateItem(context.TODO(), mocks.ItemDTO)
+
+	assert.Equal(t, repositories.SlugTakenError, err)
+}
+
+func TestService_Create_Slug_Repository_Error(t *testing.T) {
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleSlugExists = func(ctx context.Context, slug string, excludeID string) (bool, apierrors.ApiError) {
+		return false, apierrors.NewInternalServerApiError("mock error", fmt.Errorf("mock error"))
+	}
+
+	service := services.NewItemsService(repository, clients.NewPriceClientMock(), clients.NewShopClientMock())
+
+	_, err := service.CreateItem(context.TODO(), mocks.ItemDTO)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, err.Status())
+}
+
+func TestService_Update_Rename_Keeps_Slug_History(t *testing.T) {
+	var updated models.Item
+
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, itemID string) (models.Item, apierrors.ApiError) {
+		return models.Item{ID: mocks.ItemIdOne, Name: "Old name", Slug: "old-name"}, nil
+	}
+	repository.HandleUpdate = func(ctx context.Context, itemID string, updateItem *models.Item) (int64, apierrors.ApiError) {
+		updated = *updateItem
+		return 1, nil
+	}
+
+	service := services.NewItemsService(repository, clients.NewPriceClientMock(), clients.NewShopClientMock())
+
+	err := service.Update(context.TODO(), mocks.ItemIdOne, mocks.ItemDTO)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "example-item", updated.Slug)
+	assert.Equal(t, []string{"old-name"}, updated.SlugHistory)
+}
+
+func TestService_Update_Rename_Retries_Taken_Slug(t *testing.T) {
+	var updated []models.Item
+
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, itemID string) (models.Item, apierrors.ApiError) {
+		return models.Item{ID: mocks.ItemIdOne, Name: "Old name", Slug: "old-name"}, nil
+	}
+	repository.HandleSlugExists = func(ctx context.Context, slug string, excludeID string) (bool, apierrors.ApiError) {
+		return len(updated) > 0 && slug == "example-item", nil
+	}
+	repository.HandleUpdate = func(ctx context.Context, itemID string, updateItem *models.Item) (int64, apierrors.ApiError) {
+		updated = append(updated, *updateItem)
+		if len(updated) == 1 {
+			return -1, repositories.SlugTakenError
+		}
+		return 1, nil
+	}
+
+	service := services.NewItemsService(repository, clients.NewPriceClientMock(), clients.NewShopClientMock())
+
+	err := service.Update(context.TODO(), mocks.ItemIdOne, mocks.ItemDTO)
+
+	assert.Nil(t, err)
+	assert.Len(t, updated, 2)
+	assert.Equal(t, "example-item-2", updated[1].Slug)
+	assert.Equal(t, []string{"old-name"}, updated[1].SlugHistory, "the taken slug is not kept in the history")
+}
+
+func TestService_Update_Same_Name_Keeps_Slug(t *testing.T) {
+	var updated models.Item
+
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleGet = func(ctx context.Context, itemID string) (models.Item, apierrors.ApiError) {
+		return models.Item{ID: mocks.ItemIdOne, Name: mocks.ItemDTO.Name, Slug: "example-item"}, nil
+	}
+	repository.HandleSlugExists = func(ctx context.Context, slug string, excludeID string) (bool, apierrors.ApiError) {
+		t.Fatal("the slug should not be regenerated")
+		return false, nil
+	}
+	repository.HandleUpdate = func(ctx context.Context, itemID string, updateItem *models.Item) (int64, apierrors.ApiError) {
+		updated = *updateItem
+		return 1, nil
+	}
+
+	service := services.NewItemsService(repository, clients.NewPriceClientMock(), clients.NewShopClientMock())
+
+	err := service.Update(context.TODO(), mocks.ItemIdOne, mocks.ItemDTO)
+
+	assert.Nil(t, err)
+	assert.Empty(t, updated.Slug)
+	assert.Empty(t, updated.SlugHistory)
+}
+
+func TestService_GetBySlug_Success(t *testing.T) {
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleGetBySlug = func(ctx context.Context, slug string) (models.Item, apierrors.ApiError) {
+		var item = mocks.ItemMockOne
+		item.Slug = slug
+		return item, nil
+	}
+
+	priceClient := clients.NewPriceClientMock()
+	priceClient.HandleGetPriceByItemID = func(ctx context.Context, itemID string) (models.Price, apierrors.ApiError) {
+		return mocks.Price, nil
+	}
+
+	service := services.NewItemsService(repository, priceClient, clients.NewShopClientMock())
+
+	item, err := service.GetBySlug(context.TODO(), "example-item")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "example-item", item.Slug)
+	assert.Equal(t, mocks.Price, item.Price)
+}
+
+func TestService_GetBySlug_Not_Found_Error(t *testing.T) {
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleGetBySlug = func(ctx context.Context, slug string) (models.Item, apierrors.ApiError) {
+		return models.Item{}, apierrors.NewNotFoundApiError("mock error")
+	}
+
+	service := services.NewItemsService(repository, clients.NewPriceClientMock(), clients.NewShopClientMock())
+
+	_, err := service.GetBySlug(context.TODO(), "example-item")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.Status())
+}
+
+func TestService_Search_Sets_Prices(t *testing.T) {
+	priceClient := clients.NewPriceClientMock()
+	priceClient.HandleGetItemsPrices = func(ctx context.Context, itemsIDs []string) (models.Prices, apierrors.ApiError) {
+		return mocks.Prices, nil
+	}
+
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleSearch = func(ctx context.Context, query models.ItemsQuery) (models.ItemsPage, apierrors.ApiError) {
+		return models.ItemsPage{Items: mocks.ItemsMock.Items, Total: 10}, nil
+	}
+
+	service := services.NewItemsService(repository, priceClient, clients.NewShopClientMock())
+
+	response, apiErr := service.Search(context.TODO(), models.ItemsQuery{Text: "remera", Limit: 2})
+
+	assert.Nil(t, apiErr)
+	assert.EqualValues(t, 10, response.Total)
+	assert.Equal(t, mocks.ItemsMock.Items[0].ID, response.Items[0].ID)
+	assert.Equal(t, mocks.Prices.Prices[0].Amount, response.Items[0].Price.Amount)
+}
+
+func TestService_Search_Empty_Page_Skips_Prices(t *testing.T) {
+	priceClient := clients.NewPriceClientMock()
+	priceClient.HandleGetItemsPrices = func(ctx context.Context, itemsIDs []string) (models.Prices, apierrors.ApiError) {
+		t.Fatal("prices must not be asked for an empty page")
+		return models.Prices{}, nil
+	}
+
+	repository := items.NewItemsRepositoryMock()
+	repository.HandleSearch = func(ctx context.Context, query models.ItemsQuery) (models.ItemsPage, apierrors.ApiError) {
+		return models.ItemsPage{Items: []models.Item{}, Total: 3}, nil
+	}
+
+	service := services.NewItemsService(repository, priceClient, clients.NewShopClientMock())
+
+	response, apiErr := service.Search(context.TODO(), models.ItemsQuery{Limit: 2, Offset: 4})
+
+	assert.Nil(t, apiErr)
+	assert.EqualValues(t, 3, response.Total)
+	assert.Empty(t, response.Items)
+}
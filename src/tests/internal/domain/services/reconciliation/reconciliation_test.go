@@ -0,0 +1,328 @@
+package reconciliation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories/memory"
+	"github.com/agustinrabini/items-api-project/src/main/domain/services"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/clients"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/repositories/reconciliation"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// pricesOf answers GetItemsPrices with the prices of priced and counts the
+// chunks asked for.
+func pricesOf(priced map[string]bool, chunks *[][]string) func(ctx context.Context, itemsIDs []string) (models.Prices, apierrors.ApiError) {
+	return func(ctx context.Context, itemsIDs []string) (models.Prices, apierrors.ApiError) {
+		*chunks = append(*chunks, itemsIDs)
+
+		var prices models.Prices
+		for _, id := range itemsIDs {
+			if priced[id] {
+				prices.Prices = append(prices.Prices, models.Price{ID: "price-" + id, ItemID: id})
+			}
+		}
+		if len(prices.Prices) == 0 {
+			return models.Prices{}, apierrors.NewNotFoundApiError("price not found")
+		}
+		return prices, nil
+	}
+}
+
+func save(t *testing.T, items repositories.ItemsRepository, name string, status string) string {
+	item := models.Item{Name: name, ShopID: "shop", UserID: "user", Status: status, Category: models.Category{ID: "category", Name: "Shoes"}}
+	id, err := items.Save(context.Background(), item)
+	assert.Nil(t, err)
+
+	return id.(primitive.ObjectID).Hex()
+}
+
+func findings(report models.ReconciliationReport) map[string]models.ReconciliationFinding {
+	byItem := map[string]models.ReconciliationFinding{}
+	for _, finding := range report.Findings {
+		byItem[finding.ItemID] = finding
+	}
+	return byItem
+}
+
+func TestReconciliationService_Run_Report_Only(t *testing.T) {
+	items := memory.NewItemsRepository()
+	pricedID := save(t, items, "Priced", models.ItemStatusActive)
+	missingID := save(t, items, "Missing", models.ItemStatusActive)
+	legacyID := save(t, items, "Legacy", "")
+	trashedID := save(t, items, "Trashed", models.ItemStatusDeleted)
+	save(t, items, "Trashed without price", models.ItemStatusDeleted)
+	backID := save(t, items, "Back", models.ItemStatusUnpriced)
+
+	var chunks [][]string
+	prices := clients.NewPriceClientMock()
+	prices.HandleGetItemsPrices = pricesOf(map[string]bool{pricedID: true, trashedID: true, backID: true}, &chunks)
+	prices.HandleDeletePrice = func(ctx context.Context, itemID string) apierrors.ApiError {
+		t.Fatal("a report only run must not delete prices")
+		return nil
+	}
+
+	var saved []models.ReconciliationReport
+	reports := reconciliation.NewRepositoryMock()
+	reports.HandleSave = func(ctx context.Context, report models.ReconciliationReport) apierrors.ApiError {
+		saved = append(saved, report)
+		return nil
+	}
+
+	report, apiErr := services.NewReconciliationService(items, prices, reports, 4).Run(context.Background(), false)
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, 6, report.ItemsChecked)
+	assert.Len(t, chunks, 2)
+	assert.Len(t, saved, 1)
+	assert.False(t, report.FinishedAt.Before(report.StartedAt))
+
+	byItem := findings(report)
+	assert.Len(t, byItem, 4)
+	assert.Equal(t, models.ReconciliationMissingPrice, byItem[missingID].Reason)
+	assert.Equal(t, models.ReconciliationMissingPrice, byItem[legacyID].Reason)
+	assert.Equal(t, models.ReconciliationOrphanPrice, byItem[trashedID].Reason)
+	assert.Equal(t, "price-"+trashedID, byItem[trashedID].PriceID)
+	assert.Equal(t, models.ReconciliationPriceBack, byItem[backID].Reason)
+	for _, finding := range report.Findings {
+		assert.False(t, finding.Repaired)
+	}
+
+	missing, _ := items.Get(context.Background(), missingID)
+	assert.Equal(t, models.ItemStatusActive, missing.Status)
+}
+
+func TestReconciliationService_Run_Repair(t *testing.T) {
+	items := memory.NewItemsRepository()
+	missingID := save(t, items, "Missing", models.ItemStatusActive)
+	trashedID := save(t, items, "Trashed", models.ItemStatusDeleted)
+	failingID := save(t, items, "Failing", models.ItemStatusDeleted)
+	backID := save(t, items, "Back", models.ItemStatusUnpriced)
+
+	var chunks [][]string
+	var deleted []string
+	prices := clients.NewPriceClientMock()
+	prices.HandleGetItemsPrices = pricesOf(map[string]bool{trashedID: true, failingID: true, backID: true}, &chunks)
+	prices.HandleDeletePrice = func(ctx context.Context, itemID string) apierrors.ApiError {
+		if itemID == failingID {
+			return apierrors.NewUnauthorizedApiError("no token")
+		}
+		deleted = append(deleted, itemID)
+		return nil
+	}
+
+	report, apiErr := services.NewReconciliationService(items, prices, reconciliation.NewRepositoryMock(), 100).Run(context.Background(), true)
+
+	assert.Nil(t, apiErr)
+	assert.True(t, report.Repair)
+	assert.Equal(t, []string{trashedID}, deleted)
+
+	byItem := findings(report)
+	assert.True(t, byItem[missingID].Repaired)
+	assert.True(t, byItem[trashedID].Repaired)
+	assert.True(t, byItem[backID].Repaired)
+	assert.False(t, byItem[failingID].Repaired)
+	assert.Equal(t, "no token", byItem[failingID].Error)
+
+	missing, _ := items.Get(context.Background(), missingID)
+	assert.Equal(t, models.ItemStatusUnpriced, missing.Status)
+	assert.Equal(t, "Shoes", missing.Category.Name)
+
+	back, _ := items.Get(context.Background(), backID)
+	assert.Equal(t, models.ItemStatusActive, back.Status)
+}
+
+func TestReconciliationService_Run_Prices_Error(t *testing.T) {
+	items := memory.NewItemsRepository()
+	save(t, items, "Item", models.ItemStatusActive)
+
+	prices := clients.NewPriceClientMock()
+	prices.HandleGetItemsPrices = func(ctx context.Context, itemsIDs []string) (models.Prices, apierrors.ApiError) {
+		return models.Prices{}, apierrors.NewInternalServerApiError("mock error", fmt.Errorf("mock error"))
+	}
+
+	saved := false
+	reports := reconciliation.NewRepositoryMock()
+	reports.HandleSave = func(ctx context.Context, report models.ReconciliationReport) apierrors.ApiError {
+		saved = true
+		return nil
+	}
+
+	_, apiErr := services.NewReconciliationService(items, prices, reports, 100).Run(context.Background(), false)
+
+	assert.NotNil(t, apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.Status())
+	assert.False(t, saved)
+}
+
+func TestReconciliationService_Run_Price_Scan_Error(t *testing.T) {
+	items := memory.NewItemsRepository()
+	missingID := save(t, items, "Missing", models.ItemStatusActive)
+
+	var chunks [][]string
+	prices := clients.NewPriceClientMock()
+	prices.HandleGetItemsPrices = pricesOf(map[string]bool{}, &chunks)
+	prices.HandleListPricedItems = func(ctx context.Context, offset int, limit int) (models.PricedItems, apierrors.ApiError) {
+		return models.PricedItems{}, apierrors.NewNotFoundApiError("route not found")
+	}
+
+	var saved models.ReconciliationReport
+	reports := reconciliation.NewRepositoryMock()
+	reports.HandleSave = func(ctx context.Context, report models.ReconciliationReport) apierrors.ApiError {
+		saved = report
+		return nil
+	}
+
+	report, apiErr := services.NewReconciliationService(items, prices, reports, 100).Run(context.Background(), true)
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, "route not found", report.PriceScanError)
+	assert.Equal(t, report, saved)
+	assert.True(t, findings(report)[missingID].Repaired)
+}
+
+func TestReconciliationService_Run_Already_Running(t *testing.T) {
+	items := memory.NewItemsRepository()
+	save(t, items, "Item", models.ItemStatusActive)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	prices := clients.NewPriceClientMock()
+	prices.HandleGetItemsPrices = func(ctx context.Context, itemsIDs []string) (models.Prices, apierrors.ApiError) {
+		close(started)
+		<-release
+		return models.Prices{}, nil
+	}
+
+	service := services.NewReconciliationService(items, prices, reconciliation.NewRepositoryMock(), 100)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, apiErr := service.Run(context.Background(), false)
+		assert.Nil(t, apiErr)
+	}()
+	<-started
+
+	_, apiErr := service.Run(context.Background(), false)
+	close(release)
+	wg.Wait()
+
+	assert.NotNil(t, apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.Status())
+}
+
+func TestReconciliationService_Run_Prices_Without_Item(t *testing.T) {
+	items := memory.NewItemsRepository()
+	keptID := save(t, items, "Kept", models.ItemStatusActive)
+	removedID := primitive.NewObjectID().Hex()
+	failingID := primitive.NewObjectID().Hex()
+
+	var chunks [][]string
+	var pages []int
+	var deleted []string
+	prices := clients.NewPriceClientMock()
+	prices.HandleGetItemsPrices = pricesOf(map[string]bool{keptID: true}, &chunks)
+	prices.HandleListPricedItems = func(ctx context.Context, offset int, limit int) (models.PricedItems, apierrors.ApiError) {
+		pages = append(pages, offset)
+		all := []string{keptID, removedID, "legacy-id", failingID}
+		return models.PricedItems{ItemIDs: all[offset:min(offset+limit, len(all))], Total: int64(len(all))}, nil
+	}
+	prices.HandleDeletePrice = func(ctx context.Context, itemID string) apierrors.ApiError {
+		if itemID == failingID {
+			return apierrors.NewUnauthorizedApiError("no token")
+		}
+		deleted = append(deleted, itemID)
+		return nil
+	}
+
+	report, apiErr := services.NewReconciliationService(items, prices, reconciliation.NewRepositoryMock(), 2).Run(context.Background(), true)
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, []int{0, 2}, pages)
+	assert.Equal(t, []string{removedID, "legacy-id"}, deleted)
+
+	byItem := findings(report)
+	assert.Len(t, byItem, 3)
+	for _, itemID := range []string{removedID, "legacy-id", failingID} {
+		assert.Equal(t, models.ReconciliationPriceWithoutItem, byItem[itemID].Reason)
+	}
+	assert.True(t, byItem[removedID].Repaired)
+	assert.False(t, byItem[failingID].Repaired)
+	assert.Equal(t, "no token", byItem[failingID].Error)
+}
+
+func TestReconciliationService_Run_Lease_Held_By_Another_Replica(t *testing.T) {
+	items := memory.NewItemsRepository()
+	save(t, items, "Item", models.ItemStatusActive)
+
+	// a shared lease, like the locks collection of two replicas
+	var mu sync.Mutex
+	holder := ""
+	reports := reconciliation.NewRepositoryMock()
+	reports.HandleAcquireLease = func(ctx context.Context, owner string, ttl time.Duration) (bool, apierrors.ApiError) {
+		mu.Lock()
+		defer mu.Unlock()
+		if holder != "" && holder != owner {
+			return false, nil
+		}
+		holder = owner
+		return true, nil
+	}
+	reports.HandleReleaseLease = func(ctx context.Context, owner string) apierrors.ApiError {
+		mu.Lock()
+		defer mu.Unlock()
+		if holder == owner {
+			holder = ""
+		}
+		return nil
+	}
+
+	started, release := make(chan struct{}), make(chan struct{})
+	prices := clients.NewPriceClientMock()
+	prices.HandleGetItemsPrices = func(ctx context.Context, itemsIDs []string) (models.Prices, apierrors.ApiError) {
+		close(started)
+		<-release
+		return models.Prices{}, nil
+	}
+
+	first := services.NewReconciliationService(items, prices, reports, 100)
+	second := services.NewReconciliationService(items, clients.NewPriceClientMock(), reports, 100)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, apiErr := first.Run(context.Background(), false)
+		assert.Nil(t, apiErr)
+	}()
+	<-started
+
+	_, apiErr := second.Run(context.Background(), false)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, services.ErrorReconciliationRunning, apiErr)
+
+	_, apiErr = second.Run(context.Background(), false)
+	assert.Nil(t, apiErr, "the lease is released when the run ends")
+}
+
+func TestReconciliationService_LastReport_Not_Run(t *testing.T) {
+	service := services.NewReconciliationService(memory.NewItemsRepository(), clients.NewPriceClientMock(), reconciliation.NewRepositoryMock(), 100)
+
+	_, apiErr := service.LastReport(context.Background())
+
+	assert.NotNil(t, apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.Status())
+}
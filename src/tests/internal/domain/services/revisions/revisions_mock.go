@@ -0,0 +1,40 @@
+package revisions
+
+import (
+	"context"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+)
+
+type RevisionsServiceMock struct {
+	HandleList   func(ctx context.Context, userID string, itemID string) ([]models.Revision, apierrors.ApiError)
+	HandleGet    func(ctx context.Context, userID string, itemID string, number int) (models.Revision, apierrors.ApiError)
+	HandleRevert func(ctx context.Context, userID string, itemID string, number int) apierrors.ApiError
+}
+
+func NewRevisionsServiceMock() RevisionsServiceMock {
+	return RevisionsServiceMock{}
+}
+
+func (mock RevisionsServiceMock) List(ctx context.Context, userID string, itemID string) ([]models.Revision, apierrors.ApiError) {
+	if mock.HandleList != nil {
+		return mock.HandleList(ctx, userID, itemID)
+	}
+	return []models.Revision{}, nil
+}
+
+func (mock RevisionsServiceMock) Get(ctx context.Context, userID string, itemID string, number int) (models.Revision, apierrors.ApiError) {
+	if mock.HandleGet != nil {
+		return mock.HandleGet(ctx, userID, itemID, number)
+	}
+	return models.Revision{}, nil
+}
+
+func (mock RevisionsServiceMock) Revert(ctx context.Context, userID string, itemID string, number int) apierrors.ApiError {
+	if mock.HandleRevert != nil {
+		return mock.HandleRevert(ctx, userID, itemID, number)
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package revisions
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories"
+	"github.com/agustinrabini/items-api-project/src/main/domain/repositories/memory"
+	"github.com/agustinrabini/items-api-project/src/main/domain/services"
+	"github.com/agustinrabini/items-api-project/src/main/domain/utils"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/actor"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/clients"
+
+	"github.com/jopitnow/go-jopit-toolkit/goutils/apierrors"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const owner = "user"
+
+// fixture records the revisions in memory and keeps one price per item, as
+// the prices API does.
+type fixture struct {
+	items      repositories.ItemsRepository
+	revisions  repositories.RevisionsRepository
+	categories repositories.CategoriesRepository
+	prices     map[string]models.Price
+	priceErr   apierrors.ApiError
+	category   models.Category
+}
+
+func newFixture(t *testing.T) *fixture {
+	f := &fixture{
+		revisions:  memory.NewRevisionsRepository(),
+		categories: memory.NewCategoriesRepository(),
+		prices:     map[string]models.Price{},
+	}
+	f.items = repositories.NewRevisionedItemsRepository(memory.NewItemsRepository(), f.revisions)
+
+	id, err := f.categories.Create(context.Background(), models.Category{Name: "Shoes", Slug: "shoes"})
+	assert.Nil(t, err)
+	f.category, err = f.categories.Get(context.Background(), id.(primitive.ObjectID).Hex())
+	assert.Nil(t, err)
+
+	return f
+}
+
+func (f *fixture) pricesClient() clients.PriceClientMock {
+	prices := clients.NewPriceClientMock()
+	prices.HandleGetPriceByItemID = func(ctx context.Context, itemID string) (models.Price, apierrors.ApiError) {
+		return f.prices[itemID], nil
+	}
+	prices.HandleModifyPrice = func(ctx context.Context, price *models.Price) apierrors.ApiError {
+		if f.priceErr != nil {
+			return f.priceErr
+		}
+		f.prices[price.ItemID] = *price
+		return nil
+	}
+	return prices
+}
+
+func (f *fixture) itemsService() services.ItemsService {
+	prices := repositories.NewRevisionedPriceClient(f.pricesClient(), f.items, f.revisions)
+	return services.NewItemsService(f.items, prices, clients.NewShopClientMock())
+}
+
+func (f *fixture) service() services.RevisionsService {
+	return services.NewRevisionsService(f.items, f.revisions, f.categories, f.itemsService(), f.pricesClient())
+}
+
+func (f *fixture) item(t *testing.T, description string, amount float64) string {
+	item := models.Item{Name: "Boots", Slug: utils.Slugify(description), Description: description, ShopID: "shop", UserID: owner, Status: models.ItemStatusActive, Category: f.category}
+	id, err := f.items.Save(context.Background(), item)
+	assert.Nil(t, err)
+
+	itemID := id.(primitive.ObjectID).Hex()
+	f.prices[itemID] = models.Price{ID: "price-" + itemID, ItemID: itemID, Amount: amount}
+
+	return itemID
+}
+
+// update changes the item through the items service, as PUT /items/:id does.
+func (f *fixture) update(t *testing.T, itemID string, description string, amount float64) {
+	item, err := f.items.Get(context.Background(), itemID)
+	assert.Nil(t, err)
+	item.Description = description
+
+	request, mapErr := dto.NewItemDTO(item, models.Price{Amount: amount})
+	assert.Nil(t, mapErr)
+
+	ctx := actor.With(context.Background(), owner)
+	assert.Nil(t, f.itemsService().Update(ctx, itemID, request))
+}
+
+func TestRevisions_Update_Records_Initial_State_And_Diff(t *testing.T) {
+	f := newFixture(t)
+	itemID := f.item(t, "Leather boots", 10)
+
+	f.update(t, itemID, "Suede boots", 12)
+
+	revisions, err := f.service().List(context.Background(), owner, itemID)
+
+	assert.Nil(t, err)
+	assert.Len(t, revisions, 3)
+	assert.Equal(t, models.RevisionInitial, revisions[0].Action)
+	assert.Equal(t, 1, revisions[0].Number)
+	assert.Empty(t, revisions[0].Changes)
+	assert.Nil(t, revisions[0].Item)
+
+	assert.Equal(t, models.RevisionUpdate, revisions[1].Action)
+	assert.Equal(t, 2, revisions[1].Number)
+	assert.Equal(t, owner, revisions[1].Actor)
+	assert.Equal(t, []models.FieldChange{{Field: "description", Before: "Leather boots", After: "Suede boots"}}, revisions[1].Changes)
+
+	assert.Equal(t, models.RevisionPrice, revisions[2].Action)
+	assert.Equal(t, owner, revisions[2].Actor)
+	assert.Equal(t, []models.FieldChange{{Field: "price.amount", Before: 10.0, After: 12.0}}, revisions[2].Changes)
+
+	revision, err := f.service().Get(context.Background(), owner, itemID, 3)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Suede boots", revision.Item.Description)
+	assert.Equal(t, 12.0, revision.Price.Amount)
+}
+
+func TestRevisions_Price_Change(t *testing.T) {
+	f := newFixture(t)
+	itemID := f.item(t, "Leather boots", 10)
+	f.update(t, itemID, "Suede boots", 12)
+
+	f.update(t, itemID, "Suede boots", 15)
+
+	revisions, _ := f.service().List(context.Background(), owner, itemID)
+
+	assert.Len(t, revisions, 4)
+	assert.Equal(t, models.RevisionPrice, revisions[3].Action)
+	assert.Equal(t, []models.FieldChange{{Field: "price.amount", Before: 12.0, After: 15.0}}, revisions[3].Changes)
+}
+
+func TestRevisions_First_Change_Of_The_Price(t *testing.T) {
+	f := newFixture(t)
+	itemID := f.item(t, "Leather boots", 10)
+
+	f.update(t, itemID, "Leather boots", 12)
+
+	revisions, _ := f.service().List(context.Background(), owner, itemID)
+
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, models.RevisionInitial, revisions[0].Action)
+	assert.Equal(t, []models.FieldChange{{Field: "price.amount", Before: 10.0, After: 12.0}}, revisions[1].Changes)
+
+	initial, err := f.service().Get(context.Background(), owner, itemID, 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 10.0, initial.Price.Amount)
+}
+
+func TestRevisions_Failed_Price_Update_Records_No_Price(t *testing.T) {
+	f := newFixture(t)
+	itemID := f.item(t, "Leather boots", 10)
+	f.priceErr = apierrors.NewInternalServerApiError("prices API down", nil)
+
+	item, _ := f.items.Get(context.Background(), itemID)
+	request, mapErr := dto.NewItemDTO(item, models.Price{Amount: 12})
+	assert.Nil(t, mapErr)
+
+	err := f.itemsService().Update(context.Background(), itemID, request)
+
+	assert.NotNil(t, err)
+	revisions, _ := f.service().List(context.Background(), owner, itemID)
+	assert.Empty(t, revisions)
+	assert.Equal(t, 10.0, f.prices[itemID].Amount)
+}
+
+func TestRevisions_No_Change_Records_Nothing(t *testing.T) {
+	f := newFixture(t)
+	itemID := f.item(t, "Leather boots", 10)
+	f.update(t, itemID, "Suede boots", 12)
+
+	f.update(t, itemID, "Suede boots", 12)
+
+	revisions, _ := f.service().List(context.Background(), owner, itemID)
+	assert.Len(t, revisions, 3)
+}
+
+func TestRevisions_Status_Change(t *testing.T) {
+	f := newFixture(t)
+	itemID := f.item(t, "Leather boots", 10)
+
+	ctx := actor.With(context.Background(), actor.Reconciliation)
+	_, err := f.items.Update(ctx, itemID, &models.Item{Status: models.ItemStatusUnpriced, Category: f.category})
+	assert.Nil(t, err)
+
+	revisions, _ := f.service().List(context.Background(), owner, itemID)
+
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, models.RevisionStatus, revisions[1].Action)
+	assert.Equal(t, actor.Reconciliation, revisions[1].Actor)
+	assert.Equal(t, []models.FieldChange{{Field: "status", Before: models.ItemStatusActive, After: models.ItemStatusUnpriced}}, revisions[1].Changes)
+}
+
+func TestRevisions_Category_Sync(t *testing.T) {
+	f := newFixture(t)
+	bootsID := f.item(t, "Leather boots", 10)
+	sandalsID := f.item(t, "Sandals", 8)
+
+	renamed := models.Category{ID: f.category.ID, Name: "Footwear", Slug: "footwear"}
+	assert.Nil(t, f.items.UpdateItemsCategories(context.Background(), &renamed))
+
+	for _, itemID := range []string{bootsID, sandalsID} {
+		revisions, _ := f.service().List(context.Background(), owner, itemID)
+
+		assert.Len(t, revisions, 2)
+		assert.Equal(t, models.RevisionCategorySync, revisions[1].Action)
+		assert.Equal(t, []models.FieldChange{
+			{Field: "category.name", Before: "Shoes", After: "Footwear"},
+			{Field: "category.slug", Before: "shoes", After: "footwear"},
+		}, revisions[1].Changes)
+	}
+}
+
+func TestRevisions_Revert(t *testing.T) {
+	f := newFixture(t)
+	itemID := f.item(t, "Leather boots", 10)
+	f.update(t, itemID, "Suede boots", 12)
+	f.update(t, itemID, "Boots", 15)
+
+	ctx := actor.With(context.Background(), owner)
+	err := f.service().Revert(ctx, owner, itemID, 3)
+
+	assert.Nil(t, err)
+
+	item, _ := f.items.Get(context.Background(), itemID)
+	assert.Equal(t, "Suede boots", item.Description)
+	assert.Equal(t, 12.0, f.prices[itemID].Amount)
+	assert.Equal(t, "price-"+itemID, f.prices[itemID].ID)
+
+	revisions, _ := f.service().List(context.Background(), owner, itemID)
+	assert.Len(t, revisions, 7)
+	assert.Equal(t, []models.FieldChange{{Field: "description", Before: "Boots", After: "Suede boots"}}, revisions[5].Changes)
+	assert.Equal(t, []models.FieldChange{{Field: "price.amount", Before: 15.0, After: 12.0}}, revisions[6].Changes)
+}
+
+func TestRevisions_Revert_Initial_Keeps_The_Current_Price(t *testing.T) {
+	f := newFixture(t)
+	itemID := f.item(t, "Leather boots", 10)
+	f.update(t, itemID, "Suede boots", 12)
+
+	err := f.service().Revert(context.Background(), owner, itemID, 1)
+
+	assert.Nil(t, err)
+
+	item, _ := f.items.Get(context.Background(), itemID)
+	assert.Equal(t, "Leather boots", item.Description)
+	assert.Equal(t, 12.0, f.prices[itemID].Amount)
+}
+
+func TestRevisions_Revert_Category_Gone(t *testing.T) {
+	f := newFixture(t)
+	itemID := f.item(t, "Leather boots", 10)
+	f.update(t, itemID, "Suede boots", 12)
+	_, err := f.categories.Delete(context.Background(), f.category.ID)
+	assert.Nil(t, err)
+
+	err = f.service().Revert(context.Background(), owner, itemID, 1)
+
+	assert.Equal(t, services.ErrorRevisionCategoryGone, err)
+}
+
+func TestRevisions_Revert_Without_Item(t *testing.T) {
+	f := newFixture(t)
+	itemID := f.item(t, "Leather boots", 10)
+	f.update(t, itemID, "Suede boots", 12)
+
+	last, err := f.revisions.Last(context.Background(), itemID)
+	assert.Nil(t, err)
+	err = f.revisions.Save(context.Background(), models.Revision{ItemID: itemID, Number: last.Number + 1, Action: models.RevisionUpdate})
+	assert.Nil(t, err)
+
+	err = f.service().Revert(context.Background(), owner, itemID, last.Number+1)
+
+	assert.Equal(t, services.ErrorRevisionWithoutItem, err)
+}
+
+func TestRevisions_Not_Owned(t *testing.T) {
+	f := newFixture(t)
+	itemID := f.item(t, "Leather boots", 10)
+
+	_, err := f.service().List(context.Background(), "other", itemID)
+	assert.Equal(t, services.ErrorItemNotOwned, err)
+
+	err = f.service().Revert(context.Background(), "other", itemID, 1)
+	assert.Equal(t, services.ErrorItemNotOwned, err)
+}
+
+func TestRevisions_Get_Not_Found(t *testing.T) {
+	f := newFixture(t)
+	itemID := f.item(t, "Leather boots", 10)
+
+	_, err := f.service().Get(context.Background(), owner, itemID, 1)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.Status())
+}
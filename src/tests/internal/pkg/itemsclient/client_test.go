@@ -0,0 +1,309 @@
+package itemsclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/domain/models"
+	"github.com/agustinrabini/items-api-project/src/main/domain/models/dto"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/itemsclient"
+	"github.com/agustinrabini/items-api-project/src/tests/internal/domain/mocks"
+
+	"github.com/jopitnow/go-jopit-toolkit/goauth"
+	"github.com/jopitnow/go-jopit-toolkit/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+var fastRetries = itemsclient.WithRetryPolicy(itemsclient.RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+
+func fakeServer(t *testing.T) *itemsclient.FakeServer {
+	fake := itemsclient.NewFakeServer()
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+func sellerContext() context.Context {
+	ctx := context.WithValue(context.Background(), tracing.XtraceHeaderKey, "trace-1")
+	return itemsclient.WithAuthorization(ctx, "Bearer token")
+}
+
+func TestClient_GetItem_Success(t *testing.T) {
+	fake := fakeServer(t)
+	item := fake.AddItem(models.Item{Name: "Mate Imperial"})
+
+	ctx := context.WithValue(context.Background(), tracing.XtraceHeaderKey, "trace-1")
+	ctx = context.WithValue(ctx, goauth.FirebaseAuthHeader, "Bearer token")
+
+	response, apiErr := fake.Client().GetItem(ctx, item.ID)
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, item.ID, response.ID)
+	assert.Equal(t, "mate-imperial", response.Slug)
+
+	requests := fake.Requests()
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "/v1/items/"+item.ID, requests[0].Path)
+	assert.Equal(t, "trace-1", requests[0].Header.Get("X-Trace-ID"))
+	assert.Equal(t, "Bearer token", requests[0].Header.Get("Authorization"))
+}
+
+func TestClient_GetItem_Not_Found(t *testing.T) {
+	fake := fakeServer(t)
+
+	_, apiErr := fake.Client().GetItem(context.Background(), mocks.ItemIdOne)
+
+	assert.NotNil(t, apiErr)
+	assert.True(t, itemsclient.IsNotFound(apiErr))
+	assert.Equal(t, "not_found", apiErr.Code())
+	assert.Equal(t, "item "+mocks.ItemIdOne+" not found", apiErr.Message())
+	assert.Empty(t, fake.Requests()[0].Header.Get("Authorization"))
+}
+
+func TestClient_Decodes_Problem_Details(t *testing.T) {
+	fake := fakeServer(t)
+
+	_, apiErr := fake.Client(itemsclient.WithVersion("v2")).SearchItems(context.Background(), itemsclient.SearchQuery{Limit: 500})
+
+	assert.NotNil(t, apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Status())
+	assert.Equal(t, "bad_request", apiErr.Code())
+	assert.Equal(t, "limit must be between 1 and 100", apiErr.Message())
+	assert.Equal(t, "/v2/items/search", fake.Requests()[0].Path)
+}
+
+func TestClient_Retries_Unavailable(t *testing.T) {
+	fake := fakeServer(t)
+	item := fake.AddItem(models.Item{Name: "Mate"})
+	fake.FailNext(http.StatusServiceUnavailable, http.StatusTooManyRequests)
+
+	response, apiErr := fake.Client(fastRetries).GetItem(context.Background(), item.ID)
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, item.ID, response.ID)
+	assert.Len(t, fake.Requests(), 3)
+}
+
+func TestClient_Retries_Run_Out(t *testing.T) {
+	fake := fakeServer(t)
+	fake.FailNext(http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+
+	_, apiErr := fake.Client(fastRetries).GetItem(context.Background(), mocks.ItemIdOne)
+
+	assert.NotNil(t, apiErr)
+	assert.Equal(t, http.StatusBadGateway, apiErr.Status())
+	assert.Equal(t, "fake failure", apiErr.Message())
+	assert.Len(t, fake.Requests(), 3)
+}
+
+func TestClient_Does_Not_Retry_Server_Errors(t *testing.T) {
+	fake := fakeServer(t)
+	fake.FailNext(http.StatusInternalServerError)
+
+	_, apiErr := fake.Client(fastRetries).GetItem(context.Background(), mocks.ItemIdOne)
+
+	assert.Equal(t, http.StatusInternalServerError, apiErr.Status())
+	assert.Len(t, fake.Requests(), 1)
+}
+
+func TestClient_No_Response(t *testing.T) {
+	fake := itemsclient.NewFakeServer()
+	client := fake.Client(itemsclient.WithRetryPolicy(itemsclient.RetryPolicy{}))
+	fake.Close()
+
+	_, apiErr := client.GetItem(context.Background(), mocks.ItemIdOne)
+
+	assert.NotNil(t, apiErr)
+	assert.Equal(t, "internal_server_error", apiErr.Code())
+	assert.Equal(t, "unexpected error calling items api, url: /v1/items/"+mocks.ItemIdOne, apiErr.Message())
+}
+
+func TestClient_CreateItem_Keeps_Idempotency_Key_Across_Retries(t *testing.T) {
+	fake := fakeServer(t)
+	category := fake.AddCategory(models.Category{Name: "Mock"})
+	fake.FailNext(http.StatusServiceUnavailable)
+
+	input := mocks.ItemDTO
+	input.Category.ID = category.ID
+
+	id, apiErr := fake.Client(fastRetries).CreateItem(sellerContext(), input)
+
+	assert.Nil(t, apiErr)
+	item, found := fake.Item(id)
+	assert.True(t, found)
+	assert.Equal(t, fake.ShopID, item.ShopID)
+	assert.Equal(t, input.Name, item.Name)
+
+	requests := fake.Requests()
+	assert.Len(t, requests, 2)
+	assert.NotEmpty(t, requests[0].Header.Get(itemsclient.IdempotencyKeyHeader))
+	assert.Equal(t, requests[0].Header.Get(itemsclient.IdempotencyKeyHeader), requests[1].Header.Get(itemsclient.IdempotencyKeyHeader))
+}
+
+func TestClient_CreateItem_Idempotency_Key_From_Context(t *testing.T) {
+	fake := fakeServer(t)
+	category := fake.AddCategory(models.Category{Name: "Mock"})
+
+	input := mocks.ItemDTO
+	input.Category.ID = category.ID
+
+	ctx := itemsclient.WithIdempotencyKey(sellerContext(), "order-42")
+	_, apiErr := fake.Client().CreateItem(ctx, input)
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, "order-42", fake.Requests()[0].Header.Get(itemsclient.IdempotencyKeyHeader))
+}
+
+func TestClient_Token_Source(t *testing.T) {
+	fake := fakeServer(t)
+	item := fake.AddItem(models.Item{Name: "Mate"})
+
+	client := fake.Client(itemsclient.WithTokenSource(func(ctx context.Context) (string, error) {
+		return "Bearer service", nil
+	}))
+
+	apiErr := client.DeleteItem(context.Background(), item.ID)
+
+	assert.Nil(t, apiErr)
+	assert.Equal(t, "Bearer service", fake.Requests()[0].Header.Get("Authorization"))
+
+	_, found := fake.Item(item.ID)
+	assert.False(t, found)
+}
+
+func TestClient_Token_Source_Error(t *testing.T) {
+	fake := fakeServer(t)
+
+	client := fake.Client(itemsclient.WithTokenSource(func(ctx context.Context) (string, error) {
+		return "", errors.New("token expired")
+	}))
+
+	apiErr := client.DeleteItem(context.Background(), mocks.ItemIdOne)
+
+	assert.Equal(t, http.StatusUnauthorized, apiErr.Status())
+	assert.Equal(t, "error getting the items api token: token expired", apiErr.Message())
+	assert.Empty(t, fake.Requests())
+}
+
+func TestClient_Seller_Routes_Need_Authorization(t *testing.T) {
+	fake := fakeServer(t)
+
+	_, apiErr := fake.Client().GetUserItems(context.Background())
+
+	assert.Equal(t, http.StatusUnauthorized, apiErr.Status())
+	assert.Equal(t, "unauthorized", apiErr.Code())
+}
+
+func TestClient_GetItemsByIDs_Leaves_Missing_Out(t *testing.T) {
+	fake := fakeServer(t)
+	item := fake.AddItem(models.Item{Name: "Mate"})
+
+	response, apiErr := fake.Client().GetItemsByIDs(context.Background(), []string{item.ID, mocks.ItemIdOne})
+
+	assert.Nil(t, apiErr)
+	assert.Len(t, response.Items, 1)
+	assert.Equal(t, item.ID, response.Items[0].ID)
+}
+
+func TestClient_SearchAllItems_Goes_Through_Pages(t *testing.T) {
+	fake := fakeServer(t)
+	for _, name := range []string{"Mate", "Mate Imperial", "Bombilla", "Mate Camionero", "Yerba"} {
+		fake.AddItem(models.Item{Name: name})
+	}
+
+	it := fake.Client().SearchAllItems(itemsclient.SearchQuery{Text: "mate", Limit: 2})
+
+	names := []string{}
+	for it.Next(context.Background()) {
+		names = append(names, it.Item().Name)
+	}
+
+	assert.Nil(t, it.Err())
+	assert.ElementsMatch(t, []string{"Mate", "Mate Imperial", "Mate Camionero"}, names)
+	assert.Len(t, fake.Requests(), 2)
+	assert.Equal(t, "/v1/items/search", fake.Requests()[1].Path)
+}
+
+func TestClient_GetShopItems_Goes_Through_Pages_On_V2(t *testing.T) {
+	fake := fakeServer(t)
+	for i := 0; i < models.MaxItemsPageSize+5; i++ {
+		fake.AddItem(models.Item{Name: "Mate"})
+	}
+	fake.AddItem(models.Item{Name: "Yerba", ShopID: "other-shop"})
+
+	items, apiErr := fake.Client(itemsclient.WithVersion("v2")).GetShopItems(context.Background(), fake.ShopID)
+
+	assert.Nil(t, apiErr)
+	assert.Len(t, items.Items, models.MaxItemsPageSize+5)
+	assert.Len(t, fake.Requests(), 2)
+	assert.Equal(t, "/v2/items/shop/"+fake.ShopID, fake.Requests()[1].Path)
+
+	items, apiErr = fake.Client().GetShopItems(context.Background(), fake.ShopID)
+
+	assert.Nil(t, apiErr)
+	assert.Len(t, items.Items, models.MaxItemsPageSize+5)
+	assert.Len(t, fake.Requests(), 3)
+}
+
+func TestClient_SearchAllItems_Stops_On_Error(t *testing.T) {
+	fake := fakeServer(t)
+	fake.AddItem(models.Item{Name: "Mate"})
+	fake.FailNext(http.StatusBadRequest)
+
+	it := fake.Client().SearchAllItems(itemsclient.SearchQuery{})
+
+	assert.False(t, it.Next(context.Background()))
+	assert.Equal(t, http.StatusBadRequest, it.Err().Status())
+}
+
+func TestClient_Categories(t *testing.T) {
+	fake := fakeServer(t)
+	category := fake.AddCategory(models.Category{Name: "Mate"})
+	fake.AddCategory(models.Category{Name: "Yerba"})
+	fake.AddItem(models.Item{Name: "Mate Imperial", Category: category})
+	client := fake.Client(itemsclient.WithHeader("X-Password", "secret"))
+
+	apiErr := client.CreateCategory(context.Background(), models.Category{Name: "Bombillas"})
+	assert.Nil(t, apiErr)
+	assert.Equal(t, "secret", fake.Requests()[0].Header.Get("X-Password"))
+
+	categories, apiErr := client.GetCategories(context.Background())
+	assert.Nil(t, apiErr)
+	assert.Len(t, categories, 3)
+
+	counts, apiErr := client.GetShopCategories(context.Background(), fake.ShopID)
+	assert.Nil(t, apiErr)
+	assert.Len(t, counts, 1)
+	assert.Equal(t, int64(1), counts[0].ItemsCount)
+
+	apiErr = client.SetCategoryTranslation(context.Background(), category.ID, "pt", dto.CategoryTranslationDTO{Name: "Cuia"})
+	assert.Nil(t, apiErr)
+
+	response, apiErr := client.GetCategoryBySlug(context.Background(), "mate")
+	assert.Nil(t, apiErr)
+	assert.Equal(t, "Cuia", response.NameI18n["pt"])
+}
+
+func TestClient_Collections(t *testing.T) {
+	fake := fakeServer(t)
+	first := fake.AddItem(models.Item{Name: "Mate"})
+	second := fake.AddItem(models.Item{Name: "Bombilla"})
+	client := fake.Client()
+
+	id, apiErr := client.CreateCollection(sellerContext(), mocks.CollectionDTO)
+	assert.Nil(t, apiErr)
+
+	apiErr = client.SetCollectionItems(sellerContext(), id, []string{second.ID, first.ID})
+	assert.Nil(t, apiErr)
+
+	collection, apiErr := client.GetCollection(context.Background(), id)
+	assert.Nil(t, apiErr)
+	assert.Equal(t, mocks.CollectionDTO.Name, collection.Name)
+	assert.Equal(t, []string{second.ID, first.ID}, []string{collection.Items[0].ID, collection.Items[1].ID})
+
+	collections, apiErr := client.GetShopCollections(context.Background(), fake.ShopID)
+	assert.Nil(t, apiErr)
+	assert.Len(t, collections, 1)
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/handlers"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/metrics"
+	"github.com/gin-gonic/gin"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func scrape(router http.Handler) string {
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	return response.Body.String()
+}
+
+func TestMetrics_Requests_By_Route_Name(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	router.POST("/things", handlers.LoggerHandler("CreateThing"), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/things", nil))
+	body := scrape(router)
+
+	assert.Contains(t, body, `items_api_http_requests_total{method="POST",route="CreateThing",status="201"} 1`)
+	assert.Contains(t, body, `items_api_http_request_duration_seconds_count{method="POST",route="CreateThing"} 1`)
+}
+
+func TestMetrics_Mongo_And_Clients(t *testing.T) {
+	metrics.ObserveMongo("things", "Get", time.Now())
+	metrics.ObserveClient("things-api", "GetThing", http.StatusNotFound, time.Now())
+	metrics.ObserveClient("things-api", "GetThing", 0, time.Now())
+
+	body := scrape(metrics.Handler())
+
+	assert.Contains(t, body, `items_api_mongo_operation_duration_seconds_count{operation="Get",repository="things"} 1`)
+	assert.Contains(t, body, `items_api_client_requests_total{client="things-api",operation="GetThing",status="404"} 1`)
+	assert.Contains(t, body, `items_api_client_requests_total{client="things-api",operation="GetThing",status="error"} 1`)
+	assert.Contains(t, body, `items_api_client_request_duration_seconds_count{client="things-api",operation="GetThing"} 2`)
+}
+
+func TestMetrics_Business_Counters(t *testing.T) {
+	body := scrape(metrics.Handler())
+
+	assert.Contains(t, body, "items_api_items_created_total")
+	assert.Contains(t, body, "items_api_items_deleted_total")
+	assert.Contains(t, body, "go_goroutines")
+}
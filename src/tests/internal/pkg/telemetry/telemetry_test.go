@@ -0,0 +1,82 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agustinrabini/items-api-project/src/main/api/handlers"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/telemetry"
+	"github.com/gin-gonic/gin"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func recordSpans() *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	return recorder
+}
+
+func TestTelemetry_Handler_Continues_Caller_Trace(t *testing.T) {
+	recorder := recordSpans()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/things/:id", handlers.LoggerHandler("GetThing"), func(c *gin.Context) {
+		_, span := telemetry.Start(c.Request.Context(), "ThingsService.Get")
+		span.End()
+
+		xTraceID, _ := c.Get("X-Trace-ID")
+		c.String(http.StatusInternalServerError, "%v", xTraceID)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/things/1", nil)
+	request.Header.Set("traceparent", traceparent)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	spans := recorder.Ended()
+	assert.Equal(t, 2, len(spans))
+
+	service, server := spans[0], spans[1]
+	assert.Equal(t, "ThingsService.Get", service.Name())
+	assert.Equal(t, "GetThing", server.Name())
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, codes.Error, server.Status().Code)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+	assert.Equal(t, server.SpanContext().SpanID(), service.Parent().SpanID())
+
+	// with no X-Trace-ID set by anyone else the trace ID is used
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", response.Body.String())
+}
+
+func TestTelemetry_Client_Injects_Traceparent(t *testing.T) {
+	recorder := recordSpans()
+	ctx, parent := telemetry.Start(context.Background(), "ItemsService.Get")
+
+	headers := http.Header{}
+	_, span := telemetry.StartClient(ctx, "prices.GetPriceByItemID", headers)
+	telemetry.EndClient(span, 0)
+	parent.End()
+
+	assert.Contains(t, headers.Get("traceparent"), parent.SpanContext().TraceID().String())
+
+	client := recorder.Ended()[0]
+	assert.Equal(t, trace.SpanKindClient, client.SpanKind())
+	assert.Equal(t, codes.Error, client.Status().Code)
+}
+
+func TestTelemetry_Setup_None(t *testing.T) {
+	shutdown, err := telemetry.Setup(context.Background(), telemetry.Config{Exporter: telemetry.ExporterNone})
+
+	assert.Nil(t, err)
+	assert.Nil(t, shutdown(context.Background()))
+}
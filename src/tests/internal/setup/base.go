@@ -1,40 +1,99 @@
 package setup
 
 import (
+	"net/http"
+
 	"github.com/agustinrabini/items-api-project/src/main/api/app"
+	"github.com/agustinrabini/items-api-project/src/main/api/config"
 	"github.com/agustinrabini/items-api-project/src/main/api/dependencies"
+	"github.com/agustinrabini/items-api-project/src/main/api/graph"
 	"github.com/agustinrabini/items-api-project/src/main/api/handlers"
+	"github.com/agustinrabini/items-api-project/src/main/api/platform/ratelimit"
+	"github.com/agustinrabini/items-api-project/src/main/pkg/metrics"
 	"github.com/gin-gonic/gin"
 )
 
 // BuildRouter Helper function to create a router during testing.
 func BuildRouter(depend dependencies.HandlersStruct) *gin.Engine {
-	router := app.ConfigureRouter()
+	router, err := app.ConfigureRouter()
+	if err != nil {
+		panic(err)
+	}
 	mockRouteMapper(router, depend)
 	return router
 }
 
 func mockRouteMapper(router *gin.Engine, h dependencies.HandlersStruct) {
 	// Health
-	health := handlers.NewHealthCheckerHandler()
-	router.GET("/ping", health.Ping)
-
-	// Items
-	router.GET("/items", handlers.LoggerHandler("GetItemsByUserID"), mockAuthFirebase("01-USER-TEST"), h.Items.GetItemsByUserID)
-	router.GET("/items/:id", handlers.LoggerHandler("GetItemByID"), h.Items.GetItemByID)
-	router.GET("/items/shop/:id", handlers.LoggerHandler("GetItemsByShopID"), h.Items.GetItemsByShopID)
-	router.GET("/items/shop/:id/category/:category_id", handlers.LoggerHandler("GetItemsByShopCategoryID"), h.Items.GetItemsByShopCategoryID)
-	router.POST("/items/list", handlers.LoggerHandler("GetItemsByIDs"), h.Items.GetItemsByIDs)
-	router.POST("/items", handlers.LoggerHandler("CreateItem"), mockAuthFirebase("01-USER-TEST"), h.Items.CreateItem)
-	router.DELETE("/items/:id", handlers.LoggerHandler("DeleteItem"), h.Items.DeleteItem)
-	router.PUT("/items/:id", handlers.LoggerHandler("UpdateItem"), mockAuthFirebase("01-USER-TEST"), h.Items.UpdateItem)
-
-	//Categories
-	router.PUT("/items/category", handlers.LoggerHandler("UpdateCategory"), h.Categories.Update)
-	router.DELETE("/items/category/:id_category", handlers.LoggerHandler("DeleteCategory"), h.Categories.Delete)
-	router.POST("/items/category", handlers.LoggerHandler("CreateCategory"), h.Categories.Create)
-	router.GET("/items/category/:id_category", handlers.LoggerHandler("GetCategory"), h.Categories.Get)
-	router.GET("/items/categories", handlers.LoggerHandler("GetAllCategories"), h.Categories.GetAllCategories)
+	router.GET("/ping", h.Health.Ping)
+	router.GET("/health/live", h.Health.Live)
+	router.GET("/health/ready", h.Health.Ready)
+
+	// Metrics
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Error catalog, the problem types point here
+	router.GET("/errors", handlers.ErrorCatalog)
+	router.GET("/errors/:code", handlers.ErrorType)
+
+	// Rate limits, per route and caller
+	readLimit := handlers.RateLimitHandler(h.RateLimiter, ratelimit.Limit{Rate: config.ConfMap.RateLimitReadRate, Burst: config.ConfMap.RateLimitReadBurst})
+	writeLimit := handlers.RateLimitHandler(h.RateLimiter, ratelimit.Limit{Rate: config.ConfMap.RateLimitWriteRate, Burst: config.ConfMap.RateLimitWriteBurst})
+
+	// Idempotency-Key support for the POSTs that create resources
+	idempotent := handlers.IdempotencyHandler(h.Idempotency)
+
+	// Items, collections and categories, mounted once per API version
+	auth := mockAuthFirebase("01-USER-TEST")
+
+	// GraphQL, reads are public and mutations need a Firebase token
+	limits := handlers.OperationHandler(graph.IsMutation, []gin.HandlerFunc{readLimit}, []gin.HandlerFunc{writeLimit, idempotent})
+	graphQL := []gin.HandlerFunc{handlers.OptionalAuthHandler(auth), limits, h.GraphQL.Serve}
+	router.GET("/graphql", graphQL...)
+	router.POST("/graphql", graphQL...)
+	v1 := app.Routes{
+		// Items
+		app.NewRoute(http.MethodGet, "/items", "GetItemsByUserID", auth, readLimit, h.Items.GetItemsByUserID),
+		app.NewRoute(http.MethodGet, "/items/:id", "GetItemByID", readLimit, h.Items.GetItemByID),
+		app.NewRoute(http.MethodGet, "/items/by-slug/:slug", "GetItemBySlug", readLimit, h.Items.GetItemBySlug),
+		app.NewRoute(http.MethodGet, "/items/shop/:id", "GetItemsByShopID", readLimit, h.Items.GetItemsByShopID),
+		app.NewRoute(http.MethodGet, "/items/shop/:id/category/:category_id", "GetItemsByShopCategoryID", readLimit, h.Items.GetItemsByShopCategoryID),
+		app.NewRoute(http.MethodGet, "/items/shop/:id/categories", "GetShopCategories", readLimit, h.Categories.GetShopCategories),
+		app.NewRoute(http.MethodPost, "/items/list", "GetItemsByIDs", readLimit, h.Items.GetItemsByIDs),
+		app.NewRoute(http.MethodGet, "/items/search", "SearchItems", readLimit, h.Items.SearchItems),
+		app.NewRoute(http.MethodPost, "/items", "CreateItem", auth, writeLimit, idempotent, h.Items.CreateItem),
+		app.NewRoute(http.MethodDelete, "/items/:id", "DeleteItem", writeLimit, h.Items.DeleteItem),
+		app.NewRoute(http.MethodPut, "/items/:id", "UpdateItem", auth, writeLimit, h.Items.UpdateItem),
+		app.NewRoute(http.MethodPut, "/items/:id/translations/:locale", "SetItemTranslation", auth, writeLimit, h.Items.SetItemTranslation),
+		app.NewRoute(http.MethodDelete, "/items/:id/translations/:locale", "DeleteItemTranslation", auth, writeLimit, h.Items.DeleteItemTranslation),
+		app.NewRoute(http.MethodGet, "/items/:id/revisions", "ListItemRevisions", auth, readLimit, h.Revisions.ListRevisions),
+		app.NewRoute(http.MethodGet, "/items/:id/revisions/:rev", "GetItemRevision", auth, readLimit, h.Revisions.GetRevision),
+		app.NewRoute(http.MethodPost, "/items/:id/revisions/:rev/revert", "RevertItemRevision", auth, writeLimit, h.Revisions.RevertRevision),
+
+		// Collections
+		app.NewRoute(http.MethodGet, "/items/shop/:id/collections", "GetShopCollections", readLimit, h.Collections.GetShopCollections),
+		app.NewRoute(http.MethodGet, "/items/collections/:id", "GetCollection", readLimit, h.Collections.GetCollection),
+		app.NewRoute(http.MethodPost, "/items/collections", "CreateCollection", auth, writeLimit, idempotent, h.Collections.CreateCollection),
+		app.NewRoute(http.MethodPut, "/items/collections/:id", "UpdateCollection", auth, writeLimit, h.Collections.UpdateCollection),
+		app.NewRoute(http.MethodPut, "/items/collections/:id/items", "SetCollectionItems", auth, writeLimit, h.Collections.SetCollectionItems),
+		app.NewRoute(http.MethodDelete, "/items/collections/:id", "DeleteCollection", auth, writeLimit, h.Collections.DeleteCollection),
+
+		//Categories
+		app.NewRoute(http.MethodPut, "/items/category", "UpdateCategory", h.Categories.Update),
+		app.NewRoute(http.MethodDelete, "/items/category/:id_category", "DeleteCategory", h.Categories.Delete),
+		app.NewRoute(http.MethodPost, "/items/category", "CreateCategory", idempotent, h.Categories.Create),
+		app.NewRoute(http.MethodGet, "/items/category/:id_category", "GetCategory", readLimit, h.Categories.Get),
+		app.NewRoute(http.MethodGet, "/items/category/by-slug/:slug", "GetCategoryBySlug", readLimit, h.Categories.GetBySlug),
+		app.NewRoute(http.MethodGet, "/items/categories", "GetAllCategories", readLimit, h.Categories.GetAllCategories),
+		app.NewRoute(http.MethodPut, "/items/category/:id_category/translations/:locale", "SetCategoryTranslation", h.Categories.SetTranslation),
+		app.NewRoute(http.MethodDelete, "/items/category/:id_category/translations/:locale", "DeleteCategoryTranslation", h.Categories.DeleteTranslation),
+	}
+
+	v2 := v1.Override(
+		app.NewRoute(http.MethodGet, "/items/shop/:id", "GetItemsByShopID", readLimit, h.Items.GetItemsPageByShopID),
+	)
+
+	app.MountVersions(router, v1, v2)
 }
 
 func mockAuthFirebase(userID string) gin.HandlerFunc {